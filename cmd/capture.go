@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"nnw_s/pkg/smtp"
+)
+
+const captureCommand = "capture"
+
+// runCapture starts SMTP server which keeps emails in memory and shows them over HTTP, it is for development only.
+// Server is started against it with plain connection, any credentials are accepted:
+//
+//	go run ./cmd capture -smtp 127.0.0.1:1025 -http 127.0.0.1:8025
+//	SMTP_HOST=127.0.0.1 SMTP_PORT=1025 SMTP_TLS_MODE=none go run ./cmd
+func runCapture(args []string) error {
+	flags := flag.NewFlagSet(captureCommand, flag.ContinueOnError)
+	smtpAddr := flags.String("smtp", "127.0.0.1:1025", "address of SMTP server")
+	httpAddr := flags.String("http", "127.0.0.1:8025", "address of web page with captured emails")
+	maxMessages := flags.Int("max", 100, "how many last emails are kept")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	server := smtp.NewCaptureServer()
+	server.MaxMessages = *maxMessages
+
+	l, err := net.Listen("tcp", *smtpAddr)
+	if err != nil {
+		return err
+	}
+	defer server.Close()
+
+	go func() {
+		log.Printf("captured emails are shown at http://%s", *httpAddr)
+		if err := http.ListenAndServe(*httpAddr, server.Handler()); err != nil {
+			log.Fatalf("failed to start HTTP server: %v", err)
+		}
+	}()
+
+	log.Printf("capturing emails at %s", *smtpAddr)
+	return server.Serve(l)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"nnw_s/config"
+	"nnw_s/internal/health"
+	"nnw_s/pkg/wallet"
+	btc_rpc "nnw_s/pkg/wallet/Bitcoin/rpc"
+	eth_rpc "nnw_s/pkg/wallet/Ethereum/rpc"
+	"nnw_s/pkg/wallet/node"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chains are clients of nodes of enabled chains, client of disabled chain is nil
+type chains struct {
+	btc        *btc_rpc.Client
+	btcNetwork *wallet.BTCNetwork
+	eth        *eth_rpc.Client
+}
+
+// nodeConfig maps config of chain to config of its node connection, requests are recorded to metrics of chain
+func nodeConfig(chain string, cfg *config.ChainConfig) node.Config {
+	return node.Config{
+		Chain:      chain,
+		URL:        cfg.RpcURL,
+		Auth:       cfg.RpcAuth,
+		User:       cfg.RpcUser,
+		Password:   cfg.RpcPassword,
+		CookieFile: cfg.RpcCookieFile,
+		Token:      cfg.RpcToken,
+		Timeout:    cfg.RpcTimeout,
+		Observer:   observeRPC(chain),
+	}
+}
+
+// newChains creates clients of nodes of enabled chains. BTC node which runs another network is an error,
+// node which is not available yet is only logged.
+func newChains(cfg *config.Config, logger *logrus.Logger) (*chains, error) {
+	var (
+		c   chains
+		err error
+	)
+
+	if c.btcNetwork, err = wallet.NewBTCNetwork(cfg.BTC.Network); err != nil {
+		return nil, err
+	}
+
+	if cfg.BTC.Enabled {
+		if c.btc, err = btc_rpc.NewClient(nodeConfig("BTC", &cfg.BTC)); err != nil {
+			return nil, err
+		}
+
+		chain, err := c.btc.GetChain(context.Background())
+		switch {
+		case err != nil:
+			logger.Warnf("failed to check network of BTC node: %v", err)
+		case chain != c.btcNetwork.NodeChain:
+			return nil, fmt.Errorf("BTC node runs %s chain, expected %s network", chain, c.btcNetwork.Name)
+		}
+	}
+
+	if cfg.ETH.Enabled {
+		if c.eth, err = eth_rpc.NewClient(nodeConfig("ETH", &cfg.ETH)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &c, nil
+}
+
+// addChecks adds checks of nodes of enabled chains to readiness of the server
+func (c *chains) addChecks(checker *health.Checker) {
+	if c.btc != nil {
+		checker.AddOptional("btc_node", func(ctx context.Context) error {
+			chain, err := c.btc.GetChain(ctx)
+			if err == nil && chain != c.btcNetwork.NodeChain {
+				return fmt.Errorf("node runs %s chain, expected %s network", chain, c.btcNetwork.Name)
+			}
+			return err
+		})
+	}
+	if c.eth != nil {
+		checker.AddOptional("eth_node", func(ctx context.Context) error {
+			_, err := c.eth.GetBlockNumber(ctx)
+			return err
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// lifecycle runs background workers until shutdown and then closes resources in reverse order
+type lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	closers []closer
+
+	log *logrus.Logger
+}
+
+type closer struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+func newLifecycle(log *logrus.Logger) *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{ctx: ctx, cancel: cancel, log: log}
+}
+
+// Go runs worker in background, ctx of the worker is done on shutdown
+func (l *lifecycle) Go(name string, run func(ctx context.Context)) {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		run(l.ctx)
+		l.log.Infof("%s is stopped", name)
+	}()
+}
+
+// OnStop adds resource which is closed after workers are stopped, e.g. database connection
+func (l *lifecycle) OnStop(name string, close func(ctx context.Context) error) {
+	l.closers = append(l.closers, closer{name: name, close: close})
+}
+
+// Stop stops workers and waits for them until ctx is done, then closes resources
+func (l *lifecycle) Stop(ctx context.Context) {
+	l.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		l.log.Warn("background workers are not stopped in time")
+	}
+
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		if err := l.closers[i].close(ctx); err != nil {
+			l.log.Errorf("failed to close %s: %v", l.closers[i].name, err)
+		}
+	}
+}
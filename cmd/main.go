@@ -1,25 +1,69 @@
 package main
 
 import (
+	"context"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	"github.com/sirupsen/logrus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"log"
 	"net/http"
 	"nnw_s/config"
+	"nnw_s/internal/admin"
 	"nnw_s/internal/auth"
 	"nnw_s/internal/auth/jwt"
 	"nnw_s/internal/auth/twofa"
 	"nnw_s/internal/auth/verification"
+	"nnw_s/internal/docs"
+	"nnw_s/internal/events"
+	"nnw_s/internal/health"
+	"nnw_s/internal/stream"
 	"nnw_s/internal/user"
+	"nnw_s/internal/user/addressbook"
 	"nnw_s/internal/user/credentials"
+	"nnw_s/internal/user/notifications"
 	"nnw_s/internal/user/wallet"
-	"nnw_s/pkg/mongodb"
+	"nnw_s/internal/webhooks"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/httpx"
+	"nnw_s/pkg/i18n"
+	"nnw_s/pkg/logging"
+	"nnw_s/pkg/metrics"
 	"nnw_s/pkg/notificator"
-	"nnw_s/pkg/smtp"
+	"nnw_s/pkg/openapi"
+	"nnw_s/pkg/outbound"
+	"nnw_s/pkg/tracing"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	// Render email templates without starting the server
+	if len(os.Args) > 1 && os.Args[1] == previewCommand {
+		if err := runPreview(os.Args[2:]); err != nil {
+			log.Fatalf("failed to preview template: %v", err)
+		}
+		return
+	}
+
+	// Apply migrations of mongo storage without starting the server
+	if len(os.Args) > 1 && os.Args[1] == migrateCommand {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("failed to migrate: %v", err)
+		}
+		return
+	}
+
+	// Capture emails of local development instead of sending them
+	if len(os.Args) > 1 && os.Args[1] == captureCommand {
+		if err := runCapture(os.Args[2:]); err != nil {
+			log.Fatalf("failed to run capture server: %v", err)
+		}
+		return
+	}
+
 	// Init config
 	cfg, err := config.Get()
 	if err != nil {
@@ -27,62 +71,122 @@ func main() {
 	}
 
 	// Init logger
-	logger := logrus.New()
+	logger := logging.New(cfg.Environment)
+
+	// Background workers are stopped and connections are closed on shutdown
+	app := newLifecycle(logger)
+
+	// Tracing, spans are flushed after everything else is stopped
+	shutdownTracing, err := tracing.Setup(tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		Endpoint:    cfg.TracingEndpoint,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatalf("failed to setup tracing: %v", err)
+	}
+	app.OnStop("tracing", shutdownTracing)
 
-	// Create App
+	// Create App, start of the server is logged by logger, not by banner of echo
 	router := echo.New()
+	router.HideBanner = true
+	router.HidePort = true
 
 	// Connection to DB
-	db, err := mongodb.NewConn(cfg)
+	repos, err := newRepositories(cfg, logger)
 	if err != nil {
-		logger.Fatalf("failed to connect to mongodb: %v", err)
+		logger.Fatalf("failed to init %s storage: %v", cfg.Storage, err)
 	}
+	app.OnStop("storage", repos.close)
 
 	// Init App Middleware
 	router.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{cfg.CorsOrigin.DevOrigin, cfg.CorsOrigin.ProdOrigin},
 		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept},
 	}))
+	// Errors of handlers and middleware are returned as problems of RFC 7807
+	router.HTTPErrorHandler = errors.HTTPErrorHandler(logger)
+	router.Use(tracing.Middleware())
+	router.Use(logging.Middleware(logger))
+	router.Use(metrics.Middleware())
+	// Error response is written once, so the middleware above sees its status
+	router.Use(httpx.ErrorResponse())
+
+	// OpenAPI specification of the API
+	spec, err := docs.Spec()
+	if err != nil {
+		logger.Fatalf("failed to load OpenAPI specification: %v", err)
+	}
+	if cfg.ValidateRequests {
+		validator, err := openapi.Middleware(spec)
+		if err != nil {
+			logger.Fatalf("failed to create validator of requests: %v", err)
+		}
+		router.Use(validator)
+	}
 
 	// Init dependencies
-	userRepo := user.NewRepository(db, logger)
 	credentialsSvc, err := credentials.NewService(logger, cfg.Shift, cfg.PasswordSalt)
 	if err != nil {
 		logger.Fatalf("failed to create credentials service: %v", err)
 	}
 
-	userSvc, err := user.NewService(userRepo, credentialsSvc, logger)
+	userSvc, err := user.NewService(repos.user, credentialsSvc, logger)
 	if err != nil {
 		logger.Fatalf("failed to create user service: %v", err)
 	}
 
-	smtpClient := smtp.NewClient(cfg.SmtpHost, cfg.SmtpPort, cfg.SmtpUserApiKey, cfg.SmtpPasswordKey)
-	notificatorSvc, err := notificator.NewService(logger, smtpClient)
+	smtpClient, err := newSMTPClient(cfg)
 	if err != nil {
-		logger.Fatalf("failed to create user service: %v", err)
+		logger.Fatalf("failed to create smtp client: %v", err)
 	}
+	app.OnStop("smtp client", func(context.Context) error { return smtpClient.Close() })
 
-	verificationRepo, err := verification.NewRepository(db, logger)
+	translations, err := i18n.NewBundle()
 	if err != nil {
-		logger.Fatalf("failed to create verification repo: %v", err)
+		logger.Fatalf("failed to load translations: %v", err)
 	}
 
-	verificationSvc, err := verification.NewService(verificationRepo, logger)
+	templates, err := notificator.NewRegistry(translations)
 	if err != nil {
-		logger.Fatalf("failed to create verification service: %v", err)
+		logger.Fatalf("failed to parse email templates: %v", err)
 	}
 
-	twoFaSvc, err := twofa.NewService(cfg.TwoFAIssuer)
+	notificatorSvc, err := notificator.NewService(logger, repos.outbox, templates, clock.New())
 	if err != nil {
-		logger.Fatalf("failed to create TwoFA service: %v", err)
+		logger.Fatalf("failed to create notificator service: %v", err)
+	}
+
+	transports, err := newTransports(cfg, smtpClient, logger)
+	if err != nil {
+		logger.Fatalf("failed to create notification transports: %v", err)
 	}
 
-	jwtRepo, err := jwt.NewRepository(db)
+	outboxWorker, err := notificator.NewWorker(logger, repos.outbox, transports, clock.New(), notificator.WorkerOptions{})
 	if err != nil {
-		logger.Fatalf("failed to create JWT repo: %v", err)
+		logger.Fatalf("failed to create outbox worker: %v", err)
 	}
+	app.Go("outbox worker", outboxWorker.Run)
 
-	jwtSvc, err := jwt.NewService(jwtRepo, cfg.JwtSecretKey)
+	// Domain events, side effects of services are done by subscribers of the bus
+	eventBus, err := eventbus.NewBus(logger, repos.eventLog)
+	if err != nil {
+		logger.Fatalf("failed to create event bus: %v", err)
+	}
+	eventBus.Register(events.Factories()...)
+
+	verificationSvc, err := verification.NewService(repos.verification, logger)
+	if err != nil {
+		logger.Fatalf("failed to create verification service: %v", err)
+	}
+
+	twoFaSvc, err := twofa.NewService(cfg.TwoFAIssuer)
+	if err != nil {
+		logger.Fatalf("failed to create TwoFA service: %v", err)
+	}
+
+	jwtSvc, err := jwt.NewService(repos.jwt, cfg.JwtSecretKey)
 	if err != nil {
 		logger.Fatalf("failed to create JWT service: %v", err)
 	}
@@ -94,6 +198,8 @@ func main() {
 		TwoFAService:        twoFaSvc,
 		JWTService:          jwtSvc,
 		CredentialsService:  credentialsSvc,
+		EventBus:            eventBus,
+		UnitOfWork:          repos.unitOfWork,
 	}
 
 	registrationSvc, err := auth.NewRegistrationService(logger, cfg.EmailFrom, &authDeps)
@@ -111,11 +217,85 @@ func main() {
 		logger.Fatalf("failed to connect reset password service: %v", err)
 	}
 
+	nodes, err := newChains(cfg, logger)
+	if err != nil {
+		logger.Fatalf("failed to create clients of blockchain nodes: %v", err)
+	}
+
+	addressBookDeps := addressbook.ServiceDeps{
+		Repository:   repos.addressBook,
+		UserService:  userSvc,
+		TwoFAService: twoFaSvc,
+		BTCNetwork:   nodes.btcNetwork,
+	}
+
+	addressBookSvc, err := addressbook.NewService(logger, &addressBookDeps)
+	if err != nil {
+		logger.Fatalf("failed to create address book service: %v", err)
+	}
+
+	notificationsDeps := notifications.ServiceDeps{
+		Repository:         repos.notificationSettings,
+		UserService:        userSvc,
+		NotificatorService: notificatorSvc,
+		TwoFAService:       twoFaSvc,
+		CredentialsService: credentialsSvc,
+	}
+
+	notificationsSvc, err := notifications.NewService(logger, cfg.EmailFrom, &notificationsDeps)
+	if err != nil {
+		logger.Fatalf("failed to create notifications service: %v", err)
+	}
+
+	notificationSubscriber, err := events.NewNotificationSubscriber(notificationsSvc)
+	if err != nil {
+		logger.Fatalf("failed to create notification subscriber: %v", err)
+	}
+	notificationSubscriber.Subscribe(eventBus)
+
+	auditSubscriber, err := events.NewAuditSubscriber(logger)
+	if err != nil {
+		logger.Fatalf("failed to create audit subscriber: %v", err)
+	}
+	auditSubscriber.Subscribe(eventBus)
+	events.NewMetricsSubscriber().Subscribe(eventBus)
+
+	webhooksDeps := webhooks.ServiceDeps{
+		Repository:  repos.webhooks,
+		UserService: userSvc,
+	}
+
+	webhooksSvc, err := webhooks.NewService(logger, &webhooksDeps)
+	if err != nil {
+		logger.Fatalf("failed to create webhooks service: %v", err)
+	}
+
+	webhooksSubscriber, err := webhooks.NewSubscriber(webhooksSvc)
+	if err != nil {
+		logger.Fatalf("failed to create webhooks subscriber: %v", err)
+	}
+	webhooksSubscriber.Subscribe(eventBus)
+
+	webhooksWorker, err := webhooks.NewWorker(logger, repos.webhooks, outbound.NewClient(cfg.WebhookTimeout), clock.New(), webhooks.WorkerOptions{
+		SendTimeout: cfg.WebhookTimeout,
+	})
+	if err != nil {
+		logger.Fatalf("failed to create webhooks worker: %v", err)
+	}
+	app.Go("webhooks worker", webhooksWorker.Run)
+
 	walletDeps := wallet.ServiceDeps{
+		WalletRepository:   repos.wallet,
 		UserService:        userSvc,
+		AddressBookService: addressBookSvc,
 		TwoFAService:       twoFaSvc,
 		JWTService:         jwtSvc,
 		CredentialsService: credentialsSvc,
+		EventBus:           eventBus,
+		UnitOfWork:         repos.unitOfWork,
+		BTCClient:          nodes.btc,
+		ETHClient:          nodes.eth,
+		BTCNetwork:         nodes.btcNetwork,
 	}
 
 	walletSvc, err := wallet.NewWalletService(logger, &walletDeps)
@@ -123,7 +303,57 @@ func main() {
 		logger.Fatalf("failed to connect wallet wallet service: %v", err)
 	}
 
+	// Real-time updates of connected users
+	streamHub, err := stream.NewHub(logger, repos.streamBroker)
+	if err != nil {
+		logger.Fatalf("failed to create stream hub: %v", err)
+	}
+	app.Go("stream hub", streamHub.Run)
+
+	streamSubscriber, err := stream.NewSubscriber(streamHub)
+	if err != nil {
+		logger.Fatalf("failed to create stream subscriber: %v", err)
+	}
+	streamSubscriber.Subscribe(eventBus)
+
+	// Chains are watched by one instance only, otherwise deposits and confirmations are published by each of them
+	if cfg.WatchChains {
+		chainWatcher, err := stream.NewWatcher(logger, streamHub, walletSvc, eventBus, stream.WatcherOptions{
+			PollInterval: cfg.StreamPollInterval,
+		})
+		if err != nil {
+			logger.Fatalf("failed to create chain watcher: %v", err)
+		}
+		app.Go("chain watcher", chainWatcher.Run)
+	}
+
+	// Health of the server and its dependencies
+	checker := health.NewChecker(cfg.HealthCheckTimeout)
+	checker.Add("storage", repos.ping)
+	checker.AddOptional("smtp", func(context.Context) error { return smtpClient.Check() })
+	nodes.addChecks(checker)
+
+	// Metrics of the server for Prometheus
+	metricsRegistry, err := newMetricsRegistry(repos, cfg.HealthCheckTimeout)
+	if err != nil {
+		logger.Fatalf("failed to register metrics: %v", err)
+	}
+
 	// Handlers
+	// Metrics
+	router.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})))
+
+	// Health
+	healthHandler := health.NewHandler(checker)
+	healthHandler.SetupRoutes(router)
+
+	// Docs of API
+	docsHandler, err := docs.NewHandler(spec)
+	if err != nil {
+		logger.Fatalf("failed to create docs handler: %v", err)
+	}
+	docsHandler.SetupRoutes(router)
+
 	// User
 	userHandler := user.NewHandler(userSvc, jwtSvc, cfg.Shift)
 	userHandler.SetupRoutes(router)
@@ -136,20 +366,54 @@ func main() {
 	walletHandler := wallet.NewHandler(walletSvc, jwtSvc, cfg.Shift)
 	walletHandler.SetupRoutes(router)
 
-	// NotFound Urls
-	echo.NotFoundHandler = func(c echo.Context) error {
-		// Return HTTP 404 status and JSON response.
-		return c.JSON(http.StatusNotFound, echo.Map{
-			"error":    true,
-			"endpoint": c.Request().URL.Path,
-			"msg":      "Sorry, endpoint is not found",
-		})
-	}
+	// Admin
+	adminHandler := admin.NewHandler(notificatorSvc, webhooksSvc, eventBus, cfg.AdminApiKey)
+	adminHandler.SetupRoutes(router)
+
+	// Address book
+	addressBookHandler := addressbook.NewHandler(addressBookSvc, jwtSvc)
+	addressBookHandler.SetupRoutes(router)
+
+	// Notification settings
+	notificationsHandler := notifications.NewHandler(notificationsSvc, jwtSvc)
+	notificationsHandler.SetupRoutes(router)
+
+	// Webhooks
+	webhooksHandler := webhooks.NewHandler(webhooksSvc, jwtSvc)
+	webhooksHandler.SetupRoutes(router)
+
+	// Stream
+	streamHandler := stream.NewHandler(streamHub, jwtSvc)
+	streamHandler.SetupRoutes(router)
+
+	// Streams are finished on shutdown, otherwise server waits for them until timeout
+	router.Server.RegisterOnShutdown(streamHub.Close)
 
 	// Starting App
-	logger.Info("starting NNW server at :%s...", cfg.PORT)
-	if err = router.Start(":" + cfg.PORT); err != nil {
-		logger.Errorf("failed to start HTTP server: %v", err)
-		return
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	go func() {
+		logger.Infof("starting NNW server at :%s...", cfg.PORT)
+		if err := router.Start(":" + cfg.PORT); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("failed to start HTTP server: %v", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("shutting down NNW server...")
+
+	// load balancer stops sending requests once server is not ready
+	checker.Drain()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err = router.Shutdown(shutdownCtx); err != nil {
+		logger.Errorf("failed to drain HTTP requests: %v", err)
 	}
+	app.Stop(shutdownCtx)
+
+	logger.Info("NNW server is stopped")
 }
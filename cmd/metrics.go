@@ -0,0 +1,44 @@
+package main
+
+import (
+	"nnw_s/pkg/metrics"
+	"nnw_s/pkg/wallet/node"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newMetricsRegistry returns registry of metrics exposed by /metrics: runtime of the process,
+// metrics of the server and sizes of queues of background workers
+func newMetricsRegistry(repos *repositories, countTimeout time.Duration) (*prometheus.Registry, error) {
+	reg := prometheus.NewRegistry()
+
+	if err := reg.Register(prometheus.NewGoCollector()); err != nil {
+		return nil, err
+	}
+	if err := reg.Register(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{})); err != nil {
+		return nil, err
+	}
+	if err := metrics.Register(reg); err != nil {
+		return nil, err
+	}
+
+	queues := metrics.NewQueueCollector(countTimeout)
+	queues.Add("outbox", repos.outbox.CountPending)
+	queues.Add("webhooks", repos.webhooks.CountPending)
+	if err := reg.Register(queues); err != nil {
+		return nil, err
+	}
+
+	return reg, nil
+}
+
+// observeRPC records requests to node of chain to metrics
+func observeRPC(chain string) node.Observer {
+	return func(method string, duration time.Duration, err error) {
+		metrics.RPCDuration.WithLabelValues(chain, method).Observe(duration.Seconds())
+		if err != nil {
+			metrics.RPCErrors.WithLabelValues(chain, method).Inc()
+		}
+	}
+}
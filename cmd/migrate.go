@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"nnw_s/config"
+	"nnw_s/pkg/logging"
+	"nnw_s/pkg/mongodb"
+)
+
+const migrateCommand = "migrate"
+
+// runMigrate applies pending migrations of mongo storage, so they can be applied before a deploy
+// of servers which have MIGRATE_ON_START disabled.
+//
+//	go run ./cmd migrate -dry-run
+//	go run ./cmd migrate
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet(migrateCommand, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "list pending migrations without applying them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		return err
+	}
+	if cfg.Storage != config.StorageMongo {
+		return errors.New("migrations are applied only to mongo storage")
+	}
+
+	db, err := mongodb.NewConn(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	migrator, err := newMigrator(db, logging.New(cfg.Environment))
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		pending, err := migrator.Pending(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Println("database is up to date")
+		}
+		for _, m := range pending {
+			fmt.Printf("%d: %s\n", m.Version, m.Description)
+		}
+		return nil
+	}
+
+	applied, err := migrator.Up(context.Background())
+	fmt.Printf("applied %d migrations\n", len(applied))
+	return err
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"nnw_s/internal/auth/jwt"
+	"nnw_s/internal/auth/verification"
+	"nnw_s/internal/stream"
+	"nnw_s/internal/user"
+	"nnw_s/internal/user/addressbook"
+	"nnw_s/internal/user/notifications"
+	"nnw_s/internal/user/wallet"
+	"nnw_s/internal/webhooks"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/mongodb/migrations"
+	"nnw_s/pkg/notificator"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// schemaMigrations are migrations of mongo storage. Applied migrations are never run again,
+// so a new index or a change of TTL is a new migration with the next version.
+func schemaMigrations(logger *logrus.Logger) []*migrations.Migration {
+	return []*migrations.Migration{
+		{Version: 1, Description: "create unique index of user emails", Up: user.CreateIndexes},
+		{Version: 2, Description: "create TTL index of tokens", Up: jwt.CreateIndexes},
+		{Version: 3, Description: "create TTL indexes of verification and reset password codes", Up: verification.CreateIndexes},
+		{Version: 4, Description: "create indexes of wallets", Up: wallet.CreateIndexes},
+		{
+			Version:     5,
+			Description: "move wallets embedded into users to wallets collection",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return wallet.MigrateEmbeddedWallets(ctx, db, logger)
+			},
+		},
+		{Version: 6, Description: "create indexes of address book contacts", Up: addressbook.CreateIndexes},
+		{Version: 7, Description: "create indexes of notifications outbox", Up: notificator.CreateIndexes},
+		{Version: 8, Description: "create indexes of notification settings", Up: notifications.CreateIndexes},
+		{Version: 9, Description: "create indexes of webhook endpoints and deliveries", Up: webhooks.CreateIndexes},
+		{Version: 10, Description: "create TTL index of stream messages", Up: stream.CreateIndexes},
+		{Version: 11, Description: "create index of event log", Up: eventbus.CreateIndexes},
+	}
+}
+
+func newMigrator(db *mongo.Database, logger *logrus.Logger) (migrations.Migrator, error) {
+	return migrations.NewMigrator(db, logger, clock.New(), schemaMigrations(logger))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"nnw_s/pkg/i18n"
+	"nnw_s/pkg/notificator"
+	"os"
+	"strings"
+)
+
+const previewCommand = "preview"
+
+// runPreview renders email template of the event with sample data, so templates can be checked without sending mail.
+//
+//	go run ./cmd preview -list
+//	go run ./cmd preview -event verify_email -format html -out verify_email.html
+//	go run ./cmd preview -event tx_received -lang ru -format txt
+func runPreview(args []string) error {
+	flags := flag.NewFlagSet(previewCommand, flag.ContinueOnError)
+	list := flags.Bool("list", false, "list events which have templates and supported languages")
+	event := flags.String("event", "", "event to render, e.g. verify_email")
+	format := flags.String("format", "html", "part to render: html, txt or subject")
+	name := flags.String("name", "", "recipient name, sample name is used if empty")
+	lang := flags.String("lang", i18n.DefaultLanguage, "language or Accept-Language value, e.g. ru or uk-UA,uk;q=0.9")
+	out := flags.String("out", "", "file to write, stdout if empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	translations, err := i18n.NewBundle()
+	if err != nil {
+		return err
+	}
+
+	templates, err := notificator.NewRegistry(translations)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		fmt.Println("languages:", strings.Join(translations.Languages(), ", "))
+		for _, e := range templates.Events() {
+			fmt.Println(e)
+		}
+		return nil
+	}
+
+	data, err := templates.Sample(notificator.Event(*event))
+	if err != nil {
+		return err
+	}
+	if *name != "" {
+		data.SetName(*name)
+	}
+
+	rendered, err := templates.Render(data, *lang)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	switch *format {
+	case "html":
+		content = rendered.HTML
+	case "txt":
+		content = rendered.Text
+	case "subject":
+		content = rendered.Subject + "\n"
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.WriteString(content)
+		return err
+	}
+	return ioutil.WriteFile(*out, []byte(content), 0644)
+}
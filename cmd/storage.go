@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"nnw_s/config"
+	"nnw_s/internal/auth/jwt"
+	"nnw_s/internal/auth/verification"
+	"nnw_s/internal/stream"
+	"nnw_s/internal/user"
+	"nnw_s/internal/user/addressbook"
+	"nnw_s/internal/user/notifications"
+	"nnw_s/internal/user/wallet"
+	"nnw_s/internal/webhooks"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/mongodb"
+	"nnw_s/pkg/notificator"
+	"nnw_s/pkg/uow"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type repositories struct {
+	user         user.Repository
+	jwt          jwt.Repository
+	verification verification.Repository
+	wallet       wallet.Repository
+	addressBook  addressbook.Repository
+	outbox       notificator.OutboxRepository
+	// notificationSettings are user's settings of notification channels
+	notificationSettings notifications.Repository
+	// eventLog stores published domain events, it is nil if event log is disabled
+	eventLog eventbus.Store
+	// webhooks stores webhook endpoints and their delivery log
+	webhooks webhooks.Repository
+	// streamBroker passes real-time updates between server instances
+	streamBroker stream.Broker
+	// unitOfWork makes multi-step changes of services atomic
+	unitOfWork uow.UnitOfWork
+	// db is a database of mongo storage, it is nil for memory storage
+	db *mongo.Database
+}
+
+// ping checks connection to the database of the storage
+func (r *repositories) ping(ctx context.Context) error {
+	if r.db == nil {
+		return nil
+	}
+	return r.db.Client().Ping(ctx, readpref.Primary())
+}
+
+// close disconnects from the database of the storage
+func (r *repositories) close(ctx context.Context) error {
+	if r.db == nil {
+		return nil
+	}
+	return r.db.Client().Disconnect(ctx)
+}
+
+// newRepositories creates repositories of the storage set in config
+func newRepositories(cfg *config.Config, logger *logrus.Logger) (*repositories, error) {
+	if cfg.Storage == config.StorageMemory {
+		logger.Warn("in-memory storage is used, all data will be lost on restart")
+		return newMemoryRepositories(cfg, logger)
+	}
+	return newMongoRepositories(cfg, logger)
+}
+
+func newMemoryRepositories(cfg *config.Config, logger *logrus.Logger) (*repositories, error) {
+	clk := clock.New()
+
+	userRepo, err := user.NewMemoryRepository(clk)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtRepo, err := jwt.NewMemoryRepository(clk)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationRepo, err := verification.NewMemoryRepository(clk)
+	if err != nil {
+		return nil, err
+	}
+
+	walletRepo, err := wallet.NewMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	addressBookRepo, err := addressbook.NewMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRepo, err := notificator.NewOutboxMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	notificationSettingsRepo, err := notifications.NewMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	webhooksRepo, err := webhooks.NewMemoryRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	streamBroker, err := stream.NewMemoryBroker()
+	if err != nil {
+		return nil, err
+	}
+
+	var eventLog eventbus.Store
+	if cfg.EventLog {
+		if eventLog, err = eventbus.NewMemoryStore(); err != nil {
+			return nil, err
+		}
+	}
+
+	unitOfWork, err := uow.NewMemory(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repositories{
+		user:         userRepo,
+		jwt:          jwtRepo,
+		verification: verificationRepo,
+		wallet:       walletRepo,
+		addressBook:  addressBookRepo,
+		outbox:       outboxRepo,
+
+		notificationSettings: notificationSettingsRepo,
+		eventLog:             eventLog,
+		webhooks:             webhooksRepo,
+		streamBroker:         streamBroker,
+		unitOfWork:           unitOfWork,
+	}, nil
+}
+
+// migrate applies pending migrations if MIGRATE_ON_START is enabled, otherwise it only warns about them
+func migrate(cfg *config.Config, db *mongo.Database, logger *logrus.Logger) error {
+	migrator, err := newMigrator(db, logger)
+	if err != nil {
+		return err
+	}
+
+	if cfg.MigrateOnStart {
+		_, err = migrator.Up(context.Background())
+		return err
+	}
+
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		logger.Warnf("%d migrations of storage are pending, apply them with migrate command", len(pending))
+	}
+	return nil
+}
+
+// newMongoUnitOfWork returns unit of work in mongo transactions, standalone mongo server of development
+// does not support transactions, so changes are not rolled back there
+func newMongoUnitOfWork(db *mongo.Database, logger *logrus.Logger) (uow.UnitOfWork, error) {
+	supported, err := uow.SupportsTransactions(context.Background(), db.Client())
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		logger.Warn("mongo server is not a replica set, multi-step changes are not atomic")
+		return uow.NewMemory(logger)
+	}
+	return uow.NewMongo(db.Client(), logger)
+}
+
+func newMongoRepositories(cfg *config.Config, logger *logrus.Logger) (*repositories, error) {
+	db, err := mongodb.NewConn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = migrate(cfg, db, logger); err != nil {
+		return nil, err
+	}
+
+	verificationRepo, err := verification.NewRepository(db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtRepo, err := jwt.NewRepository(db)
+	if err != nil {
+		return nil, err
+	}
+
+	walletRepo, err := wallet.NewRepository(db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	addressBookRepo, err := addressbook.NewRepository(db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRepo, err := notificator.NewOutboxRepository(db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationSettingsRepo, err := notifications.NewRepository(db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooksRepo, err := webhooks.NewRepository(db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	streamBroker, err := stream.NewMemoryBroker()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.StreamBroker == config.StorageMongo {
+		if streamBroker, err = stream.NewMongoBroker(db, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	var eventLog eventbus.Store
+	if cfg.EventLog {
+		if eventLog, err = eventbus.NewStore(db, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	unitOfWork, err := newMongoUnitOfWork(db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repositories{
+		user:         user.NewRepository(db, logger),
+		jwt:          jwtRepo,
+		verification: verificationRepo,
+		wallet:       walletRepo,
+		addressBook:  addressBookRepo,
+		outbox:       outboxRepo,
+
+		notificationSettings: notificationSettingsRepo,
+		eventLog:             eventLog,
+		webhooks:             webhooksRepo,
+		streamBroker:         streamBroker,
+		unitOfWork:           unitOfWork,
+		db:                   db,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"nnw_s/config"
+	"nnw_s/pkg/notificator"
+	"nnw_s/pkg/outbound"
+	"nnw_s/pkg/smtp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newSMTPClient creates smtp client with TLS policy of the config
+func newSMTPClient(cfg *config.Config) (*smtp.Client, error) {
+	opts := smtp.Options{
+		Host:        cfg.SmtpHost,
+		Port:        cfg.SmtpPort,
+		Username:    cfg.SmtpUserApiKey,
+		Password:    cfg.SmtpPasswordKey,
+		TLSMode:     smtp.TLSMode(cfg.SmtpTLSMode),
+		Timeout:     cfg.SmtpTimeout,
+		IdleTimeout: cfg.SmtpIdleTimeout,
+	}
+
+	if cfg.SmtpCAFile != "" {
+		roots, err := smtp.LoadRootCAs(cfg.SmtpCAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.RootCAs = roots
+	}
+
+	return smtp.NewClient(opts)
+}
+
+// newTransports creates transports of notification channels, channels without settings are not configured
+// and their messages are moved to dead letter by the worker
+func newTransports(cfg *config.Config, sender notificator.Sender, logger *logrus.Logger) (map[notificator.Channel]notificator.Transport, error) {
+	// telegram and push urls come from the config, webhook urls come from users and can't reach private hosts
+	client := &http.Client{Timeout: cfg.WebhookTimeout}
+
+	email, err := notificator.NewEmailTransport(sender)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := notificator.NewWebhookTransport(outbound.NewClient(cfg.WebhookTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	transports := map[notificator.Channel]notificator.Transport{
+		notificator.ChannelEmail:   email,
+		notificator.ChannelWebhook: webhook,
+	}
+
+	if cfg.TelegramBotToken != "" {
+		telegram, err := notificator.NewTelegramTransport(cfg.TelegramApiUrl, cfg.TelegramBotToken, client)
+		if err != nil {
+			return nil, err
+		}
+		transports[notificator.ChannelTelegram] = telegram
+	} else {
+		logger.Warn("telegram notifications are disabled, TELEGRAM_BOT_TOKEN is not set")
+	}
+
+	if cfg.PushServerKey != "" {
+		push, err := notificator.NewPushTransport(cfg.PushUrl, cfg.PushServerKey, client)
+		if err != nil {
+			return nil, err
+		}
+		transports[notificator.ChannelPush] = push
+	} else {
+		logger.Warn("push notifications are disabled, PUSH_SERVER_KEY is not set")
+	}
+
+	return transports, nil
+}
@@ -2,21 +2,41 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	PORT        string `required:"true" default:"4000" envconfig:"PORT"`
+	PORT string `required:"true" default:"4000" envconfig:"PORT"`
+	// Environment selects format of logs, logs are JSON lines outside of development
 	Environment string `required:"true" default:"development" envconfig:"APP_ENV"`
 	EmailFrom   string `required:"true" envconfig:"EMAIL_FROM"`
 	TwoFAIssuer string `required:"true" envconfig:"TWO_FA_ISSUER" default:"NNW"`
+	// Storage is a kind of storage for repositories, in-memory storage is for development and tests only
+	Storage string `required:"true" default:"mongo" envconfig:"STORAGE"`
+	// EventLog stores published domain events in storage, so they can be replayed to subscribers
+	EventLog bool `default:"false" envconfig:"EVENT_LOG"`
+	// MigrateOnStart applies pending migrations of mongo storage on start, otherwise they are applied by migrate command
+	MigrateOnStart bool `default:"true" envconfig:"MIGRATE_ON_START"`
+	// ShutdownTimeout limits time of draining requests and stopping workers on SIGTERM
+	ShutdownTimeout time.Duration `default:"15s" envconfig:"SHUTDOWN_TIMEOUT"`
+	// HealthCheckTimeout limits time of checks of dependencies of /readyz
+	HealthCheckTimeout time.Duration `default:"5s" envconfig:"HEALTH_CHECK_TIMEOUT"`
+	// ValidateRequests rejects requests which don't match OpenAPI specification of /api/docs before they are handled
+	ValidateRequests bool `default:"false" envconfig:"VALIDATE_REQUESTS"`
 
 	Secrets
 	MongoConfig
 	SMTPConfig
+	ChannelsConfig
+	StreamConfig
+	ChainsConfig
+	TracingConfig
 	CorsOrigin
 }
 
@@ -29,13 +49,28 @@ type Secrets struct {
 	JwtSecretKey string `required:"true" envconfig:"JWT_SECRET_KEY"`
 	Shift        int    `required:"true" envconfig:"SHIFT"`
 	PasswordSalt int    `required:"true" envconfig:"PASSWORD_SALT"`
+	// AdminApiKey enables admin endpoints, they are disabled if it is empty
+	AdminApiKey string `envconfig:"ADMIN_API_KEY"`
 }
 
+const (
+	StorageMongo  = "mongo"
+	StorageMemory = "memory"
+)
+
+// MongoConfig is required only if mongo storage is used
 type MongoConfig struct {
-	MongoDbName string `required:"true" envconfig:"MONGO_DB_NAME"`
-	MongoDbUser string `required:"true" envconfig:"MONGO_DB_USER"`
-	MongoDbPass string `required:"true" envconfig:"MONGO_DB_PASS"`
-	MongoDbUrl  string `required:"true" envconfig:"MONGO_DB_URL"`
+	MongoDbName string `envconfig:"MONGO_DB_NAME"`
+	MongoDbUser string `envconfig:"MONGO_DB_USER"`
+	MongoDbPass string `envconfig:"MONGO_DB_PASS"`
+	MongoDbUrl  string `envconfig:"MONGO_DB_URL"`
+}
+
+func (cfg MongoConfig) validate() error {
+	if cfg.MongoDbName == "" || cfg.MongoDbUser == "" || cfg.MongoDbPass == "" || cfg.MongoDbUrl == "" {
+		return errors.New("mongo storage requires MONGO_DB_NAME, MONGO_DB_USER, MONGO_DB_PASS and MONGO_DB_URL")
+	}
+	return nil
 }
 
 type SMTPConfig struct {
@@ -43,6 +78,104 @@ type SMTPConfig struct {
 	SmtpPort        int    `required:"true" envconfig:"SMTP_PORT"`
 	SmtpUserApiKey  string `required:"true" envconfig:"SMTP_USER_API_KEY"`
 	SmtpPasswordKey string `required:"true" envconfig:"SMTP_PASSWORD_KEY"`
+	// SmtpTLSMode is implicit, starttls or none, it is implicit for port 465 and starttls otherwise if it is empty
+	SmtpTLSMode string `envconfig:"SMTP_TLS_MODE"`
+	// SmtpCAFile is PEM file of CA which signed server certificate, system roots are used if it is empty
+	SmtpCAFile      string        `envconfig:"SMTP_CA_FILE"`
+	SmtpTimeout     time.Duration `default:"30s" envconfig:"SMTP_TIMEOUT"`
+	SmtpIdleTimeout time.Duration `default:"1m" envconfig:"SMTP_IDLE_TIMEOUT"`
+}
+
+// ChannelsConfig configures notification channels beyond email, a channel is disabled if its settings are empty
+type ChannelsConfig struct {
+	TelegramBotToken string `envconfig:"TELEGRAM_BOT_TOKEN"`
+	TelegramApiUrl   string `envconfig:"TELEGRAM_API_URL"`
+	PushServerKey    string `envconfig:"PUSH_SERVER_KEY"`
+	PushUrl          string `envconfig:"PUSH_URL"`
+	// WebhookTimeout limits time of webhook and other channel requests
+	WebhookTimeout time.Duration `default:"10s" envconfig:"WEBHOOK_TIMEOUT"`
+}
+
+// StreamConfig configures real-time updates of connected users
+type StreamConfig struct {
+	// StreamBroker passes updates between server instances, it is memory for a single instance
+	// or mongo for several ones, mongo broker uses change streams and requires replica set
+	StreamBroker string `default:"memory" envconfig:"STREAM_BROKER"`
+	// StreamPollInterval is an interval of checks of balances and transactions of wallets
+	StreamPollInterval time.Duration `default:"30s" envconfig:"STREAM_POLL_INTERVAL"`
+	// WatchChains runs the watcher of wallets which publishes deposits and confirmations, it should be
+	// enabled on one server instance only, otherwise events are published by every instance
+	WatchChains bool `default:"true" envconfig:"WATCH_CHAINS"`
+}
+
+// Networks of chains
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+	NetworkRegtest = "regtest"
+	NetworkSepolia = "sepolia"
+)
+
+// ChainsConfig configures nodes of blockchains, e.g. BTC_RPC_URL or ETH_NETWORK
+type ChainsConfig struct {
+	BTC ChainConfig `envconfig:"BTC"`
+	ETH ChainConfig `envconfig:"ETH"`
+}
+
+// ChainConfig configures node of a chain, wallets of disabled chain are not created
+type ChainConfig struct {
+	Enabled bool `default:"true" envconfig:"ENABLED"`
+	// RpcURL is local node of the network by default
+	RpcURL string `envconfig:"RPC_URL"`
+	// RpcAuth is none, basic, cookie or bearer
+	RpcAuth       string `default:"none" envconfig:"RPC_AUTH"`
+	RpcUser       string `envconfig:"RPC_USER"`
+	RpcPassword   string `envconfig:"RPC_PASSWORD"`
+	RpcCookieFile string `envconfig:"RPC_COOKIE_FILE"`
+	RpcToken      string `envconfig:"RPC_TOKEN"`
+	// Network is mainnet, testnet or regtest for BTC and mainnet or sepolia for ETH, it is a test network by default
+	Network    string        `envconfig:"NETWORK"`
+	RpcTimeout time.Duration `default:"30s" envconfig:"RPC_TIMEOUT"`
+}
+
+// defaultRpcPorts are default RPC ports of nodes of networks
+var defaultRpcPorts = map[string]map[string]string{
+	"BTC": {NetworkMainnet: "8332", NetworkTestnet: "18332", NetworkRegtest: "18443"},
+	"ETH": {NetworkMainnet: "8545", NetworkSepolia: "8545"},
+}
+
+func (cfg *ChainsConfig) setDefaults() error {
+	if err := cfg.BTC.setDefaults("BTC", NetworkTestnet); err != nil {
+		return err
+	}
+	return cfg.ETH.setDefaults("ETH", NetworkSepolia)
+}
+
+func (cfg *ChainConfig) setDefaults(chain, network string) error {
+	if cfg.Network == "" {
+		cfg.Network = network
+	}
+
+	port, ok := defaultRpcPorts[chain][cfg.Network]
+	if !ok {
+		return fmt.Errorf("unknown %s network %q", chain, cfg.Network)
+	}
+
+	if cfg.RpcURL == "" {
+		cfg.RpcURL = "http://127.0.0.1:" + port
+	}
+	return nil
+}
+
+// TracingConfig configures export of OpenTelemetry traces
+type TracingConfig struct {
+	// TracingExporter is none, stdout for local development or otlp
+	TracingExporter string `default:"none" envconfig:"TRACING_EXPORTER"`
+	// TracingEndpoint is base URL of OTLP/HTTP receiver, e.g. OpenTelemetry collector
+	TracingEndpoint    string `default:"http://127.0.0.1:4318" envconfig:"TRACING_ENDPOINT"`
+	TracingServiceName string `default:"nnw" envconfig:"TRACING_SERVICE_NAME"`
+	// TracingSampleRatio is a share of exported traces of requests without trace context of caller
+	TracingSampleRatio float64 `default:"1" envconfig:"TRACING_SAMPLE_RATIO"`
 }
 
 type CorsOrigin struct {
@@ -66,6 +199,33 @@ func Get() (*Config, error) {
 			return
 		}
 
+		switch cfg.Storage {
+		case StorageMongo:
+			if err = cfg.MongoConfig.validate(); err != nil {
+				return
+			}
+		case StorageMemory:
+		default:
+			err = fmt.Errorf("unknown storage %q, should be %q or %q", cfg.Storage, StorageMongo, StorageMemory)
+			return
+		}
+
+		if err = cfg.ChainsConfig.setDefaults(); err != nil {
+			return
+		}
+
+		switch cfg.StreamBroker {
+		case StorageMemory:
+		case StorageMongo:
+			if cfg.Storage != StorageMongo {
+				err = errors.New("mongo stream broker requires mongo storage")
+				return
+			}
+		default:
+			err = fmt.Errorf("unknown stream broker %q, should be %q or %q", cfg.StreamBroker, StorageMongo, StorageMemory)
+			return
+		}
+
 		config = &cfg
 	})
 
@@ -4,6 +4,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestInit(t *testing.T) {
@@ -85,6 +86,11 @@ func TestInit(t *testing.T) {
 				Environment: "development",
 				EmailFrom:   "example@example.com",
 				TwoFAIssuer: "Example",
+				Storage:     StorageMongo,
+
+				MigrateOnStart:     true,
+				ShutdownTimeout:    15 * time.Second,
+				HealthCheckTimeout: 5 * time.Second,
 
 				Secrets: Secrets{
 					JwtSecretKey: "123qwerty",
@@ -104,6 +110,42 @@ func TestInit(t *testing.T) {
 					SmtpPort:        25,
 					SmtpUserApiKey:  "key",
 					SmtpPasswordKey: "password",
+					SmtpTimeout:     30 * time.Second,
+					SmtpIdleTimeout: time.Minute,
+				},
+
+				ChannelsConfig: ChannelsConfig{
+					WebhookTimeout: 10 * time.Second,
+				},
+
+				StreamConfig: StreamConfig{
+					StreamBroker:       StorageMemory,
+					StreamPollInterval: 30 * time.Second,
+					WatchChains:        true,
+				},
+
+				ChainsConfig: ChainsConfig{
+					BTC: ChainConfig{
+						Enabled:    true,
+						RpcURL:     "http://127.0.0.1:18332",
+						RpcAuth:    "none",
+						Network:    NetworkTestnet,
+						RpcTimeout: 30 * time.Second,
+					},
+					ETH: ChainConfig{
+						Enabled:    true,
+						RpcURL:     "http://127.0.0.1:8545",
+						RpcAuth:    "none",
+						Network:    NetworkSepolia,
+						RpcTimeout: 30 * time.Second,
+					},
+				},
+
+				TracingConfig: TracingConfig{
+					TracingExporter:    "none",
+					TracingEndpoint:    "http://127.0.0.1:4318",
+					TracingServiceName: "nnw",
+					TracingSampleRatio: 1,
 				},
 
 				CorsOrigin: CorsOrigin{
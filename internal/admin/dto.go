@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"nnw_s/pkg/validation"
+	"time"
+)
+
+func Validate(dto interface{}) error {
+	validate := validation.New()
+	if err := validate.Struct(dto); err != nil {
+		return validation.Error(ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+type GetOutboxDTO struct {
+	Status string `json:"status" validate:"required,oneof=pending sent dead"`
+}
+
+type RequeueOutboxDTO struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// ReplayEventsDTO selects events of the log to replay, every subscriber gets them if subscriber is empty
+type ReplayEventsDTO struct {
+	Subscriber string    `json:"subscriber"`
+	Since      time.Time `json:"since" validate:"required"`
+	Limit      int       `json:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+type ReplayedEventsDTO struct {
+	Replayed int `json:"replayed"`
+}
+
+// GetClientWebhooksDTO selects webhook endpoints of API client, they get events of every user
+type GetClientWebhooksDTO struct {
+	Client string `json:"client" validate:"required,max=64"`
+}
+
+type CreateClientWebhookDTO struct {
+	Client      string   `json:"client" validate:"required,max=64"`
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	Description string   `json:"description" validate:"max=128"`
+	Events      []string `json:"events" validate:"max=20"`
+}
+
+// ClientWebhookDTO selects webhook endpoint of API client
+type ClientWebhookDTO struct {
+	Client     string `json:"client" validate:"required,max=64"`
+	EndpointID string `json:"endpoint_id" validate:"required"`
+}
@@ -0,0 +1,16 @@
+package admin
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+)
+
+const (
+	StatusInvalidRequest errors.Status = "invalid_request"
+	StatusInvalidAPIKey  errors.Status = "invalid_admin_key"
+)
+
+var (
+	ErrInvalidRequest = errors.New(codes.BadRequest, StatusInvalidRequest)
+	ErrInvalidAPIKey  = errors.New(codes.Unauthorized, StatusInvalidAPIKey)
+)
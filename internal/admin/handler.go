@@ -0,0 +1,228 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"nnw_s/internal/webhooks"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/notificator"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderAdminKey is a header with admin API key
+const HeaderAdminKey = "X-Admin-Key"
+
+type Handler struct {
+	notificatorSvc notificator.Service
+	webhooksSvc    webhooks.Service
+	eventBus       *eventbus.Bus
+	apiKey         string
+}
+
+func NewHandler(notificatorSvc notificator.Service, webhooksSvc webhooks.Service, eventBus *eventbus.Bus, apiKey string) *Handler {
+	return &Handler{
+		notificatorSvc: notificatorSvc,
+		webhooksSvc:    webhooksSvc,
+		eventBus:       eventBus,
+		apiKey:         apiKey,
+	}
+}
+
+// SetupRoutes registers admin routes, they are not available at all if admin API key is not set
+func (h *Handler) SetupRoutes(router *echo.Echo) {
+	if h.apiKey == "" {
+		return
+	}
+
+	admin := router.Group("/api/v1/admin", h.checkAPIKey)
+
+	// Outbox
+	admin.POST("/get-outbox", h.getOutbox)
+	admin.POST("/requeue-outbox", h.requeueOutbox)
+
+	// Event log
+	admin.POST("/replay-events", h.replayEvents)
+
+	// Webhooks of API clients
+	admin.POST("/get-client-webhooks", h.getClientWebhooks)
+	admin.POST("/create-client-webhook", h.createClientWebhook)
+	admin.POST("/delete-client-webhook", h.deleteClientWebhook)
+	admin.POST("/get-client-webhook-deliveries", h.getClientWebhookDeliveries)
+	admin.POST("/send-client-test-webhook", h.sendClientTestWebhook)
+}
+
+func (h *Handler) checkAPIKey(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key := ctx.Request().Header.Get(HeaderAdminKey)
+		if subtle.ConstantTimeCompare([]byte(key), []byte(h.apiKey)) != 1 {
+			return ErrInvalidAPIKey
+		}
+		return next(ctx)
+	}
+}
+
+func (h *Handler) getOutbox(ctx echo.Context) error {
+	var dto GetOutboxDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	messages, err := h.notificatorSvc.GetMessages(ctx.Request().Context(), notificator.MessageStatus(dto.Status))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, messages)
+}
+
+func (h *Handler) requeueOutbox(ctx echo.Context) error {
+	var dto RequeueOutboxDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	message, err := h.notificatorSvc.RequeueMessage(ctx.Request().Context(), dto.ID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, message)
+}
+
+func (h *Handler) replayEvents(ctx echo.Context) error {
+	var dto ReplayEventsDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	replayed, err := h.eventBus.Replay(ctx.Request().Context(), &eventbus.ReplayFilter{
+		Subscriber: dto.Subscriber,
+		Since:      dto.Since,
+		Limit:      dto.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, &ReplayedEventsDTO{Replayed: replayed})
+}
+
+func (h *Handler) getClientWebhooks(ctx echo.Context) error {
+	var dto GetClientWebhooksDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	owner := &webhooks.Owner{Client: dto.Client}
+	endpoints, err := h.webhooksSvc.GetEndpoints(ctx.Request().Context(), owner)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, endpoints)
+}
+
+func (h *Handler) createClientWebhook(ctx echo.Context) error {
+	var dto CreateClientWebhookDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	owner := &webhooks.Owner{Client: dto.Client}
+	endpoint, err := h.webhooksSvc.CreateEndpoint(ctx.Request().Context(), owner, &webhooks.CreateEndpointDTO{
+		URL:         dto.URL,
+		Description: dto.Description,
+		Events:      dto.Events,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, endpoint)
+}
+
+func (h *Handler) deleteClientWebhook(ctx echo.Context) error {
+	var dto ClientWebhookDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	owner := &webhooks.Owner{Client: dto.Client}
+	if err := h.webhooksSvc.DeleteEndpoint(ctx.Request().Context(), owner, dto.EndpointID); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (h *Handler) getClientWebhookDeliveries(ctx echo.Context) error {
+	var dto ClientWebhookDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	owner := &webhooks.Owner{Client: dto.Client}
+	deliveries, err := h.webhooksSvc.GetDeliveries(ctx.Request().Context(), owner, dto.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (h *Handler) sendClientTestWebhook(ctx echo.Context) error {
+	var dto ClientWebhookDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	owner := &webhooks.Owner{Client: dto.Client}
+	delivery, err := h.webhooksSvc.SendTestEvent(ctx.Request().Context(), owner, dto.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, delivery)
+}
@@ -1,8 +1,8 @@
 package auth
 
 import (
-	"nnw_s/pkg/errors"
 	"nnw_s/pkg/helpers"
+	"nnw_s/pkg/validation"
 	"time"
 	"unicode"
 
@@ -12,7 +12,7 @@ import (
 const passwordMinLength = 8
 
 func Validate(dto interface{}, shift int) error {
-	validate := validator.New()
+	validate := validation.New()
 	_ = validate.RegisterValidation("password", func(fl validator.FieldLevel) bool {
 		password := fl.Field().String()
 
@@ -44,15 +44,7 @@ func Validate(dto interface{}, shift int) error {
 	})
 
 	if err := validate.Struct(dto); err != nil {
-		if _, ok := err.(*validator.InvalidValidationError); ok {
-			return errors.WithMessage(ErrInvalidRequest, err.Error())
-		}
-
-		validationErr := ErrInvalidRequest
-		for _, err := range err.(validator.ValidationErrors) {
-			validationErr = errors.WithMessage(validationErr, err.Error())
-		}
-		return validationErr
+		return validation.Error(ErrInvalidRequest, err)
 	}
 	return nil
 }
@@ -60,6 +52,10 @@ func Validate(dto interface{}, shift int) error {
 type RegisterUserDTO struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,password"`
+
+	// AcceptLanguage is taken from the request header, emails are sent in this language
+	// unless user has a stored locale
+	AcceptLanguage string `json:"-"`
 }
 
 type VerifyUserDTO struct {
@@ -69,6 +65,8 @@ type VerifyUserDTO struct {
 
 type ResendActivationEmailDTO struct {
 	Email string `json:"email" validate:"required,email"`
+
+	AcceptLanguage string `json:"-"`
 }
 
 type SetupTwoFaDTO struct {
@@ -88,6 +86,10 @@ type LoginDTO struct {
 type LoginCodeDTO struct {
 	Email string `json:"email" validate:"required,email"`
 	Code  string `json:"code" validate:"required,len=6"`
+
+	// IP and UserAgent are taken from the request, user is alerted about the login with them
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 type LogoutCodeDTO struct {
@@ -105,10 +107,14 @@ type ValidateTokenDTO struct {
 
 type ResetPasswordDTO struct {
 	Email string `json:"email" validate:"required,email"`
+
+	AcceptLanguage string `json:"-"`
 }
 
 type ResendResetPasswordDTO struct {
 	Email string `json:"email" validate:"required,email"`
+
+	AcceptLanguage string `json:"-"`
 }
 
 type ResetPasswordCodedDTO struct {
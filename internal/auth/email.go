@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"nnw_s/internal/user"
+	"nnw_s/pkg/notificator"
+)
+
+// setRecipientProfile greets the user by display name, shows the user's anti-phishing phrase and puts
+// the user's locale before other preferred languages of the email, e.g. Accept-Language of the request.
+func setRecipientProfile(email *notificator.Email, userDTO *user.DTO) {
+	if userDTO == nil {
+		return
+	}
+	email.Data.SetAntiPhishingPhrase(userDTO.AntiPhishingPhrase)
+
+	if userDTO.Profile == nil {
+		return
+	}
+	if userDTO.Profile.DisplayName != "" {
+		email.Data.SetName(userDTO.Profile.DisplayName)
+	}
+	if userDTO.Profile.Locale != "" {
+		email.Languages = append([]string{userDTO.Profile.Locale}, email.Languages...)
+	}
+}
+
+// verificationEmailKey is an idempotency key of verification email, email with the same code is queued once
+func verificationEmailKey(email, code string) string {
+	return "verification:" + email + ":" + code
+}
+
+// resetPasswordEmailKey is an idempotency key of reset password email, email with the same code is queued once
+func resetPasswordEmailKey(email, code string) string {
+	return "reset_password:" + email + ":" + code
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"nnw_s/internal/user"
+	"nnw_s/pkg/notificator"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRecipientProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *user.ProfileDTO
+		expect  []string
+	}{
+		{
+			name:    "should use request languages of user without profile",
+			profile: nil,
+			expect:  []string{"ru-RU,ru;q=0.9"},
+		},
+		{
+			name:    "should use request languages of user who hasn't chosen locale",
+			profile: user.MapProfileToDTO(user.NewProfile()),
+			expect:  []string{"ru-RU,ru;q=0.9"},
+		},
+		{
+			name:    "should put chosen locale before request languages",
+			profile: &user.ProfileDTO{Locale: "en"},
+			expect:  []string{"en", "ru-RU,ru;q=0.9"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			email := &notificator.Email{Data: &notificator.VerifyEmailData{}, Languages: []string{"ru-RU,ru;q=0.9"}}
+			setRecipientProfile(email, &user.DTO{Profile: test.profile})
+			assert.Equal(t, test.expect, email.Languages)
+		})
+	}
+}
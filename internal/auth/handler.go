@@ -8,6 +8,9 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// headerAcceptLanguage is passed to services which send emails, so emails are sent in the user's language
+const headerAcceptLanguage = "Accept-Language"
+
 type Handler struct {
 	registrationSvc  RegistrationService
 	loginSvc         LoginService
@@ -59,14 +62,15 @@ func (h *Handler) registerUser(ctx echo.Context) error {
 	var dto RegisterUserDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
+	dto.AcceptLanguage = ctx.Request().Header.Get(headerAcceptLanguage)
 	if err := h.registrationSvc.RegisterUser(ctx.Request().Context(), &dto); err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(200)
@@ -76,15 +80,15 @@ func (h *Handler) verifyUser(ctx echo.Context) error {
 	var dto VerifyUserDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	if err := h.registrationSvc.VerifyUser(ctx.Request().Context(), &dto); err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(200)
@@ -94,15 +98,16 @@ func (h *Handler) resendVerificationRegistrationEmail(ctx echo.Context) error {
 	var dto ResendActivationEmailDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
+	dto.AcceptLanguage = ctx.Request().Header.Get(headerAcceptLanguage)
 	if err := h.registrationSvc.ResendVerificationEmail(ctx.Request().Context(), &dto); err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(200)
@@ -113,22 +118,22 @@ func (h *Handler) setupTwoFA(ctx echo.Context) error {
 
 	if err := ctx.Bind(&dto); err != nil {
 
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	buf, err := h.registrationSvc.SetupTwoFA(ctx.Request().Context(), &dto)
 	if err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	// write image bytes
 	ctx.Response().Header().Set("Content-Type", "image/png")
 	if _, err = ctx.Response().Write(buf); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, errors.NewInternal(err.Error()))
+		return errors.NewInternal(err.Error())
 	}
 	return ctx.NoContent(http.StatusOK)
 }
@@ -137,15 +142,15 @@ func (h *Handler) activateUser(ctx echo.Context) error {
 	var dto ActivateUserDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	if err := h.registrationSvc.ActivateUser(ctx.Request().Context(), &dto); err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(http.StatusCreated)
@@ -155,16 +160,16 @@ func (h *Handler) login(ctx echo.Context) error {
 	var dto LoginDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	err := h.loginSvc.Login(ctx.Request().Context(), &dto)
 	if err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(http.StatusNoContent)
@@ -174,16 +179,18 @@ func (h *Handler) loginCode(ctx echo.Context) error {
 	var dto LoginCodeDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
+	dto.IP = ctx.RealIP()
+	dto.UserAgent = ctx.Request().UserAgent()
 	tokenDTO, err := h.loginSvc.CheckCode(ctx.Request().Context(), &dto)
 	if err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, tokenDTO)
@@ -193,16 +200,16 @@ func (h *Handler) validateToken(ctx echo.Context) error {
 	var dto ValidateTokenDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	_, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Token)
 	if err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(http.StatusOK)
@@ -212,16 +219,16 @@ func (h *Handler) logout(ctx echo.Context) error {
 	var dto LogoutCodeDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	err := h.jwtSvc.DeleteJWT(ctx.Request().Context(), dto.Token)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	return ctx.NoContent(http.StatusOK)
@@ -231,17 +238,18 @@ func (h *Handler) resetPassword(ctx echo.Context) error {
 	var dto ResetPasswordDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
-	//
+	dto.AcceptLanguage = ctx.Request().Header.Get(headerAcceptLanguage)
+
 	err := h.resetPasswordSvc.ResetPassword(ctx.Request().Context(), &dto)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	return ctx.NoContent(http.StatusOK)
@@ -251,15 +259,16 @@ func (h *Handler) resendResetPasswordEmail(ctx echo.Context) error {
 	var dto ResendResetPasswordDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
+	dto.AcceptLanguage = ctx.Request().Header.Get(headerAcceptLanguage)
 	if err := h.resetPasswordSvc.ResendResetPasswordEmail(ctx.Request().Context(), &dto); err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(200)
@@ -269,15 +278,15 @@ func (h *Handler) resetPasswordCode(ctx echo.Context) error {
 	var dto ResetPasswordCodedDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	if err := h.resetPasswordSvc.ResetPasswordCode(ctx.Request().Context(), &dto); err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(200)
@@ -287,15 +296,15 @@ func (h *Handler) setupNewPassword(ctx echo.Context) error {
 	var dto SetupNewPasswordDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	if err := h.resetPasswordSvc.SetupNewPassword(ctx.Request().Context(), &dto); err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.NoContent(200)
@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"context"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/errors"
+	"sync"
+)
+
+type memoryRepository struct {
+	mu     sync.Mutex
+	tokens map[string]JWT
+	clock  clock.Clock
+}
+
+// NewMemoryRepository returns thread-safe repository which keeps tokens in memory,
+// tokens expire in the same time as in mongo
+func NewMemoryRepository(clk clock.Clock) (Repository, error) {
+	if clk == nil {
+		return nil, errors.NewInternal("invalid clock")
+	}
+	return &memoryRepository{tokens: make(map[string]JWT), clock: clk}, nil
+}
+
+func (repo *memoryRepository) GetJWT(_ context.Context, token string) (*JWT, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	jwtData, ok := repo.tokens[token]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if repo.isExpired(&jwtData) {
+		delete(repo.tokens, token)
+		return nil, ErrNotFound
+	}
+	return &jwtData, nil
+}
+
+func (repo *memoryRepository) SaveJWT(_ context.Context, jwt *JWT) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.removeExpired()
+
+	for _, t := range repo.tokens {
+		if t.ID == jwt.ID {
+			return "", ErrAlreadyExists
+		}
+	}
+
+	repo.tokens[jwt.Jwt] = *jwt
+	return jwt.ID.Hex(), nil
+}
+
+func (repo *memoryRepository) DeleteJWT(_ context.Context, token string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.tokens, token)
+	return nil
+}
+
+func (repo *memoryRepository) isExpired(jwt *JWT) bool {
+	return !repo.clock.Now().Before(jwt.CreatedAt.Add(jwtExpiry))
+}
+
+func (repo *memoryRepository) removeExpired() {
+	for token, jwtData := range repo.tokens {
+		if repo.isExpired(&jwtData) {
+			delete(repo.tokens, token)
+		}
+	}
+}
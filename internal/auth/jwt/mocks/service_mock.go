@@ -36,18 +36,18 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // CreateJWT mocks base method.
-func (m *MockService) CreateJWT(ctx context.Context, email string) (*jwt.DTO, error) {
+func (m *MockService) CreateJWT(ctx context.Context, userID, email string) (*jwt.DTO, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateJWT", ctx, email)
+	ret := m.ctrl.Call(m, "CreateJWT", ctx, userID, email)
 	ret0, _ := ret[0].(*jwt.DTO)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateJWT indicates an expected call of CreateJWT.
-func (mr *MockServiceMockRecorder) CreateJWT(ctx, email interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CreateJWT(ctx, userID, email interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJWT", reflect.TypeOf((*MockService)(nil).CreateJWT), ctx, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJWT", reflect.TypeOf((*MockService)(nil).CreateJWT), ctx, userID, email)
 }
 
 // DeleteJWT mocks base method.
@@ -6,6 +6,8 @@ import (
 )
 
 type Payload struct {
+	// UserID is ObjectID of the user, it identifies the user in logs instead of email
+	UserID    string    `json:"user_id"`
 	Email     string    `json:"email"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
@@ -3,10 +3,10 @@ package jwt
 import (
 	"context"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 //go:generate mockgen -source=repository.go -destination=mocks/repository_mock.go
@@ -27,6 +27,11 @@ func NewRepository(db *mongo.Database) (Repository, error) {
 	return &repository{db: db}, nil
 }
 
+// CreateIndexes creates TTL index of tokens, so expired tokens are removed
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	return migrations.SetTTL(ctx, db.Collection("jwt"), "created_at", jwtExpiry)
+}
+
 func (repo *repository) GetJWT(ctx context.Context, token string) (*JWT, error) {
 	var jwtData JWT
 	if err := repo.db.Collection("jwt").FindOne(ctx, bson.M{"jwt": token}).Decode(&jwtData); err != nil {
@@ -39,17 +44,7 @@ func (repo *repository) GetJWT(ctx context.Context, token string) (*JWT, error)
 }
 
 func (repo *repository) SaveJWT(ctx context.Context, jwt *JWT) (string, error) {
-	mod := mongo.IndexModel{
-		Keys:    bson.M{"created_at": 1}, // index in ascending order or -1 for descending order
-		Options: options.Index().SetExpireAfterSeconds(600),
-	}
-
-	_, err := repo.db.Collection("jwt").Indexes().CreateOne(ctx, mod)
-	if err != nil {
-		return "", errors.NewInternal(err.Error())
-	}
-
-	_, err = repo.db.Collection("jwt").InsertOne(ctx, jwt)
+	_, err := repo.db.Collection("jwt").InsertOne(ctx, jwt)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return "", ErrAlreadyExists
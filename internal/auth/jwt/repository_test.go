@@ -0,0 +1,99 @@
+package jwt_test
+
+import (
+	"context"
+	"nnw_s/internal/auth/jwt"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/mongodb/mongotest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) jwt.Repository {
+		repo, err := jwt.NewMemoryRepository(clock.New())
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMongoRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) jwt.Repository {
+		repo, err := jwt.NewRepository(mongotest.DB(t))
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMemoryRepositoryExpiry(t *testing.T) {
+	ctx := context.Background()
+	token := jwt.NewJWT("token")
+
+	clk := clock.NewMock(token.CreatedAt)
+	repo, err := jwt.NewMemoryRepository(clk)
+	require.Nil(t, err)
+
+	_, err = repo.SaveJWT(ctx, token)
+	require.Nil(t, err)
+
+	clk.Add(10*time.Minute - time.Second)
+	_, err = repo.GetJWT(ctx, token.Jwt)
+	assert.Nil(t, err)
+
+	clk.Add(time.Second)
+	_, err = repo.GetJWT(ctx, token.Jwt)
+	assert.Equal(t, jwt.ErrNotFound, err)
+}
+
+// testRepository is a conformance suite which every jwt.Repository implementation should pass
+func testRepository(t *testing.T, newRepo func(t *testing.T) jwt.Repository) {
+	ctx := context.Background()
+
+	t.Run("should save and get token", func(t *testing.T) {
+		repo := newRepo(t)
+		token := jwt.NewJWT("token")
+
+		id, err := repo.SaveJWT(ctx, token)
+		require.Nil(t, err)
+		assert.Equal(t, token.ID.Hex(), id)
+
+		loaded, err := repo.GetJWT(ctx, token.Jwt)
+		require.Nil(t, err)
+		assert.Equal(t, token.ID, loaded.ID)
+		assert.Equal(t, token.Jwt, loaded.Jwt)
+	})
+
+	t.Run("should return 'not found' error", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetJWT(ctx, "not_existent_token")
+		assert.Equal(t, jwt.ErrNotFound, err)
+	})
+
+	t.Run("should return 'already exists' error", func(t *testing.T) {
+		repo := newRepo(t)
+		token := jwt.NewJWT("token")
+
+		_, err := repo.SaveJWT(ctx, token)
+		require.Nil(t, err)
+
+		_, err = repo.SaveJWT(ctx, token)
+		assert.Equal(t, jwt.ErrAlreadyExists, err)
+	})
+
+	t.Run("should delete token", func(t *testing.T) {
+		repo := newRepo(t)
+		token := jwt.NewJWT("token")
+
+		_, err := repo.SaveJWT(ctx, token)
+		require.Nil(t, err)
+
+		require.Nil(t, repo.DeleteJWT(ctx, token.Jwt))
+
+		_, err = repo.GetJWT(ctx, token.Jwt)
+		assert.Equal(t, jwt.ErrNotFound, err)
+	})
+}
@@ -3,6 +3,7 @@ package jwt
 import (
 	"context"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/logging"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -12,7 +13,7 @@ const jwtExpiry = time.Second * 600
 
 //go:generate mockgen -source=service.go -destination=mocks/service_mock.go
 type Service interface {
-	CreateJWT(ctx context.Context, email string) (*DTO, error)
+	CreateJWT(ctx context.Context, userID, email string) (*DTO, error)
 	VerifyJWT(ctx context.Context, id string) (*Payload, error)
 	DeleteJWT(ctx context.Context, token string) error
 }
@@ -32,9 +33,10 @@ func NewService(repo Repository, secretKey string) (Service, error) {
 	return &service{repo: repo, secretKey: secretKey}, nil
 }
 
-func (svc *service) CreateJWT(ctx context.Context, email string) (*DTO, error) {
+func (svc *service) CreateJWT(ctx context.Context, userID, email string) (*DTO, error) {
 	// create JWT
 	payload := &Payload{
+		UserID:    userID,
 		Email:     email,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(jwtExpiry),
@@ -90,6 +92,11 @@ func (svc *service) VerifyJWT(ctx context.Context, token string) (*Payload, erro
 	if !ok {
 		return nil, ErrTokenDoesNotValid
 	}
+
+	// the rest of logs of the request are of the user, tokens issued before user id was added have none
+	if payload.UserID != "" {
+		logging.SetUserID(ctx, payload.UserID)
+	}
 	return payload, nil
 }
 
@@ -5,10 +5,16 @@ import (
 	"nnw_s/internal/auth/jwt"
 	"nnw_s/internal/auth/twofa"
 	"nnw_s/internal/auth/verification"
+	"nnw_s/internal/events"
 	"nnw_s/internal/user"
 	"nnw_s/internal/user/credentials"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/metrics"
 	"nnw_s/pkg/notificator"
+	"nnw_s/pkg/tracing"
+	"nnw_s/pkg/uow"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -26,6 +32,7 @@ type loginSvc struct {
 	twoFaSvc       twofa.Service
 	jwtSvc         jwt.Service
 	credentialsSvc credentials.Service
+	eventBus       eventbus.Publisher
 
 	log *logrus.Logger
 }
@@ -37,6 +44,9 @@ type ServiceDeps struct {
 	TwoFAService        twofa.Service
 	JWTService          jwt.Service
 	CredentialsService  credentials.Service
+	EventBus            eventbus.Publisher
+	// UnitOfWork makes multi-step changes of users atomic, it's required by registration and reset password services
+	UnitOfWork uow.UnitOfWork
 }
 
 func NewLoginService(log *logrus.Logger, deps *ServiceDeps) (LoginService, error) {
@@ -61,6 +71,9 @@ func NewLoginService(log *logrus.Logger, deps *ServiceDeps) (LoginService, error
 	if deps.CredentialsService == nil {
 		return nil, errors.NewInternal("invalid credentials service")
 	}
+	if deps.EventBus == nil {
+		return nil, errors.NewInternal("invalid event bus")
+	}
 	if log == nil {
 		return nil, errors.NewInternal("invalid logger")
 	}
@@ -69,11 +82,22 @@ func NewLoginService(log *logrus.Logger, deps *ServiceDeps) (LoginService, error
 		twoFaSvc:       deps.TwoFAService,
 		credentialsSvc: deps.CredentialsService,
 		jwtSvc:         deps.JWTService,
+		eventBus:       deps.EventBus,
 		log:            log,
 	}, nil
 }
 
-func (svc *loginSvc) Login(ctx context.Context, dto *LoginDTO) error {
+func (svc *loginSvc) Login(ctx context.Context, dto *LoginDTO) (err error) {
+	ctx, span := tracing.Start(ctx, "auth.Login")
+	defer span.End()
+
+	// successful logins are counted by user_logged_in events
+	defer func() {
+		if err != nil {
+			metrics.LoginFailures.Inc()
+		}
+	}()
+
 	// find user
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -103,6 +127,9 @@ func (svc *loginSvc) Login(ctx context.Context, dto *LoginDTO) error {
 }
 
 func (svc *loginSvc) CheckCode(ctx context.Context, dto *LoginCodeDTO) (*TokenDTO, error) {
+	ctx, span := tracing.Start(ctx, "auth.CheckCode")
+	defer span.End()
+
 	// find user
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -126,10 +153,12 @@ func (svc *loginSvc) CheckCode(ctx context.Context, dto *LoginCodeDTO) (*TokenDT
 	}
 
 	// create JWT
-	jwtTokenDTO, err := svc.jwtSvc.CreateJWT(ctx, dto.Email)
+	jwtTokenDTO, err := svc.jwtSvc.CreateJWT(ctx, userDTO.ID, dto.Email)
 	if err != nil {
 		return nil, errors.WithMessage(ErrUnauthorized, err.Error())
 	}
+
+	svc.eventBus.Publish(ctx, &events.UserLoggedIn{Email: dto.Email, IP: dto.IP, UserAgent: dto.UserAgent, Time: time.Now()})
 	return &TokenDTO{Token: jwtTokenDTO.Token, ExpireAt: jwtTokenDTO.ExpireAt}, nil
 }
 
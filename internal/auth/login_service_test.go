@@ -10,13 +10,15 @@ import (
 	"nnw_s/internal/auth/twofa"
 	mock_twofa "nnw_s/internal/auth/twofa/mocks"
 	mock_verification "nnw_s/internal/auth/verification/mocks"
+	"nnw_s/internal/events"
 	"nnw_s/internal/user"
 	"nnw_s/internal/user/credentials"
 	mock_credentials "nnw_s/internal/user/credentials/mocks"
 	mock_user "nnw_s/internal/user/mocks"
 	"nnw_s/pkg/errors"
+	mock_eventbus "nnw_s/pkg/eventbus/mocks"
 	mock_notificator "nnw_s/pkg/notificator/mocks"
-	"nnw_s/pkg/wallet"
+	"nnw_s/pkg/tracing/tracingtest"
 	"testing"
 	"time"
 )
@@ -41,6 +43,7 @@ func TestNewLoginService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
 			},
 			expect: func(t *testing.T, service LoginService, err error) {
 				assert.NotNil(t, service)
@@ -77,6 +80,7 @@ func TestNewLoginService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
 			},
 			expect: func(t *testing.T, service LoginService, err error) {
 				assert.Nil(t, service)
@@ -94,6 +98,7 @@ func TestNewLoginService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
 			},
 			expect: func(t *testing.T, service LoginService, err error) {
 				assert.Nil(t, service)
@@ -111,6 +116,7 @@ func TestNewLoginService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
 			},
 			expect: func(t *testing.T, service LoginService, err error) {
 				assert.Nil(t, service)
@@ -128,6 +134,7 @@ func TestNewLoginService(t *testing.T) {
 				TwoFAService:        nil,
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
 			},
 			expect: func(t *testing.T, service LoginService, err error) {
 				assert.Nil(t, service)
@@ -145,6 +152,7 @@ func TestNewLoginService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          nil,
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
 			},
 			expect: func(t *testing.T, service LoginService, err error) {
 				assert.Nil(t, service)
@@ -162,6 +170,7 @@ func TestNewLoginService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  nil,
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
 			},
 			expect: func(t *testing.T, service LoginService, err error) {
 				assert.Nil(t, service)
@@ -169,6 +178,23 @@ func TestNewLoginService(t *testing.T) {
 				assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid credentials service")
 			},
 		},
+		{
+			name: "should return invalid event bus",
+			log:  logrus.New(),
+			deps: &ServiceDeps{
+				UserService:         mock_user.NewMockService(controller),
+				NotificatorService:  mock_notificator.NewMockService(controller),
+				VerificationService: mock_verification.NewMockService(controller),
+				TwoFAService:        mock_twofa.NewMockService(controller),
+				JWTService:          mock_jwt.NewMockService(controller),
+				CredentialsService:  mock_credentials.NewMockService(controller),
+			},
+			expect: func(t *testing.T, service LoginService, err error) {
+				assert.Nil(t, service)
+				assert.NotNil(t, err)
+				assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid event bus")
+			},
+		},
 		{
 			name: "should return invalid logger",
 			log:  nil,
@@ -179,6 +205,7 @@ func TestNewLoginService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
 			},
 			expect: func(t *testing.T, service LoginService, err error) {
 				assert.Nil(t, service)
@@ -210,6 +237,7 @@ func TestLoginSvc_Login(t *testing.T) {
 		TwoFAService:        mock_twofa.NewMockService(controller),
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mockCredSvc,
+		EventBus:            mock_eventbus.NewMockPublisher(controller),
 	}
 
 	service, _ := NewLoginService(log, deps)
@@ -225,26 +253,18 @@ func TestLoginSvc_Login(t *testing.T) {
 	testCred.SecretOTP = &secretKey
 	credDTO := credentials.MapToDTO(&testCred)
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Verify user
-	testActiveUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testActiveUser, _ := user.NewUser("some@mail.com", &testCred)
 	testActiveUser.SetToActive()
 	testActiveUser.SetToVerified()
 	activeUserDTO := user.MapToDTO(testActiveUser)
 
 	// Disable user
-	testDisableUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testDisableUser, _ := user.NewUser("some@mail.com", &testCred)
 	disableUserDTO := user.MapToDTO(testDisableUser)
 
 	// User with wrong id
-	testWrongUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testWrongUser, _ := user.NewUser("some@mail.com", &testCred)
 	testWrongUser.SetToActive()
 	testWrongUser.SetToVerified()
 	wrongUserDTO := user.MapToDTO(testWrongUser)
@@ -259,11 +279,11 @@ func TestLoginSvc_Login(t *testing.T) {
 	}{
 		{
 			name: "should return status ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &loginUserDTO,
 			setup: func(ctx context.Context, dto *LoginDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(activeUserDTO, nil)
-				mockCredSvc.EXPECT().ValidatePassword(ctx, credDTO, dto.Password).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(activeUserDTO, nil)
+				mockCredSvc.EXPECT().ValidatePassword(tracingtest.FromContext(ctx), credDTO, dto.Password).Return(nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -271,10 +291,10 @@ func TestLoginSvc_Login(t *testing.T) {
 		},
 		{
 			name: "should permission_denied by getUserByEmail",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &loginUserDTO,
 			setup: func(ctx context.Context, dto *LoginDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, ErrPermissionDenied)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, ErrPermissionDenied)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -283,10 +303,10 @@ func TestLoginSvc_Login(t *testing.T) {
 		},
 		{
 			name: "should permission_denied disable user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &loginUserDTO,
 			setup: func(ctx context.Context, dto *LoginDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(disableUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(disableUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -295,11 +315,11 @@ func TestLoginSvc_Login(t *testing.T) {
 		},
 		{
 			name: "should invalid_user_password",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &loginUserDTO,
 			setup: func(ctx context.Context, dto *LoginDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(activeUserDTO, nil)
-				mockCredSvc.EXPECT().ValidatePassword(ctx, credDTO, dto.Password).Return(user.ErrInvalidPassword)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(activeUserDTO, nil)
+				mockCredSvc.EXPECT().ValidatePassword(tracingtest.FromContext(ctx), credDTO, dto.Password).Return(user.ErrInvalidPassword)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -308,10 +328,10 @@ func TestLoginSvc_Login(t *testing.T) {
 		},
 		{
 			name: "should wrong object id",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &loginUserDTO,
 			setup: func(ctx context.Context, dto *LoginDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -337,6 +357,7 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 	mockUserSvc := mock_user.NewMockService(controller)
 	mockTwoFaSvc := mock_twofa.NewMockService(controller)
 	mockJwtSvc := mock_jwt.NewMockService(controller)
+	mockEventBus := mock_eventbus.NewMockPublisher(controller)
 	deps := &ServiceDeps{
 		UserService:         mockUserSvc,
 		NotificatorService:  mock_notificator.NewMockService(controller),
@@ -344,6 +365,7 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 		TwoFAService:        mockTwoFaSvc,
 		JWTService:          mockJwtSvc,
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mockEventBus,
 	}
 
 	service, _ := NewLoginService(log, deps)
@@ -354,26 +376,18 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 	testCred.Password = "==WvZitmZDgzSHgAWvKs"
 	testCred.SecretOTP = &secretKey
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Verify user
-	testActiveUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testActiveUser, _ := user.NewUser("some@mail.com", &testCred)
 	testActiveUser.SetToActive()
 	testActiveUser.SetToVerified()
 	activeUserDTO := user.MapToDTO(testActiveUser)
 
 	// Disable user
-	testDisableUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testDisableUser, _ := user.NewUser("some@mail.com", &testCred)
 	disableUserDTO := user.MapToDTO(testDisableUser)
 
 	// User with wrong id
-	testWrongUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testWrongUser, _ := user.NewUser("some@mail.com", &testCred)
 	testWrongUser.SetToActive()
 	testWrongUser.SetToVerified()
 	wrongUserDTO := user.MapToDTO(testWrongUser)
@@ -382,6 +396,8 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 	var loginCodeDTO LoginCodeDTO
 	loginCodeDTO.Email = "some@mail.com"
 	loginCodeDTO.Code = "241241"
+	loginCodeDTO.IP = "203.0.113.7"
+	loginCodeDTO.UserAgent = "Mozilla/5.0"
 
 	var testJwtDTO jwt.DTO
 	testJwtDTO.ID = "id"
@@ -397,12 +413,19 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 	}{
 		{
 			name:     "should return token",
-			ctx:      context.Background(),
+			ctx:      tracingtest.Context(),
 			loginDto: &loginCodeDTO,
 			setup: func(ctx context.Context, loginDto *LoginCodeDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, loginDto.Email).Return(activeUserDTO, nil)
-				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, loginDto.Code, *testCred.SecretOTP).Return(nil)
-				mockJwtSvc.EXPECT().CreateJWT(ctx, loginDto.Email).Return(&testJwtDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), loginDto.Email).Return(activeUserDTO, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(tracingtest.FromContext(ctx), loginDto.Code, *testCred.SecretOTP).Return(nil)
+				mockJwtSvc.EXPECT().CreateJWT(tracingtest.FromContext(ctx), activeUserDTO.ID, loginDto.Email).Return(&testJwtDTO, nil)
+				mockEventBus.EXPECT().Publish(tracingtest.FromContext(ctx), gomock.AssignableToTypeOf(&events.UserLoggedIn{})).Do(
+					func(ctx context.Context, event *events.UserLoggedIn) {
+						assert.Equal(t, loginDto.Email, event.Email)
+						assert.Equal(t, "203.0.113.7", event.IP)
+						assert.Equal(t, "Mozilla/5.0", event.UserAgent)
+						assert.False(t, event.Time.IsZero())
+					})
 			},
 			expect: func(t *testing.T, dto *TokenDTO, err error) {
 				assert.NotEmpty(t, dto)
@@ -412,10 +435,10 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 		},
 		{
 			name:     "should permission_denied by getUserByEmail",
-			ctx:      context.Background(),
+			ctx:      tracingtest.Context(),
 			loginDto: &loginCodeDTO,
 			setup: func(ctx context.Context, loginDto *LoginCodeDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, loginDto.Email).Return(nil, ErrPermissionDenied)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), loginDto.Email).Return(nil, ErrPermissionDenied)
 			},
 			expect: func(t *testing.T, dto *TokenDTO, err error) {
 				assert.NotNil(t, err)
@@ -424,10 +447,10 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 		},
 		{
 			name:     "should wrong object id",
-			ctx:      context.Background(),
+			ctx:      tracingtest.Context(),
 			loginDto: &loginCodeDTO,
 			setup: func(ctx context.Context, loginDto *LoginCodeDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, loginDto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), loginDto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, dto *TokenDTO, err error) {
 				assert.NotNil(t, err)
@@ -436,10 +459,10 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 		},
 		{
 			name:     "should permission_denied disable user",
-			ctx:      context.Background(),
+			ctx:      tracingtest.Context(),
 			loginDto: &loginCodeDTO,
 			setup: func(ctx context.Context, loginDto *LoginCodeDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, loginDto.Email).Return(disableUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), loginDto.Email).Return(disableUserDTO, nil)
 			},
 			expect: func(t *testing.T, dto *TokenDTO, err error) {
 				assert.NotNil(t, err)
@@ -448,11 +471,11 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 		},
 		{
 			name:     "should invalid twoFa code",
-			ctx:      context.Background(),
+			ctx:      tracingtest.Context(),
 			loginDto: &loginCodeDTO,
 			setup: func(ctx context.Context, loginDto *LoginCodeDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, loginDto.Email).Return(activeUserDTO, nil)
-				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, loginDto.Code, *testCred.SecretOTP).Return(twofa.ErrInvalidTwoFACode)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), loginDto.Email).Return(activeUserDTO, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(tracingtest.FromContext(ctx), loginDto.Code, *testCred.SecretOTP).Return(twofa.ErrInvalidTwoFACode)
 			},
 			expect: func(t *testing.T, dto *TokenDTO, err error) {
 				assert.NotNil(t, err)
@@ -461,12 +484,12 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 		},
 		{
 			name:     "should token invalid",
-			ctx:      context.Background(),
+			ctx:      tracingtest.Context(),
 			loginDto: &loginCodeDTO,
 			setup: func(ctx context.Context, loginDto *LoginCodeDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, loginDto.Email).Return(activeUserDTO, nil)
-				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, loginDto.Code, *testCred.SecretOTP).Return(nil)
-				mockJwtSvc.EXPECT().CreateJWT(ctx, loginDto.Email).Return(nil, jwt.ErrTokenDoesNotValid)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), loginDto.Email).Return(activeUserDTO, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(tracingtest.FromContext(ctx), loginDto.Code, *testCred.SecretOTP).Return(nil)
+				mockJwtSvc.EXPECT().CreateJWT(tracingtest.FromContext(ctx), activeUserDTO.ID, loginDto.Email).Return(nil, jwt.ErrTokenDoesNotValid)
 			},
 			expect: func(t *testing.T, dto *TokenDTO, err error) {
 				assert.NotNil(t, err)
@@ -475,12 +498,12 @@ func TestLoginSvc_CheckCode(t *testing.T) {
 		},
 		{
 			name:     "should token expired",
-			ctx:      context.Background(),
+			ctx:      tracingtest.Context(),
 			loginDto: &loginCodeDTO,
 			setup: func(ctx context.Context, loginDto *LoginCodeDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, loginDto.Email).Return(activeUserDTO, nil)
-				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, loginDto.Code, *testCred.SecretOTP).Return(nil)
-				mockJwtSvc.EXPECT().CreateJWT(ctx, loginDto.Email).Return(nil, jwt.ErrTokenHasBeenExpired)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), loginDto.Email).Return(activeUserDTO, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(tracingtest.FromContext(ctx), loginDto.Code, *testCred.SecretOTP).Return(nil)
+				mockJwtSvc.EXPECT().CreateJWT(tracingtest.FromContext(ctx), activeUserDTO.ID, loginDto.Email).Return(nil, jwt.ErrTokenHasBeenExpired)
 			},
 			expect: func(t *testing.T, dto *TokenDTO, err error) {
 				assert.NotNil(t, err)
@@ -4,9 +4,14 @@ import (
 	"context"
 	"nnw_s/internal/auth/twofa"
 	"nnw_s/internal/auth/verification"
+	"nnw_s/internal/events"
 	"nnw_s/internal/user"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/i18n"
 	"nnw_s/pkg/notificator"
+	"nnw_s/pkg/tracing"
+	"nnw_s/pkg/uow"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -26,18 +31,13 @@ type registrationSvc struct {
 	notificatorSvc  notificator.Service
 	verificationSvc verification.Service
 	twoFaSvc        twofa.Service
+	eventBus        eventbus.Publisher
+	uow             uow.UnitOfWork
 
 	log         *logrus.Logger
 	emailSender string
 }
 
-const (
-	emailVerificationSubject      = "Verification email."
-	emailVerificationTopic        = "Verification email."
-	emailVerificationMessage      = "You're receiving this e-mail because you requested a verify your email for your NoName Wallet account."
-	emailVerificationTemplateName = "authTemplate.html"
-)
-
 func NewRegistrationService(log *logrus.Logger, emailSender string, deps *ServiceDeps) (RegistrationService, error) {
 	if deps == nil {
 		return nil, errors.NewInternal("invalid service dependencies")
@@ -60,6 +60,12 @@ func NewRegistrationService(log *logrus.Logger, emailSender string, deps *Servic
 	if deps.CredentialsService == nil {
 		return nil, errors.NewInternal("invalid credentials service")
 	}
+	if deps.EventBus == nil {
+		return nil, errors.NewInternal("invalid event bus")
+	}
+	if deps.UnitOfWork == nil {
+		return nil, errors.NewInternal("invalid unit of work")
+	}
 	if log == nil {
 		return nil, errors.NewInternal("invalid logger")
 	}
@@ -72,66 +78,56 @@ func NewRegistrationService(log *logrus.Logger, emailSender string, deps *Servic
 		verificationSvc: deps.VerificationService,
 		log:             log,
 		emailSender:     emailSender,
+		eventBus:        deps.EventBus,
 		twoFaSvc:        deps.TwoFAService,
+		uow:             deps.UnitOfWork,
 	}, nil
 }
 
 func (svc *registrationSvc) RegisterUser(ctx context.Context, dto *RegisterUserDTO) error {
+	ctx, span := tracing.Start(ctx, "auth.RegisterUser")
+	defer span.End()
+
 	userDTO, _ := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 
-	if userDTO == nil {
-		_, err := svc.userSvc.CreateUser(ctx, &user.CreateUserDTO{Email: dto.Email, Password: dto.Password})
-		if err != nil {
-			svc.log.WithContext(ctx).Errorf("failed to register user: %v", err)
-			return err
-		}
-	} else if userDTO.Status == "disabled" {
-		err := svc.userSvc.DeleteUserByEmail(ctx, dto.Email)
-		if err != nil {
-			svc.log.WithContext(ctx).Errorf("failed to delete user: %v", err)
-			return err
+	// locale of the profile stays empty until user chooses it, emails are sent in language of
+	// the request, it is only recorded in the event
+	locale, _ := i18n.PreferredLanguage(dto.AcceptLanguage)
+
+	if userDTO != nil && userDTO.Status != "disabled" {
+		svc.log.WithContext(ctx).Errorf("failed to register user %v: user already exists", dto.Email)
+		return user.ErrAlreadyExists
+	}
+
+	// user, its verification code and email are saved together, so user is not registered
+	// if the email can't be queued, and disabled user is not deleted if the new one fails
+	err := svc.uow.Do(ctx, func(ctx context.Context) error {
+		if userDTO != nil {
+			if err := svc.userSvc.DeleteUserByEmail(ctx, dto.Email); err != nil {
+				svc.log.WithContext(ctx).Errorf("failed to delete user: %v", err)
+				return err
+			}
 		}
 
-		_, err = svc.userSvc.CreateUser(ctx, &user.CreateUserDTO{Email: dto.Email, Password: dto.Password})
-		if err != nil {
+		if _, err := svc.userSvc.CreateUser(ctx, &user.CreateUserDTO{Email: dto.Email, Password: dto.Password}); err != nil {
 			svc.log.WithContext(ctx).Errorf("failed to register user: %v", err)
 			return err
 		}
-	} else {
-		svc.log.WithContext(ctx).Errorf("failed to register user %v: user already exists", dto.Email)
-		return user.ErrAlreadyExists
-	}
 
-	// create verification code for further activation by email
-	newVerificationCode, err := svc.verificationSvc.CreateVerificationCode(ctx, dto.Email)
+		return svc.queueVerificationEmail(ctx, dto.Email, dto.AcceptLanguage, nil)
+	})
 	if err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to create verification code: %v", err)
-		return ErrFailedCreateCode
-	}
-
-	emailData := notificator.Email{
-		Subject:   emailVerificationSubject,
-		Recipient: dto.Email,
-		Sender:    svc.emailSender,
-		Template:  emailVerificationTemplateName,
-		Data: map[string]interface{}{
-			"topic":   emailVerificationTopic,
-			"message": emailVerificationMessage,
-			"code":    newVerificationCode,
-		},
-	}
-
-	// send email to recipient
-	if err = svc.notificatorSvc.SendEmail(ctx, &emailData); err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to send email: %v", err)
-		return ErrFailedSendEmail
+		return err
 	}
 
-	svc.log.WithContext(ctx).Infof("verification code successfully sent to: %s", dto.Email)
+	svc.eventBus.Publish(ctx, &events.UserRegistered{Email: dto.Email, Locale: locale})
 	return nil
 }
 
 func (svc *registrationSvc) VerifyUser(ctx context.Context, dto *VerifyUserDTO) error {
+	ctx, span := tracing.Start(ctx, "auth.VerifyUser")
+	defer span.End()
+
 	// check if user's verification code is valid
 	if err := svc.verificationSvc.CheckVerificationCode(ctx, dto.Email, dto.Code); err != nil {
 		return ErrInvalidCode
@@ -166,11 +162,14 @@ func (svc *registrationSvc) VerifyUser(ctx context.Context, dto *VerifyUserDTO)
 		return user.ErrFailedUpdateUser
 	}
 
-	svc.log.WithContext(ctx).Infof("user '%s' successfully verified", notActivatedUser.Email)
+	svc.eventBus.Publish(ctx, &events.UserVerified{Email: notActivatedUser.Email})
 	return nil
 }
 
 func (svc *registrationSvc) ResendVerificationEmail(ctx context.Context, dto *ResendActivationEmailDTO) error {
+	ctx, span := tracing.Start(ctx, "auth.ResendVerificationEmail")
+	defer span.End()
+
 	// get not activated user
 	notActivatedUserDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -188,36 +187,51 @@ func (svc *registrationSvc) ResendVerificationEmail(ctx context.Context, dto *Re
 		return user.ErrUserAlreadyVerify
 	}
 
-	// create verification code for further activation by email
-	newVerificationCode, err := svc.verificationSvc.CreateVerificationCode(ctx, dto.Email)
+	// code and email are saved together, so the code is not replaced if the email can't be queued
+	err = svc.uow.Do(ctx, func(ctx context.Context) error {
+		return svc.queueVerificationEmail(ctx, dto.Email, dto.AcceptLanguage, notActivatedUserDTO)
+	})
+	if err != nil {
+		return err
+	}
+
+	svc.log.WithContext(ctx).Infof("verification code successfully queued to: %s", dto.Email)
+	return nil
+}
+
+// queueVerificationEmail creates verification code for further activation by email and queues it to
+// the user, email is delivered in background. userDTO is nil if user is created in the same unit of work.
+func (svc *registrationSvc) queueVerificationEmail(ctx context.Context, email, acceptLanguage string, userDTO *user.DTO) error {
+	newVerificationCode, err := svc.verificationSvc.CreateVerificationCode(ctx, email)
 	if err != nil {
 		svc.log.WithContext(ctx).Errorf("failed to create verification code: %v", err)
 		return ErrFailedCreateCode
 	}
 
 	emailData := notificator.Email{
-		Subject:   emailVerificationSubject,
-		Recipient: dto.Email,
-		Sender:    svc.emailSender,
-		Template:  emailVerificationTemplateName,
-		Data: map[string]interface{}{
-			"topic":   emailVerificationTopic,
-			"message": emailVerificationMessage,
-			"code":    newVerificationCode,
-		},
-	}
-
-	// send email to recipient
-	if err = svc.notificatorSvc.SendEmail(ctx, &emailData); err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to send email: %v", err)
-		return ErrFailedSendEmail
+		Recipient:      email,
+		Sender:         svc.emailSender,
+		Data:           &notificator.VerifyEmailData{Code: newVerificationCode, ExpiresInMinutes: verification.VerificationCodeExpiry / 60},
+		Languages:      []string{acceptLanguage},
+		IdempotencyKey: verificationEmailKey(email, newVerificationCode),
 	}
 
-	svc.log.WithContext(ctx).Infof("verification code successfully sent to: %s", dto.Email)
+	// greet recipient by display name and use stored locale if they are set up
+	if userDTO != nil {
+		setRecipientProfile(&emailData, userDTO)
+	}
+
+	if err = svc.notificatorSvc.QueueEmail(ctx, &emailData); err != nil {
+		svc.log.WithContext(ctx).Errorf("failed to queue email: %v", err)
+		return ErrFailedSendEmail
+	}
 	return nil
 }
 
 func (svc *registrationSvc) SetupTwoFA(ctx context.Context, dto *SetupTwoFaDTO) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "auth.SetupTwoFA")
+	defer span.End()
+
 	// find user
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -242,21 +256,22 @@ func (svc *registrationSvc) SetupTwoFA(ctx context.Context, dto *SetupTwoFaDTO)
 		return nil, ErrFailedGenerateTwoFaImage
 	}
 
-	// update user SecretOTP key
-	userEntity.Credentials.SetSecretOTP(key)
-	userEntity.UpdatedAt = time.Now()
-
-	// map to userDTO
-	userDTO = user.MapToDTO(userEntity)
-
-	// update user in storage
-	if err = svc.userSvc.UpdateUser(ctx, userDTO); err != nil {
+	// update only user SecretOTP key, so concurrent changes of user are not overwritten
+	_, err = svc.userSvc.ModifyUser(ctx, dto.Email, func(u *user.User) (user.Fields, error) {
+		u.Credentials.SetSecretOTP(key)
+		u.UpdatedAt = time.Now()
+		return user.Fields{user.FieldSecretOTP: u.Credentials.SecretOTP}, nil
+	})
+	if err != nil {
 		return nil, user.ErrFailedUpdateUser
 	}
 	return buffImg.Bytes(), nil
 }
 
 func (svc *registrationSvc) ActivateUser(ctx context.Context, dto *ActivateUserDTO) error {
+	ctx, span := tracing.Start(ctx, "auth.ActivateUser")
+	defer span.End()
+
 	// find user
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -290,6 +305,6 @@ func (svc *registrationSvc) ActivateUser(ctx context.Context, dto *ActivateUserD
 		return user.ErrFailedUpdateUser
 	}
 
-	svc.log.WithContext(ctx).Infof("user '%s' successfully activated TwoFA authentication", userEntity.Email)
+	svc.eventBus.Publish(ctx, &events.TwoFAEnabled{Email: userEntity.Email})
 	return nil
 }
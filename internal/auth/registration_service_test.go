@@ -11,17 +11,25 @@ import (
 	mock_jwt "nnw_s/internal/auth/jwt/mocks"
 	mock_twofa "nnw_s/internal/auth/twofa/mocks"
 	mock_verification "nnw_s/internal/auth/verification/mocks"
+	"nnw_s/internal/events"
 	"nnw_s/internal/user"
 	"nnw_s/internal/user/credentials"
 	mock_credentials "nnw_s/internal/user/credentials/mocks"
 	mock_user "nnw_s/internal/user/mocks"
 	"nnw_s/pkg/errors"
+	mock_eventbus "nnw_s/pkg/eventbus/mocks"
 	"nnw_s/pkg/notificator"
 	mock_notificator "nnw_s/pkg/notificator/mocks"
-	"nnw_s/pkg/wallet"
+	"nnw_s/pkg/tracing/tracingtest"
+	"nnw_s/pkg/uow"
 	"testing"
 )
 
+func newUnitOfWork() uow.UnitOfWork {
+	unit, _ := uow.NewMemory(logrus.New())
+	return unit
+}
+
 func TestNewRegistrationService(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -45,6 +53,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -73,6 +83,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -91,6 +103,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -109,6 +123,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -127,6 +143,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        nil,
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -145,6 +163,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          nil,
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -163,6 +183,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  nil,
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -171,6 +193,45 @@ func TestNewRegistrationService(t *testing.T) {
 				assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid credentials service")
 			},
 		},
+		{
+			name: "should return invalid event bus",
+			log:  logrus.New(),
+			deps: &ServiceDeps{
+				UserService:         mock_user.NewMockService(controller),
+				NotificatorService:  mock_notificator.NewMockService(controller),
+				VerificationService: mock_verification.NewMockService(controller),
+				TwoFAService:        mock_twofa.NewMockService(controller),
+				JWTService:          mock_jwt.NewMockService(controller),
+				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            nil,
+				UnitOfWork:          newUnitOfWork(),
+			},
+			emailSender: emailSender,
+			expect: func(t *testing.T, service RegistrationService, err error) {
+				assert.Nil(t, service)
+				assert.NotNil(t, err)
+				assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid event bus")
+			},
+		},
+		{
+			name: "should return invalid unit of work",
+			log:  logrus.New(),
+			deps: &ServiceDeps{
+				UserService:         mock_user.NewMockService(controller),
+				NotificatorService:  mock_notificator.NewMockService(controller),
+				VerificationService: mock_verification.NewMockService(controller),
+				TwoFAService:        mock_twofa.NewMockService(controller),
+				JWTService:          mock_jwt.NewMockService(controller),
+				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+			},
+			emailSender: emailSender,
+			expect: func(t *testing.T, service RegistrationService, err error) {
+				assert.Nil(t, service)
+				assert.NotNil(t, err)
+				assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid unit of work")
+			},
+		},
 		{
 			name: "should return invalid logger",
 			log:  nil,
@@ -181,6 +242,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -199,6 +262,8 @@ func TestNewRegistrationService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: "",
 			expect: func(t *testing.T, service RegistrationService, err error) {
@@ -227,6 +292,8 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 	mockVerificationSvc := mock_verification.NewMockService(controller)
 	mockNotificationSvc := mock_notificator.NewMockService(controller)
 
+	mockEventBus := mock_eventbus.NewMockPublisher(controller)
+
 	deps := &ServiceDeps{
 		UserService:         mockUserSvc,
 		NotificatorService:  mockNotificationSvc,
@@ -234,6 +301,8 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 		TwoFAService:        mock_twofa.NewMockService(controller),
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mockEventBus,
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -261,16 +330,8 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 
 	//testCredDTO := credentials.MapToDTO(&testCred)
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	notActiveUser := user.MapToDTO(testUser)
 
@@ -284,15 +345,11 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 
 	// Test Email data
 	testEmailData := notificator.Email{
-		Subject:   emailVerificationSubject,
-		Recipient: userEmail,
-		Sender:    emailSender,
-		Template:  emailVerificationTemplateName,
-		Data: map[string]interface{}{
-			"topic":   emailVerificationTopic,
-			"message": emailVerificationMessage,
-			"code":    code,
-		},
+		Recipient:      userEmail,
+		Sender:         emailSender,
+		Data:           &notificator.VerifyEmailData{Code: code, ExpiresInMinutes: 10},
+		Languages:      []string{""},
+		IdempotencyKey: verificationEmailKey(userEmail, code),
 	}
 
 	tests := []struct {
@@ -304,11 +361,11 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 	}{
 		{
 			name: "should return failed to register doesn't exist user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &registerUserDTO,
 			setup: func(ctx context.Context, dto *RegisterUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, nil)
-				mockUserSvc.EXPECT().CreateUser(ctx, &user.CreateUserDTO{
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, nil)
+				mockUserSvc.EXPECT().CreateUser(tracingtest.FromContext(ctx), &user.CreateUserDTO{
 					Email:    dto.Email,
 					Password: dto.Password,
 				}).Return("", errors.NewInternal("Failed to create user"))
@@ -320,11 +377,11 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 		},
 		{
 			name: "should return failed to delete user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &registerUserDTO,
 			setup: func(ctx context.Context, dto *RegisterUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
-				mockUserSvc.EXPECT().DeleteUserByEmail(ctx, dto.Email).Return(errors.NewInternal("Failed to delete user"))
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
+				mockUserSvc.EXPECT().DeleteUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(errors.NewInternal("Failed to delete user"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -333,12 +390,12 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 		},
 		{
 			name: "should return failed to register exist user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &registerUserDTO,
 			setup: func(ctx context.Context, dto *RegisterUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
-				mockUserSvc.EXPECT().DeleteUserByEmail(ctx, dto.Email).Return(nil)
-				mockUserSvc.EXPECT().CreateUser(ctx, &user.CreateUserDTO{
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
+				mockUserSvc.EXPECT().DeleteUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil)
+				mockUserSvc.EXPECT().CreateUser(tracingtest.FromContext(ctx), &user.CreateUserDTO{
 					Email:    dto.Email,
 					Password: dto.Password,
 				}).Return("", errors.NewInternal("Failed to create user"))
@@ -350,10 +407,10 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 		},
 		{
 			name: "should return user already exist",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &registerUserDTO,
 			setup: func(ctx context.Context, dto *RegisterUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -362,15 +419,15 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 		},
 		{
 			name: "should return failed to create verification code",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &registerUserDTO,
 			setup: func(ctx context.Context, dto *RegisterUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, nil)
-				mockUserSvc.EXPECT().CreateUser(ctx, &user.CreateUserDTO{
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, nil)
+				mockUserSvc.EXPECT().CreateUser(tracingtest.FromContext(ctx), &user.CreateUserDTO{
 					Email:    dto.Email,
 					Password: dto.Password,
 				}).Return("", nil)
-				mockVerificationSvc.EXPECT().CreateVerificationCode(ctx, dto.Email).Return("", ErrFailedCreateCode)
+				mockVerificationSvc.EXPECT().CreateVerificationCode(tracingtest.FromContext(ctx), dto.Email).Return("", ErrFailedCreateCode)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -379,16 +436,16 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 		},
 		{
 			name: "should return failed to send email",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &registerUserDTO,
 			setup: func(ctx context.Context, dto *RegisterUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, nil)
-				mockUserSvc.EXPECT().CreateUser(ctx, &user.CreateUserDTO{
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, nil)
+				mockUserSvc.EXPECT().CreateUser(tracingtest.FromContext(ctx), &user.CreateUserDTO{
 					Email:    dto.Email,
 					Password: dto.Password,
 				}).Return("", nil)
-				mockVerificationSvc.EXPECT().CreateVerificationCode(ctx, dto.Email).Return(code, nil)
-				mockNotificationSvc.EXPECT().SendEmail(ctx, &testEmailData).Return(ErrFailedSendEmail)
+				mockVerificationSvc.EXPECT().CreateVerificationCode(tracingtest.FromContext(ctx), dto.Email).Return(code, nil)
+				mockNotificationSvc.EXPECT().QueueEmail(tracingtest.FromContext(ctx), &testEmailData).Return(ErrFailedSendEmail)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -397,16 +454,17 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &registerUserDTO,
 			setup: func(ctx context.Context, dto *RegisterUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, nil)
-				mockUserSvc.EXPECT().CreateUser(ctx, &user.CreateUserDTO{
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, nil)
+				mockUserSvc.EXPECT().CreateUser(tracingtest.FromContext(ctx), &user.CreateUserDTO{
 					Email:    dto.Email,
 					Password: dto.Password,
 				}).Return("", nil)
-				mockVerificationSvc.EXPECT().CreateVerificationCode(ctx, dto.Email).Return(code, nil)
-				mockNotificationSvc.EXPECT().SendEmail(ctx, &testEmailData).Return(nil)
+				mockVerificationSvc.EXPECT().CreateVerificationCode(tracingtest.FromContext(ctx), dto.Email).Return(code, nil)
+				mockNotificationSvc.EXPECT().QueueEmail(tracingtest.FromContext(ctx), &testEmailData).Return(nil)
+				mockEventBus.EXPECT().Publish(tracingtest.FromContext(ctx), &events.UserRegistered{Email: dto.Email})
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -423,6 +481,72 @@ func TestRegistrationSvc_RegisterUser(t *testing.T) {
 	}
 }
 
+// unitKey marks context of the unit of work run by recordingUnit
+type unitKey struct{}
+
+// recordingUnit runs fn like a transaction, repositories called with ctx of fn join it and their
+// changes are discarded if fn fails
+type recordingUnit struct {
+	rolledBack bool
+}
+
+func (u *recordingUnit) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(context.WithValue(ctx, unitKey{}, u))
+	u.rolledBack = err != nil
+	return err
+}
+
+func (u *recordingUnit) Compensate(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// inUnit matches context of the unit of work
+type inUnit struct {
+	unit *recordingUnit
+}
+
+func (m inUnit) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	return ok && ctx.Value(unitKey{}) == m.unit
+}
+
+func (m inUnit) String() string {
+	return "is context of unit of work"
+}
+
+func TestRegistrationSvc_RegisterUserUnitOfWork(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx := tracingtest.Context()
+	userEmail := "user@example.com"
+
+	mockUserSvc := mock_user.NewMockService(controller)
+	mockVerificationSvc := mock_verification.NewMockService(controller)
+	mockNotificationSvc := mock_notificator.NewMockService(controller)
+	unit := &recordingUnit{}
+
+	service, _ := NewRegistrationService(logrus.New(), "example@example.com", &ServiceDeps{
+		UserService:         mockUserSvc,
+		NotificatorService:  mockNotificationSvc,
+		VerificationService: mockVerificationSvc,
+		TwoFAService:        mock_twofa.NewMockService(controller),
+		JWTService:          mock_jwt.NewMockService(controller),
+		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:          unit,
+	})
+
+	mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), userEmail).Return(nil, user.ErrNotFound)
+	mockUserSvc.EXPECT().CreateUser(inUnit{unit}, &user.CreateUserDTO{Email: userEmail, Password: "password"}).Return("user_id", nil)
+	mockVerificationSvc.EXPECT().CreateVerificationCode(inUnit{unit}, userEmail).Return("ASDDSA", nil)
+	mockNotificationSvc.EXPECT().QueueEmail(inUnit{unit}, gomock.AssignableToTypeOf(&notificator.Email{})).Return(ErrFailedSendEmail)
+
+	err := service.RegisterUser(ctx, &RegisterUserDTO{Email: userEmail, Password: "password"})
+	assert.Equal(t, ErrFailedSendEmail, err)
+	assert.True(t, unit.rolledBack, "created user should be rolled back with failed email")
+}
+
 func TestRegistrationSvc_VerifyUser(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -432,6 +556,8 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 	mockUserSvc := mock_user.NewMockService(controller)
 	mockVerificationSvc := mock_verification.NewMockService(controller)
 
+	mockEventBus := mock_eventbus.NewMockPublisher(controller)
+
 	deps := &ServiceDeps{
 		UserService:         mockUserSvc,
 		NotificatorService:  mock_notificator.NewMockService(controller),
@@ -439,6 +565,8 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 		TwoFAService:        mock_twofa.NewMockService(controller),
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mockEventBus,
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -459,16 +587,8 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 	testCred.Password = "==WvZitmZDgzSHgAWvKs"
 	testCred.SecretOTP = &secretKey
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	wrongUserDTO := user.MapToDTO(testUser)
 	wrongUserDTO.ID = "example"
@@ -490,10 +610,10 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 	}{
 		{
 			name: "should return invalid code",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &verifyUserDTO,
 			setup: func(ctx context.Context, dto *VerifyUserDTO) {
-				mockVerificationSvc.EXPECT().CheckVerificationCode(ctx, dto.Email, dto.Code).Return(ErrInvalidCode)
+				mockVerificationSvc.EXPECT().CheckVerificationCode(tracingtest.FromContext(ctx), dto.Email, dto.Code).Return(ErrInvalidCode)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -502,11 +622,11 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 		},
 		{
 			name: "should return not found user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &verifyUserDTO,
 			setup: func(ctx context.Context, dto *VerifyUserDTO) {
-				mockVerificationSvc.EXPECT().CheckVerificationCode(ctx, dto.Email, dto.Code).Return(nil)
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, errors.NewInternal("User not found"))
+				mockVerificationSvc.EXPECT().CheckVerificationCode(tracingtest.FromContext(ctx), dto.Email, dto.Code).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, errors.NewInternal("User not found"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -515,11 +635,11 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 		},
 		{
 			name: "should return user already verify",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &verifyUserDTO,
 			setup: func(ctx context.Context, dto *VerifyUserDTO) {
-				mockVerificationSvc.EXPECT().CheckVerificationCode(ctx, dto.Email, dto.Code).Return(nil)
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CheckVerificationCode(tracingtest.FromContext(ctx), dto.Email, dto.Code).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -528,11 +648,11 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 		},
 		{
 			name: "should return wrong object id",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &verifyUserDTO,
 			setup: func(ctx context.Context, dto *VerifyUserDTO) {
-				mockVerificationSvc.EXPECT().CheckVerificationCode(ctx, dto.Email, dto.Code).Return(nil)
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockVerificationSvc.EXPECT().CheckVerificationCode(tracingtest.FromContext(ctx), dto.Email, dto.Code).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -541,12 +661,12 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 		},
 		{
 			name: "should return failed to update user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &verifyUserDTO,
 			setup: func(ctx context.Context, dto *VerifyUserDTO) {
-				mockVerificationSvc.EXPECT().CheckVerificationCode(ctx, dto.Email, dto.Code).Return(nil)
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveAndVerifiedUserDTO, nil)
-				mockUserSvc.EXPECT().UpdateUser(ctx, gomock.AssignableToTypeOf(verifiedUserDTO)).Return(user.ErrFailedUpdateUser)
+				mockVerificationSvc.EXPECT().CheckVerificationCode(tracingtest.FromContext(ctx), dto.Email, dto.Code).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveAndVerifiedUserDTO, nil)
+				mockUserSvc.EXPECT().UpdateUser(tracingtest.FromContext(ctx), gomock.AssignableToTypeOf(verifiedUserDTO)).Return(user.ErrFailedUpdateUser)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -555,12 +675,13 @@ func TestRegistrationSvc_VerifyUser(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &verifyUserDTO,
 			setup: func(ctx context.Context, dto *VerifyUserDTO) {
-				mockVerificationSvc.EXPECT().CheckVerificationCode(ctx, dto.Email, dto.Code).Return(nil)
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveAndVerifiedUserDTO, nil)
-				mockUserSvc.EXPECT().UpdateUser(ctx, gomock.AssignableToTypeOf(verifiedUserDTO)).Return(nil)
+				mockVerificationSvc.EXPECT().CheckVerificationCode(tracingtest.FromContext(ctx), dto.Email, dto.Code).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveAndVerifiedUserDTO, nil)
+				mockUserSvc.EXPECT().UpdateUser(tracingtest.FromContext(ctx), gomock.AssignableToTypeOf(verifiedUserDTO)).Return(nil)
+				mockEventBus.EXPECT().Publish(tracingtest.FromContext(ctx), &events.UserVerified{Email: dto.Email})
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -594,6 +715,8 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 		TwoFAService:        mock_twofa.NewMockService(controller),
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -608,15 +731,11 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 
 	// Email Data
 	emailData := notificator.Email{
-		Subject:   emailVerificationSubject,
-		Recipient: userEmail,
-		Sender:    emailSender,
-		Template:  emailVerificationTemplateName,
-		Data: map[string]interface{}{
-			"topic":   emailVerificationTopic,
-			"message": emailVerificationMessage,
-			"code":    code,
-		},
+		Recipient:      userEmail,
+		Sender:         emailSender,
+		Data:           &notificator.VerifyEmailData{Code: code, ExpiresInMinutes: 10},
+		Languages:      []string{""},
+		IdempotencyKey: verificationEmailKey(userEmail, code),
 	}
 
 	// Test Cred
@@ -625,16 +744,8 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 	testCred.Password = "==WvZitmZDgzSHgAWvKs"
 	testCred.SecretOTP = &secretKey
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	notActiveUser := user.MapToDTO(testUser)
 
@@ -654,10 +765,10 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 	}{
 		{
 			name: "should return permission_denied",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendRegistrationEmailDTO,
 			setup: func(ctx context.Context, dto *ResendActivationEmailDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, ErrPermissionDenied)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, ErrPermissionDenied)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -666,10 +777,10 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 		},
 		{
 			name: "should return invalid dto",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendRegistrationEmailDTO,
 			setup: func(ctx context.Context, dto *ResendActivationEmailDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -678,10 +789,10 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 		},
 		{
 			name: "should return already_verify user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendRegistrationEmailDTO,
 			setup: func(ctx context.Context, dto *ResendActivationEmailDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -690,11 +801,11 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 		},
 		{
 			name: "should return failed to create reset password code",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendRegistrationEmailDTO,
 			setup: func(ctx context.Context, dto *ResendActivationEmailDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
-				mockVerificationSvc.EXPECT().CreateVerificationCode(ctx, dto.Email).Return("", ErrFailedCreateCode)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
+				mockVerificationSvc.EXPECT().CreateVerificationCode(tracingtest.FromContext(ctx), dto.Email).Return("", ErrFailedCreateCode)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -703,12 +814,12 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 		},
 		{
 			name: "should return failed to send email",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendRegistrationEmailDTO,
 			setup: func(ctx context.Context, dto *ResendActivationEmailDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
-				mockVerificationSvc.EXPECT().CreateVerificationCode(ctx, dto.Email).Return(code, nil)
-				mockNotificationSvc.EXPECT().SendEmail(ctx, &emailData).Return(ErrFailedSendEmail)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
+				mockVerificationSvc.EXPECT().CreateVerificationCode(tracingtest.FromContext(ctx), dto.Email).Return(code, nil)
+				mockNotificationSvc.EXPECT().QueueEmail(tracingtest.FromContext(ctx), &emailData).Return(ErrFailedSendEmail)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -717,12 +828,12 @@ func TestRegistrationSvc_ResendVerificationEmail(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendRegistrationEmailDTO,
 			setup: func(ctx context.Context, dto *ResendActivationEmailDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
-				mockVerificationSvc.EXPECT().CreateVerificationCode(ctx, dto.Email).Return(code, nil)
-				mockNotificationSvc.EXPECT().SendEmail(ctx, &emailData).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
+				mockVerificationSvc.EXPECT().CreateVerificationCode(tracingtest.FromContext(ctx), dto.Email).Return(code, nil)
+				mockNotificationSvc.EXPECT().QueueEmail(tracingtest.FromContext(ctx), &emailData).Return(nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -755,6 +866,8 @@ func TestRegistrationSvc_SetupTwoFA(t *testing.T) {
 		TwoFAService:        mockTwoFaSvc,
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -778,16 +891,8 @@ func TestRegistrationSvc_SetupTwoFA(t *testing.T) {
 	testCred.Password = "==WvZitmZDgzSHgAWvKs"
 	testCred.SecretOTP = &secretKey
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	notActiveUser := user.MapToDTO(testUser)
 
@@ -810,10 +915,10 @@ func TestRegistrationSvc_SetupTwoFA(t *testing.T) {
 	}{
 		{
 			name: "should return user not found",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &twoFaDTO,
 			setup: func(ctx context.Context, dto *SetupTwoFaDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, user.ErrNotFound)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, user.ErrNotFound)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -822,10 +927,10 @@ func TestRegistrationSvc_SetupTwoFA(t *testing.T) {
 		},
 		{
 			name: "should return invalid dto",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &twoFaDTO,
 			setup: func(ctx context.Context, dto *SetupTwoFaDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -834,10 +939,10 @@ func TestRegistrationSvc_SetupTwoFA(t *testing.T) {
 		},
 		{
 			name: "should return already_verify user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &twoFaDTO,
 			setup: func(ctx context.Context, dto *SetupTwoFaDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -846,12 +951,12 @@ func TestRegistrationSvc_SetupTwoFA(t *testing.T) {
 		},
 		{
 			name: "should return failed_generate_twoFa_image",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &twoFaDTO,
 			setup: func(ctx context.Context, dto *SetupTwoFaDTO) {
 				fmt.Println(notActiveUser)
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(verifiedUser, nil)
-				mockTwoFaSvc.EXPECT().GenerateTwoFAImage(ctx, dto.Email).Return(nil, nil, ErrFailedGenerateTwoFaImage)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(verifiedUser, nil)
+				mockTwoFaSvc.EXPECT().GenerateTwoFAImage(tracingtest.FromContext(ctx), dto.Email).Return(nil, nil, ErrFailedGenerateTwoFaImage)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -860,12 +965,12 @@ func TestRegistrationSvc_SetupTwoFA(t *testing.T) {
 		},
 		{
 			name: "should return failed to update user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &twoFaDTO,
 			setup: func(ctx context.Context, dto *SetupTwoFaDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(verifiedUser, nil)
-				mockTwoFaSvc.EXPECT().GenerateTwoFAImage(ctx, dto.Email).Return(&bufImage, key, nil)
-				mockUserSvc.EXPECT().UpdateUser(ctx, gomock.AssignableToTypeOf(testUserDTO)).Return(user.ErrFailedUpdateUser)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(verifiedUser, nil)
+				mockTwoFaSvc.EXPECT().GenerateTwoFAImage(tracingtest.FromContext(ctx), dto.Email).Return(&bufImage, key, nil)
+				mockUserSvc.EXPECT().ModifyUser(tracingtest.FromContext(ctx), dto.Email, gomock.Any()).Return(nil, user.ErrConflict)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -874,12 +979,19 @@ func TestRegistrationSvc_SetupTwoFA(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &twoFaDTO,
 			setup: func(ctx context.Context, dto *SetupTwoFaDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(verifiedUser, nil)
-				mockTwoFaSvc.EXPECT().GenerateTwoFAImage(ctx, dto.Email).Return(&bufImage, key, nil)
-				mockUserSvc.EXPECT().UpdateUser(ctx, gomock.AssignableToTypeOf(testUserDTO)).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(verifiedUser, nil)
+				mockTwoFaSvc.EXPECT().GenerateTwoFAImage(tracingtest.FromContext(ctx), dto.Email).Return(&bufImage, key, nil)
+				mockUserSvc.EXPECT().ModifyUser(tracingtest.FromContext(ctx), dto.Email, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, email string, modify user.ModifyFunc) (*user.DTO, error) {
+						u, _ := user.MapToEntity(verifiedUser)
+						fields, err := modify(u)
+						assert.Equal(t, user.Fields{user.FieldSecretOTP: u.Credentials.SecretOTP}, fields)
+						assert.Equal(t, key.Secret(), *u.Credentials.SecretOTP)
+						return user.MapToDTO(u), err
+					})
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -905,6 +1017,8 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 	mockUserSvc := mock_user.NewMockService(controller)
 	mockTwoFaSvc := mock_twofa.NewMockService(controller)
 
+	mockEventBus := mock_eventbus.NewMockPublisher(controller)
+
 	deps := &ServiceDeps{
 		UserService:         mockUserSvc,
 		NotificatorService:  mock_notificator.NewMockService(controller),
@@ -912,6 +1026,8 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 		TwoFAService:        mockTwoFaSvc,
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mockEventBus,
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -932,16 +1048,8 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 	testCred.Password = "==WvZitmZDgzSHgAWvKs"
 	testCred.SecretOTP = &secretKey
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	//notActiveUser := user.MapToDTO(testUser)
 
@@ -964,10 +1072,10 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 	}{
 		{
 			name: "should return user not found",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &activateUserDTO,
 			setup: func(ctx context.Context, dto *ActivateUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, user.ErrNotFound)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, user.ErrNotFound)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -976,10 +1084,10 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 		},
 		{
 			name: "should return invalid dto",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &activateUserDTO,
 			setup: func(ctx context.Context, dto *ActivateUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -988,10 +1096,10 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 		},
 		{
 			name: "should return already_active user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &activateUserDTO,
 			setup: func(ctx context.Context, dto *ActivateUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -1000,11 +1108,11 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 		},
 		{
 			name: "should return invalid_code",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &activateUserDTO,
 			setup: func(ctx context.Context, dto *ActivateUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(verifiedUser, nil)
-				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, dto.Code, secret).Return(ErrInvalidCode)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(verifiedUser, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(tracingtest.FromContext(ctx), dto.Code, secret).Return(ErrInvalidCode)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -1013,12 +1121,12 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 		},
 		{
 			name: "should return failed update user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &activateUserDTO,
 			setup: func(ctx context.Context, dto *ActivateUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(verifiedUser, nil)
-				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, dto.Code, secret).Return(nil)
-				mockUserSvc.EXPECT().UpdateUser(ctx, gomock.AssignableToTypeOf(testUserDTO)).Return(user.ErrFailedUpdateUser)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(verifiedUser, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(tracingtest.FromContext(ctx), dto.Code, secret).Return(nil)
+				mockUserSvc.EXPECT().UpdateUser(tracingtest.FromContext(ctx), gomock.AssignableToTypeOf(testUserDTO)).Return(user.ErrFailedUpdateUser)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -1027,12 +1135,13 @@ func TestRegistrationSvc_ActivateUser(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &activateUserDTO,
 			setup: func(ctx context.Context, dto *ActivateUserDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(verifiedUser, nil)
-				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, dto.Code, secret).Return(nil)
-				mockUserSvc.EXPECT().UpdateUser(ctx, gomock.AssignableToTypeOf(testUserDTO)).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(verifiedUser, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(tracingtest.FromContext(ctx), dto.Code, secret).Return(nil)
+				mockUserSvc.EXPECT().UpdateUser(tracingtest.FromContext(ctx), gomock.AssignableToTypeOf(testUserDTO)).Return(nil)
+				mockEventBus.EXPECT().Publish(tracingtest.FromContext(ctx), &events.TwoFAEnabled{Email: dto.Email})
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -4,10 +4,15 @@ import (
 	"context"
 	"github.com/sirupsen/logrus"
 	"nnw_s/internal/auth/verification"
+	"nnw_s/internal/events"
 	"nnw_s/internal/user"
 	"nnw_s/internal/user/credentials"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
 	"nnw_s/pkg/notificator"
+	"nnw_s/pkg/tracing"
+	"nnw_s/pkg/uow"
+	"time"
 )
 
 //go:generate mockgen -source=resetPassword_service.go -destination=mocks/resetPassword_service_mock.go
@@ -23,18 +28,13 @@ type resetPasswordSvc struct {
 	notificatorSvc  notificator.Service
 	verificationSvc verification.Service
 	credentialsSvc  credentials.Service
+	eventBus        eventbus.Publisher
+	uow             uow.UnitOfWork
 
 	log         *logrus.Logger
 	emailSender string
 }
 
-const (
-	emailResetPasswordSubject      = "Reset password."
-	emailResetPasswordTopic        = "Reset password."
-	emailResetPasswordMessage      = "You're receiving this e-mail because you requested a reset your password for your NoName Wallet account."
-	emailResetPasswordTemplateName = "authTemplate.html"
-)
-
 func NewResetPasswordService(log *logrus.Logger, emailSender string, deps *ServiceDeps) (ResetPasswordService, error) {
 	if deps == nil {
 		return nil, errors.NewInternal("invalid service dependencies")
@@ -57,6 +57,12 @@ func NewResetPasswordService(log *logrus.Logger, emailSender string, deps *Servi
 	if deps.CredentialsService == nil {
 		return nil, errors.NewInternal("invalid credentials service")
 	}
+	if deps.EventBus == nil {
+		return nil, errors.NewInternal("invalid event bus")
+	}
+	if deps.UnitOfWork == nil {
+		return nil, errors.NewInternal("invalid unit of work")
+	}
 	if log == nil {
 		return nil, errors.NewInternal("invalid logger")
 	}
@@ -71,10 +77,15 @@ func NewResetPasswordService(log *logrus.Logger, emailSender string, deps *Servi
 		credentialsSvc:  deps.CredentialsService,
 		log:             log,
 		emailSender:     emailSender,
+		eventBus:        deps.EventBus,
+		uow:             deps.UnitOfWork,
 	}, nil
 }
 
 func (svc *resetPasswordSvc) ResetPassword(ctx context.Context, dto *ResetPasswordDTO) error {
+	ctx, span := tracing.Start(ctx, "auth.ResetPassword")
+	defer span.End()
+
 	// find user
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -92,35 +103,22 @@ func (svc *resetPasswordSvc) ResetPassword(ctx context.Context, dto *ResetPasswo
 		return user.ErrUserDoesNotVerify
 	}
 
-	newResetPasswordCode, err := svc.verificationSvc.CreateResetPasswordCode(ctx, userEntity.Email)
+	// code and email are saved together, so the code is not replaced if the email can't be queued
+	err = svc.uow.Do(ctx, func(ctx context.Context) error {
+		return svc.queueResetPasswordEmail(ctx, dto.Email, dto.AcceptLanguage, userDTO)
+	})
 	if err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to create reset password code: %v", err)
 		return err
 	}
 
-	emailData := notificator.Email{
-		Subject:   emailResetPasswordSubject,
-		Recipient: dto.Email,
-		Sender:    svc.emailSender,
-		Template:  emailResetPasswordTemplateName,
-		Data: map[string]interface{}{
-			"topic":   emailResetPasswordTopic,
-			"message": emailResetPasswordMessage,
-			"code":    newResetPasswordCode,
-		},
-	}
-
-	// send email to recipient
-	if err = svc.notificatorSvc.SendEmail(ctx, &emailData); err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to send email: %v", err)
-		return err
-	}
-
-	svc.log.WithContext(ctx).Infof("reset password code successfully sent to: %s", dto.Email)
+	svc.log.WithContext(ctx).Infof("reset password code successfully queued to: %s", dto.Email)
 	return nil
 }
 
 func (svc *resetPasswordSvc) ResendResetPasswordEmail(ctx context.Context, dto *ResendResetPasswordDTO) error {
+	ctx, span := tracing.Start(ctx, "auth.ResendResetPasswordEmail")
+	defer span.End()
+
 	// find user
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -138,35 +136,48 @@ func (svc *resetPasswordSvc) ResendResetPasswordEmail(ctx context.Context, dto *
 		return user.ErrUserDoesNotVerify
 	}
 
-	newResetPasswordCode, err := svc.verificationSvc.CreateResetPasswordCode(ctx, userEntity.Email)
+	// code and email are saved together, so the code is not replaced if the email can't be queued
+	err = svc.uow.Do(ctx, func(ctx context.Context) error {
+		return svc.queueResetPasswordEmail(ctx, dto.Email, dto.AcceptLanguage, userDTO)
+	})
+	if err != nil {
+		return err
+	}
+
+	svc.log.WithContext(ctx).Infof("reset password code successfully requeued to: %s", dto.Email)
+	return nil
+}
+
+// queueResetPasswordEmail creates reset password code and queues it to the user, email is delivered in background
+func (svc *resetPasswordSvc) queueResetPasswordEmail(ctx context.Context, email, acceptLanguage string, userDTO *user.DTO) error {
+	newResetPasswordCode, err := svc.verificationSvc.CreateResetPasswordCode(ctx, email)
 	if err != nil {
 		svc.log.WithContext(ctx).Errorf("failed to create reset password code: %v", err)
 		return err
 	}
 
 	emailData := notificator.Email{
-		Subject:   emailResetPasswordSubject,
-		Recipient: dto.Email,
-		Sender:    svc.emailSender,
-		Template:  emailResetPasswordTemplateName,
-		Data: map[string]interface{}{
-			"topic":   emailResetPasswordTopic,
-			"message": emailResetPasswordMessage,
-			"code":    newResetPasswordCode,
-		},
-	}
-
-	// send email to recipient
-	if err = svc.notificatorSvc.SendEmail(ctx, &emailData); err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to send email: %v", err)
-		return err
+		Recipient:      email,
+		Sender:         svc.emailSender,
+		Data:           &notificator.ResetPasswordData{Code: newResetPasswordCode, ExpiresInMinutes: verification.ResetPasswordCodeExpiry / 60},
+		Languages:      []string{acceptLanguage},
+		IdempotencyKey: resetPasswordEmailKey(email, newResetPasswordCode),
 	}
 
-	svc.log.WithContext(ctx).Infof("reset password code successfully resend to: %s", dto.Email)
+	// greet recipient by display name and use stored locale if they are set up
+	setRecipientProfile(&emailData, userDTO)
+
+	if err = svc.notificatorSvc.QueueEmail(ctx, &emailData); err != nil {
+		svc.log.WithContext(ctx).Errorf("failed to queue email: %v", err)
+		return err
+	}
 	return nil
 }
 
 func (svc *resetPasswordSvc) ResetPasswordCode(ctx context.Context, dto *ResetPasswordCodedDTO) error {
+	ctx, span := tracing.Start(ctx, "auth.ResetPasswordCode")
+	defer span.End()
+
 	// find user
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -193,6 +204,9 @@ func (svc *resetPasswordSvc) ResetPasswordCode(ctx context.Context, dto *ResetPa
 }
 
 func (svc *resetPasswordSvc) SetupNewPassword(ctx context.Context, dto *SetupNewPasswordDTO) error {
+	ctx, span := tracing.Start(ctx, "auth.SetupNewPassword")
+	defer span.End()
+
 	// find user
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
@@ -220,16 +234,15 @@ func (svc *resetPasswordSvc) SetupNewPassword(ctx context.Context, dto *SetupNew
 	// map credentialsDTO to entity
 	userCredentials := credentials.MapToEntity(userCredentialsDTO)
 
-	// Set-up new credentials
-	userEntity.Credentials = userCredentials
-
-	// map to userDTO
-	resetPasswordUserDTO := user.MapToDTO(userEntity)
-
-	err = svc.userSvc.UpdateUser(ctx, resetPasswordUserDTO)
+	// Set-up only new password, so concurrent changes of user are not overwritten
+	_, err = svc.userSvc.ModifyUser(ctx, dto.Email, func(u *user.User) (user.Fields, error) {
+		u.Credentials.SetNewPassword(userCredentials.Password)
+		return user.Fields{user.FieldPassword: u.Credentials.Password}, nil
+	})
 	if err != nil {
 		return err
 	}
 
+	svc.eventBus.Publish(ctx, &events.PasswordReset{Email: dto.Email, Time: time.Now()})
 	return nil
 }
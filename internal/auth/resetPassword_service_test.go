@@ -8,14 +8,16 @@ import (
 	mock_jwt "nnw_s/internal/auth/jwt/mocks"
 	mock_twofa "nnw_s/internal/auth/twofa/mocks"
 	mock_verification "nnw_s/internal/auth/verification/mocks"
+	"nnw_s/internal/events"
 	"nnw_s/internal/user"
 	"nnw_s/internal/user/credentials"
 	mock_credentials "nnw_s/internal/user/credentials/mocks"
 	mock_user "nnw_s/internal/user/mocks"
 	"nnw_s/pkg/errors"
+	mock_eventbus "nnw_s/pkg/eventbus/mocks"
 	"nnw_s/pkg/notificator"
 	mock_notificator "nnw_s/pkg/notificator/mocks"
-	"nnw_s/pkg/wallet"
+	"nnw_s/pkg/tracing/tracingtest"
 	"testing"
 )
 
@@ -42,6 +44,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -70,6 +74,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -88,6 +94,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -106,6 +114,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -124,6 +134,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        nil,
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -142,6 +154,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          nil,
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -160,6 +174,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  nil,
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -168,6 +184,45 @@ func TestNewResetPasswordService(t *testing.T) {
 				assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid credentials service")
 			},
 		},
+		{
+			name: "should return invalid event bus",
+			log:  logrus.New(),
+			deps: &ServiceDeps{
+				UserService:         mock_user.NewMockService(controller),
+				NotificatorService:  mock_notificator.NewMockService(controller),
+				VerificationService: mock_verification.NewMockService(controller),
+				TwoFAService:        mock_twofa.NewMockService(controller),
+				JWTService:          mock_jwt.NewMockService(controller),
+				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            nil,
+				UnitOfWork:          newUnitOfWork(),
+			},
+			emailSender: emailSender,
+			expect: func(t *testing.T, service ResetPasswordService, err error) {
+				assert.Nil(t, service)
+				assert.NotNil(t, err)
+				assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid event bus")
+			},
+		},
+		{
+			name: "should return invalid unit of work",
+			log:  logrus.New(),
+			deps: &ServiceDeps{
+				UserService:         mock_user.NewMockService(controller),
+				NotificatorService:  mock_notificator.NewMockService(controller),
+				VerificationService: mock_verification.NewMockService(controller),
+				TwoFAService:        mock_twofa.NewMockService(controller),
+				JWTService:          mock_jwt.NewMockService(controller),
+				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+			},
+			emailSender: emailSender,
+			expect: func(t *testing.T, service ResetPasswordService, err error) {
+				assert.Nil(t, service)
+				assert.NotNil(t, err)
+				assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid unit of work")
+			},
+		},
 		{
 			name: "should return invalid logger",
 			log:  nil,
@@ -178,6 +233,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: emailSender,
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -196,6 +253,8 @@ func TestNewResetPasswordService(t *testing.T) {
 				TwoFAService:        mock_twofa.NewMockService(controller),
 				JWTService:          mock_jwt.NewMockService(controller),
 				CredentialsService:  mock_credentials.NewMockService(controller),
+				EventBus:            mock_eventbus.NewMockPublisher(controller),
+				UnitOfWork:          newUnitOfWork(),
 			},
 			emailSender: "",
 			expect: func(t *testing.T, service ResetPasswordService, err error) {
@@ -231,6 +290,8 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 		TwoFAService:        mock_twofa.NewMockService(controller),
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -245,15 +306,11 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 
 	// Test Email Data
 	emailData := notificator.Email{
-		Subject:   emailResetPasswordSubject,
-		Recipient: userEmail,
-		Sender:    emailSender,
-		Template:  emailResetPasswordTemplateName,
-		Data: map[string]interface{}{
-			"topic":   emailResetPasswordTopic,
-			"message": emailResetPasswordMessage,
-			"code":    code,
-		},
+		Recipient:      userEmail,
+		Sender:         emailSender,
+		Data:           &notificator.ResetPasswordData{Code: code, ExpiresInMinutes: 5},
+		Languages:      []string{""},
+		IdempotencyKey: resetPasswordEmailKey(userEmail, code),
 	}
 
 	// Test Cred
@@ -262,16 +319,8 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 	testCred.Password = "==WvZitmZDgzSHgAWvKs"
 	testCred.SecretOTP = &secretKey
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	notActiveUser := user.MapToDTO(testUser)
 
@@ -291,10 +340,10 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 	}{
 		{
 			name: "should return permission_denied",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, ErrPermissionDenied)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, ErrPermissionDenied)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -303,10 +352,10 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 		},
 		{
 			name: "should return wrong object id",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -315,10 +364,10 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 		},
 		{
 			name: "should return user_does_not_verify",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -327,11 +376,11 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 		},
 		{
 			name: "should return failed to create reset password code",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockVerificationSvc.EXPECT().CreateResetPasswordCode(ctx, dto.Email).Return("", errors.NewInternal("Failed to create reset password code"))
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CreateResetPasswordCode(tracingtest.FromContext(ctx), dto.Email).Return("", errors.NewInternal("Failed to create reset password code"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -340,12 +389,12 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 		},
 		{
 			name: "should return failed to send email",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockVerificationSvc.EXPECT().CreateResetPasswordCode(ctx, dto.Email).Return(code, nil)
-				mockNotificationSvc.EXPECT().SendEmail(ctx, &emailData).Return(errors.NewInternal("Failed to send email"))
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CreateResetPasswordCode(tracingtest.FromContext(ctx), dto.Email).Return(code, nil)
+				mockNotificationSvc.EXPECT().QueueEmail(tracingtest.FromContext(ctx), &emailData).Return(errors.NewInternal("Failed to send email"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -354,12 +403,12 @@ func TestResetPasswordSvc_ResetPassword(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockVerificationSvc.EXPECT().CreateResetPasswordCode(ctx, dto.Email).Return(code, nil)
-				mockNotificationSvc.EXPECT().SendEmail(ctx, &emailData).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CreateResetPasswordCode(tracingtest.FromContext(ctx), dto.Email).Return(code, nil)
+				mockNotificationSvc.EXPECT().QueueEmail(tracingtest.FromContext(ctx), &emailData).Return(nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -393,6 +442,8 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 		TwoFAService:        mock_twofa.NewMockService(controller),
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -407,15 +458,11 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 
 	// Test Email Data
 	emailData := notificator.Email{
-		Subject:   emailResetPasswordSubject,
-		Recipient: userEmail,
-		Sender:    emailSender,
-		Template:  emailResetPasswordTemplateName,
-		Data: map[string]interface{}{
-			"topic":   emailResetPasswordTopic,
-			"message": emailResetPasswordMessage,
-			"code":    code,
-		},
+		Recipient:      userEmail,
+		Sender:         emailSender,
+		Data:           &notificator.ResetPasswordData{Code: code, ExpiresInMinutes: 5},
+		Languages:      []string{""},
+		IdempotencyKey: resetPasswordEmailKey(userEmail, code),
 	}
 
 	// Test Cred
@@ -424,16 +471,8 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 	testCred.Password = "==WvZitmZDgzSHgAWvKs"
 	testCred.SecretOTP = &secretKey
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	notActiveUser := user.MapToDTO(testUser)
 
@@ -453,10 +492,10 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 	}{
 		{
 			name: "should return permission_denied",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendPasswordDTO,
 			setup: func(ctx context.Context, dto *ResendResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, ErrPermissionDenied)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, ErrPermissionDenied)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -465,10 +504,10 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 		},
 		{
 			name: "should return wrong object id",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendPasswordDTO,
 			setup: func(ctx context.Context, dto *ResendResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -477,10 +516,10 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 		},
 		{
 			name: "should return user_does_not_verify",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendPasswordDTO,
 			setup: func(ctx context.Context, dto *ResendResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -489,11 +528,11 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 		},
 		{
 			name: "should return failed to create reset password code",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendPasswordDTO,
 			setup: func(ctx context.Context, dto *ResendResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockVerificationSvc.EXPECT().CreateResetPasswordCode(ctx, dto.Email).Return("", errors.NewInternal("Failed to create reset password code"))
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CreateResetPasswordCode(tracingtest.FromContext(ctx), dto.Email).Return("", errors.NewInternal("Failed to create reset password code"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -502,12 +541,12 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 		},
 		{
 			name: "should return failed to send email",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendPasswordDTO,
 			setup: func(ctx context.Context, dto *ResendResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockVerificationSvc.EXPECT().CreateResetPasswordCode(ctx, dto.Email).Return(code, nil)
-				mockNotificationSvc.EXPECT().SendEmail(ctx, &emailData).Return(errors.NewInternal("Failed to send email"))
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CreateResetPasswordCode(tracingtest.FromContext(ctx), dto.Email).Return(code, nil)
+				mockNotificationSvc.EXPECT().QueueEmail(tracingtest.FromContext(ctx), &emailData).Return(errors.NewInternal("Failed to send email"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -516,12 +555,12 @@ func TestResetPasswordSvc_ResendResetPasswordEmail(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resendPasswordDTO,
 			setup: func(ctx context.Context, dto *ResendResetPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockVerificationSvc.EXPECT().CreateResetPasswordCode(ctx, dto.Email).Return(code, nil)
-				mockNotificationSvc.EXPECT().SendEmail(ctx, &emailData).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CreateResetPasswordCode(tracingtest.FromContext(ctx), dto.Email).Return(code, nil)
+				mockNotificationSvc.EXPECT().QueueEmail(tracingtest.FromContext(ctx), &emailData).Return(nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -554,6 +593,8 @@ func TestResetPasswordSvc_ResetPasswordCode(t *testing.T) {
 		TwoFAService:        mock_twofa.NewMockService(controller),
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mock_credentials.NewMockService(controller),
+		EventBus:            mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -573,16 +614,8 @@ func TestResetPasswordSvc_ResetPasswordCode(t *testing.T) {
 	testCred.Password = "==WvZitmZDgzSHgAWvKs"
 	testCred.SecretOTP = &secretKey
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	notActiveUser := user.MapToDTO(testUser)
 
@@ -602,10 +635,10 @@ func TestResetPasswordSvc_ResetPasswordCode(t *testing.T) {
 	}{
 		{
 			name: "should return permission_denied",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordCodeDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordCodedDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, ErrPermissionDenied)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, ErrPermissionDenied)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -614,10 +647,10 @@ func TestResetPasswordSvc_ResetPasswordCode(t *testing.T) {
 		},
 		{
 			name: "should return wrong object id",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordCodeDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordCodedDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -626,10 +659,10 @@ func TestResetPasswordSvc_ResetPasswordCode(t *testing.T) {
 		},
 		{
 			name: "should return permission_denied not active user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordCodeDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordCodedDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -638,11 +671,11 @@ func TestResetPasswordSvc_ResetPasswordCode(t *testing.T) {
 		},
 		{
 			name: "should return check reset password code",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordCodeDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordCodedDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockVerificationSvc.EXPECT().CheckResetPasswordCode(ctx, dto.Email, dto.Code).Return(errors.NewInternal("Failed to check reset password code"))
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CheckResetPasswordCode(tracingtest.FromContext(ctx), dto.Email, dto.Code).Return(errors.NewInternal("Failed to check reset password code"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -651,11 +684,11 @@ func TestResetPasswordSvc_ResetPasswordCode(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &resetPasswordCodeDTO,
 			setup: func(ctx context.Context, dto *ResetPasswordCodedDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockVerificationSvc.EXPECT().CheckResetPasswordCode(ctx, dto.Email, dto.Code).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockVerificationSvc.EXPECT().CheckResetPasswordCode(tracingtest.FromContext(ctx), dto.Email, dto.Code).Return(nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -681,6 +714,8 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 	mockUserSvc := mock_user.NewMockService(controller)
 	mockCredentialsSvc := mock_credentials.NewMockService(controller)
 
+	mockEventBus := mock_eventbus.NewMockPublisher(controller)
+
 	deps := &ServiceDeps{
 		UserService:         mockUserSvc,
 		NotificatorService:  mock_notificator.NewMockService(controller),
@@ -688,6 +723,8 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 		TwoFAService:        mock_twofa.NewMockService(controller),
 		JWTService:          mock_jwt.NewMockService(controller),
 		CredentialsService:  mockCredentialsSvc,
+		EventBus:            mockEventBus,
+		UnitOfWork:          newUnitOfWork(),
 	}
 
 	// Test Data
@@ -710,16 +747,8 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 
 	testCredDTO := credentials.MapToDTO(&testCred)
 
-	// Test wallet
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
 	// Test user
-	testUser, _ := user.NewUser(userEmail, &testWallet, &testCred)
+	testUser, _ := user.NewUser(userEmail, &testCred)
 
 	notActiveUser := user.MapToDTO(testUser)
 
@@ -739,10 +768,10 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 	}{
 		{
 			name: "should return permission_denied",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &setupNewPasswordDTO,
 			setup: func(ctx context.Context, dto *SetupNewPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(nil, ErrPermissionDenied)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(nil, ErrPermissionDenied)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -751,10 +780,10 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 		},
 		{
 			name: "should return wrong object id",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &setupNewPasswordDTO,
 			setup: func(ctx context.Context, dto *SetupNewPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(wrongUserDTO, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(wrongUserDTO, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -763,10 +792,10 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 		},
 		{
 			name: "should return permission_denied not active user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &setupNewPasswordDTO,
 			setup: func(ctx context.Context, dto *SetupNewPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(notActiveUser, nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(notActiveUser, nil)
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -775,11 +804,11 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 		},
 		{
 			name: "should return failed to create user credentials",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &setupNewPasswordDTO,
 			setup: func(ctx context.Context, dto *SetupNewPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockCredentialsSvc.EXPECT().CreateCredentials(ctx, dto.Password, testCred.SecretOTP).Return(nil, errors.NewInternal("Failed to create user credentials"))
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockCredentialsSvc.EXPECT().CreateCredentials(tracingtest.FromContext(ctx), dto.Password, testCred.SecretOTP).Return(nil, errors.NewInternal("Failed to create user credentials"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -788,12 +817,12 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 		},
 		{
 			name: "should return failed to update user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &setupNewPasswordDTO,
 			setup: func(ctx context.Context, dto *SetupNewPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockCredentialsSvc.EXPECT().CreateCredentials(ctx, dto.Password, testCred.SecretOTP).Return(testCredDTO, nil)
-				mockUserSvc.EXPECT().UpdateUser(ctx, testUserDTO).Return(errors.NewInternal("Failed to update user"))
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockCredentialsSvc.EXPECT().CreateCredentials(tracingtest.FromContext(ctx), dto.Password, testCred.SecretOTP).Return(testCredDTO, nil)
+				mockUserSvc.EXPECT().ModifyUser(tracingtest.FromContext(ctx), dto.Email, gomock.Any()).Return(nil, errors.NewInternal("Failed to update user"))
 			},
 			expect: func(t *testing.T, err error) {
 				assert.NotNil(t, err)
@@ -802,12 +831,23 @@ func TestResetPasswordSvc_SetupNewPassword(t *testing.T) {
 		},
 		{
 			name: "should return ok",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto:  &setupNewPasswordDTO,
 			setup: func(ctx context.Context, dto *SetupNewPasswordDTO) {
-				mockUserSvc.EXPECT().GetUserByEmail(ctx, dto.Email).Return(testUserDTO, nil)
-				mockCredentialsSvc.EXPECT().CreateCredentials(ctx, dto.Password, testCred.SecretOTP).Return(testCredDTO, nil)
-				mockUserSvc.EXPECT().UpdateUser(ctx, testUserDTO).Return(nil)
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), dto.Email).Return(testUserDTO, nil)
+				mockCredentialsSvc.EXPECT().CreateCredentials(tracingtest.FromContext(ctx), dto.Password, testCred.SecretOTP).Return(testCredDTO, nil)
+				mockUserSvc.EXPECT().ModifyUser(tracingtest.FromContext(ctx), dto.Email, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, email string, modify user.ModifyFunc) (*user.DTO, error) {
+						u, _ := user.MapToEntity(testUserDTO)
+						fields, err := modify(u)
+						assert.Equal(t, user.Fields{user.FieldPassword: testCredDTO.Password}, fields)
+						return user.MapToDTO(u), err
+					})
+				mockEventBus.EXPECT().Publish(tracingtest.FromContext(ctx), gomock.AssignableToTypeOf(&events.PasswordReset{})).Do(
+					func(ctx context.Context, event *events.PasswordReset) {
+						assert.Equal(t, dto.Email, event.Email)
+						assert.False(t, event.Time.IsZero())
+					})
 			},
 			expect: func(t *testing.T, err error) {
 				assert.Nil(t, err)
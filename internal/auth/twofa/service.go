@@ -5,6 +5,7 @@ import (
 	"context"
 	"image/png"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/metrics"
 
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
@@ -53,6 +54,7 @@ func (svc *service) GenerateTwoFAImage(ctx context.Context, email string) (*byte
 
 func (svc *service) CheckTwoFACode(ctx context.Context, code, secret string) error {
 	if !totp.Validate(code, secret) {
+		metrics.TwoFAFailures.Inc()
 		return ErrInvalidTwoFACode
 	}
 	return nil
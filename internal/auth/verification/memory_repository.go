@@ -0,0 +1,80 @@
+package verification
+
+import (
+	"context"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/errors"
+	"sync"
+	"time"
+)
+
+type memoryRepository struct {
+	mu                 sync.Mutex
+	verificationCodes  []Code
+	resetPasswordCodes []Code
+	clock              clock.Clock
+}
+
+// NewMemoryRepository returns thread-safe repository which keeps codes in memory,
+// codes expire in the same time as in mongo
+func NewMemoryRepository(clk clock.Clock) (Repository, error) {
+	if clk == nil {
+		return nil, errors.NewInternal("invalid clock")
+	}
+	return &memoryRepository{clock: clk}, nil
+}
+
+func (repo *memoryRepository) SaveVerificationCode(_ context.Context, code *Code) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.verificationCodes = repo.save(repo.verificationCodes, code, VerificationCodeExpiry)
+	return nil
+}
+
+func (repo *memoryRepository) GetVerificationCode(_ context.Context, email, code string) (*Code, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.find(repo.verificationCodes, email, code, VerificationCodeExpiry)
+}
+
+func (repo *memoryRepository) SaveResetPasswordCode(_ context.Context, code *Code) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.resetPasswordCodes = repo.save(repo.resetPasswordCodes, code, ResetPasswordCodeExpiry)
+	return nil
+}
+
+func (repo *memoryRepository) GetResetPasswordCode(_ context.Context, email, code string) (*Code, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.find(repo.resetPasswordCodes, email, code, ResetPasswordCodeExpiry)
+}
+
+// save removes expired codes and appends the new one
+func (repo *memoryRepository) save(codes []Code, code *Code, expiry int) []Code {
+	actual := codes[:0]
+	for _, c := range codes {
+		if !repo.isExpired(&c, expiry) {
+			actual = append(actual, c)
+		}
+	}
+	return append(actual, *code)
+}
+
+func (repo *memoryRepository) find(codes []Code, email, code string, expiry int) (*Code, error) {
+	for _, c := range codes {
+		if c.Email == email && c.Code == code && !repo.isExpired(&c, expiry) {
+			found := c
+			return &found, nil
+		}
+	}
+	return nil, ErrCodeNotFound
+}
+
+func (repo *memoryRepository) isExpired(code *Code, expiry int) bool {
+	return !repo.clock.Now().Before(code.CreatedAt.Add(time.Duration(expiry) * time.Second))
+}
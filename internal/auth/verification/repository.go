@@ -3,16 +3,18 @@ package verification
 import (
 	"context"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// lifetime of codes in seconds
 const (
-	verificationCodeExpiry  = 600
-	resetPasswordCodeExpiry = 300
+	VerificationCodeExpiry  = 600
+	ResetPasswordCodeExpiry = 300
 )
 
 //go:generate mockgen -source=repository.go -destination=mocks/repository_mock.go
@@ -39,19 +41,16 @@ func NewRepository(db *mongo.Database, log *logrus.Logger) (Repository, error) {
 	return &repository{db: db, log: log}, nil
 }
 
-func (repo *repository) SaveVerificationCode(ctx context.Context, code *Code) error {
-	mod := mongo.IndexModel{
-		Keys:    bson.M{"created_at": 1}, // index in ascending order or -1 for descending order
-		Options: options.Index().SetExpireAfterSeconds(verificationCodeExpiry),
-	}
-
-	_, err := repo.db.Collection("verification_code").Indexes().CreateOne(ctx, mod)
-	if err != nil {
-		repo.log.WithContext(ctx).Errorf("failed to create verification code index: %v", err)
-		return errors.NewInternal(err.Error())
+// CreateIndexes creates TTL indexes of codes, so expired codes are removed
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := migrations.SetTTL(ctx, db.Collection("verification_code"), "created_at", VerificationCodeExpiry*time.Second); err != nil {
+		return err
 	}
+	return migrations.SetTTL(ctx, db.Collection("reset_password_code"), "created_at", ResetPasswordCodeExpiry*time.Second)
+}
 
-	_, err = repo.db.Collection("verification_code").InsertOne(ctx, code)
+func (repo *repository) SaveVerificationCode(ctx context.Context, code *Code) error {
+	_, err := repo.db.Collection("verification_code").InsertOne(ctx, code)
 	if err != nil {
 		repo.log.WithContext(ctx).Errorf("failed to insert verification code to db: %v", err)
 		return errors.NewInternal(err.Error())
@@ -81,18 +80,7 @@ func (repo *repository) GetVerificationCode(ctx context.Context, email, code str
 }
 
 func (repo *repository) SaveResetPasswordCode(ctx context.Context, code *Code) error {
-	mod := mongo.IndexModel{
-		Keys:    bson.M{"created_at": 1}, // index in ascending order or -1 for descending order
-		Options: options.Index().SetExpireAfterSeconds(resetPasswordCodeExpiry),
-	}
-
-	_, err := repo.db.Collection("reset_password_code").Indexes().CreateOne(ctx, mod)
-	if err != nil {
-		repo.log.WithContext(ctx).Errorf("failed to create reset password code index: %v", err)
-		return errors.NewInternal(err.Error())
-	}
-
-	_, err = repo.db.Collection("reset_password_code").InsertOne(ctx, code)
+	_, err := repo.db.Collection("reset_password_code").InsertOne(ctx, code)
 	if err != nil {
 		repo.log.WithContext(ctx).Errorf("failed to insert reset password code to db: %v", err)
 		return errors.NewInternal(err.Error())
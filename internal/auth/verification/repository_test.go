@@ -0,0 +1,132 @@
+package verification_test
+
+import (
+	"context"
+	"nnw_s/internal/auth/verification"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/mongodb/mongotest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) verification.Repository {
+		repo, err := verification.NewMemoryRepository(clock.New())
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMongoRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) verification.Repository {
+		repo, err := verification.NewRepository(mongotest.DB(t), logrus.New())
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMemoryRepositoryExpiry(t *testing.T) {
+	ctx := context.Background()
+
+	code, err := verification.NewCode("some@mail.com")
+	require.Nil(t, err)
+
+	tests := []struct {
+		name   string
+		expiry time.Duration
+		save   func(verification.Repository) error
+		get    func(verification.Repository) (*verification.Code, error)
+	}{
+		{
+			name:   "verification code should expire in 10 minutes",
+			expiry: 10 * time.Minute,
+			save: func(repo verification.Repository) error {
+				return repo.SaveVerificationCode(ctx, code)
+			},
+			get: func(repo verification.Repository) (*verification.Code, error) {
+				return repo.GetVerificationCode(ctx, code.Email, code.Code)
+			},
+		},
+		{
+			name:   "reset password code should expire in 5 minutes",
+			expiry: 5 * time.Minute,
+			save: func(repo verification.Repository) error {
+				return repo.SaveResetPasswordCode(ctx, code)
+			},
+			get: func(repo verification.Repository) (*verification.Code, error) {
+				return repo.GetResetPasswordCode(ctx, code.Email, code.Code)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clk := clock.NewMock(code.CreatedAt)
+			repo, err := verification.NewMemoryRepository(clk)
+			require.Nil(t, err)
+
+			require.Nil(t, tc.save(repo))
+
+			clk.Add(tc.expiry - time.Second)
+			_, err = tc.get(repo)
+			assert.Nil(t, err)
+
+			clk.Add(time.Second)
+			_, err = tc.get(repo)
+			assert.Equal(t, verification.ErrCodeNotFound, err)
+		})
+	}
+}
+
+// testRepository is a conformance suite which every verification.Repository implementation should pass
+func testRepository(t *testing.T, newRepo func(t *testing.T) verification.Repository) {
+	ctx := context.Background()
+
+	t.Run("should save and get verification code", func(t *testing.T) {
+		repo := newRepo(t)
+		code, err := verification.NewCode("some@mail.com")
+		require.Nil(t, err)
+
+		require.Nil(t, repo.SaveVerificationCode(ctx, code))
+
+		loaded, err := repo.GetVerificationCode(ctx, code.Email, code.Code)
+		require.Nil(t, err)
+		assert.Equal(t, code.ID, loaded.ID)
+
+		_, err = repo.GetResetPasswordCode(ctx, code.Email, code.Code)
+		assert.Equal(t, verification.ErrCodeNotFound, err)
+	})
+
+	t.Run("should save and get reset password code", func(t *testing.T) {
+		repo := newRepo(t)
+		code, err := verification.NewCode("some@mail.com")
+		require.Nil(t, err)
+
+		require.Nil(t, repo.SaveResetPasswordCode(ctx, code))
+
+		loaded, err := repo.GetResetPasswordCode(ctx, code.Email, code.Code)
+		require.Nil(t, err)
+		assert.Equal(t, code.ID, loaded.ID)
+
+		_, err = repo.GetVerificationCode(ctx, code.Email, code.Code)
+		assert.Equal(t, verification.ErrCodeNotFound, err)
+	})
+
+	t.Run("should return 'code not found' error on wrong email or code", func(t *testing.T) {
+		repo := newRepo(t)
+		code, err := verification.NewCode("some@mail.com")
+		require.Nil(t, err)
+
+		require.Nil(t, repo.SaveVerificationCode(ctx, code))
+
+		_, err = repo.GetVerificationCode(ctx, "other@mail.com", code.Code)
+		assert.Equal(t, verification.ErrCodeNotFound, err)
+
+		_, err = repo.GetVerificationCode(ctx, code.Email, "wrong_code")
+		assert.Equal(t, verification.ErrCodeNotFound, err)
+	})
+}
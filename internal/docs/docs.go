@@ -0,0 +1,85 @@
+// Package docs serves OpenAPI specification of the API and its docs UI.
+package docs
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// specYAML is OpenAPI specification of routes of auth, user and wallet handlers,
+// docs_test.go checks that it matches the routes and their DTOs
+//
+//go:embed openapi.yaml
+var specYAML []byte
+
+// indexHTML is Swagger UI of the specification
+//
+//go:embed index.html
+var indexHTML []byte
+
+// assetsFS is swagger-ui-dist 4.15.5 (Apache License 2.0), it is served by the API itself,
+// so no third-party script runs on origin of the API
+//
+//go:embed swagger-ui
+var assetsFS embed.FS
+
+// Spec returns OpenAPI specification of the API, it fails if the specification is invalid
+func Spec() (*openapi3.T, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(specYAML)
+	if err != nil {
+		return nil, err
+	}
+	if err = spec.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+type Handler struct {
+	specJSON []byte
+	assets   http.Handler
+}
+
+func NewHandler(spec *openapi3.T) (*Handler, error) {
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := fs.Sub(assetsFS, "swagger-ui")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		specJSON: specJSON,
+		assets:   http.StripPrefix("/api/docs/assets/", http.FileServer(http.FS(assets))),
+	}, nil
+}
+
+func (h *Handler) SetupRoutes(router *echo.Echo) {
+	docs := router.Group("/api/docs")
+
+	docs.GET("", h.index)
+	docs.GET("/openapi.yaml", h.getSpecYAML)
+	docs.GET("/openapi.json", h.getSpecJSON)
+	docs.GET("/assets/*", echo.WrapHandler(h.assets))
+}
+
+func (h *Handler) index(ctx echo.Context) error {
+	return ctx.HTMLBlob(http.StatusOK, indexHTML)
+}
+
+func (h *Handler) getSpecYAML(ctx echo.Context) error {
+	return ctx.Blob(http.StatusOK, "application/yaml", specYAML)
+}
+
+func (h *Handler) getSpecJSON(ctx echo.Context) error {
+	return ctx.JSONBlob(http.StatusOK, h.specJSON)
+}
@@ -0,0 +1,216 @@
+package docs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/internal/auth"
+	"nnw_s/internal/docs"
+	"nnw_s/internal/user"
+	"nnw_s/internal/user/wallet"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requests are DTOs which handlers bind requests of operations to
+var requests = map[string]interface{}{
+	"POST /api/v1/register-user":               auth.RegisterUserDTO{},
+	"POST /api/v1/verify-user":                 auth.VerifyUserDTO{},
+	"POST /api/v1/resend-verification-email":   auth.ResendActivationEmailDTO{},
+	"POST /api/v1/setup-twoFa":                 auth.SetupTwoFaDTO{},
+	"POST /api/v1/activate-user":               auth.ActivateUserDTO{},
+	"POST /api/v1/login":                       auth.LoginDTO{},
+	"POST /api/v1/login-code":                  auth.LoginCodeDTO{},
+	"POST /api/v1/logout":                      auth.LogoutCodeDTO{},
+	"POST /api/v1/reset-password":              auth.ResetPasswordDTO{},
+	"POST /api/v1/resend-reset-password-email": auth.ResendResetPasswordDTO{},
+	"POST /api/v1/reset-password-code":         auth.ResetPasswordCodedDTO{},
+	"POST /api/v1/setup-new-password":          auth.SetupNewPasswordDTO{},
+	"POST /api/v1/validate-token":              auth.ValidateTokenDTO{},
+	"POST /api/v1/get-user":                    user.GetUserDTO{},
+	"POST /api/v1/get-profile":                 user.GetProfileDTO{},
+	"PATCH /api/v1/profile":                    user.UpdateProfileDTO{},
+	"POST /api/v1/get-wallet":                  wallet.GetWalletDTO{},
+	"POST /api/v1/get-wallets":                 wallet.GetWalletsDTO{},
+	"POST /api/v1/rename-wallet":               wallet.RenameWalletDTO{},
+	"POST /api/v1/archive-wallet":              wallet.ArchiveWalletDTO{},
+	"POST /api/v1/create-wallet":               wallet.CreateWalletDTO{},
+	"POST /api/v1/get-balance":                 wallet.GetWalletBalanceDTO{},
+	"POST /api/v1/get-tx":                      wallet.GetWalletTxDTO{},
+	"POST /api/v1/create-tx":                   wallet.CreateTxDTO{},
+	"POST /api/v1/send-tx":                     wallet.SendTxDTO{},
+}
+
+// responses are DTOs of schemas of responses
+var responses = map[string]interface{}{
+	"Token":           auth.TokenDTO{},
+	"GetUserResponse": user.GetUserResponseDTO{},
+	"Profile":         user.ProfileDTO{},
+	"Wallet":          wallet.WalletDTO{},
+	"Balance":         wallet.BalanceDTO{},
+	"Tx":              wallet.TxsDTO{},
+	"TxInput":         wallet.InputTxDTO{},
+	"TxOutput":        wallet.OutTxDTO{},
+}
+
+func loadSpec(t *testing.T) *openapi3.T {
+	t.Helper()
+
+	spec, err := docs.Spec()
+	require.Nil(t, err)
+	return spec
+}
+
+// fieldsOf returns names of fields of dto by tag and names of fields which are required by its validation rules
+func fieldsOf(dto interface{}, tag string) (fields []string, required []string) {
+	typ := reflect.TypeOf(dto)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fields = append(fields, name)
+		if strings.Split(field.Tag.Get("validate"), ",")[0] == "required" {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(fields)
+	sort.Strings(required)
+	return fields, required
+}
+
+func propertiesOf(schema *openapi3.Schema) (properties []string, required []string) {
+	for name := range schema.Properties {
+		properties = append(properties, name)
+	}
+	required = append(required, schema.Required...)
+	sort.Strings(properties)
+	sort.Strings(required)
+	return properties, required
+}
+
+func TestRoutes(t *testing.T) {
+	spec := loadSpec(t)
+
+	router := echo.New()
+	auth.NewHandler(nil, nil, nil, nil, 0).SetupRoutes(router)
+	user.NewHandler(nil, nil, 0).SetupRoutes(router)
+	wallet.NewHandler(nil, nil, 0).SetupRoutes(router)
+
+	var routes []string
+	for _, r := range router.Routes() {
+		routes = append(routes, r.Method+" "+r.Path)
+	}
+
+	var operations []string
+	for path, item := range spec.Paths {
+		for method := range item.Operations() {
+			operations = append(operations, method+" "+path)
+		}
+	}
+
+	assert.ElementsMatch(t, routes, operations, "every route of handlers is in specification")
+}
+
+func TestRequests(t *testing.T) {
+	spec := loadSpec(t)
+
+	checked := make(map[string]bool)
+	for path, item := range spec.Paths {
+		for method, op := range item.Operations() {
+			name := method + " " + path
+			dto, ok := requests[name]
+
+			if op.RequestBody != nil {
+				require.True(t, ok, "DTO of %s is not known", name)
+				checked[name] = true
+
+				schema := op.RequestBody.Value.Content.Get(echo.MIMEApplicationJSON).Schema.Value
+				fields, required := fieldsOf(dto, "json")
+				properties, requiredProperties := propertiesOf(schema)
+				assert.Equal(t, fields, properties, "fields of %s", name)
+				assert.Equal(t, required, requiredProperties, "required fields of %s", name)
+			}
+
+			if method == http.MethodGet && ok {
+				checked[name] = true
+
+				var params, requiredParams []string
+				for _, p := range op.Parameters {
+					if p.Value.In == openapi3.ParameterInQuery {
+						params = append(params, p.Value.Name)
+						if p.Value.Required {
+							requiredParams = append(requiredParams, p.Value.Name)
+						}
+					}
+				}
+				sort.Strings(params)
+				sort.Strings(requiredParams)
+
+				fields, required := fieldsOf(dto, "query")
+				assert.Equal(t, fields, params, "query of %s", name)
+				assert.Equal(t, required, requiredParams, "required query of %s", name)
+			}
+		}
+	}
+
+	for name := range requests {
+		assert.True(t, checked[name], "request of %s is not in specification", name)
+	}
+}
+
+func TestResponses(t *testing.T) {
+	spec := loadSpec(t)
+
+	for name, dto := range responses {
+		schema, ok := spec.Components.Schemas[name]
+		require.True(t, ok, "schema %s is not in specification", name)
+
+		fields, _ := fieldsOf(dto, "json")
+		properties, _ := propertiesOf(schema.Value)
+		assert.Equal(t, fields, properties, "fields of %s", name)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	handler, err := docs.NewHandler(loadSpec(t))
+	require.Nil(t, err)
+
+	router := echo.New()
+	handler.SetupRoutes(router)
+
+	for path, contentType := range map[string]string{
+		"/api/docs":              echo.MIMETextHTMLCharsetUTF8,
+		"/api/docs/openapi.yaml": "application/yaml",
+		"/api/docs/openapi.json": echo.MIMEApplicationJSONCharsetUTF8,
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, contentType, rec.Header().Get(echo.HeaderContentType), path)
+		assert.NotEmpty(t, rec.Body.String(), path)
+	}
+
+	t.Run("should serve docs UI from origin of the API", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+		assert.NotContains(t, rec.Body.String(), "https://")
+
+		for _, path := range []string{"/api/docs/assets/swagger-ui.css", "/api/docs/assets/swagger-ui-bundle.js"} {
+			rec = httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+			assert.NotEmpty(t, rec.Body.String(), path)
+		}
+	})
+}
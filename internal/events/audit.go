@@ -0,0 +1,38 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+
+	"github.com/sirupsen/logrus"
+)
+
+const auditSubscriber = "audit"
+
+// AuditSubscriber writes every domain event to the log, so changes of accounts can be traced
+type AuditSubscriber struct {
+	log *logrus.Logger
+}
+
+func NewAuditSubscriber(log *logrus.Logger) (*AuditSubscriber, error) {
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &AuditSubscriber{log: log}, nil
+}
+
+func (s *AuditSubscriber) Subscribe(bus *eventbus.Bus) {
+	bus.Subscribe(auditSubscriber, s.handle)
+}
+
+func (s *AuditSubscriber) handle(ctx context.Context, event eventbus.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.log.WithContext(ctx).WithField("event", event.EventName()).Infof("domain event: %s", payload)
+	return nil
+}
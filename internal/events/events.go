@@ -0,0 +1,151 @@
+package events
+
+import (
+	"nnw_s/pkg/eventbus"
+	"time"
+)
+
+// Names of domain events
+const (
+	NameUserRegistered       = "user_registered"
+	NameUserVerified         = "user_verified"
+	NameTwoFAEnabled         = "two_fa_enabled"
+	NameUserLoggedIn         = "user_logged_in"
+	NamePasswordReset        = "password_reset"
+	NameWalletCreated        = "wallet_created"
+	NameTransactionBroadcast = "transaction_broadcast"
+	NameTransactionConfirmed = "transaction_confirmed"
+	NameDepositReceived      = "deposit_received"
+)
+
+// UserEvent is an event of user's account, every domain event is
+type UserEvent interface {
+	eventbus.Event
+	// UserEmail returns email of the user the event happened to
+	UserEmail() string
+}
+
+// UserRegistered is published when user signs up, the user is not verified yet
+type UserRegistered struct {
+	Email string `bson:"email" json:"email"`
+	// Locale is preferred language of the registration request, it is not stored in the profile
+	Locale string `bson:"locale" json:"locale"`
+}
+
+func (*UserRegistered) EventName() string   { return NameUserRegistered }
+func (e *UserRegistered) UserEmail() string { return e.Email }
+
+// UserVerified is published when user confirms the email
+type UserVerified struct {
+	Email string `bson:"email" json:"email"`
+}
+
+func (*UserVerified) EventName() string   { return NameUserVerified }
+func (e *UserVerified) UserEmail() string { return e.Email }
+
+// TwoFAEnabled is published when user confirms 2FA code, the user becomes active
+type TwoFAEnabled struct {
+	Email string `bson:"email" json:"email"`
+}
+
+func (*TwoFAEnabled) EventName() string   { return NameTwoFAEnabled }
+func (e *TwoFAEnabled) UserEmail() string { return e.Email }
+
+// UserLoggedIn is published when user confirms login by 2FA code and gets token
+type UserLoggedIn struct {
+	Email     string    `bson:"email" json:"email"`
+	IP        string    `bson:"ip" json:"ip"`
+	UserAgent string    `bson:"user_agent" json:"user_agent"`
+	Time      time.Time `bson:"time" json:"time"`
+}
+
+func (*UserLoggedIn) EventName() string   { return NameUserLoggedIn }
+func (e *UserLoggedIn) UserEmail() string { return e.Email }
+
+// PasswordReset is published when user sets up new password
+type PasswordReset struct {
+	Email string    `bson:"email" json:"email"`
+	Time  time.Time `bson:"time" json:"time"`
+}
+
+func (*PasswordReset) EventName() string   { return NamePasswordReset }
+func (e *PasswordReset) UserEmail() string { return e.Email }
+
+// WalletCreated is published for every wallet created for user
+type WalletCreated struct {
+	Email    string `bson:"email" json:"email"`
+	WalletID string `bson:"wallet_id" json:"wallet_id"`
+	Chain    string `bson:"chain" json:"chain"`
+	Address  string `bson:"address" json:"address"`
+}
+
+func (*WalletCreated) EventName() string   { return NameWalletCreated }
+func (e *WalletCreated) UserEmail() string { return e.Email }
+
+// TransactionBroadcast is published when signed transaction is sent to the network
+type TransactionBroadcast struct {
+	Email    string `bson:"email" json:"email"`
+	WalletID string `bson:"wallet_id" json:"wallet_id"`
+	Chain    string `bson:"chain" json:"chain"`
+	From     string `bson:"from" json:"from"`
+	// To is empty if client hasn't sent destination address
+	To     string `bson:"to" json:"to"`
+	Amount string `bson:"amount" json:"amount"`
+	TxHash string `bson:"tx_hash" json:"tx_hash"`
+}
+
+func (*TransactionBroadcast) EventName() string   { return NameTransactionBroadcast }
+func (e *TransactionBroadcast) UserEmail() string { return e.Email }
+
+// TransactionConfirmed is published when transaction of the wallet gets enough confirmations to be final
+type TransactionConfirmed struct {
+	Email         string `bson:"email" json:"email"`
+	WalletID      string `bson:"wallet_id" json:"wallet_id"`
+	Chain         string `bson:"chain" json:"chain"`
+	TxHash        string `bson:"tx_hash" json:"tx_hash"`
+	Confirmations int64  `bson:"confirmations" json:"confirmations"`
+}
+
+func (*TransactionConfirmed) EventName() string   { return NameTransactionConfirmed }
+func (e *TransactionConfirmed) UserEmail() string { return e.Email }
+
+// DepositReceived is published when incoming transaction of the wallet appears on the chain,
+// it is not confirmed yet, TransactionConfirmed follows it
+type DepositReceived struct {
+	Email    string `bson:"email" json:"email"`
+	WalletID string `bson:"wallet_id" json:"wallet_id"`
+	Chain    string `bson:"chain" json:"chain"`
+	Address  string `bson:"address" json:"address"`
+	// From is empty if sender address is unknown
+	From   string `bson:"from" json:"from"`
+	Amount string `bson:"amount" json:"amount"`
+	TxHash string `bson:"tx_hash" json:"tx_hash"`
+}
+
+func (*DepositReceived) EventName() string   { return NameDepositReceived }
+func (e *DepositReceived) UserEmail() string { return e.Email }
+
+// Factories returns factories of all domain events, they are registered in the bus to replay the event log
+func Factories() []func() eventbus.Event {
+	return []func() eventbus.Event{
+		func() eventbus.Event { return &UserRegistered{} },
+		func() eventbus.Event { return &UserVerified{} },
+		func() eventbus.Event { return &TwoFAEnabled{} },
+		func() eventbus.Event { return &UserLoggedIn{} },
+		func() eventbus.Event { return &PasswordReset{} },
+		func() eventbus.Event { return &WalletCreated{} },
+		func() eventbus.Event { return &TransactionBroadcast{} },
+		func() eventbus.Event { return &TransactionConfirmed{} },
+		func() eventbus.Event { return &DepositReceived{} },
+	}
+}
+
+// Names returns names of all domain events
+func Names() []string {
+	factories := Factories()
+	names := make([]string, 0, len(factories))
+	for _, factory := range factories {
+		names = append(names, factory().EventName())
+	}
+	return names
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/metrics"
+)
+
+const metricsSubscriber = "metrics"
+
+// MetricsSubscriber counts domain events by name, so services do not keep counters of their changes
+type MetricsSubscriber struct{}
+
+func NewMetricsSubscriber() *MetricsSubscriber {
+	return &MetricsSubscriber{}
+}
+
+func (s *MetricsSubscriber) Subscribe(bus *eventbus.Bus) {
+	bus.Subscribe(metricsSubscriber, s.handle)
+}
+
+// handle counts the event, replayed events are counted when they are published
+func (s *MetricsSubscriber) handle(ctx context.Context, event eventbus.Event) error {
+	if meta, ok := eventbus.MetaFromContext(ctx); ok && meta.Replayed {
+		return nil
+	}
+	metrics.Events.WithLabelValues(event.EventName()).Inc()
+	return nil
+}
@@ -0,0 +1,39 @@
+package events_test
+
+import (
+	"context"
+	"nnw_s/internal/events"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/metrics"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsSubscriber(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := eventbus.NewMemoryStore()
+	require.Nil(t, err)
+
+	bus, err := eventbus.NewBus(logrus.New(), store)
+	require.Nil(t, err)
+	bus.Register(events.Factories()...)
+	events.NewMetricsSubscriber().Subscribe(bus)
+
+	logins := metrics.Events.WithLabelValues(events.NameUserLoggedIn)
+	before := testutil.ToFloat64(logins)
+
+	bus.Publish(ctx, &events.UserLoggedIn{Email: "user@example.com"})
+	bus.Publish(ctx, &events.UserLoggedIn{Email: "user@example.com"})
+	assert.Equal(t, before+2, testutil.ToFloat64(logins))
+
+	t.Run("should not count replayed events again", func(t *testing.T) {
+		_, err := bus.Replay(ctx, &eventbus.ReplayFilter{})
+		require.Nil(t, err)
+		assert.Equal(t, before+2, testutil.ToFloat64(logins))
+	})
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"nnw_s/internal/user/notifications"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/notificator"
+	"strconv"
+)
+
+const notificationsSubscriber = "notifications"
+
+// NotificationSubscriber notifies users about changes of their accounts and wallets
+type NotificationSubscriber struct {
+	notificationsSvc notifications.Service
+}
+
+func NewNotificationSubscriber(notificationsSvc notifications.Service) (*NotificationSubscriber, error) {
+	if notificationsSvc == nil {
+		return nil, errors.NewInternal("invalid notifications service")
+	}
+	return &NotificationSubscriber{notificationsSvc: notificationsSvc}, nil
+}
+
+func (s *NotificationSubscriber) Subscribe(bus *eventbus.Bus) {
+	bus.Subscribe(notificationsSubscriber, s.handle, NameUserLoggedIn, NamePasswordReset, NameTransactionBroadcast, NameDepositReceived)
+}
+
+// handle notifies user, idempotency keys are built of the event, so replayed events are not sent twice
+func (s *NotificationSubscriber) handle(ctx context.Context, event eventbus.Event) error {
+	switch e := event.(type) {
+	case *UserLoggedIn:
+		key := "new_login:" + e.Email + ":" + strconv.FormatInt(e.Time.Unix(), 10)
+		data := &notificator.NewLoginData{IP: e.IP, UserAgent: e.UserAgent, Time: e.Time}
+		return s.notificationsSvc.Notify(ctx, e.Email, data, key)
+	case *PasswordReset:
+		key := "password_changed:" + e.Email + ":" + strconv.FormatInt(e.Time.Unix(), 10)
+		return s.notificationsSvc.Notify(ctx, e.Email, &notificator.PasswordChangedData{Time: e.Time}, key)
+	case *TransactionBroadcast:
+		data := &notificator.TxSentData{Chain: e.Chain, Amount: e.Amount, To: e.To, TxHash: e.TxHash}
+		return s.notificationsSvc.Notify(ctx, e.Email, data, "tx_sent:"+e.Chain+":"+e.TxHash)
+	case *DepositReceived:
+		data := &notificator.TxReceivedData{Chain: e.Chain, Amount: e.Amount, From: e.From, TxHash: e.TxHash}
+		return s.notificationsSvc.Notify(ctx, e.Email, data, "tx_received:"+e.Chain+":"+e.WalletID+":"+e.TxHash)
+	}
+	return nil
+}
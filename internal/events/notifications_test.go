@@ -0,0 +1,102 @@
+package events_test
+
+import (
+	"context"
+	"nnw_s/internal/events"
+	mock_notifications "nnw_s/internal/user/notifications/mocks"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/notificator"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationSubscriber(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx := context.Background()
+
+	notificationsSvc := mock_notifications.NewMockService(controller)
+
+	subscriber, err := events.NewNotificationSubscriber(notificationsSvc)
+	require.Nil(t, err)
+
+	store, err := eventbus.NewMemoryStore()
+	require.Nil(t, err)
+
+	bus, err := eventbus.NewBus(logrus.New(), store)
+	require.Nil(t, err)
+	bus.Register(events.Factories()...)
+	subscriber.Subscribe(bus)
+
+	t.Run("should alert user about new login", func(t *testing.T) {
+		now := time.Unix(1640995200, 0).UTC()
+		data := &notificator.NewLoginData{IP: "203.0.113.7", UserAgent: "Mozilla/5.0", Time: now}
+		notificationsSvc.EXPECT().Notify(gomock.Any(), "user@example.com", data, "new_login:user@example.com:1640995200").Return(nil)
+
+		bus.Publish(ctx, &events.UserLoggedIn{Email: "user@example.com", IP: "203.0.113.7", UserAgent: "Mozilla/5.0", Time: now})
+	})
+
+	t.Run("should notify user about changed password", func(t *testing.T) {
+		now := time.Unix(1640995200, 0).UTC()
+		notificationsSvc.EXPECT().Notify(gomock.Any(), "user@example.com", &notificator.PasswordChangedData{Time: now}, "password_changed:user@example.com:1640995200").Return(nil)
+
+		bus.Publish(ctx, &events.PasswordReset{Email: "user@example.com", Time: now})
+	})
+
+	t.Run("should notify user about sent transaction", func(t *testing.T) {
+		data := &notificator.TxSentData{Chain: "BTC", Amount: "0.5", To: "tb1qreceiver", TxHash: "hash"}
+		notificationsSvc.EXPECT().Notify(gomock.Any(), "user@example.com", data, "tx_sent:BTC:hash").Return(nil)
+
+		bus.Publish(ctx, &events.TransactionBroadcast{
+			Email:  "user@example.com",
+			Chain:  "BTC",
+			From:   "tb1qsender",
+			To:     "tb1qreceiver",
+			Amount: "0.5",
+			TxHash: "hash",
+		})
+	})
+
+	t.Run("should notify user about received deposit", func(t *testing.T) {
+		data := &notificator.TxReceivedData{Chain: "BTC", Amount: "0.25", From: "tb1qsender", TxHash: "deposit"}
+		notificationsSvc.EXPECT().Notify(gomock.Any(), "user@example.com", data, "tx_received:BTC:wallet_id:deposit").Return(nil)
+
+		bus.Publish(ctx, &events.DepositReceived{
+			Email:    "user@example.com",
+			WalletID: "wallet_id",
+			Chain:    "BTC",
+			Address:  "tb1qreceiver",
+			From:     "tb1qsender",
+			Amount:   "0.25",
+			TxHash:   "deposit",
+		})
+	})
+
+	t.Run("should not notify about events without notification", func(t *testing.T) {
+		bus.Publish(ctx, &events.UserRegistered{Email: "user@example.com", Locale: "en"})
+		bus.Publish(ctx, &events.WalletCreated{Email: "user@example.com", Chain: "BTC", Address: "tb1qsender"})
+		bus.Publish(ctx, &events.TransactionConfirmed{Email: "user@example.com", Chain: "BTC", TxHash: "deposit", Confirmations: 6})
+	})
+
+	t.Run("should notify with the same idempotency key on replay", func(t *testing.T) {
+		notificationsSvc.EXPECT().Notify(gomock.Any(), "user@example.com", gomock.Any(), "new_login:user@example.com:1640995200").Return(nil)
+		notificationsSvc.EXPECT().Notify(gomock.Any(), "user@example.com", gomock.Any(), "password_changed:user@example.com:1640995200").Return(nil)
+		notificationsSvc.EXPECT().Notify(gomock.Any(), "user@example.com", gomock.Any(), "tx_sent:BTC:hash").Return(nil)
+		notificationsSvc.EXPECT().Notify(gomock.Any(), "user@example.com", gomock.Any(), "tx_received:BTC:wallet_id:deposit").Return(nil)
+
+		replayed, err := bus.Replay(ctx, &eventbus.ReplayFilter{Subscriber: "notifications"})
+		require.Nil(t, err)
+		assert.Equal(t, 7, replayed)
+	})
+}
+
+func TestNewNotificationSubscriber(t *testing.T) {
+	_, err := events.NewNotificationSubscriber(nil)
+	assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid notifications service")
+}
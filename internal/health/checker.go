@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultTimeout limits time of every check
+const defaultTimeout = 5 * time.Second
+
+type Status string
+
+const (
+	StatusUp Status = "up"
+	// StatusDegraded is a failure of optional dependency, the server still serves requests
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+	// StatusDraining is a status of server which is shutting down and doesn't accept new requests
+	StatusDraining Status = "draining"
+)
+
+// Check returns an error if dependency is not available
+type Check func(ctx context.Context) error
+
+// Result is a status of one dependency
+type Result struct {
+	Status   Status `json:"status"`
+	Optional bool   `json:"optional,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Latency  string `json:"latency"`
+}
+
+// Report is a readiness of the server with statuses of its dependencies
+type Report struct {
+	Status Status             `json:"status"`
+	Checks map[string]*Result `json:"checks,omitempty"`
+}
+
+type check struct {
+	name     string
+	check    Check
+	optional bool
+}
+
+// Checker checks dependencies of the server. Failure of required dependency makes server not ready,
+// failure of optional one only degrades it.
+type Checker struct {
+	timeout  time.Duration
+	checks   []check
+	draining int32
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Add adds required dependency, it should be called before the server is started
+func (c *Checker) Add(name string, fn Check) {
+	c.checks = append(c.checks, check{name: name, check: fn})
+}
+
+// AddOptional adds dependency which only some requests need, e.g. SMTP server or blockchain node
+func (c *Checker) AddOptional(name string, fn Check) {
+	c.checks = append(c.checks, check{name: name, check: fn, optional: true})
+}
+
+// Drain marks server as shutting down, so load balancer stops sending requests to it
+func (c *Checker) Drain() {
+	atomic.StoreInt32(&c.draining, 1)
+}
+
+// Check runs all checks concurrently, check which doesn't finish in time fails
+func (c *Checker) Check(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]*Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i := range c.checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = run(ctx, c.checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusUp, Checks: make(map[string]*Result, len(c.checks))}
+	for i, ch := range c.checks {
+		report.Checks[ch.name] = results[i]
+		if results[i].Status == StatusUp {
+			continue
+		}
+		if !ch.optional {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	if atomic.LoadInt32(&c.draining) == 1 {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// run runs the check and stops waiting for it when ctx is done, e.g. for clients which don't accept ctx
+func run(ctx context.Context, ch check) *Result {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- ch.check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := &Result{Status: StatusUp, Optional: ch.optional, Latency: time.Since(start).Round(time.Millisecond).String()}
+	if err != nil {
+		res.Status, res.Error = StatusDown, err.Error()
+	}
+	return res
+}
@@ -0,0 +1,89 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"nnw_s/internal/health"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(c *health.Checker)
+		status   health.Status
+		failures map[string]string
+	}{
+		{
+			name: "should be up if all dependencies are up",
+			setup: func(c *health.Checker) {
+				c.Add("mongo", ok)
+				c.AddOptional("smtp", ok)
+			},
+			status: health.StatusUp,
+		},
+		{
+			name: "should be degraded if optional dependency is down",
+			setup: func(c *health.Checker) {
+				c.Add("mongo", ok)
+				c.AddOptional("smtp", fail)
+			},
+			status:   health.StatusDegraded,
+			failures: map[string]string{"smtp": "connection refused"},
+		},
+		{
+			name: "should be down if required dependency is down",
+			setup: func(c *health.Checker) {
+				c.Add("mongo", fail)
+				c.AddOptional("smtp", fail)
+			},
+			status:   health.StatusDown,
+			failures: map[string]string{"mongo": "connection refused", "smtp": "connection refused"},
+		},
+		{
+			name: "should fail check which doesn't finish in time",
+			setup: func(c *health.Checker) {
+				c.Add("mongo", ok)
+				c.AddOptional("btc_node", hang)
+			},
+			status:   health.StatusDegraded,
+			failures: map[string]string{"btc_node": context.DeadlineExceeded.Error()},
+		},
+		{
+			name: "should be draining on shutdown",
+			setup: func(c *health.Checker) {
+				c.Add("mongo", ok)
+				c.Drain()
+			},
+			status: health.StatusDraining,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := health.NewChecker(50 * time.Millisecond)
+			tc.setup(checker)
+
+			report := checker.Check(context.Background())
+			assert.Equal(t, tc.status, report.Status)
+			for name, res := range report.Checks {
+				if msg, ok := tc.failures[name]; ok {
+					assert.Equal(t, health.StatusDown, res.Status, name)
+					assert.Equal(t, msg, res.Error, name)
+				} else {
+					assert.Equal(t, health.StatusUp, res.Status, name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	checker *Checker
+}
+
+func NewHandler(checker *Checker) *Handler {
+	return &Handler{checker: checker}
+}
+
+func (h *Handler) SetupRoutes(router *echo.Echo) {
+	// Liveness of the process, dependencies are not checked so failure of database doesn't restart the server
+	router.GET("/healthz", h.liveness)
+	// Readiness to serve requests
+	router.GET("/readyz", h.readiness)
+}
+
+func (h *Handler) liveness(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, &Report{Status: StatusUp})
+}
+
+func (h *Handler) readiness(ctx echo.Context) error {
+	report := h.checker.Check(ctx.Request().Context())
+
+	code := http.StatusOK
+	if report.Status == StatusDown || report.Status == StatusDraining {
+		code = http.StatusServiceUnavailable
+	}
+	return ctx.JSON(code, report)
+}
@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	messagesCollection = "stream_messages"
+	// messagesTTL is how long messages are kept for change streams which resume after failure
+	messagesTTL = time.Hour
+)
+
+// Broker passes messages between server instances, every instance gets every published message
+type Broker interface {
+	Publish(ctx context.Context, envelope *Envelope) error
+	// Listen passes published messages to deliver until ctx is done or the broker fails
+	Listen(ctx context.Context, deliver func(*Envelope)) error
+}
+
+// memoryBroker is a broker of a single server instance
+type memoryBroker struct {
+	mu        sync.RWMutex
+	listeners map[int]func(*Envelope)
+	nextID    int
+}
+
+func NewMemoryBroker() (Broker, error) {
+	return &memoryBroker{listeners: make(map[int]func(*Envelope))}, nil
+}
+
+func (b *memoryBroker) Publish(_ context.Context, envelope *Envelope) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, deliver := range b.listeners {
+		deliver(envelope)
+	}
+	return nil
+}
+
+func (b *memoryBroker) Listen(ctx context.Context, deliver func(*Envelope)) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.listeners[id] = deliver
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.listeners, id)
+	b.mu.Unlock()
+	return nil
+}
+
+// mongoBroker passes messages through a collection which every instance watches by change stream,
+// change streams require replica set or sharded cluster
+type mongoBroker struct {
+	db  *mongo.Database
+	log *logrus.Logger
+}
+
+func NewMongoBroker(db *mongo.Database, log *logrus.Logger) (Broker, error) {
+	if db == nil {
+		return nil, errors.NewInternal("invalid db")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &mongoBroker{db: db, log: log}, nil
+}
+
+// CreateIndexes creates TTL index, so delivered messages are removed
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	return migrations.SetTTL(ctx, db.Collection(messagesCollection), "created_at", messagesTTL)
+}
+
+func (b *mongoBroker) Publish(ctx context.Context, envelope *Envelope) error {
+	if _, err := b.db.Collection(messagesCollection).InsertOne(ctx, envelope); err != nil {
+		b.log.WithContext(ctx).Errorf("failed to publish stream message: %v", err)
+		return errors.NewInternal(err.Error())
+	}
+	return nil
+}
+
+func (b *mongoBroker) Listen(ctx context.Context, deliver func(*Envelope)) error {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	cs, err := b.db.Collection(messagesCollection).Watch(ctx, pipeline)
+	if err != nil {
+		return errors.NewInternal(err.Error())
+	}
+	defer cs.Close(context.Background())
+
+	for cs.Next(ctx) {
+		var change struct {
+			Envelope Envelope `bson:"fullDocument"`
+		}
+		if err = cs.Decode(&change); err != nil {
+			b.log.WithContext(ctx).Errorf("failed to decode stream message: %v", err)
+			continue
+		}
+		deliver(&change.Envelope)
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return errors.NewInternal(cs.Err().Error())
+}
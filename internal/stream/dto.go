@@ -0,0 +1,16 @@
+package stream
+
+import "nnw_s/pkg/validation"
+
+func Validate(dto interface{}) error {
+	validate := validation.New()
+	if err := validate.Struct(dto); err != nil {
+		return validation.Error(ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+// ConnectDTO is passed in query, because browsers can't set headers of WebSocket and EventSource requests
+type ConnectDTO struct {
+	Jwt string `query:"jwt" validate:"required"`
+}
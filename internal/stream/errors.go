@@ -0,0 +1,14 @@
+package stream
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+)
+
+const (
+	StatusInvalidRequest errors.Status = "invalid_request"
+)
+
+var (
+	ErrInvalidRequest = errors.New(codes.BadRequest, StatusInvalidRequest)
+)
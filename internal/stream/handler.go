@@ -0,0 +1,150 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"nnw_s/internal/auth/jwt"
+	"nnw_s/pkg/errors"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+// pingInterval is an interval of ping messages of idle connection
+const pingInterval = 30 * time.Second
+
+type Handler struct {
+	hub    *Hub
+	jwtSvc jwt.Service
+}
+
+func NewHandler(hub *Hub, jwtSvc jwt.Service) *Handler {
+	return &Handler{
+		hub:    hub,
+		jwtSvc: jwtSvc,
+	}
+}
+
+func (h *Handler) SetupRoutes(router *echo.Echo) {
+	v1 := router.Group("/api/v1")
+
+	// Real-time updates, Server-Sent Events are for clients which can't use WebSocket
+	v1.GET("/stream/ws", h.webSocket)
+	v1.GET("/stream/sse", h.serverSentEvents)
+}
+
+// authorize returns payload of token passed in query
+func (h *Handler) authorize(ctx echo.Context) (*jwt.Payload, error) {
+	var dto ConnectDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return nil, errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return nil, err
+	}
+
+	return h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+}
+
+func (h *Handler) webSocket(ctx echo.Context) error {
+	jwtPayload, err := h.authorize(ctx)
+	if err != nil {
+		return err
+	}
+
+	// connection is authorized by token, not by cookies, so requests of other origins are accepted
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		// messages of client are not expected, read fails when connection is closed
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var msg string
+			for websocket.Message.Receive(conn, &msg) == nil {
+			}
+		}()
+
+		h.stream(conn.Request().Context(), jwtPayload, closed,
+			func(envelope *Envelope) error {
+				return websocket.Message.Send(conn, envelope.Payload)
+			},
+			func() error {
+				return websocket.JSON.Send(conn, &Message{Type: TypePing, Time: time.Now().UTC()})
+			},
+		)
+	}}
+	server.ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
+
+func (h *Handler) serverSentEvents(ctx echo.Context) error {
+	jwtPayload, err := h.authorize(ctx)
+	if err != nil {
+		return err
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	// disables buffering of nginx
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	write := func(format string, args ...interface{}) error {
+		if _, err := fmt.Fprintf(res, format, args...); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	}
+
+	h.stream(ctx.Request().Context(), jwtPayload, nil,
+		func(envelope *Envelope) error {
+			return write("event: %s\ndata: %s\n\n", envelope.Type, envelope.Payload)
+		},
+		func() error {
+			return write(": %s\n\n", TypePing)
+		},
+	)
+	return nil
+}
+
+// stream sends messages of the user until connection is closed, token of the user expires or hub is closed
+func (h *Handler) stream(ctx context.Context, jwtPayload *jwt.Payload, closed <-chan struct{}, send func(*Envelope) error, ping func() error) {
+	sub := h.hub.Subscribe(jwtPayload.Email)
+	defer sub.Close()
+
+	pings := time.NewTicker(pingInterval)
+	defer pings.Stop()
+
+	expired := time.NewTimer(time.Until(jwtPayload.ExpiredAt))
+	defer expired.Stop()
+
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-closed:
+			return
+		case <-sub.Done():
+			return
+		case <-expired.C:
+			return
+		case envelope := <-sub.C():
+			err = send(envelope)
+		case <-pings.C:
+			err = ping()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
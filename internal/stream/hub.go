@@ -0,0 +1,156 @@
+package stream
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// subscriptionBuffer is a number of messages waiting for slow connection, next messages are dropped
+	subscriptionBuffer = 32
+	// listenRetryDelay is a delay before listening to failed broker again
+	listenRetryDelay = 5 * time.Second
+)
+
+// Hub fans out messages to connections of users. Messages published by any server instance are passed
+// by Broker to connections of every instance.
+type Hub struct {
+	broker Broker
+
+	mu   sync.RWMutex
+	subs map[string]map[*Subscription]struct{}
+
+	// done is closed when the hub is closed on shutdown
+	done      chan struct{}
+	closeOnce sync.Once
+
+	log *logrus.Logger
+}
+
+// Subscription is a connection of the user to the hub
+type Subscription struct {
+	email string
+	c     chan *Envelope
+	hub   *Hub
+	once  sync.Once
+}
+
+func NewHub(log *logrus.Logger, broker Broker) (*Hub, error) {
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	if broker == nil {
+		return nil, errors.NewInternal("invalid broker")
+	}
+	return &Hub{
+		broker: broker,
+		subs:   make(map[string]map[*Subscription]struct{}),
+		done:   make(chan struct{}),
+		log:    log,
+	}, nil
+}
+
+// Run passes messages of the broker to connections until ctx is done
+func (h *Hub) Run(ctx context.Context) {
+	for {
+		if err := h.broker.Listen(ctx, h.deliver); err != nil {
+			h.log.WithContext(ctx).Errorf("failed to listen stream broker: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// Close ends all subscriptions, so streams of connections are finished before server shutdown
+func (h *Hub) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// Subscribe connects the user to the hub, subscription should be closed when connection is closed
+func (h *Hub) Subscribe(email string) *Subscription {
+	sub := &Subscription{email: email, c: make(chan *Envelope, subscriptionBuffer), hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subs[email] == nil {
+		h.subs[email] = make(map[*Subscription]struct{})
+	}
+	h.subs[email][sub] = struct{}{}
+	return sub
+}
+
+// Publish sends message to connections of the user on every server instance
+func (h *Hub) Publish(ctx context.Context, email string, msg *Message) error {
+	envelope, err := NewEnvelope(email, msg)
+	if err != nil {
+		return err
+	}
+	return h.broker.Publish(ctx, envelope)
+}
+
+// Send sends message to connections of the user on this server instance only
+func (h *Hub) Send(email string, msg *Message) error {
+	envelope, err := NewEnvelope(email, msg)
+	if err != nil {
+		return err
+	}
+	h.deliver(envelope)
+	return nil
+}
+
+// Connected returns emails of users connected to this server instance
+func (h *Hub) Connected() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	emails := make([]string, 0, len(h.subs))
+	for email := range h.subs {
+		emails = append(emails, email)
+	}
+	return emails
+}
+
+func (h *Hub) deliver(envelope *Envelope) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs[envelope.Email] {
+		select {
+		case sub.c <- envelope:
+		default:
+			h.log.Warnf("stream connection is too slow, '%s' message is dropped", envelope.Type)
+		}
+	}
+}
+
+// C returns messages of the subscription
+func (s *Subscription) C() <-chan *Envelope {
+	return s.c
+}
+
+// Done is closed when the hub is closed, connection should be finished then
+func (s *Subscription) Done() <-chan struct{} {
+	return s.hub.done
+}
+
+// Close disconnects the subscription from the hub
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.mu.Lock()
+		defer s.hub.mu.Unlock()
+
+		delete(s.hub.subs[s.email], s)
+		if len(s.hub.subs[s.email]) == 0 {
+			delete(s.hub.subs, s.email)
+		}
+	})
+}
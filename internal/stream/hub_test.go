@@ -0,0 +1,128 @@
+package stream_test
+
+import (
+	"context"
+	"encoding/json"
+	"nnw_s/internal/stream"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runHubs starts hubs which share one broker like server instances do
+func runHubs(t *testing.T, n int) []*stream.Hub {
+	broker, err := stream.NewMemoryBroker()
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	hubs := make([]*stream.Hub, 0, n)
+	for i := 0; i < n; i++ {
+		hub, err := stream.NewHub(logrus.New(), broker)
+		require.Nil(t, err)
+		go hub.Run(ctx)
+		hubs = append(hubs, hub)
+	}
+
+	// wait until every hub listens to the broker
+	probe := hubs[0].Subscribe("probe")
+	defer probe.Close()
+	require.Eventually(t, func() bool {
+		require.Nil(t, hubs[0].Publish(ctx, "probe", &stream.Message{Type: stream.TypePing}))
+		select {
+		case <-probe.C():
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+
+	return hubs
+}
+
+func receive(t *testing.T, sub *stream.Subscription) *stream.Message {
+	select {
+	case envelope := <-sub.C():
+		var msg stream.Message
+		require.Nil(t, json.Unmarshal([]byte(envelope.Payload), &msg))
+		assert.Equal(t, envelope.Type, msg.Type)
+		return &msg
+	case <-time.After(time.Second):
+		t.Fatal("message is not received")
+		return nil
+	}
+}
+
+func assertNoMessage(t *testing.T, sub *stream.Subscription) {
+	select {
+	case envelope := <-sub.C():
+		t.Fatalf("unexpected message: %s", envelope.Payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHub(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should publish message to connections of the user on every instance", func(t *testing.T) {
+		hubs := runHubs(t, 2)
+		first, second := hubs[0].Subscribe("alice@mail.com"), hubs[1].Subscribe("alice@mail.com")
+		other := hubs[1].Subscribe("bob@mail.com")
+		defer first.Close()
+		defer second.Close()
+		defer other.Close()
+
+		require.Nil(t, hubs[0].Publish(ctx, "alice@mail.com", &stream.Message{
+			Type: stream.TypeSecurity,
+			Data: &stream.SecurityData{Event: "password_reset"},
+		}))
+
+		for _, sub := range []*stream.Subscription{first, second} {
+			msg := receive(t, sub)
+			assert.Equal(t, stream.TypeSecurity, msg.Type)
+			assert.Equal(t, map[string]interface{}{"event": "password_reset"}, msg.Data)
+		}
+		assertNoMessage(t, other)
+	})
+
+	t.Run("should send message to connections of this instance only", func(t *testing.T) {
+		hubs := runHubs(t, 2)
+		local, remote := hubs[0].Subscribe("alice@mail.com"), hubs[1].Subscribe("alice@mail.com")
+		defer local.Close()
+		defer remote.Close()
+
+		require.Nil(t, hubs[0].Send("alice@mail.com", &stream.Message{Type: stream.TypeBalance}))
+
+		assert.Equal(t, stream.TypeBalance, receive(t, local).Type)
+		assertNoMessage(t, remote)
+	})
+
+	t.Run("should forget closed connections", func(t *testing.T) {
+		hub := runHubs(t, 1)[0]
+		sub := hub.Subscribe("alice@mail.com")
+		assert.Equal(t, []string{"alice@mail.com"}, hub.Connected())
+
+		sub.Close()
+		sub.Close()
+		assert.Empty(t, hub.Connected())
+		require.Nil(t, hub.Send("alice@mail.com", &stream.Message{Type: stream.TypeBalance}))
+		assertNoMessage(t, sub)
+	})
+
+	t.Run("should finish subscriptions when hub is closed", func(t *testing.T) {
+		hub := runHubs(t, 1)[0]
+		sub := hub.Subscribe("alice@mail.com")
+
+		hub.Close()
+		hub.Close()
+		select {
+		case <-sub.Done():
+		case <-time.After(time.Second):
+			t.Fatal("subscription is not finished")
+		}
+	})
+}
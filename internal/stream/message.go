@@ -0,0 +1,73 @@
+package stream
+
+import (
+	"encoding/json"
+	"nnw_s/pkg/errors"
+	"time"
+)
+
+// Types of messages pushed to connected users
+const (
+	TypeBalance       = "balance"
+	TypeTransaction   = "transaction"
+	TypeConfirmations = "confirmations"
+	TypeSecurity      = "security"
+	// TypePing keeps idle connections open behind proxies
+	TypePing = "ping"
+)
+
+type Message struct {
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// BalanceData is sent when balance of the wallet changes
+type BalanceData struct {
+	WalletID   string  `json:"wallet_id"`
+	Chain      string  `json:"chain"`
+	Address    string  `json:"address"`
+	Balance    float64 `json:"balance"`
+	BalanceStr string  `json:"balance_str"`
+	Unit       string  `json:"unit"`
+}
+
+// TransactionData is sent on new transaction of the wallet and on every new confirmation of it
+type TransactionData struct {
+	WalletID      string    `json:"wallet_id"`
+	Chain         string    `json:"chain"`
+	TxHash        string    `json:"tx_hash"`
+	Confirmations int64     `json:"confirmations"`
+	Time          time.Time `json:"time,omitempty"`
+}
+
+// SecurityData is sent on changes of account security, e.g. reset password or enabled 2FA
+type SecurityData struct {
+	Event string `json:"event"`
+}
+
+// Envelope is an encoded message of the user, it is passed between server instances by Broker
+type Envelope struct {
+	Email     string    `bson:"email"`
+	Type      string    `bson:"type"`
+	Payload   string    `bson:"payload"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func NewEnvelope(email string, msg *Message) (*Envelope, error) {
+	if msg.Time.IsZero() {
+		msg.Time = time.Now().UTC()
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return &Envelope{
+		Email:     email,
+		Type:      msg.Type,
+		Payload:   string(payload),
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,69 @@
+package stream
+
+import (
+	"context"
+	"nnw_s/internal/events"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+)
+
+const subscriberName = "stream"
+
+// Subscriber pushes security events and transactions of wallets to connections of the user
+type Subscriber struct {
+	hub *Hub
+}
+
+func NewSubscriber(hub *Hub) (*Subscriber, error) {
+	if hub == nil {
+		return nil, errors.NewInternal("invalid hub")
+	}
+	return &Subscriber{hub: hub}, nil
+}
+
+func (s *Subscriber) Subscribe(bus *eventbus.Bus) {
+	bus.Subscribe(subscriberName, s.handle,
+		events.NamePasswordReset,
+		events.NameTwoFAEnabled,
+		events.NameTransactionBroadcast,
+		events.NameDepositReceived,
+		events.NameTransactionConfirmed,
+	)
+}
+
+func (s *Subscriber) handle(ctx context.Context, event eventbus.Event) error {
+	msg := &Message{Type: TypeSecurity, Data: &SecurityData{Event: event.EventName()}}
+	if meta, ok := eventbus.MetaFromContext(ctx); ok {
+		msg.Time = meta.OccurredAt
+	}
+
+	var email string
+	switch e := event.(type) {
+	case *events.PasswordReset:
+		email = e.Email
+	case *events.TwoFAEnabled:
+		email = e.Email
+	case *events.TransactionBroadcast:
+		email = e.Email
+		msg.Type = TypeTransaction
+		msg.Data = &TransactionData{WalletID: e.WalletID, Chain: e.Chain, TxHash: e.TxHash, Time: msg.Time}
+	case *events.DepositReceived:
+		email = e.Email
+		msg.Type = TypeTransaction
+		msg.Data = &TransactionData{WalletID: e.WalletID, Chain: e.Chain, TxHash: e.TxHash, Time: msg.Time}
+	case *events.TransactionConfirmed:
+		email = e.Email
+		msg.Type = TypeConfirmations
+		msg.Data = &TransactionData{
+			WalletID:      e.WalletID,
+			Chain:         e.Chain,
+			TxHash:        e.TxHash,
+			Confirmations: e.Confirmations,
+			Time:          msg.Time,
+		}
+	default:
+		return nil
+	}
+
+	return s.hub.Publish(ctx, email, msg)
+}
@@ -0,0 +1,266 @@
+package stream
+
+import (
+	"context"
+	"nnw_s/internal/events"
+	"nnw_s/internal/user/wallet"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPollInterval  = 30 * time.Second
+	defaultConfirmations = 6
+)
+
+type WatcherOptions struct {
+	PollInterval time.Duration
+	// Confirmations is a number of confirmations after which transaction is confirmed and its updates are not sent
+	Confirmations int64
+}
+
+// Watcher checks balances and transactions of wallets of every user. Incoming and confirmed transactions
+// are published to the event bus, so they reach webhooks, notifications and connections of the user
+// through subscribers. Balances and confirmations are sent to connections of the user on every instance.
+// Only one server instance should run the watcher, otherwise every instance publishes the same changes.
+// States of wallets are kept in memory, so changes which happen while the watcher is stopped are not published.
+type Watcher struct {
+	hub       *Hub
+	walletSvc wallet.Service
+	eventBus  eventbus.Publisher
+	opts      WatcherOptions
+
+	// states are the last seen states of wallets by email of the owner
+	states map[string]map[string]*walletState
+
+	log *logrus.Logger
+}
+
+type walletState struct {
+	// balance is empty until balance is loaded first time
+	balance string
+	// txs are confirmations of transactions, it is nil until transactions are loaded first time
+	txs map[string]int64
+}
+
+func NewWatcher(log *logrus.Logger, hub *Hub, walletSvc wallet.Service, eventBus eventbus.Publisher, opts WatcherOptions) (*Watcher, error) {
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	if hub == nil {
+		return nil, errors.NewInternal("invalid hub")
+	}
+	if walletSvc == nil {
+		return nil, errors.NewInternal("invalid wallet service")
+	}
+	if eventBus == nil {
+		return nil, errors.NewInternal("invalid event bus")
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.Confirmations <= 0 {
+		opts.Confirmations = defaultConfirmations
+	}
+
+	return &Watcher{
+		hub:       hub,
+		walletSvc: walletSvc,
+		eventBus:  eventBus,
+		opts:      opts,
+		states:    make(map[string]map[string]*walletState),
+		log:       log,
+	}, nil
+}
+
+// Run checks wallets until ctx is done
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks every wallet once, state of archived wallets is forgotten
+func (w *Watcher) Poll(ctx context.Context) {
+	watched, err := w.walletSvc.WatchedWallets(ctx)
+	if err != nil {
+		w.log.WithContext(ctx).Errorf("failed to get watched wallets: %v", err)
+		return
+	}
+
+	for email, wallets := range watched {
+		states := make(map[string]*walletState, len(wallets))
+		for _, walletDTO := range wallets {
+			state, ok := w.states[email][walletDTO.WalletId]
+			if !ok {
+				state = &walletState{}
+			}
+			states[walletDTO.WalletId] = state
+			w.pollWallet(ctx, email, walletDTO, state)
+		}
+		w.states[email] = states
+	}
+
+	for email := range w.states {
+		if _, ok := watched[email]; !ok {
+			delete(w.states, email)
+		}
+	}
+}
+
+func (w *Watcher) pollWallet(ctx context.Context, email string, walletDTO *wallet.WalletDTO, state *walletState) {
+	if err := w.pollBalance(ctx, email, walletDTO, state); err != nil {
+		w.log.WithContext(ctx).Errorf("failed to get balance of wallet '%s': %v", walletDTO.WalletId, err)
+	}
+	if err := w.pollTxs(ctx, email, walletDTO, state); err != nil {
+		w.log.WithContext(ctx).Errorf("failed to get transactions of wallet '%s': %v", walletDTO.WalletId, err)
+	}
+}
+
+// pollBalance sends balance when it changes, current balance is loaded by client itself
+func (w *Watcher) pollBalance(ctx context.Context, email string, walletDTO *wallet.WalletDTO, state *walletState) error {
+	balance, err := w.walletSvc.GetBalance(ctx, &wallet.GetWalletBalanceDTO{
+		Name:     walletDTO.Chain,
+		WalletId: walletDTO.WalletId,
+		Address:  walletDTO.Address,
+	}, email)
+	if err != nil {
+		return err
+	}
+
+	previous := state.balance
+	state.balance = balance.BalanceStr
+	if previous == "" || previous == balance.BalanceStr {
+		return nil
+	}
+
+	return w.hub.Publish(ctx, email, &Message{Type: TypeBalance, Data: &BalanceData{
+		WalletID:   walletDTO.WalletId,
+		Chain:      walletDTO.Chain,
+		Address:    walletDTO.Address,
+		Balance:    balance.Balance,
+		BalanceStr: balance.BalanceStr,
+		Unit:       balance.Unit,
+	}})
+}
+
+// pollTxs publishes transactions which appear after the first poll and their confirmations
+func (w *Watcher) pollTxs(ctx context.Context, email string, walletDTO *wallet.WalletDTO, state *walletState) error {
+	txs, err := w.walletSvc.GetWalletTx(ctx, &wallet.GetWalletTxDTO{
+		Name:     walletDTO.Chain,
+		WalletId: walletDTO.WalletId,
+		Address:  walletDTO.Address,
+	}, email)
+	if err != nil {
+		return err
+	}
+
+	// history of the wallet is loaded by client itself, so transactions of the first poll are not published
+	initialized := state.txs != nil
+	if !initialized {
+		state.txs = make(map[string]int64)
+	}
+
+	for _, tx := range txs {
+		confirmations, known := state.txs[tx.Txid]
+		state.txs[tx.Txid] = tx.Confirmations
+		if !initialized || (known && (tx.Confirmations == confirmations || confirmations >= w.opts.Confirmations)) {
+			continue
+		}
+
+		if !known {
+			if err = w.publishNewTx(ctx, email, walletDTO, tx); err != nil {
+				return err
+			}
+		}
+
+		if tx.Confirmations >= w.opts.Confirmations {
+			w.eventBus.Publish(ctx, &events.TransactionConfirmed{
+				Email:         email,
+				WalletID:      walletDTO.WalletId,
+				Chain:         walletDTO.Chain,
+				TxHash:        tx.Txid,
+				Confirmations: tx.Confirmations,
+			})
+			continue
+		}
+
+		if known {
+			if err = w.hub.Publish(ctx, email, &Message{Type: TypeConfirmations, Data: &TransactionData{
+				WalletID:      walletDTO.WalletId,
+				Chain:         walletDTO.Chain,
+				TxHash:        tx.Txid,
+				Confirmations: tx.Confirmations,
+				Time:          tx.Time,
+			}}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// publishNewTx publishes deposit if the wallet receives the transaction, other transactions,
+// e.g. sent by another client of the wallet, are only sent to connections of the user
+func (w *Watcher) publishNewTx(ctx context.Context, email string, walletDTO *wallet.WalletDTO, tx *wallet.TxsDTO) error {
+	if deposit := newDeposit(email, walletDTO, tx); deposit != nil {
+		w.eventBus.Publish(ctx, deposit)
+		return nil
+	}
+
+	return w.hub.Publish(ctx, email, &Message{Type: TypeTransaction, Data: &TransactionData{
+		WalletID:      walletDTO.WalletId,
+		Chain:         walletDTO.Chain,
+		TxHash:        tx.Txid,
+		Confirmations: tx.Confirmations,
+		Time:          tx.Time,
+	}})
+}
+
+// newDeposit returns deposit of the transaction, it is nil if the transaction doesn't pay to the wallet
+// or spends its outputs, i.e. it is sent by the wallet and its outputs to the wallet are change
+func newDeposit(email string, walletDTO *wallet.WalletDTO, tx *wallet.TxsDTO) *events.DepositReceived {
+	var from string
+	for _, in := range tx.Input {
+		if in.Address == walletDTO.Address {
+			return nil
+		}
+		if from == "" {
+			from = in.Address
+		}
+	}
+
+	var amount float64
+	for _, out := range tx.Output {
+		if out.Address == walletDTO.Address {
+			amount += out.Value
+		}
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	return &events.DepositReceived{
+		Email:    email,
+		WalletID: walletDTO.WalletId,
+		Chain:    walletDTO.Chain,
+		Address:  walletDTO.Address,
+		From:     from,
+		Amount:   strconv.FormatFloat(amount, 'f', -1, 64),
+		TxHash:   tx.Txid,
+	}
+}
@@ -0,0 +1,159 @@
+package stream_test
+
+import (
+	"context"
+	"nnw_s/internal/events"
+	"nnw_s/internal/stream"
+	"nnw_s/internal/user/wallet"
+	mock_wallet "nnw_s/internal/user/wallet/mocks"
+	"nnw_s/pkg/eventbus"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx := context.Background()
+	email := "alice@mail.com"
+	btcWallet := &wallet.WalletDTO{WalletId: "wallet_id", Chain: "BTC", Address: "tb1qalice"}
+
+	// published records events published by the watcher
+	var published []eventbus.Event
+
+	setup := func(t *testing.T) (*stream.Watcher, *mock_wallet.MockService, *stream.Subscription) {
+		published = nil
+		hub := runHubs(t, 1)[0]
+		walletSvc := mock_wallet.NewMockService(controller)
+
+		bus, err := eventbus.NewBus(logrus.New(), nil)
+		require.Nil(t, err)
+		subscriber, err := stream.NewSubscriber(hub)
+		require.Nil(t, err)
+		subscriber.Subscribe(bus)
+		bus.Subscribe("test", func(_ context.Context, event eventbus.Event) error {
+			published = append(published, event)
+			return nil
+		})
+
+		watcher, err := stream.NewWatcher(logrus.New(), hub, walletSvc, bus, stream.WatcherOptions{Confirmations: 2})
+		require.Nil(t, err)
+
+		sub := hub.Subscribe(email)
+		t.Cleanup(sub.Close)
+		return watcher, walletSvc, sub
+	}
+
+	expectPoll := func(walletSvc *mock_wallet.MockService, balance string, txs ...*wallet.TxsDTO) {
+		walletSvc.EXPECT().WatchedWallets(ctx).Return(map[string][]*wallet.WalletDTO{email: {btcWallet}}, nil)
+		walletSvc.EXPECT().GetBalance(ctx, &wallet.GetWalletBalanceDTO{Name: "BTC", WalletId: "wallet_id", Address: "tb1qalice"}, email).
+			Return(&wallet.BalanceDTO{BalanceStr: balance, Unit: "BTC"}, nil)
+		walletSvc.EXPECT().GetWalletTx(ctx, &wallet.GetWalletTxDTO{Name: "BTC", WalletId: "wallet_id", Address: "tb1qalice"}, email).
+			Return(txs, nil)
+	}
+
+	deposit := func(confirmations int64) *wallet.TxsDTO {
+		return &wallet.TxsDTO{
+			Txid:          "deposit",
+			Input:         []*wallet.InputTxDTO{{Address: "tb1qbob", Value: 1}},
+			Output:        []*wallet.OutTxDTO{{Address: "tb1qalice", Value: 0.25}, {Address: "tb1qbob", Value: 0.7}},
+			Confirmations: confirmations,
+		}
+	}
+
+	t.Run("should send balance on change", func(t *testing.T) {
+		watcher, walletSvc, sub := setup(t)
+
+		expectPoll(walletSvc, "0.5 BTC")
+		watcher.Poll(ctx)
+		assertNoMessage(t, sub)
+
+		expectPoll(walletSvc, "0.5 BTC")
+		watcher.Poll(ctx)
+		assertNoMessage(t, sub)
+
+		expectPoll(walletSvc, "0.7 BTC")
+		watcher.Poll(ctx)
+		msg := receive(t, sub)
+		assert.Equal(t, stream.TypeBalance, msg.Type)
+		assert.Equal(t, "0.7 BTC", msg.Data.(map[string]interface{})["balance_str"])
+	})
+
+	t.Run("should publish deposit and its confirmation", func(t *testing.T) {
+		watcher, walletSvc, sub := setup(t)
+
+		expectPoll(walletSvc, "0.5 BTC", &wallet.TxsDTO{Txid: "old", Confirmations: 100})
+		watcher.Poll(ctx)
+		assert.Empty(t, published)
+
+		expectPoll(walletSvc, "0.5 BTC", &wallet.TxsDTO{Txid: "old", Confirmations: 101}, deposit(0))
+		watcher.Poll(ctx)
+		require.Equal(t, []eventbus.Event{&events.DepositReceived{
+			Email:    email,
+			WalletID: "wallet_id",
+			Chain:    "BTC",
+			Address:  "tb1qalice",
+			From:     "tb1qbob",
+			Amount:   "0.25",
+			TxHash:   "deposit",
+		}}, published)
+		msg := receive(t, sub)
+		assert.Equal(t, stream.TypeTransaction, msg.Type)
+		assert.Equal(t, "deposit", msg.Data.(map[string]interface{})["tx_hash"])
+
+		expectPoll(walletSvc, "0.5 BTC", deposit(1))
+		watcher.Poll(ctx)
+		msg = receive(t, sub)
+		assert.Equal(t, stream.TypeConfirmations, msg.Type)
+		assert.EqualValues(t, 1, msg.Data.(map[string]interface{})["confirmations"])
+		assert.Len(t, published, 1)
+
+		expectPoll(walletSvc, "0.5 BTC", deposit(2))
+		watcher.Poll(ctx)
+		require.Len(t, published, 2)
+		assert.Equal(t, &events.TransactionConfirmed{Email: email, WalletID: "wallet_id", Chain: "BTC", TxHash: "deposit", Confirmations: 2}, published[1])
+		msg = receive(t, sub)
+		assert.Equal(t, stream.TypeConfirmations, msg.Type)
+		assert.EqualValues(t, 2, msg.Data.(map[string]interface{})["confirmations"])
+
+		// transaction is confirmed, so its updates are not sent anymore
+		expectPoll(walletSvc, "0.5 BTC", deposit(3))
+		watcher.Poll(ctx)
+		assertNoMessage(t, sub)
+		assert.Len(t, published, 2)
+	})
+
+	t.Run("should not publish deposit for transaction sent by the wallet", func(t *testing.T) {
+		watcher, walletSvc, sub := setup(t)
+
+		expectPoll(walletSvc, "0.5 BTC")
+		watcher.Poll(ctx)
+
+		sent := &wallet.TxsDTO{
+			Txid:   "sent",
+			Input:  []*wallet.InputTxDTO{{Address: "tb1qalice", Value: 0.5}},
+			Output: []*wallet.OutTxDTO{{Address: "tb1qbob", Value: 0.2}, {Address: "tb1qalice", Value: 0.29}},
+		}
+		expectPoll(walletSvc, "0.5 BTC", sent)
+		watcher.Poll(ctx)
+		assert.Empty(t, published)
+		assert.Equal(t, stream.TypeTransaction, receive(t, sub).Type)
+	})
+
+	t.Run("should watch wallets of users who are not connected", func(t *testing.T) {
+		watcher, walletSvc, sub := setup(t)
+		sub.Close()
+
+		expectPoll(walletSvc, "0.5 BTC")
+		watcher.Poll(ctx)
+
+		expectPoll(walletSvc, "0.5 BTC", deposit(0))
+		watcher.Poll(ctx)
+		assert.Len(t, published, 1)
+	})
+}
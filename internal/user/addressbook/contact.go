@@ -0,0 +1,103 @@
+package addressbook
+
+import (
+	stderrors "errors"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/wallet"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ChainBTC = "BTC"
+	ChainETH = "ETH"
+)
+
+type Address struct {
+	Chain   string `bson:"chain"`
+	Address string `bson:"address"`
+}
+
+// Validate checks that address has a valid format of its chain, BTC address should be of the network of node
+func (a *Address) Validate(btcNetwork *wallet.BTCNetwork) error {
+	switch a.Chain {
+	case ChainBTC:
+		if _, err := btcNetwork.DecodeAddress(a.Address); err != nil {
+			if stderrors.Is(err, wallet.ErrWrongNetwork) {
+				return errors.WithMessage(ErrWrongNetwork, "BTC address of another network: "+a.Address+", expected "+btcNetwork.Name)
+			}
+			return errors.WithMessage(ErrInvalidAddress, "invalid BTC address: "+a.Address)
+		}
+	case ChainETH:
+		if !common.IsHexAddress(a.Address) {
+			return errors.WithMessage(ErrInvalidAddress, "invalid ETH address: "+a.Address)
+		}
+	default:
+		return errors.WithMessage(ErrInvalidAddress, "unsupported chain: "+a.Chain)
+	}
+	return nil
+}
+
+type Contact struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	UserID    string             `bson:"user_id"`
+	Name      string             `bson:"name"`
+	Addresses []*Address         `bson:"addresses"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func NewContact(userID, name string, addresses []*Address, btcNetwork *wallet.BTCNetwork) (*Contact, error) {
+	if userID == "" {
+		return nil, errors.WithMessage(ErrInvalidContact, "user id should be not empty")
+	}
+
+	contact := &Contact{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}
+	if err := contact.Update(name, addresses, btcNetwork); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// Update replaces contact's name and addresses, there can be only one address per chain
+func (c *Contact) Update(name string, addresses []*Address, btcNetwork *wallet.BTCNetwork) error {
+	if name == "" {
+		return errors.WithMessage(ErrInvalidContact, "name should be not empty")
+	}
+	if len(addresses) == 0 {
+		return errors.WithMessage(ErrInvalidContact, "at least one address is required")
+	}
+
+	chains := make(map[string]bool, len(addresses))
+	for _, a := range addresses {
+		if err := a.Validate(btcNetwork); err != nil {
+			return err
+		}
+		if chains[a.Chain] {
+			return errors.WithMessage(ErrInvalidContact, "only one address per chain is allowed: "+a.Chain)
+		}
+		chains[a.Chain] = true
+	}
+
+	c.Name = name
+	c.Addresses = addresses
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// Address returns contact's address of the chain
+func (c *Contact) Address(chain string) (string, error) {
+	for _, a := range c.Addresses {
+		if a.Chain == chain {
+			return a.Address, nil
+		}
+	}
+	return "", errors.WithMessage(ErrAddressNotFound, "contact has no "+chain+" address")
+}
@@ -0,0 +1,104 @@
+package addressbook_test
+
+import (
+	"nnw_s/internal/user/addressbook"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/wallet"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testBTCAddress = "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"
+	testETHAddress = "0x52908400098527886E0F7030069857D2E4169EE7"
+)
+
+var testNetwork, _ = wallet.NewBTCNetwork(wallet.BTCTestnet)
+
+func TestAddressValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		address *addressbook.Address
+		status  errors.Status
+	}{
+		{
+			name:    "should accept testnet BTC address",
+			address: &addressbook.Address{Chain: addressbook.ChainBTC, Address: testBTCAddress},
+		},
+		{
+			name:    "should accept legacy testnet BTC address",
+			address: &addressbook.Address{Chain: addressbook.ChainBTC, Address: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"},
+		},
+		{
+			name:    "should reject BTC address of another network",
+			address: &addressbook.Address{Chain: addressbook.ChainBTC, Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+			status:  addressbook.StatusWrongNetwork,
+		},
+		{
+			name:    "should reject ETH address as BTC address",
+			address: &addressbook.Address{Chain: addressbook.ChainBTC, Address: testETHAddress},
+			status:  addressbook.StatusInvalidAddress,
+		},
+		{
+			name:    "should accept ETH address",
+			address: &addressbook.Address{Chain: addressbook.ChainETH, Address: testETHAddress},
+		},
+		{
+			name:    "should reject malformed ETH address",
+			address: &addressbook.Address{Chain: addressbook.ChainETH, Address: "0x5290840009852788"},
+			status:  addressbook.StatusInvalidAddress,
+		},
+		{
+			name:    "should reject unsupported chain",
+			address: &addressbook.Address{Chain: "DOGE", Address: testBTCAddress},
+			status:  addressbook.StatusInvalidAddress,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.address.Validate(testNetwork)
+			if tc.status != "" {
+				assert.Equal(t, tc.status, statusOf(err))
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+
+	t.Run("should validate BTC address with the configured network", func(t *testing.T) {
+		mainnet, err := wallet.NewBTCNetwork(wallet.BTCMainnet)
+		assert.Nil(t, err)
+
+		address := &addressbook.Address{Chain: addressbook.ChainBTC, Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"}
+		assert.Nil(t, address.Validate(mainnet))
+
+		address = &addressbook.Address{Chain: addressbook.ChainBTC, Address: testBTCAddress}
+		assert.Equal(t, addressbook.StatusWrongNetwork, statusOf(address.Validate(mainnet)))
+	})
+}
+
+func TestNewContact(t *testing.T) {
+	t.Run("should reject two addresses of one chain", func(t *testing.T) {
+		_, err := addressbook.NewContact("user_id", "Alice", []*addressbook.Address{
+			{Chain: addressbook.ChainETH, Address: testETHAddress},
+			{Chain: addressbook.ChainETH, Address: testETHAddress},
+		}, testNetwork)
+		assert.Equal(t, addressbook.StatusInvalidContact, statusOf(err))
+	})
+
+	t.Run("should return contact address of the chain", func(t *testing.T) {
+		c, err := addressbook.NewContact("user_id", "Alice", []*addressbook.Address{
+			{Chain: addressbook.ChainBTC, Address: testBTCAddress},
+		}, testNetwork)
+		assert.Nil(t, err)
+
+		address, err := c.Address(addressbook.ChainBTC)
+		assert.Nil(t, err)
+		assert.Equal(t, testBTCAddress, address)
+
+		_, err = c.Address(addressbook.ChainETH)
+		assert.Equal(t, addressbook.StatusAddressNotFound, statusOf(err))
+	})
+}
@@ -0,0 +1,58 @@
+package addressbook
+
+import (
+	"nnw_s/pkg/validation"
+	"time"
+)
+
+func Validate(dto interface{}) error {
+	validate := validation.New()
+	if err := validate.Struct(dto); err != nil {
+		return validation.Error(ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+type AddressDTO struct {
+	Chain   string `json:"chain" validate:"required,oneof=BTC ETH"`
+	Address string `json:"address" validate:"required"`
+}
+
+type ContactDTO struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Addresses []*AddressDTO `json:"addresses"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type GetContactsDTO struct {
+	Jwt string `json:"jwt" validate:"required"`
+}
+
+type GetContactDTO struct {
+	Jwt       string `json:"jwt" validate:"required"`
+	ContactID string `json:"contact_id" validate:"required"`
+}
+
+// CreateContactDTO requires 2FA code, so a stolen session is not enough to add a contact
+type CreateContactDTO struct {
+	Jwt       string        `json:"jwt" validate:"required"`
+	Name      string        `json:"name" validate:"required,max=64"`
+	Addresses []*AddressDTO `json:"addresses" validate:"required,min=1,dive,required"`
+	TwoFaCode string        `json:"two_fa_code" validate:"required"`
+}
+
+// UpdateContactDTO replaces contact's name and addresses, it requires 2FA code like CreateContactDTO
+type UpdateContactDTO struct {
+	Jwt       string        `json:"jwt" validate:"required"`
+	ContactID string        `json:"contact_id" validate:"required"`
+	Name      string        `json:"name" validate:"required,max=64"`
+	Addresses []*AddressDTO `json:"addresses" validate:"required,min=1,dive,required"`
+	TwoFaCode string        `json:"two_fa_code" validate:"required"`
+}
+
+type DeleteContactDTO struct {
+	Jwt       string `json:"jwt" validate:"required"`
+	ContactID string `json:"contact_id" validate:"required"`
+}
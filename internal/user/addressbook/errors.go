@@ -0,0 +1,28 @@
+package addressbook
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+)
+
+const (
+	StatusInvalidRequest       errors.Status = "invalid_request"
+	StatusInvalidContact       errors.Status = "invalid_contact"
+	StatusInvalidAddress       errors.Status = "invalid_contact_address"
+	StatusWrongNetwork         errors.Status = "wrong_network_address"
+	StatusContactNotFound      errors.Status = "contact_not_found"
+	StatusContactAlreadyExists errors.Status = "contact_already_exists"
+	StatusAddressNotFound      errors.Status = "contact_address_not_found"
+	StatusTwoFARequired        errors.Status = "two_fa_required"
+)
+
+var (
+	ErrInvalidRequest  = errors.New(codes.BadRequest, StatusInvalidRequest)
+	ErrInvalidContact  = errors.New(codes.BadRequest, StatusInvalidContact)
+	ErrInvalidAddress  = errors.New(codes.BadRequest, StatusInvalidAddress)
+	ErrWrongNetwork    = errors.New(codes.BadRequest, StatusWrongNetwork)
+	ErrNotFound        = errors.New(codes.NotFound, StatusContactNotFound)
+	ErrAlreadyExists   = errors.New(codes.DuplicateError, StatusContactAlreadyExists)
+	ErrAddressNotFound = errors.New(codes.BadRequest, StatusAddressNotFound)
+	ErrTwoFARequired   = errors.New(codes.Forbidden, StatusTwoFARequired)
+)
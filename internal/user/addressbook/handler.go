@@ -0,0 +1,151 @@
+package addressbook
+
+import (
+	"net/http"
+	"nnw_s/internal/auth/jwt"
+	"nnw_s/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	addressBookSvc Service
+	jwtSvc         jwt.Service
+}
+
+func NewHandler(addressBookSvc Service, jwtSvc jwt.Service) *Handler {
+	return &Handler{
+		addressBookSvc: addressBookSvc,
+		jwtSvc:         jwtSvc,
+	}
+}
+
+func (h *Handler) SetupRoutes(router *echo.Echo) {
+	v1 := router.Group("/api/v1")
+
+	// Address book
+	v1.POST("/get-contacts", h.getContacts)
+	v1.POST("/get-contact", h.getContact)
+	v1.POST("/create-contact", h.createContact)
+	v1.POST("/update-contact", h.updateContact)
+	v1.POST("/delete-contact", h.deleteContact)
+}
+
+func (h *Handler) getContacts(ctx echo.Context) error {
+	var dto GetContactsDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	contacts, err := h.addressBookSvc.GetContacts(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, contacts)
+}
+
+func (h *Handler) getContact(ctx echo.Context) error {
+	var dto GetContactDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	contact, err := h.addressBookSvc.GetContact(ctx.Request().Context(), jwtPayload.Email, dto.ContactID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, contact)
+}
+
+func (h *Handler) createContact(ctx echo.Context) error {
+	var dto CreateContactDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	contact, err := h.addressBookSvc.CreateContact(ctx.Request().Context(), jwtPayload.Email, &dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, contact)
+}
+
+func (h *Handler) updateContact(ctx echo.Context) error {
+	var dto UpdateContactDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	contact, err := h.addressBookSvc.UpdateContact(ctx.Request().Context(), jwtPayload.Email, &dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, contact)
+}
+
+func (h *Handler) deleteContact(ctx echo.Context) error {
+	var dto DeleteContactDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	if err = h.addressBookSvc.DeleteContact(ctx.Request().Context(), jwtPayload.Email, dto.ContactID); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
@@ -0,0 +1,32 @@
+package addressbook
+
+func MapToDTO(c *Contact) *ContactDTO {
+	addresses := make([]*AddressDTO, 0, len(c.Addresses))
+	for _, a := range c.Addresses {
+		addresses = append(addresses, &AddressDTO{Chain: a.Chain, Address: a.Address})
+	}
+
+	return &ContactDTO{
+		ID:        c.ID.Hex(),
+		Name:      c.Name,
+		Addresses: addresses,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func MapToDTOs(contacts []*Contact) []*ContactDTO {
+	dtos := make([]*ContactDTO, 0, len(contacts))
+	for _, c := range contacts {
+		dtos = append(dtos, MapToDTO(c))
+	}
+	return dtos
+}
+
+func MapAddressesToEntity(dtos []*AddressDTO) []*Address {
+	addresses := make([]*Address, 0, len(dtos))
+	for _, a := range dtos {
+		addresses = append(addresses, &Address{Chain: a.Chain, Address: a.Address})
+	}
+	return addresses
+}
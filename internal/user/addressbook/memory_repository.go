@@ -0,0 +1,120 @@
+package addressbook
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/memory"
+	"sort"
+	"sync"
+)
+
+type memoryRepository struct {
+	mu       sync.RWMutex
+	contacts map[string]*Contact // by contact id
+}
+
+// NewMemoryRepository returns thread-safe repository which keeps contacts in memory
+func NewMemoryRepository() (Repository, error) {
+	return &memoryRepository{contacts: make(map[string]*Contact)}, nil
+}
+
+func (repo *memoryRepository) GetContact(_ context.Context, userID, contactID string) (*Contact, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	contact, ok := repo.contacts[contactID]
+	if !ok || contact.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return copyContact(contact)
+}
+
+func (repo *memoryRepository) GetContacts(_ context.Context, userID string) ([]*Contact, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	contacts := make([]*Contact, 0)
+	for _, c := range repo.contacts {
+		if c.UserID != userID {
+			continue
+		}
+
+		copied, err := copyContact(c)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, copied)
+	}
+
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].Name < contacts[j].Name
+	})
+	return contacts, nil
+}
+
+func (repo *memoryRepository) SaveContact(_ context.Context, contact *Contact) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.contacts[contact.ID.Hex()]; ok || repo.nameTaken(contact) {
+		return ErrAlreadyExists
+	}
+
+	stored, err := copyContact(contact)
+	if err != nil {
+		return err
+	}
+	repo.contacts[contact.ID.Hex()] = stored
+	return nil
+}
+
+func (repo *memoryRepository) UpdateContact(_ context.Context, contact *Contact) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	current, ok := repo.contacts[contact.ID.Hex()]
+	if !ok || current.UserID != contact.UserID {
+		return ErrNotFound
+	}
+	if repo.nameTaken(contact) {
+		return ErrAlreadyExists
+	}
+
+	stored, err := copyContact(contact)
+	if err != nil {
+		return err
+	}
+	repo.contacts[contact.ID.Hex()] = stored
+	return nil
+}
+
+func (repo *memoryRepository) DeleteContact(_ context.Context, userID, contactID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	contact, ok := repo.contacts[contactID]
+	if !ok || contact.UserID != userID {
+		return ErrNotFound
+	}
+
+	delete(repo.contacts, contactID)
+	return nil
+}
+
+// nameTaken checks if user has another contact with the same name
+func (repo *memoryRepository) nameTaken(contact *Contact) bool {
+	for _, c := range repo.contacts {
+		if c.UserID == contact.UserID && c.Name == contact.Name && c.ID != contact.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func copyContact(c *Contact) (*Contact, error) {
+	var copied Contact
+	if err := memory.Copy(c, &copied); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	return &copied, nil
+}
@@ -0,0 +1,142 @@
+package addressbook
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const contactsCollection = "contacts"
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository_mock.go
+type Repository interface {
+	GetContact(ctx context.Context, userID, contactID string) (*Contact, error)
+	GetContacts(ctx context.Context, userID string) ([]*Contact, error)
+	SaveContact(ctx context.Context, contact *Contact) error
+	UpdateContact(ctx context.Context, contact *Contact) error
+	DeleteContact(ctx context.Context, userID, contactID string) error
+}
+
+type repository struct {
+	db  *mongo.Database
+	log *logrus.Logger
+}
+
+func NewRepository(db *mongo.Database, log *logrus.Logger) (Repository, error) {
+	if db == nil {
+		return nil, errors.NewInternal("invalid db")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &repository{db: db, log: log}, nil
+}
+
+// CreateIndexes creates indexes of contacts collection, contact name is unique per user
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	mod := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	return migrations.CreateIndexes(ctx, db.Collection(contactsCollection), mod)
+}
+
+func (repo *repository) GetContact(ctx context.Context, userID, contactID string) (*Contact, error) {
+	id, err := primitive.ObjectIDFromHex(contactID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	var contact Contact
+	err = repo.db.
+		Collection(contactsCollection).
+		FindOne(ctx, bson.M{"_id": id, "user_id": userID}).
+		Decode(&contact)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			repo.log.WithContext(ctx).Errorf("unable to find contact by id '%s': %v", contactID, err)
+			return nil, ErrNotFound
+		}
+
+		repo.log.WithContext(ctx).Errorf("unable to find contact due to internal error: %v; contact id: %s", err, contactID)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return &contact, nil
+}
+
+func (repo *repository) GetContacts(ctx context.Context, userID string) ([]*Contact, error) {
+	cursor, err := repo.db.
+		Collection(contactsCollection).
+		Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to find user contacts due to internal error: %v; user id: %s", err, userID)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	contacts := make([]*Contact, 0)
+	if err = cursor.All(ctx, &contacts); err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to decode user contacts: %v; user id: %s", err, userID)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return contacts, nil
+}
+
+func (repo *repository) SaveContact(ctx context.Context, contact *Contact) error {
+	if _, err := repo.db.Collection(contactsCollection).InsertOne(ctx, contact); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			repo.log.WithContext(ctx).Errorf("failed to insert contact to db due to duplicate error: %v", err)
+			return ErrAlreadyExists
+		}
+
+		repo.log.WithContext(ctx).Errorf("failed to insert contact to db: %v", err)
+		return errors.NewInternal(err.Error())
+	}
+	return nil
+}
+
+func (repo *repository) UpdateContact(ctx context.Context, contact *Contact) error {
+	res, err := repo.db.
+		Collection(contactsCollection).
+		UpdateOne(ctx, bson.M{"_id": contact.ID, "user_id": contact.UserID}, bson.M{"$set": contact})
+
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyExists
+		}
+
+		repo.log.WithContext(ctx).Errorf("failed to update contact '%s': %v", contact.ID.Hex(), err)
+		return errors.NewInternal(err.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (repo *repository) DeleteContact(ctx context.Context, userID, contactID string) error {
+	id, err := primitive.ObjectIDFromHex(contactID)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	res, err := repo.db.Collection(contactsCollection).DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to delete contact '%s': %v", contactID, err)
+		return errors.NewInternal(err.Error())
+	}
+
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package addressbook_test
+
+import (
+	"context"
+	"nnw_s/internal/user/addressbook"
+	"nnw_s/pkg/mongodb/mongotest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) addressbook.Repository {
+		repo, err := addressbook.NewMemoryRepository()
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMongoRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) addressbook.Repository {
+		db := mongotest.DB(t)
+		require.Nil(t, addressbook.CreateIndexes(context.Background(), db))
+
+		repo, err := addressbook.NewRepository(db, logrus.New())
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+// testRepository is a conformance suite which every addressbook.Repository implementation should pass
+func testRepository(t *testing.T, newRepo func(t *testing.T) addressbook.Repository) {
+	ctx := context.Background()
+
+	newContact := func(t *testing.T, userID, name string) *addressbook.Contact {
+		c, err := addressbook.NewContact(userID, name, []*addressbook.Address{
+			{Chain: addressbook.ChainETH, Address: testETHAddress},
+		}, testNetwork)
+		require.Nil(t, err)
+		return c
+	}
+
+	t.Run("should save and get contacts sorted by name", func(t *testing.T) {
+		repo := newRepo(t)
+		bob := newContact(t, "user_id", "Bob")
+		alice := newContact(t, "user_id", "Alice")
+
+		require.Nil(t, repo.SaveContact(ctx, bob))
+		require.Nil(t, repo.SaveContact(ctx, alice))
+
+		loaded, err := repo.GetContact(ctx, "user_id", bob.ID.Hex())
+		require.Nil(t, err)
+		assert.Equal(t, bob.Addresses, loaded.Addresses)
+
+		contacts, err := repo.GetContacts(ctx, "user_id")
+		require.Nil(t, err)
+		require.Len(t, contacts, 2)
+		assert.Equal(t, "Alice", contacts[0].Name)
+		assert.Equal(t, "Bob", contacts[1].Name)
+	})
+
+	t.Run("should not return contact of another user", func(t *testing.T) {
+		repo := newRepo(t)
+		c := newContact(t, "user_id", "Alice")
+
+		require.Nil(t, repo.SaveContact(ctx, c))
+
+		_, err := repo.GetContact(ctx, "other_user_id", c.ID.Hex())
+		assert.Equal(t, addressbook.ErrNotFound, err)
+
+		assert.Equal(t, addressbook.ErrNotFound, repo.DeleteContact(ctx, "other_user_id", c.ID.Hex()))
+	})
+
+	t.Run("should return 'already exists' error on duplicate name", func(t *testing.T) {
+		repo := newRepo(t)
+
+		require.Nil(t, repo.SaveContact(ctx, newContact(t, "user_id", "Alice")))
+		require.Nil(t, repo.SaveContact(ctx, newContact(t, "other_user_id", "Alice")))
+
+		err := repo.SaveContact(ctx, newContact(t, "user_id", "Alice"))
+		assert.Equal(t, addressbook.ErrAlreadyExists, err)
+	})
+
+	t.Run("should update contact", func(t *testing.T) {
+		repo := newRepo(t)
+		c := newContact(t, "user_id", "Alice")
+
+		require.Nil(t, repo.SaveContact(ctx, c))
+
+		require.Nil(t, c.Update("Alice B.", []*addressbook.Address{{Chain: addressbook.ChainBTC, Address: testBTCAddress}}, testNetwork))
+		require.Nil(t, repo.UpdateContact(ctx, c))
+
+		loaded, err := repo.GetContact(ctx, "user_id", c.ID.Hex())
+		require.Nil(t, err)
+		assert.Equal(t, "Alice B.", loaded.Name)
+		assert.Equal(t, c.Addresses, loaded.Addresses)
+	})
+
+	t.Run("should delete contact", func(t *testing.T) {
+		repo := newRepo(t)
+		c := newContact(t, "user_id", "Alice")
+
+		require.Nil(t, repo.SaveContact(ctx, c))
+		require.Nil(t, repo.DeleteContact(ctx, "user_id", c.ID.Hex()))
+
+		_, err := repo.GetContact(ctx, "user_id", c.ID.Hex())
+		assert.Equal(t, addressbook.ErrNotFound, err)
+	})
+}
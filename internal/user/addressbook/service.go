@@ -0,0 +1,178 @@
+package addressbook
+
+import (
+	"context"
+	"nnw_s/internal/auth/twofa"
+	"nnw_s/internal/user"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/wallet"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/service_mock.go
+type Service interface {
+	GetContacts(ctx context.Context, email string) ([]*ContactDTO, error)
+	GetContact(ctx context.Context, email, contactID string) (*ContactDTO, error)
+	CreateContact(ctx context.Context, email string, dto *CreateContactDTO) (*ContactDTO, error)
+	UpdateContact(ctx context.Context, email string, dto *UpdateContactDTO) (*ContactDTO, error)
+	DeleteContact(ctx context.Context, email, contactID string) error
+
+	// ResolveAddress returns contact's address of the chain to send funds to
+	ResolveAddress(ctx context.Context, email, contactID, chain string) (string, error)
+}
+
+type service struct {
+	repo     Repository
+	userSvc  user.Service
+	twoFaSvc twofa.Service
+	network  *wallet.BTCNetwork
+
+	log *logrus.Logger
+}
+
+type ServiceDeps struct {
+	Repository   Repository
+	UserService  user.Service
+	TwoFAService twofa.Service
+	// BTCNetwork is a network of BTC addresses of contacts
+	BTCNetwork *wallet.BTCNetwork
+}
+
+func NewService(log *logrus.Logger, deps *ServiceDeps) (Service, error) {
+	if deps == nil {
+		return nil, errors.NewInternal("invalid service dependencies")
+	}
+	if deps.Repository == nil {
+		return nil, errors.NewInternal("invalid repo")
+	}
+	if deps.UserService == nil {
+		return nil, errors.NewInternal("invalid user service")
+	}
+	if deps.TwoFAService == nil {
+		return nil, errors.NewInternal("invalid TwoFA service")
+	}
+	if deps.BTCNetwork == nil {
+		return nil, errors.NewInternal("invalid BTC network")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &service{
+		repo:     deps.Repository,
+		userSvc:  deps.UserService,
+		twoFaSvc: deps.TwoFAService,
+		network:  deps.BTCNetwork,
+		log:      log,
+	}, nil
+}
+
+func (svc *service) GetContacts(ctx context.Context, email string) ([]*ContactDTO, error) {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := svc.repo.GetContacts(ctx, userDTO.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapToDTOs(contacts), nil
+}
+
+func (svc *service) GetContact(ctx context.Context, email, contactID string) (*ContactDTO, error) {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, err := svc.repo.GetContact(ctx, userDTO.ID, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapToDTO(contact), nil
+}
+
+func (svc *service) CreateContact(ctx context.Context, email string, dto *CreateContactDTO) (*ContactDTO, error) {
+	userDTO, err := svc.checkTwoFA(ctx, email, dto.TwoFaCode)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, err := NewContact(userDTO.ID, dto.Name, MapAddressesToEntity(dto.Addresses), svc.network)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.repo.SaveContact(ctx, contact); err != nil {
+		return nil, err
+	}
+
+	return MapToDTO(contact), nil
+}
+
+func (svc *service) UpdateContact(ctx context.Context, email string, dto *UpdateContactDTO) (*ContactDTO, error) {
+	userDTO, err := svc.checkTwoFA(ctx, email, dto.TwoFaCode)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, err := svc.repo.GetContact(ctx, userDTO.ID, dto.ContactID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = contact.Update(dto.Name, MapAddressesToEntity(dto.Addresses), svc.network); err != nil {
+		return nil, err
+	}
+
+	if err = svc.repo.UpdateContact(ctx, contact); err != nil {
+		return nil, err
+	}
+
+	return MapToDTO(contact), nil
+}
+
+func (svc *service) DeleteContact(ctx context.Context, email, contactID string) error {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.DeleteContact(ctx, userDTO.ID, contactID)
+}
+
+func (svc *service) ResolveAddress(ctx context.Context, email, contactID, chain string) (string, error) {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return "", err
+	}
+
+	contact, err := svc.repo.GetContact(ctx, userDTO.ID, contactID)
+	if err != nil {
+		return "", err
+	}
+
+	return contact.Address(chain)
+}
+
+// checkTwoFA loads user and checks 2FA code, contacts can't be changed by users without 2FA
+func (svc *service) checkTwoFA(ctx context.Context, email, code string) (*user.DTO, error) {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if userDTO.SecretOTP == "" {
+		return nil, ErrTwoFARequired
+	}
+
+	if err = svc.twoFaSvc.CheckTwoFACode(ctx, code, userDTO.SecretOTP); err != nil {
+		svc.log.WithContext(ctx).Errorf("invalid 2FA code on contact change of user '%s': %v", email, err)
+		return nil, err
+	}
+
+	return userDTO, nil
+}
@@ -0,0 +1,121 @@
+package addressbook_test
+
+import (
+	"context"
+	mock_twofa "nnw_s/internal/auth/twofa/mocks"
+	"nnw_s/internal/user"
+	"nnw_s/internal/user/addressbook"
+	mock_addressbook "nnw_s/internal/user/addressbook/mocks"
+	mock_user "nnw_s/internal/user/mocks"
+	"nnw_s/pkg/errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func statusOf(err error) errors.Status {
+	if e, ok := err.(*errors.Error); ok {
+		return e.Status
+	}
+	return ""
+}
+
+func TestCreateContact(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := mock_addressbook.NewMockRepository(controller)
+	mockUserSvc := mock_user.NewMockService(controller)
+	mockTwoFaSvc := mock_twofa.NewMockService(controller)
+
+	service, _ := addressbook.NewService(logrus.New(), &addressbook.ServiceDeps{
+		Repository:   mockRepo,
+		UserService:  mockUserSvc,
+		TwoFAService: mockTwoFaSvc,
+		BTCNetwork:   testNetwork,
+	})
+
+	email := "some@mail.com"
+	testUser := &user.DTO{ID: "user_id", Email: email, SecretOTP: "secret"}
+	validDTO := &addressbook.CreateContactDTO{
+		Name:      "Alice",
+		Addresses: []*addressbook.AddressDTO{{Chain: addressbook.ChainBTC, Address: testBTCAddress}},
+		TwoFaCode: "123456",
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		dto    *addressbook.CreateContactDTO
+		setup  func(context.Context, *addressbook.CreateContactDTO)
+		expect func(*testing.T, *addressbook.ContactDTO, error)
+	}{
+		{
+			name: "should create contact",
+			ctx:  context.Background(),
+			dto:  validDTO,
+			setup: func(ctx context.Context, dto *addressbook.CreateContactDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, dto.TwoFaCode, testUser.SecretOTP).Return(nil)
+				mockRepo.EXPECT().SaveContact(ctx, gomock.Any()).Return(nil)
+			},
+			expect: func(t *testing.T, c *addressbook.ContactDTO, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "Alice", c.Name)
+				assert.Equal(t, validDTO.Addresses, c.Addresses)
+			},
+		},
+		{
+			name: "should return 'two fa required' error if user has no 2FA",
+			ctx:  context.Background(),
+			dto:  validDTO,
+			setup: func(ctx context.Context, dto *addressbook.CreateContactDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(&user.DTO{ID: "user_id", Email: email}, nil)
+			},
+			expect: func(t *testing.T, c *addressbook.ContactDTO, err error) {
+				assert.Nil(t, c)
+				assert.Equal(t, addressbook.ErrTwoFARequired, err)
+			},
+		},
+		{
+			name: "should not save contact on invalid 2FA code",
+			ctx:  context.Background(),
+			dto:  validDTO,
+			setup: func(ctx context.Context, dto *addressbook.CreateContactDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, dto.TwoFaCode, testUser.SecretOTP).Return(errors.NewInternal("invalid code"))
+			},
+			expect: func(t *testing.T, c *addressbook.ContactDTO, err error) {
+				assert.Nil(t, c)
+				assert.Equal(t, errors.NewInternal("invalid code"), err)
+			},
+		},
+		{
+			name: "should return 'invalid address' error",
+			ctx:  context.Background(),
+			dto: &addressbook.CreateContactDTO{
+				Name:      "Alice",
+				Addresses: []*addressbook.AddressDTO{{Chain: addressbook.ChainETH, Address: testBTCAddress}},
+				TwoFaCode: "123456",
+			},
+			setup: func(ctx context.Context, dto *addressbook.CreateContactDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, dto.TwoFaCode, testUser.SecretOTP).Return(nil)
+			},
+			expect: func(t *testing.T, c *addressbook.ContactDTO, err error) {
+				assert.Nil(t, c)
+				assert.Equal(t, addressbook.StatusInvalidAddress, statusOf(err))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup(tc.ctx, tc.dto)
+			c, err := service.CreateContact(tc.ctx, email, tc.dto)
+			tc.expect(t, c, err)
+		})
+	}
+}
@@ -1,24 +1,14 @@
 package user
 
 import (
-	"github.com/go-playground/validator/v10"
-	"nnw_s/pkg/errors"
-	"nnw_s/pkg/wallet"
+	"nnw_s/pkg/validation"
 	"time"
 )
 
 func Validate(dto interface{}) error {
-	validate := validator.New()
+	validate := validation.New()
 	if err := validate.Struct(dto); err != nil {
-		if _, ok := err.(*validator.InvalidValidationError); ok {
-			return errors.WithMessage(ErrInvalidRequest, err.Error())
-		}
-
-		validationErr := ErrInvalidRequest
-		for _, err := range err.(validator.ValidationErrors) {
-			validationErr = errors.WithMessage(validationErr, err.Error())
-		}
-		return validationErr
+		return validation.Error(ErrInvalidRequest, err)
 	}
 	return nil
 }
@@ -33,23 +23,26 @@ type GetUserDTO struct {
 }
 
 type DTO struct {
-	ID         string            `json:"id"`
-	Email      string            `json:"email"`
-	Password   string            `json:"password"`
-	SecretOTP  string            `json:"secret_otp"`
-	Status     string            `json:"status"`
-	Wallet     *[]*wallet.Wallet `json:"wallet"`
-	IsVerified bool              `json:"is_verified"`
+	ID         string      `json:"id"`
+	Email      string      `json:"email"`
+	Password   string      `json:"password"`
+	SecretOTP  string      `json:"secret_otp"`
+	Status     string      `json:"status"`
+	IsVerified bool        `json:"is_verified"`
+	Profile    *ProfileDTO `json:"profile"`
+	Version    int64       `json:"version"`
+
+	AntiPhishingPhrase string `json:"anti_phishing_phrase"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type GetUserResponseDTO struct {
-	Email      string            `json:"email"`
-	Status     string            `json:"status"`
-	IsVerified bool              `json:"is_verified"`
-	Wallet     *[]*wallet.Wallet `json:"wallet"`
+	Email      string      `json:"email"`
+	Status     string      `json:"status"`
+	IsVerified bool        `json:"is_verified"`
+	Profile    *ProfileDTO `json:"profile"`
 }
 
 func NormalizeGetUserResponseDTO(dto *DTO) *GetUserResponseDTO {
@@ -57,6 +50,28 @@ func NormalizeGetUserResponseDTO(dto *DTO) *GetUserResponseDTO {
 		Email:      dto.Email,
 		Status:     dto.Status,
 		IsVerified: dto.IsVerified,
-		Wallet:     dto.Wallet,
+		Profile:    dto.Profile,
 	}
 }
+
+type ProfileDTO struct {
+	DisplayName  string `json:"display_name"`
+	Locale       string `json:"locale"`
+	TimeZone     string `json:"time_zone"`
+	FiatCurrency string `json:"fiat_currency"`
+	Units        string `json:"units"`
+}
+
+type GetProfileDTO struct {
+	Jwt string `json:"jwt" validate:"required"`
+}
+
+// UpdateProfileDTO is a partial update, only not nil fields are changed
+type UpdateProfileDTO struct {
+	Jwt          string  `json:"jwt" validate:"required"`
+	DisplayName  *string `json:"display_name" validate:"omitempty,max=64"`
+	Locale       *string `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	TimeZone     *string `json:"time_zone" validate:"omitempty,timezone"`
+	FiatCurrency *string `json:"fiat_currency" validate:"omitempty,iso4217"`
+	Units        *string `json:"units" validate:"omitempty,oneof=BTC mBTC sats"`
+}
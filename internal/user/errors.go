@@ -18,6 +18,7 @@ const (
 	StatusUserAlreadyVerifyAndActive errors.Status = "already_verify_and_active"
 	StatusUserDoesNotVerify          errors.Status = "user_does_not_verify"
 	StatusUserDoesNotActive          errors.Status = "user_does_not_active"
+	StatusUserUpdateConflict         errors.Status = "user_update_conflict"
 )
 
 var (
@@ -32,4 +33,5 @@ var (
 	ErrUserDoesNotVerify            = errors.New(codes.Forbidden, StatusUserDoesNotVerify)
 	ErrUserDoesNotActive            = errors.New(codes.Forbidden, StatusUserDoesNotActive)
 	ErrFailedUpdateUser             = errors.New(codes.InternalError, StatusFailedUpdateUser)
+	ErrConflict                     = errors.New(codes.DuplicateError, StatusUserUpdateConflict)
 )
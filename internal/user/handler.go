@@ -26,28 +26,80 @@ func (h *Handler) SetupRoutes(router *echo.Echo) {
 
 	// Create wallet
 	v1.POST("/get-user", h.getUser)
+
+	// Profile
+	v1.POST("/get-profile", h.getProfile)
+	v1.PATCH("/profile", h.updateProfile)
 }
 
 func (h *Handler) getUser(ctx echo.Context) error {
 	var dto GetUserDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	user, err := h.userSvc.GetUserByEmail(ctx.Request().Context(), jwtPayload.Email)
 	if err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, NormalizeGetUserResponseDTO(user))
 }
+
+func (h *Handler) getProfile(ctx echo.Context) error {
+	var dto GetProfileDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	profile, err := h.userSvc.GetProfile(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, profile)
+}
+
+func (h *Handler) updateProfile(ctx echo.Context) error {
+	var dto UpdateProfileDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	profile, err := h.userSvc.UpdateProfile(ctx.Request().Context(), jwtPayload.Email, &dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, profile)
+}
@@ -3,7 +3,6 @@ package user
 import (
 	"nnw_s/internal/user/credentials"
 	"nnw_s/pkg/errors"
-	"nnw_s/pkg/wallet"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -14,9 +13,9 @@ func MapToDTO(u *User) *DTO {
 		secretOTP = *u.Credentials.SecretOTP
 	}
 
-	var userWallet []*wallet.Wallet
-	if u.Wallet != nil {
-		userWallet = *u.Wallet
+	profile := u.Profile
+	if profile == nil {
+		profile = NewProfile()
 	}
 
 	return &DTO{
@@ -26,9 +25,13 @@ func MapToDTO(u *User) *DTO {
 		SecretOTP:  secretOTP,
 		Status:     string(u.Status),
 		IsVerified: u.IsVerified,
-		Wallet:     &userWallet,
-		CreatedAt:  u.CreatedAt,
-		UpdatedAt:  u.UpdatedAt,
+		Version:    u.Version,
+		Profile:    MapProfileToDTO(profile),
+
+		AntiPhishingPhrase: u.AntiPhishingPhrase,
+
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
 	}
 }
 
@@ -47,8 +50,36 @@ func MapToEntity(dto *DTO) (*User, error) {
 		},
 		Status:     Status(dto.Status),
 		IsVerified: dto.IsVerified,
-		Wallet:     dto.Wallet,
-		CreatedAt:  dto.CreatedAt,
-		UpdatedAt:  dto.UpdatedAt,
+		Version:    dto.Version,
+		Profile:    MapProfileToEntity(dto.Profile),
+
+		AntiPhishingPhrase: dto.AntiPhishingPhrase,
+
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
 	}, nil
 }
+
+func MapProfileToDTO(p *Profile) *ProfileDTO {
+	return &ProfileDTO{
+		DisplayName:  p.DisplayName,
+		Locale:       p.Locale,
+		TimeZone:     p.TimeZone,
+		FiatCurrency: p.FiatCurrency,
+		Units:        string(p.Units),
+	}
+}
+
+func MapProfileToEntity(dto *ProfileDTO) *Profile {
+	if dto == nil {
+		return NewProfile()
+	}
+
+	return &Profile{
+		DisplayName:  dto.DisplayName,
+		Locale:       dto.Locale,
+		TimeZone:     dto.TimeZone,
+		FiatCurrency: dto.FiatCurrency,
+		Units:        Units(dto.Units),
+	}
+}
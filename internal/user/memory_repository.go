@@ -0,0 +1,121 @@
+package user
+
+import (
+	"context"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/memory"
+	"sync"
+)
+
+type memoryRepository struct {
+	mu    sync.RWMutex
+	users map[string]*User // by email
+	clock clock.Clock
+}
+
+// NewMemoryRepository returns thread-safe repository which keeps users in memory
+func NewMemoryRepository(clk clock.Clock) (Repository, error) {
+	if clk == nil {
+		return nil, errors.NewInternal("invalid clock")
+	}
+	return &memoryRepository{users: make(map[string]*User), clock: clk}, nil
+}
+
+func (repo *memoryRepository) GetUserByID(_ context.Context, userID string) (*User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, u := range repo.users {
+		if u.ID.Hex() == userID {
+			return copyUser(u)
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (repo *memoryRepository) GetUserByEmail(_ context.Context, email string) (*User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	u, ok := repo.users[email]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyUser(u)
+}
+
+func (repo *memoryRepository) SaveUser(_ context.Context, user *User) (string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.users[user.Email]; ok {
+		return "", ErrAlreadyExists
+	}
+
+	stored, err := copyUser(user)
+	if err != nil {
+		return "", err
+	}
+	repo.users[user.Email] = stored
+	return user.ID.Hex(), nil
+}
+
+func (repo *memoryRepository) UpdateUser(_ context.Context, user *User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	current, ok := repo.users[user.Email]
+	if !ok || current.Version != user.Version {
+		return ErrConflict
+	}
+
+	stored, err := copyUser(user)
+	if err != nil {
+		return err
+	}
+	stored.Version++
+	repo.users[user.Email] = stored
+
+	user.Version = stored.Version
+	return nil
+}
+
+func (repo *memoryRepository) UpdateUserFields(_ context.Context, email string, version int64, fields Fields) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	current, ok := repo.users[email]
+	if !ok || current.Version != version {
+		return ErrConflict
+	}
+
+	updated, err := copyUser(current)
+	if err != nil {
+		return err
+	}
+	if err = memory.SetFields(updated, fields); err != nil {
+		return errors.NewInternal(err.Error())
+	}
+	updated.Version++
+	updated.UpdatedAt = repo.clock.Now()
+
+	repo.users[email] = updated
+	return nil
+}
+
+func (repo *memoryRepository) DeleteUserByEmail(_ context.Context, email string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.users, email)
+	return nil
+}
+
+func copyUser(u *User) (*User, error) {
+	var copied User
+	if err := memory.Copy(u, &copied); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	return &copied, nil
+}
@@ -122,3 +122,17 @@ func (mr *MockRepositoryMockRecorder) UpdateUser(ctx, user interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockRepository)(nil).UpdateUser), ctx, user)
 }
+
+// UpdateUserFields mocks base method.
+func (m *MockRepository) UpdateUserFields(ctx context.Context, email string, version int64, fields user.Fields) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserFields", ctx, email, version, fields)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserFields indicates an expected call of UpdateUserFields.
+func (mr *MockRepositoryMockRecorder) UpdateUserFields(ctx, email, version, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserFields", reflect.TypeOf((*MockRepository)(nil).UpdateUserFields), ctx, email, version, fields)
+}
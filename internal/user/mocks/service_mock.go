@@ -64,6 +64,21 @@ func (mr *MockServiceMockRecorder) DeleteUserByEmail(ctx, email interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserByEmail", reflect.TypeOf((*MockService)(nil).DeleteUserByEmail), ctx, email)
 }
 
+// GetProfile mocks base method.
+func (m *MockService) GetProfile(ctx context.Context, email string) (*user.ProfileDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, email)
+	ret0, _ := ret[0].(*user.ProfileDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockServiceMockRecorder) GetProfile(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockService)(nil).GetProfile), ctx, email)
+}
+
 // GetUserByEmail mocks base method.
 func (m *MockService) GetUserByEmail(ctx context.Context, email string) (*user.DTO, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +124,36 @@ func (mr *MockServiceMockRecorder) GetUserByWalletID(ctx, email, walletId interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByWalletID", reflect.TypeOf((*MockService)(nil).GetUserByWalletID), ctx, email, walletId)
 }
 
+// ModifyUser mocks base method.
+func (m *MockService) ModifyUser(ctx context.Context, email string, modify user.ModifyFunc) (*user.DTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyUser", ctx, email, modify)
+	ret0, _ := ret[0].(*user.DTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModifyUser indicates an expected call of ModifyUser.
+func (mr *MockServiceMockRecorder) ModifyUser(ctx, email, modify interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyUser", reflect.TypeOf((*MockService)(nil).ModifyUser), ctx, email, modify)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockService) UpdateProfile(ctx context.Context, email string, dto *user.UpdateProfileDTO) (*user.ProfileDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, email, dto)
+	ret0, _ := ret[0].(*user.ProfileDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockServiceMockRecorder) UpdateProfile(ctx, email, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockService)(nil).UpdateProfile), ctx, email, dto)
+}
+
 // UpdateUser mocks base method.
 func (m *MockService) UpdateUser(ctx context.Context, dto *user.DTO) error {
 	m.ctrl.T.Helper()
@@ -122,3 +167,17 @@ func (mr *MockServiceMockRecorder) UpdateUser(ctx, dto interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockService)(nil).UpdateUser), ctx, dto)
 }
+
+// UpdateUserFields mocks base method.
+func (m *MockService) UpdateUserFields(ctx context.Context, email string, version int64, fields user.Fields) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserFields", ctx, email, version, fields)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserFields indicates an expected call of UpdateUserFields.
+func (mr *MockServiceMockRecorder) UpdateUserFields(ctx, email, version, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserFields", reflect.TypeOf((*MockService)(nil).UpdateUserFields), ctx, email, version, fields)
+}
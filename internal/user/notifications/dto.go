@@ -0,0 +1,50 @@
+package notifications
+
+import (
+	"nnw_s/pkg/validation"
+	"time"
+)
+
+func Validate(dto interface{}) error {
+	validate := validation.New()
+	if err := validate.Struct(dto); err != nil {
+		return validation.Error(ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+type SettingsDTO struct {
+	TelegramChatID string              `json:"telegram_chat_id"`
+	WebhookURL     string              `json:"webhook_url"`
+	PushTokens     []string            `json:"push_tokens"`
+	Preferences    map[string][]string `json:"preferences"`
+	// Events are events which channels can be chosen, other events are sent by email only
+	Events   []string `json:"events"`
+	Channels []string `json:"channels"`
+	// AntiPhishingPhraseSet tells whether emails show user's phrase, the phrase itself is never returned
+	AntiPhishingPhraseSet bool `json:"anti_phishing_phrase_set"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GetSettingsDTO struct {
+	Jwt string `json:"jwt" validate:"required"`
+}
+
+// UpdateSettingsDTO replaces user's settings, events missing in preferences are sent to default channels
+type UpdateSettingsDTO struct {
+	Jwt            string              `json:"jwt" validate:"required"`
+	TelegramChatID string              `json:"telegram_chat_id" validate:"omitempty,max=64"`
+	WebhookURL     string              `json:"webhook_url" validate:"omitempty,url,max=2048"`
+	PushTokens     []string            `json:"push_tokens" validate:"max=10,dive,required,max=4096"`
+	Preferences    map[string][]string `json:"preferences" validate:"dive,keys,required,endkeys,dive,required"`
+}
+
+// SetAntiPhishingPhraseDTO sets phrase shown in every email, empty phrase removes it.
+// It requires password and 2FA code, so a stolen session is not enough to change the phrase.
+type SetAntiPhishingPhraseDTO struct {
+	Jwt       string `json:"jwt" validate:"required"`
+	Phrase    string `json:"phrase" validate:"max=32"`
+	Password  string `json:"password" validate:"required"`
+	TwoFaCode string `json:"two_fa_code" validate:"required"`
+}
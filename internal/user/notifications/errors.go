@@ -0,0 +1,22 @@
+package notifications
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+)
+
+const (
+	StatusInvalidRequest   errors.Status = "invalid_request"
+	StatusInvalidSettings  errors.Status = "invalid_notification_settings"
+	StatusSettingsNotFound errors.Status = "notification_settings_not_found"
+	StatusInvalidPhrase    errors.Status = "invalid_anti_phishing_phrase"
+	StatusTwoFARequired    errors.Status = "two_fa_required"
+)
+
+var (
+	ErrInvalidRequest  = errors.New(codes.BadRequest, StatusInvalidRequest)
+	ErrInvalidSettings = errors.New(codes.BadRequest, StatusInvalidSettings)
+	ErrNotFound        = errors.New(codes.NotFound, StatusSettingsNotFound)
+	ErrInvalidPhrase   = errors.New(codes.BadRequest, StatusInvalidPhrase)
+	ErrTwoFARequired   = errors.New(codes.Forbidden, StatusTwoFARequired)
+)
@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"net/http"
+	"nnw_s/internal/auth/jwt"
+	"nnw_s/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	notificationsSvc Service
+	jwtSvc           jwt.Service
+}
+
+func NewHandler(notificationsSvc Service, jwtSvc jwt.Service) *Handler {
+	return &Handler{
+		notificationsSvc: notificationsSvc,
+		jwtSvc:           jwtSvc,
+	}
+}
+
+func (h *Handler) SetupRoutes(router *echo.Echo) {
+	v1 := router.Group("/api/v1")
+
+	// Notification settings
+	v1.POST("/get-notification-settings", h.getSettings)
+	v1.POST("/update-notification-settings", h.updateSettings)
+	v1.POST("/set-anti-phishing-phrase", h.setAntiPhishingPhrase)
+}
+
+func (h *Handler) getSettings(ctx echo.Context) error {
+	var dto GetSettingsDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	settings, err := h.notificationsSvc.GetSettings(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, settings)
+}
+
+func (h *Handler) updateSettings(ctx echo.Context) error {
+	var dto UpdateSettingsDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	settings, err := h.notificationsSvc.UpdateSettings(ctx.Request().Context(), jwtPayload.Email, &dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, settings)
+}
+
+func (h *Handler) setAntiPhishingPhrase(ctx echo.Context) error {
+	var dto SetAntiPhishingPhraseDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	if err = h.notificationsSvc.SetAntiPhishingPhrase(ctx.Request().Context(), jwtPayload.Email, &dto); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
@@ -0,0 +1,50 @@
+package notifications
+
+import "nnw_s/pkg/notificator"
+
+func MapToDTO(s *Settings, antiPhishingPhraseSet bool) *SettingsDTO {
+	preferences := make(map[string][]string, len(s.Preferences))
+	for event, channels := range s.Preferences {
+		names := make([]string, 0, len(channels))
+		for _, c := range channels {
+			names = append(names, string(c))
+		}
+		preferences[string(event)] = names
+	}
+
+	events := make([]string, 0)
+	for _, e := range notificator.Events() {
+		if e.Configurable() {
+			events = append(events, string(e))
+		}
+	}
+
+	channels := make([]string, 0)
+	for _, c := range notificator.Channels() {
+		channels = append(channels, string(c))
+	}
+
+	return &SettingsDTO{
+		TelegramChatID: s.TelegramChatID,
+		WebhookURL:     s.WebhookURL,
+		PushTokens:     s.PushTokens,
+		Preferences:    preferences,
+		Events:         events,
+		Channels:       channels,
+		UpdatedAt:      s.UpdatedAt,
+
+		AntiPhishingPhraseSet: antiPhishingPhraseSet,
+	}
+}
+
+func MapPreferencesToEntity(dto map[string][]string) map[notificator.Event][]notificator.Channel {
+	preferences := make(map[notificator.Event][]notificator.Channel, len(dto))
+	for event, names := range dto {
+		channels := make([]notificator.Channel, 0, len(names))
+		for _, name := range names {
+			channels = append(channels, notificator.Channel(name))
+		}
+		preferences[notificator.Event(event)] = channels
+	}
+	return preferences
+}
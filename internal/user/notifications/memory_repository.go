@@ -0,0 +1,49 @@
+package notifications
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/memory"
+	"sync"
+)
+
+type memoryRepository struct {
+	mu       sync.RWMutex
+	settings map[string]*Settings // by user id
+}
+
+// NewMemoryRepository returns thread-safe repository which keeps notification settings in memory
+func NewMemoryRepository() (Repository, error) {
+	return &memoryRepository{settings: make(map[string]*Settings)}, nil
+}
+
+func (repo *memoryRepository) GetSettings(_ context.Context, userID string) (*Settings, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	settings, ok := repo.settings[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copySettings(settings)
+}
+
+func (repo *memoryRepository) SaveSettings(_ context.Context, settings *Settings) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, err := copySettings(settings)
+	if err != nil {
+		return err
+	}
+	repo.settings[settings.UserID] = stored
+	return nil
+}
+
+func copySettings(s *Settings) (*Settings, error) {
+	var copied Settings
+	if err := memory.Copy(s, &copied); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	return &copied, nil
+}
@@ -0,0 +1,32 @@
+package notifications
+
+import (
+	"nnw_s/pkg/errors"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	minPhraseLength = 4
+	maxPhraseLength = 32
+)
+
+// validatePhrase checks anti-phishing phrase, empty phrase removes it.
+// Phrase is shown in a single line of email header, so it can't have line breaks or other control characters.
+func validatePhrase(phrase string) error {
+	if phrase == "" {
+		return nil
+	}
+
+	length := utf8.RuneCountInString(phrase)
+	if length < minPhraseLength || length > maxPhraseLength {
+		return errors.WithMessage(ErrInvalidPhrase, "phrase should have from 4 to 32 characters")
+	}
+
+	for _, r := range phrase {
+		if !unicode.IsPrint(r) {
+			return errors.WithMessage(ErrInvalidPhrase, "phrase should have only printable characters")
+		}
+	}
+	return nil
+}
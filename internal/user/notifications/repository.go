@@ -0,0 +1,76 @@
+package notifications
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const settingsCollection = "notification_settings"
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository_mock.go
+type Repository interface {
+	GetSettings(ctx context.Context, userID string) (*Settings, error)
+	// SaveSettings creates or replaces settings of the user
+	SaveSettings(ctx context.Context, settings *Settings) error
+}
+
+type repository struct {
+	db  *mongo.Database
+	log *logrus.Logger
+}
+
+func NewRepository(db *mongo.Database, log *logrus.Logger) (Repository, error) {
+	if db == nil {
+		return nil, errors.NewInternal("invalid db")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &repository{db: db, log: log}, nil
+}
+
+// CreateIndexes creates indexes of notification settings collection, user has only one settings document
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	mod := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	return migrations.CreateIndexes(ctx, db.Collection(settingsCollection), mod)
+}
+
+func (repo *repository) GetSettings(ctx context.Context, userID string) (*Settings, error) {
+	var settings Settings
+	err := repo.db.
+		Collection(settingsCollection).
+		FindOne(ctx, bson.M{"user_id": userID}).
+		Decode(&settings)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+
+		repo.log.WithContext(ctx).Errorf("unable to find notification settings due to internal error: %v; user id: %s", err, userID)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return &settings, nil
+}
+
+func (repo *repository) SaveSettings(ctx context.Context, settings *Settings) error {
+	_, err := repo.db.
+		Collection(settingsCollection).
+		ReplaceOne(ctx, bson.M{"user_id": settings.UserID}, settings, options.Replace().SetUpsert(true))
+
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to save notification settings of user '%s': %v", settings.UserID, err)
+		return errors.NewInternal(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package notifications_test
+
+import (
+	"context"
+	"nnw_s/internal/user/notifications"
+	"nnw_s/pkg/mongodb/mongotest"
+	"nnw_s/pkg/notificator"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) notifications.Repository {
+		repo, err := notifications.NewMemoryRepository()
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMongoRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) notifications.Repository {
+		db := mongotest.DB(t)
+		require.Nil(t, notifications.CreateIndexes(context.Background(), db))
+
+		repo, err := notifications.NewRepository(db, logrus.New())
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+// testRepository is a conformance suite which every notifications.Repository implementation should pass
+func testRepository(t *testing.T, newRepo func(t *testing.T) notifications.Repository) {
+	ctx := context.Background()
+
+	t.Run("should return 'not found' error if user has no settings", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetSettings(ctx, "user_id")
+		assert.Equal(t, notifications.ErrNotFound, err)
+	})
+
+	t.Run("should save and replace settings of the user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		settings, err := notifications.NewSettings("user_id")
+		require.Nil(t, err)
+		require.Nil(t, repo.SaveSettings(ctx, settings))
+
+		preferences := map[notificator.Event][]notificator.Channel{
+			notificator.EventTxReceived: {notificator.ChannelTelegram, notificator.ChannelPush},
+		}
+		require.Nil(t, settings.Update("42", "https://partner.com/hook", []string{"device_1"}, preferences))
+		require.Nil(t, repo.SaveSettings(ctx, settings))
+
+		loaded, err := repo.GetSettings(ctx, "user_id")
+		require.Nil(t, err)
+		assert.Equal(t, settings.ID, loaded.ID)
+		assert.Equal(t, "42", loaded.TelegramChatID)
+		assert.Equal(t, "https://partner.com/hook", loaded.WebhookURL)
+		assert.Equal(t, []string{"device_1"}, loaded.PushTokens)
+		assert.Equal(t, preferences, loaded.Preferences)
+
+		_, err = repo.GetSettings(ctx, "other_user_id")
+		assert.Equal(t, notifications.ErrNotFound, err)
+	})
+}
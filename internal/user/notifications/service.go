@@ -0,0 +1,187 @@
+package notifications
+
+import (
+	"context"
+	"nnw_s/internal/auth/twofa"
+	"nnw_s/internal/user"
+	"nnw_s/internal/user/credentials"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/notificator"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/service_mock.go
+type Service interface {
+	GetSettings(ctx context.Context, email string) (*SettingsDTO, error)
+	UpdateSettings(ctx context.Context, email string, dto *UpdateSettingsDTO) (*SettingsDTO, error)
+
+	// SetAntiPhishingPhrase sets phrase which is shown in every email to the user
+	SetAntiPhishingPhrase(ctx context.Context, email string, dto *SetAntiPhishingPhraseDTO) error
+
+	// Notify sends the event to the user through channels chosen in user's settings
+	Notify(ctx context.Context, email string, data notificator.TemplateData, idempotencyKey string) error
+}
+
+type service struct {
+	repo           Repository
+	userSvc        user.Service
+	notificatorSvc notificator.Service
+	twoFaSvc       twofa.Service
+	credentialsSvc credentials.Service
+
+	log         *logrus.Logger
+	emailSender string
+}
+
+type ServiceDeps struct {
+	Repository         Repository
+	UserService        user.Service
+	NotificatorService notificator.Service
+	TwoFAService       twofa.Service
+	CredentialsService credentials.Service
+}
+
+func NewService(log *logrus.Logger, emailSender string, deps *ServiceDeps) (Service, error) {
+	if deps == nil {
+		return nil, errors.NewInternal("invalid service dependencies")
+	}
+	if deps.Repository == nil {
+		return nil, errors.NewInternal("invalid repo")
+	}
+	if deps.UserService == nil {
+		return nil, errors.NewInternal("invalid user service")
+	}
+	if deps.NotificatorService == nil {
+		return nil, errors.NewInternal("invalid notification service")
+	}
+	if deps.TwoFAService == nil {
+		return nil, errors.NewInternal("invalid TwoFA service")
+	}
+	if deps.CredentialsService == nil {
+		return nil, errors.NewInternal("invalid credentials service")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	if emailSender == "" {
+		return nil, errors.NewInternal("invalid email sender")
+	}
+	return &service{
+		repo:           deps.Repository,
+		userSvc:        deps.UserService,
+		notificatorSvc: deps.NotificatorService,
+		twoFaSvc:       deps.TwoFAService,
+		credentialsSvc: deps.CredentialsService,
+		log:            log,
+		emailSender:    emailSender,
+	}, nil
+}
+
+func (svc *service) GetSettings(ctx context.Context, email string) (*SettingsDTO, error) {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := svc.getSettings(ctx, userDTO.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapToDTO(settings, userDTO.AntiPhishingPhrase != ""), nil
+}
+
+func (svc *service) UpdateSettings(ctx context.Context, email string, dto *UpdateSettingsDTO) (*SettingsDTO, error) {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := svc.getSettings(ctx, userDTO.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = settings.Update(dto.TelegramChatID, dto.WebhookURL, dto.PushTokens, MapPreferencesToEntity(dto.Preferences))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.repo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return MapToDTO(settings, userDTO.AntiPhishingPhrase != ""), nil
+}
+
+func (svc *service) Notify(ctx context.Context, email string, data notificator.TemplateData, idempotencyKey string) error {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	settings, err := svc.getSettings(ctx, userDTO.ID)
+	if err != nil {
+		return err
+	}
+
+	var languages []string
+	if userDTO.Profile != nil {
+		if userDTO.Profile.DisplayName != "" {
+			data.SetName(userDTO.Profile.DisplayName)
+		}
+		if userDTO.Profile.Locale != "" {
+			languages = []string{userDTO.Profile.Locale}
+		}
+	}
+
+	return svc.notificatorSvc.Notify(ctx, &notificator.Notification{
+		Sender:         svc.emailSender,
+		Recipient:      settings.Recipient(userDTO.Email, userDTO.AntiPhishingPhrase, languages, data.Event()),
+		Data:           data,
+		IdempotencyKey: idempotencyKey,
+	})
+}
+
+func (svc *service) SetAntiPhishingPhrase(ctx context.Context, email string, dto *SetAntiPhishingPhraseDTO) error {
+	phrase := strings.TrimSpace(dto.Phrase)
+	if err := validatePhrase(phrase); err != nil {
+		return err
+	}
+
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if userDTO.SecretOTP == "" {
+		return ErrTwoFARequired
+	}
+
+	if err = svc.credentialsSvc.ValidatePassword(ctx, &credentials.DTO{Password: userDTO.Password}, dto.Password); err != nil {
+		svc.log.WithContext(ctx).Errorf("invalid password on anti-phishing phrase change of user '%s': %v", email, err)
+		return err
+	}
+
+	if err = svc.twoFaSvc.CheckTwoFACode(ctx, dto.TwoFaCode, userDTO.SecretOTP); err != nil {
+		svc.log.WithContext(ctx).Errorf("invalid 2FA code on anti-phishing phrase change of user '%s': %v", email, err)
+		return err
+	}
+
+	_, err = svc.userSvc.ModifyUser(ctx, email, func(u *user.User) (user.Fields, error) {
+		u.SetAntiPhishingPhrase(phrase)
+		return user.Fields{user.FieldAntiPhishingPhrase: phrase}, nil
+	})
+	return err
+}
+
+// getSettings returns user's settings or new settings if user hasn't saved them yet
+func (svc *service) getSettings(ctx context.Context, userID string) (*Settings, error) {
+	settings, err := svc.repo.GetSettings(ctx, userID)
+	if err == ErrNotFound {
+		return NewSettings(userID)
+	}
+	return settings, err
+}
@@ -0,0 +1,303 @@
+package notifications_test
+
+import (
+	"context"
+	mock_twofa "nnw_s/internal/auth/twofa/mocks"
+	"nnw_s/internal/user"
+	"nnw_s/internal/user/credentials"
+	mock_credentials "nnw_s/internal/user/credentials/mocks"
+	mock_user "nnw_s/internal/user/mocks"
+	"nnw_s/internal/user/notifications"
+	mock_notifications "nnw_s/internal/user/notifications/mocks"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/notificator"
+	mock_notificator "nnw_s/pkg/notificator/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusOf(err error) errors.Status {
+	if e, ok := err.(*errors.Error); ok {
+		return e.Status
+	}
+	return ""
+}
+
+func TestUpdateSettings(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := mock_notifications.NewMockRepository(controller)
+	mockUserSvc := mock_user.NewMockService(controller)
+
+	service, _ := notifications.NewService(logrus.New(), "from@nnw.com", &notifications.ServiceDeps{
+		Repository:         mockRepo,
+		UserService:        mockUserSvc,
+		NotificatorService: mock_notificator.NewMockService(controller),
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		CredentialsService: mock_credentials.NewMockService(controller),
+	})
+
+	email := "some@mail.com"
+	testUser := &user.DTO{ID: "user_id", Email: email}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		dto    *notifications.UpdateSettingsDTO
+		setup  func(context.Context, *notifications.UpdateSettingsDTO)
+		expect func(*testing.T, *notifications.SettingsDTO, error)
+	}{
+		{
+			name: "should create settings on first update",
+			ctx:  context.Background(),
+			dto: &notifications.UpdateSettingsDTO{
+				TelegramChatID: "42",
+				Preferences:    map[string][]string{"tx_received": {"telegram", "email"}},
+			},
+			setup: func(ctx context.Context, dto *notifications.UpdateSettingsDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockRepo.EXPECT().GetSettings(ctx, testUser.ID).Return(nil, notifications.ErrNotFound)
+				mockRepo.EXPECT().SaveSettings(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *notifications.Settings) error {
+					assert.Equal(t, testUser.ID, s.UserID)
+					return nil
+				})
+			},
+			expect: func(t *testing.T, dto *notifications.SettingsDTO, err error) {
+				require.Nil(t, err)
+				assert.Equal(t, "42", dto.TelegramChatID)
+				assert.Equal(t, map[string][]string{"tx_received": {"telegram", "email"}}, dto.Preferences)
+				assert.NotContains(t, dto.Events, "verify_email")
+			},
+		},
+		{
+			name: "should return 'invalid settings' error if event is email only",
+			ctx:  context.Background(),
+			dto: &notifications.UpdateSettingsDTO{
+				Preferences: map[string][]string{"reset_password": {"telegram"}},
+			},
+			setup: func(ctx context.Context, dto *notifications.UpdateSettingsDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockRepo.EXPECT().GetSettings(ctx, testUser.ID).Return(nil, notifications.ErrNotFound)
+			},
+			expect: func(t *testing.T, dto *notifications.SettingsDTO, err error) {
+				assert.Nil(t, dto)
+				assert.Equal(t, notifications.StatusInvalidSettings, statusOf(err))
+			},
+		},
+		{
+			name: "should return 'invalid settings' error on unknown channel",
+			ctx:  context.Background(),
+			dto: &notifications.UpdateSettingsDTO{
+				Preferences: map[string][]string{"tx_sent": {"pigeon"}},
+			},
+			setup: func(ctx context.Context, dto *notifications.UpdateSettingsDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockRepo.EXPECT().GetSettings(ctx, testUser.ID).Return(nil, notifications.ErrNotFound)
+			},
+			expect: func(t *testing.T, dto *notifications.SettingsDTO, err error) {
+				assert.Nil(t, dto)
+				assert.Equal(t, notifications.StatusInvalidSettings, statusOf(err))
+			},
+		},
+		{
+			name: "should return 'invalid settings' error on webhook url of private host",
+			ctx:  context.Background(),
+			dto:  &notifications.UpdateSettingsDTO{WebhookURL: "https://169.254.169.254/latest/meta-data"},
+			setup: func(ctx context.Context, dto *notifications.UpdateSettingsDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockRepo.EXPECT().GetSettings(ctx, testUser.ID).Return(nil, notifications.ErrNotFound)
+			},
+			expect: func(t *testing.T, dto *notifications.SettingsDTO, err error) {
+				assert.Nil(t, dto)
+				assert.Equal(t, notifications.StatusInvalidSettings, statusOf(err))
+			},
+		},
+		{
+			name: "should return 'invalid settings' error on not https webhook url",
+			ctx:  context.Background(),
+			dto:  &notifications.UpdateSettingsDTO{WebhookURL: "http://partner.com/hook"},
+			setup: func(ctx context.Context, dto *notifications.UpdateSettingsDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockRepo.EXPECT().GetSettings(ctx, testUser.ID).Return(nil, notifications.ErrNotFound)
+			},
+			expect: func(t *testing.T, dto *notifications.SettingsDTO, err error) {
+				assert.Nil(t, dto)
+				assert.Equal(t, notifications.StatusInvalidSettings, statusOf(err))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.setup(test.ctx, test.dto)
+			settings, err := service.UpdateSettings(test.ctx, email, test.dto)
+			test.expect(t, settings, err)
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := mock_notifications.NewMockRepository(controller)
+	mockUserSvc := mock_user.NewMockService(controller)
+	mockNotificatorSvc := mock_notificator.NewMockService(controller)
+
+	service, _ := notifications.NewService(logrus.New(), "from@nnw.com", &notifications.ServiceDeps{
+		Repository:         mockRepo,
+		UserService:        mockUserSvc,
+		NotificatorService: mockNotificatorSvc,
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		CredentialsService: mock_credentials.NewMockService(controller),
+	})
+
+	ctx := context.Background()
+	email := "some@mail.com"
+	testUser := &user.DTO{
+		ID:                 "user_id",
+		Email:              email,
+		Profile:            &user.ProfileDTO{DisplayName: "Alice", Locale: "ru"},
+		AntiPhishingPhrase: "blue whale",
+	}
+
+	t.Run("should notify user through chosen channels", func(t *testing.T) {
+		settings, err := notifications.NewSettings(testUser.ID)
+		require.Nil(t, err)
+		require.Nil(t, settings.Update("42", "", nil, map[notificator.Event][]notificator.Channel{
+			notificator.EventTxReceived: {notificator.ChannelTelegram},
+		}))
+
+		data := &notificator.TxReceivedData{Chain: "BTC", Amount: "0.5", TxHash: "hash"}
+
+		mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+		mockRepo.EXPECT().GetSettings(ctx, testUser.ID).Return(settings, nil)
+		mockNotificatorSvc.EXPECT().Notify(ctx, &notificator.Notification{
+			Sender: "from@nnw.com",
+			Recipient: &notificator.Recipient{
+				Email:              email,
+				AntiPhishingPhrase: "blue whale",
+				TelegramChatID:     "42",
+				PushTokens:         []string{},
+				Languages:          []string{"ru"},
+				Channels:           []notificator.Channel{notificator.ChannelTelegram},
+			},
+			Data:           data,
+			IdempotencyKey: "tx:hash",
+		}).Return(nil)
+
+		require.Nil(t, service.Notify(ctx, email, data, "tx:hash"))
+		assert.Equal(t, "Alice", data.Name)
+		assert.Empty(t, data.AntiPhishingPhrase)
+	})
+
+	t.Run("should notify user without settings through default channels", func(t *testing.T) {
+		data := &notificator.PasswordChangedData{}
+
+		mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+		mockRepo.EXPECT().GetSettings(ctx, testUser.ID).Return(nil, notifications.ErrNotFound)
+		mockNotificatorSvc.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n *notificator.Notification) error {
+			assert.Equal(t, email, n.Recipient.Email)
+			assert.Empty(t, n.Recipient.Channels)
+			return nil
+		})
+
+		require.Nil(t, service.Notify(ctx, email, data, "password_changed"))
+	})
+}
+
+func TestSetAntiPhishingPhrase(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserSvc := mock_user.NewMockService(controller)
+	mockTwoFaSvc := mock_twofa.NewMockService(controller)
+	mockCredSvc := mock_credentials.NewMockService(controller)
+
+	service, _ := notifications.NewService(logrus.New(), "from@nnw.com", &notifications.ServiceDeps{
+		Repository:         mock_notifications.NewMockRepository(controller),
+		UserService:        mockUserSvc,
+		NotificatorService: mock_notificator.NewMockService(controller),
+		TwoFAService:       mockTwoFaSvc,
+		CredentialsService: mockCredSvc,
+	})
+
+	email := "some@mail.com"
+	testUser := &user.DTO{ID: "user_id", Email: email, Password: "hash", SecretOTP: "secret"}
+	credDTO := &credentials.DTO{Password: testUser.Password}
+	validDTO := &notifications.SetAntiPhishingPhraseDTO{Phrase: " blue whale ", Password: "password", TwoFaCode: "123456"}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		dto    *notifications.SetAntiPhishingPhraseDTO
+		setup  func(context.Context, *notifications.SetAntiPhishingPhraseDTO)
+		expect func(*testing.T, error)
+	}{
+		{
+			name: "should set trimmed phrase",
+			ctx:  context.Background(),
+			dto:  validDTO,
+			setup: func(ctx context.Context, dto *notifications.SetAntiPhishingPhraseDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockCredSvc.EXPECT().ValidatePassword(ctx, credDTO, dto.Password).Return(nil)
+				mockTwoFaSvc.EXPECT().CheckTwoFACode(ctx, dto.TwoFaCode, testUser.SecretOTP).Return(nil)
+				mockUserSvc.EXPECT().ModifyUser(ctx, email, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, modify user.ModifyFunc) (*user.DTO, error) {
+					u := &user.User{}
+					fields, err := modify(u)
+					assert.Nil(t, err)
+					assert.Equal(t, user.Fields{user.FieldAntiPhishingPhrase: "blue whale"}, fields)
+					assert.Equal(t, "blue whale", u.AntiPhishingPhrase)
+					return testUser, nil
+				})
+			},
+			expect: func(t *testing.T, err error) {
+				assert.Nil(t, err)
+			},
+		},
+		{
+			name:  "should return 'invalid phrase' error if phrase has line break",
+			ctx:   context.Background(),
+			dto:   &notifications.SetAntiPhishingPhraseDTO{Phrase: "blue\nwhale", Password: "password", TwoFaCode: "123456"},
+			setup: func(ctx context.Context, dto *notifications.SetAntiPhishingPhraseDTO) {},
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, notifications.StatusInvalidPhrase, statusOf(err))
+			},
+		},
+		{
+			name: "should return 'two fa required' error if user has no 2FA",
+			ctx:  context.Background(),
+			dto:  validDTO,
+			setup: func(ctx context.Context, dto *notifications.SetAntiPhishingPhraseDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(&user.DTO{ID: "user_id", Email: email}, nil)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, notifications.ErrTwoFARequired, err)
+			},
+		},
+		{
+			name: "should return 'invalid password' error",
+			ctx:  context.Background(),
+			dto:  validDTO,
+			setup: func(ctx context.Context, dto *notifications.SetAntiPhishingPhraseDTO) {
+				mockUserSvc.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockCredSvc.EXPECT().ValidatePassword(ctx, credDTO, dto.Password).Return(credentials.ErrInvalidPassword)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, credentials.ErrInvalidPassword, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.setup(test.ctx, test.dto)
+			test.expect(t, service.SetAntiPhishingPhrase(test.ctx, email, test.dto))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package notifications
+
+import (
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/notificator"
+	"nnw_s/pkg/outbound"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxPushTokens limits devices of the user which get push notifications
+const maxPushTokens = 10
+
+// Settings are user's addresses in notification channels and channels chosen per event
+type Settings struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	UserID         string             `bson:"user_id"`
+	TelegramChatID string             `bson:"telegram_chat_id"`
+	WebhookURL     string             `bson:"webhook_url"`
+	PushTokens     []string           `bson:"push_tokens"`
+	// Preferences are channels chosen per event, default channels are used for events without preferences
+	Preferences map[notificator.Event][]notificator.Channel `bson:"preferences"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// NewSettings returns empty settings, so every event is sent to its default channels
+func NewSettings(userID string) (*Settings, error) {
+	if userID == "" {
+		return nil, errors.WithMessage(ErrInvalidSettings, "user id should be not empty")
+	}
+
+	now := time.Now()
+	return &Settings{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		PushTokens:  []string{},
+		Preferences: map[notificator.Event][]notificator.Channel{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Update replaces user's addresses and preferences, only configurable events can have preferences
+func (s *Settings) Update(telegramChatID, webhookURL string, pushTokens []string, preferences map[notificator.Event][]notificator.Channel) error {
+	if webhookURL != "" {
+		if err := outbound.ValidateURL(webhookURL); err != nil {
+			return errors.WithMessage(ErrInvalidSettings, "webhook "+err.Error())
+		}
+	}
+	if len(pushTokens) > maxPushTokens {
+		return errors.WithMessage(ErrInvalidSettings, "too many push tokens")
+	}
+
+	for event, channels := range preferences {
+		if !event.Valid() {
+			return errors.WithMessage(ErrInvalidSettings, "unknown event: "+string(event))
+		}
+		if !event.Configurable() {
+			return errors.WithMessage(ErrInvalidSettings, "channels of event can't be changed: "+string(event))
+		}
+
+		chosen := make(map[notificator.Channel]bool, len(channels))
+		for _, c := range channels {
+			if !c.Valid() {
+				return errors.WithMessage(ErrInvalidSettings, "unknown channel: "+string(c))
+			}
+			if chosen[c] {
+				return errors.WithMessage(ErrInvalidSettings, "duplicate channel of event: "+string(event))
+			}
+			chosen[c] = true
+		}
+	}
+
+	if pushTokens == nil {
+		pushTokens = []string{}
+	}
+	if preferences == nil {
+		preferences = map[notificator.Event][]notificator.Channel{}
+	}
+
+	s.TelegramChatID = telegramChatID
+	s.WebhookURL = webhookURL
+	s.PushTokens = pushTokens
+	s.Preferences = preferences
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Recipient returns notification recipient of the event with user's addresses and chosen channels
+func (s *Settings) Recipient(email, antiPhishingPhrase string, languages []string, event notificator.Event) *notificator.Recipient {
+	return &notificator.Recipient{
+		Email:              email,
+		AntiPhishingPhrase: antiPhishingPhrase,
+		TelegramChatID:     s.TelegramChatID,
+		WebhookURL:         s.WebhookURL,
+		PushTokens:         s.PushTokens,
+		Languages:          languages,
+		Channels:           s.Preferences[event],
+	}
+}
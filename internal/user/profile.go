@@ -0,0 +1,62 @@
+package user
+
+import (
+	"nnw_s/pkg/i18n"
+	"strconv"
+
+	"github.com/btcsuite/btcutil"
+)
+
+type Units string
+
+const (
+	UnitsBTC      Units = "BTC"
+	UnitsMilliBTC Units = "mBTC"
+	UnitsSatoshi  Units = "sats"
+)
+
+const (
+	// DefaultLocale is not stored in profile, so Accept-Language of requests is used for users who haven't
+	// chosen a locale. Emails fall back to it when rendered if neither of them is supported.
+	DefaultLocale       = i18n.DefaultLanguage
+	DefaultTimeZone     = "UTC"
+	DefaultFiatCurrency = "USD"
+	DefaultUnits        = UnitsBTC
+)
+
+type Profile struct {
+	DisplayName  string `bson:"display_name"`
+	Locale       string `bson:"locale"`
+	TimeZone     string `bson:"time_zone"`
+	FiatCurrency string `bson:"fiat_currency"`
+	Units        Units  `bson:"units"`
+}
+
+// NewProfile returns profile with default settings, locale is empty until the user chooses it
+func NewProfile() *Profile {
+	return &Profile{
+		TimeZone:     DefaultTimeZone,
+		FiatCurrency: DefaultFiatCurrency,
+		Units:        DefaultUnits,
+	}
+}
+
+// AmountUnit returns the btcutil unit that matches user's preferred units.
+func (u Units) AmountUnit() btcutil.AmountUnit {
+	switch u {
+	case UnitsMilliBTC:
+		return btcutil.AmountMilliBTC
+	case UnitsSatoshi:
+		return btcutil.AmountSatoshi
+	default:
+		return btcutil.AmountBTC
+	}
+}
+
+// FormatBTC formats amount of satoshi in user's preferred units, e.g. "0.5 BTC", "500 mBTC" or "50000000 sats".
+func (u Units) FormatBTC(amount btcutil.Amount) string {
+	if u == UnitsSatoshi {
+		return strconv.FormatInt(int64(amount), 10) + " " + string(UnitsSatoshi)
+	}
+	return amount.Format(u.AmountUnit())
+}
@@ -2,12 +2,15 @@ package user
 
 import (
 	"context"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
 )
 
 //go:generate mockgen -source=repository.go -destination=mocks/repository_mock.go
@@ -16,9 +19,8 @@ type Repository interface {
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
 	SaveUser(ctx context.Context, user *User) (string, error)
 	UpdateUser(ctx context.Context, user *User) error
+	UpdateUserFields(ctx context.Context, email string, version int64, fields Fields) error
 	DeleteUserByEmail(ctx context.Context, email string) error
-
-	GetWalletByID(ctx context.Context, email, walletId string) (*User, error)
 }
 
 type repository struct {
@@ -33,9 +35,23 @@ func NewRepository(db *mongo.Database, log *logrus.Logger) *repository {
 	}
 }
 
+// CreateIndexes creates unique index of emails, so user is registered only once
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	return migrations.CreateIndexes(ctx, db.Collection("user"), mongo.IndexModel{
+		Keys:    bson.M{"email": 1}, // index in ascending order or -1 for descending order
+		Options: options.Index().SetUnique(true),
+	})
+}
+
 func (repo *repository) GetUserByID(ctx context.Context, userID string) (*User, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to find user by invalid id '%s': %v", userID, err)
+		return nil, ErrNotFound
+	}
+
 	var user User
-	if err := repo.db.Collection("user").FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+	if err := repo.db.Collection("user").FindOne(ctx, bson.M{"_id": id}).Decode(&user); err != nil {
 		if err == mongo.ErrNoDocuments {
 			repo.log.WithContext(ctx).Errorf("unable to find user by id '%s': %v", userID, err)
 			return nil, ErrNotFound
@@ -64,18 +80,7 @@ func (repo *repository) GetUserByEmail(ctx context.Context, email string) (*User
 }
 
 func (repo *repository) SaveUser(ctx context.Context, user *User) (string, error) {
-	mod := mongo.IndexModel{
-		Keys:    bson.M{"email": 1}, // index in ascending order or -1 for descending order
-		Options: options.Index().SetUnique(true),
-	}
-
-	_, err := repo.db.Collection("user").Indexes().CreateOne(ctx, mod)
-	if err != nil {
-		repo.log.WithContext(ctx).Errorf("failed to create user index: %v", err)
-		return "", errors.NewInternal(err.Error())
-	}
-
-	_, err = repo.db.Collection("user").InsertOne(ctx, user)
+	_, err := repo.db.Collection("user").InsertOne(ctx, user)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			repo.log.WithContext(ctx).Errorf("failed to insert user data to db due to duplicate error: %v", err)
@@ -88,39 +93,69 @@ func (repo *repository) SaveUser(ctx context.Context, user *User) (string, error
 	return user.ID.Hex(), nil
 }
 
+// UpdateUser replaces user document if it was not changed since user has been loaded,
+// otherwise ErrConflict is returned. On success user's version is incremented.
 func (repo *repository) UpdateUser(ctx context.Context, user *User) error {
-	_, err := repo.db.
+	updated := *user
+	updated.Version = user.Version + 1
+
+	res, err := repo.db.
 		Collection("user").
-		UpdateOne(ctx, bson.M{"email": user.Email},
-			bson.D{primitive.E{Key: "$set", Value: user}})
+		UpdateOne(ctx, versionFilter(user.Email, user.Version),
+			bson.D{primitive.E{Key: "$set", Value: &updated}})
 
 	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to update user '%s': %v", user.Email, err)
 		return errors.NewInternal(err.Error())
 	}
+
+	if res.MatchedCount == 0 {
+		repo.log.WithContext(ctx).Errorf("failed to update user '%s': version %d is outdated", user.Email, user.Version)
+		return ErrConflict
+	}
+
+	user.Version = updated.Version
 	return nil
 }
 
-func (repo *repository) DeleteUserByEmail(ctx context.Context, email string) error {
-	_, err := repo.db.Collection("user").DeleteOne(ctx, bson.M{"email": email})
+// UpdateUserFields sets only given fields if user was not changed since version has been loaded,
+// otherwise ErrConflict is returned.
+func (repo *repository) UpdateUserFields(ctx context.Context, email string, version int64, fields Fields) error {
+	set := bson.M{"updated_at": time.Now()}
+	for field, value := range fields {
+		set[field] = value
+	}
+
+	res, err := repo.db.
+		Collection("user").
+		UpdateOne(ctx, versionFilter(email, version),
+			bson.M{"$set": set, "$inc": bson.M{"version": 1}})
+
 	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to update user '%s' fields: %v", email, err)
 		return errors.NewInternal(err.Error())
 	}
 
+	if res.MatchedCount == 0 {
+		repo.log.WithContext(ctx).Errorf("failed to update user '%s' fields: version %d is outdated", email, version)
+		return ErrConflict
+	}
 	return nil
 }
 
-func (repo *repository) GetWalletByID(ctx context.Context, email, walletId string) (*User, error) {
-	var user User
-
-	if err := repo.db.Collection("user").FindOne(ctx, bson.M{"email": email, "wallet.wallet_name": walletId}).Decode(&user); err != nil {
-		if err == mongo.ErrNoDocuments {
-			repo.log.WithContext(ctx).Errorf("unable to find wallet by id'%s': %v", walletId, err)
-			return nil, ErrNotFound
-		}
+func versionFilter(email string, version int64) bson.M {
+	if version == 0 {
+		// users created before versioning don't have version field at all
+		return bson.M{"email": email, "version": bson.M{"$in": bson.A{version, nil}}}
+	}
+	return bson.M{"email": email, "version": version}
+}
 
-		repo.log.WithContext(ctx).Errorf("unable to find user due to internal error: %v; wallet id: %s", err, walletId)
-		return nil, errors.NewInternal(err.Error())
+func (repo *repository) DeleteUserByEmail(ctx context.Context, email string) error {
+	_, err := repo.db.Collection("user").DeleteOne(ctx, bson.M{"email": email})
+	if err != nil {
+		return errors.NewInternal(err.Error())
 	}
 
-	return &user, nil
+	return nil
 }
@@ -0,0 +1,207 @@
+package user_test
+
+import (
+	"context"
+	"nnw_s/internal/user"
+	"nnw_s/internal/user/credentials"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/mongodb/mongotest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) user.Repository {
+		repo, err := user.NewMemoryRepository(clock.New())
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMongoRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) user.Repository {
+		db := mongotest.DB(t)
+		require.Nil(t, user.CreateIndexes(context.Background(), db))
+
+		return user.NewRepository(db, logrus.New())
+	})
+}
+
+// testRepository is a conformance suite which every user.Repository implementation should pass
+func testRepository(t *testing.T, newRepo func(t *testing.T) user.Repository) {
+	ctx := context.Background()
+
+	newUser := func(t *testing.T, email string) *user.User {
+		u, err := user.NewUser(email, &credentials.Credentials{Password: "password"})
+		require.Nil(t, err)
+		return u
+	}
+
+	t.Run("should save and get user", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser(t, "some@mail.com")
+
+		id, err := repo.SaveUser(ctx, u)
+		require.Nil(t, err)
+		assert.Equal(t, u.ID.Hex(), id)
+
+		byEmail, err := repo.GetUserByEmail(ctx, u.Email)
+		require.Nil(t, err)
+		assert.Equal(t, u.ID, byEmail.ID)
+		assert.Equal(t, u.Credentials, byEmail.Credentials)
+		assert.Equal(t, u.Profile, byEmail.Profile)
+
+		byID, err := repo.GetUserByID(ctx, id)
+		require.Nil(t, err)
+		assert.Equal(t, u.Email, byID.Email)
+	})
+
+	t.Run("should return 'not found' error", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetUserByEmail(ctx, "not_existent_email")
+		assert.Equal(t, user.ErrNotFound, err)
+
+		_, err = repo.GetUserByID(ctx, "not_existent_id")
+		assert.Equal(t, user.ErrNotFound, err)
+	})
+
+	t.Run("should return 'already exists' error", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.SaveUser(ctx, newUser(t, "some@mail.com"))
+		require.Nil(t, err)
+
+		_, err = repo.SaveUser(ctx, newUser(t, "some@mail.com"))
+		assert.Equal(t, user.ErrAlreadyExists, err)
+	})
+
+	t.Run("should not share stored user with caller", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser(t, "some@mail.com")
+
+		_, err := repo.SaveUser(ctx, u)
+		require.Nil(t, err)
+		u.Credentials.Password = "changed"
+
+		loaded, err := repo.GetUserByEmail(ctx, u.Email)
+		require.Nil(t, err)
+		assert.Equal(t, "password", loaded.Credentials.Password)
+	})
+
+	t.Run("should update user and increment version", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser(t, "some@mail.com")
+
+		_, err := repo.SaveUser(ctx, u)
+		require.Nil(t, err)
+
+		u.SetToActive()
+		require.Nil(t, repo.UpdateUser(ctx, u))
+		assert.Equal(t, int64(2), u.Version)
+
+		loaded, err := repo.GetUserByEmail(ctx, u.Email)
+		require.Nil(t, err)
+		assert.True(t, loaded.IsActive())
+		assert.Equal(t, int64(2), loaded.Version)
+	})
+
+	t.Run("should return 'conflict' error on outdated user", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser(t, "some@mail.com")
+
+		_, err := repo.SaveUser(ctx, u)
+		require.Nil(t, err)
+
+		stale, err := repo.GetUserByEmail(ctx, u.Email)
+		require.Nil(t, err)
+
+		require.Nil(t, repo.UpdateUser(ctx, u))
+		assert.Equal(t, user.ErrConflict, repo.UpdateUser(ctx, stale))
+		assert.Equal(t, user.ErrConflict, repo.UpdateUserFields(ctx, u.Email, stale.Version, user.Fields{user.FieldStatus: user.Active}))
+	})
+
+	t.Run("should update only given fields", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser(t, "some@mail.com")
+
+		_, err := repo.SaveUser(ctx, u)
+		require.Nil(t, err)
+
+		err = repo.UpdateUserFields(ctx, u.Email, u.Version, user.Fields{
+			user.FieldPassword: "new_password",
+			user.FieldStatus:   user.Active,
+		})
+		require.Nil(t, err)
+
+		loaded, err := repo.GetUserByEmail(ctx, u.Email)
+		require.Nil(t, err)
+		assert.Equal(t, "new_password", loaded.Credentials.Password)
+		assert.Nil(t, loaded.Credentials.SecretOTP)
+		assert.Equal(t, user.Active, loaded.Status)
+		assert.Equal(t, u.Profile, loaded.Profile)
+		assert.Equal(t, u.Version+1, loaded.Version)
+	})
+
+	t.Run("should apply only one of concurrent updates of the same version", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser(t, "some@mail.com")
+
+		_, err := repo.SaveUser(ctx, u)
+		require.Nil(t, err)
+
+		const updates = 10
+		errs := make(chan error, updates)
+
+		var wg sync.WaitGroup
+		for i := 0; i < updates; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- repo.UpdateUserFields(ctx, u.Email, u.Version, user.Fields{user.FieldIsVerified: true})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		var succeeded int
+		for err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.Equal(t, user.ErrConflict, err)
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+
+	t.Run("should delete user", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser(t, "some@mail.com")
+
+		_, err := repo.SaveUser(ctx, u)
+		require.Nil(t, err)
+
+		require.Nil(t, repo.DeleteUserByEmail(ctx, u.Email))
+
+		_, err = repo.GetUserByEmail(ctx, u.Email)
+		assert.Equal(t, user.ErrNotFound, err)
+	})
+
+	t.Run("should keep time with storage precision", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser(t, "some@mail.com")
+
+		_, err := repo.SaveUser(ctx, u)
+		require.Nil(t, err)
+
+		loaded, err := repo.GetUserByEmail(ctx, u.Email)
+		require.Nil(t, err)
+		assert.WithinDuration(t, u.CreatedAt, loaded.CreatedAt, time.Millisecond)
+	})
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"nnw_s/internal/user/credentials"
 	"nnw_s/pkg/errors"
-	"nnw_s/pkg/wallet"
+	"nnw_s/pkg/tracing"
 
 	"github.com/sirupsen/logrus"
 )
@@ -13,15 +13,22 @@ import (
 type Service interface {
 	GetUserByID(ctx context.Context, userID string) (*DTO, error)
 	GetUserByEmail(ctx context.Context, email string) (*DTO, error)
-	GetUserByWalletID(ctx context.Context, email, walletId string) (*DTO, error)
 
 	CreateUser(ctx context.Context, dto *CreateUserDTO) (string, error)
 
 	UpdateUser(ctx context.Context, dto *DTO) error
+	UpdateUserFields(ctx context.Context, email string, version int64, fields Fields) error
+	ModifyUser(ctx context.Context, email string, modify ModifyFunc) (*DTO, error)
+
+	GetProfile(ctx context.Context, email string) (*ProfileDTO, error)
+	UpdateProfile(ctx context.Context, email string, dto *UpdateProfileDTO) (*ProfileDTO, error)
 
 	DeleteUserByEmail(ctx context.Context, email string) error
 }
 
+// maxUpdateAttempts is how many times ModifyUser reloads user after concurrent update
+const maxUpdateAttempts = 3
+
 type service struct {
 	repo           Repository
 	credentialsSvc credentials.Service
@@ -42,6 +49,9 @@ func NewService(repo Repository, credentialsSvc credentials.Service, log *logrus
 }
 
 func (svc *service) GetUserByID(ctx context.Context, userID string) (*DTO, error) {
+	ctx, span := tracing.Start(ctx, "user.GetUserByID")
+	defer span.End()
+
 	u, err := svc.repo.GetUserByID(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -50,6 +60,9 @@ func (svc *service) GetUserByID(ctx context.Context, userID string) (*DTO, error
 }
 
 func (svc *service) GetUserByEmail(ctx context.Context, email string) (*DTO, error) {
+	ctx, span := tracing.Start(ctx, "user.GetUserByEmail")
+	defer span.End()
+
 	u, err := svc.repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, err
@@ -58,6 +71,9 @@ func (svc *service) GetUserByEmail(ctx context.Context, email string) (*DTO, err
 }
 
 func (svc *service) CreateUser(ctx context.Context, dto *CreateUserDTO) (string, error) {
+	ctx, span := tracing.Start(ctx, "user.CreateUser")
+	defer span.End()
+
 	// create user credentials
 	userCredentialsDTO, err := svc.credentialsSvc.CreateCredentials(ctx, dto.Password, credentials.NilSecretOTP)
 	if err != nil {
@@ -69,7 +85,7 @@ func (svc *service) CreateUser(ctx context.Context, dto *CreateUserDTO) (string,
 	userCredentials := credentials.MapToEntity(userCredentialsDTO)
 
 	// create user with new credentials
-	newUser, err := NewUser(dto.Email, wallet.NilWallet, userCredentials)
+	newUser, err := NewUser(dto.Email, userCredentials)
 	if err != nil {
 		svc.log.WithContext(ctx).Errorf("failed to create user due to validation error: %v", err)
 		return "", err
@@ -83,7 +99,11 @@ func (svc *service) CreateUser(ctx context.Context, dto *CreateUserDTO) (string,
 	return id, err
 }
 
+// UpdateUser saves whole user, ErrConflict is returned if user was changed after dto has been loaded
 func (svc *service) UpdateUser(ctx context.Context, userDTO *DTO) error {
+	ctx, span := tracing.Start(ctx, "user.UpdateUser")
+	defer span.End()
+
 	// map dto to user entity
 	updateUser, err := MapToEntity(userDTO)
 	if err != nil {
@@ -95,10 +115,59 @@ func (svc *service) UpdateUser(ctx context.Context, userDTO *DTO) error {
 		svc.log.WithContext(ctx).Errorf("failed to save user in db: %v", err)
 		return err
 	}
+
+	userDTO.Version = updateUser.Version
+	return nil
+}
+
+// UpdateUserFields saves only given fields, ErrConflict is returned if user was changed after version has been loaded
+func (svc *service) UpdateUserFields(ctx context.Context, email string, version int64, fields Fields) error {
+	ctx, span := tracing.Start(ctx, "user.UpdateUserFields")
+	defer span.End()
+
+	if err := svc.repo.UpdateUserFields(ctx, email, version, fields); err != nil {
+		svc.log.WithContext(ctx).Errorf("failed to update user fields in db: %v", err)
+		return err
+	}
 	return nil
 }
 
+// ModifyUser loads user, applies modify and saves only changed fields.
+// If user was changed concurrently, it is reloaded and modify is applied again.
+func (svc *service) ModifyUser(ctx context.Context, email string, modify ModifyFunc) (*DTO, error) {
+	ctx, span := tracing.Start(ctx, "user.ModifyUser")
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		u, err := svc.repo.GetUserByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+
+		fields, err := modify(u)
+		if err != nil {
+			return nil, err
+		}
+
+		err = svc.repo.UpdateUserFields(ctx, u.Email, u.Version, fields)
+		if err == nil {
+			u.Version++
+			return MapToDTO(u), nil
+		}
+
+		if err != ErrConflict || attempt == maxUpdateAttempts {
+			svc.log.WithContext(ctx).Errorf("failed to modify user '%s': %v", email, err)
+			return nil, err
+		}
+
+		svc.log.WithContext(ctx).Warnf("user '%s' was changed concurrently, reloading (attempt %d)", email, attempt)
+	}
+}
+
 func (svc *service) DeleteUserByEmail(ctx context.Context, email string) error {
+	ctx, span := tracing.Start(ctx, "user.DeleteUserByEmail")
+	defer span.End()
+
 	err := svc.repo.DeleteUserByEmail(ctx, email)
 	if err != nil {
 		return err
@@ -106,10 +175,50 @@ func (svc *service) DeleteUserByEmail(ctx context.Context, email string) error {
 	return nil
 }
 
-func (svc *service) GetUserByWalletID(ctx context.Context, email, walletId string) (*DTO, error) {
-	u, err := svc.repo.GetWalletByID(ctx, email, walletId)
+func (svc *service) GetProfile(ctx context.Context, email string) (*ProfileDTO, error) {
+	ctx, span := tracing.Start(ctx, "user.GetProfile")
+	defer span.End()
+
+	u, err := svc.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return MapToDTO(u).Profile, nil
+}
+
+func (svc *service) UpdateProfile(ctx context.Context, email string, dto *UpdateProfileDTO) (*ProfileDTO, error) {
+	ctx, span := tracing.Start(ctx, "user.UpdateProfile")
+	defer span.End()
+
+	userDTO, err := svc.ModifyUser(ctx, email, func(u *User) (Fields, error) {
+		profile := NewProfile()
+		if u.Profile != nil {
+			profile = u.Profile
+		}
+
+		// apply only fields which were sent
+		if dto.DisplayName != nil {
+			profile.DisplayName = *dto.DisplayName
+		}
+		if dto.Locale != nil {
+			profile.Locale = *dto.Locale
+		}
+		if dto.TimeZone != nil {
+			profile.TimeZone = *dto.TimeZone
+		}
+		if dto.FiatCurrency != nil {
+			profile.FiatCurrency = *dto.FiatCurrency
+		}
+		if dto.Units != nil {
+			profile.Units = Units(*dto.Units)
+		}
+
+		u.SetProfile(profile)
+		return Fields{FieldProfile: profile}, nil
+	})
 	if err != nil {
+		svc.log.WithContext(ctx).Errorf("failed to update user profile: %v", err)
 		return nil, err
 	}
-	return MapToDTO(u), nil
+	return userDTO.Profile, nil
 }
@@ -7,9 +7,10 @@ import (
 	mock_credentials "nnw_s/internal/user/credentials/mocks"
 	mock_user "nnw_s/internal/user/mocks"
 	"nnw_s/pkg/errors"
-	"nnw_s/pkg/wallet"
+	"nnw_s/pkg/tracing/tracingtest"
 	"testing"
 
+	"github.com/btcsuite/btcutil"
 	"github.com/golang/mock/gomock"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -94,14 +95,7 @@ func TestGetUserByID(t *testing.T) {
 
 	service, _ := user.NewService(mockRepo, mockCred, log)
 
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
-	testUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testUser, _ := user.NewUser("some@mail.com", &testCred)
 	userDto := user.MapToDTO(testUser)
 
 	tests := []struct {
@@ -113,10 +107,10 @@ func TestGetUserByID(t *testing.T) {
 	}{
 		{
 			name:   "should return test user",
-			ctx:    context.Background(),
+			ctx:    tracingtest.Context(),
 			userID: testUser.ID.Hex(),
 			setup: func(ctx context.Context, userID string) {
-				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(testUser, nil)
+				mockRepo.EXPECT().GetUserByID(tracingtest.FromContext(ctx), userID).Return(testUser, nil)
 			},
 			expect: func(t *testing.T, u *user.DTO, err error) {
 				assert.NotNil(t, u)
@@ -126,10 +120,10 @@ func TestGetUserByID(t *testing.T) {
 		},
 		{
 			name:   "should return 'not found' error",
-			ctx:    context.Background(),
+			ctx:    tracingtest.Context(),
 			userID: "not_existent_id",
 			setup: func(ctx context.Context, userID string) {
-				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(nil, user.ErrNotFound)
+				mockRepo.EXPECT().GetUserByID(tracingtest.FromContext(ctx), userID).Return(nil, user.ErrNotFound)
 			},
 			expect: func(t *testing.T, u *user.DTO, err error) {
 				assert.Nil(t, u)
@@ -138,10 +132,10 @@ func TestGetUserByID(t *testing.T) {
 		},
 		{
 			name:   "should return 'internal error' error",
-			ctx:    context.Background(),
+			ctx:    tracingtest.Context(),
 			userID: userDto.ID,
 			setup: func(ctx context.Context, userID string) {
-				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(nil, errors.NewInternal("internal error"))
+				mockRepo.EXPECT().GetUserByID(tracingtest.FromContext(ctx), userID).Return(nil, errors.NewInternal("internal error"))
 			},
 			expect: func(t *testing.T, u *user.DTO, err error) {
 				assert.Nil(t, u)
@@ -174,14 +168,7 @@ func TestGetUserByEmail(t *testing.T) {
 
 	service, _ := user.NewService(mockRepo, mockCred, log)
 
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
-	testUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testUser, _ := user.NewUser("some@mail.com", &testCred)
 	userDTO := user.MapToDTO(testUser)
 
 	tests := []struct {
@@ -193,10 +180,10 @@ func TestGetUserByEmail(t *testing.T) {
 	}{
 		{
 			name:  "should return test user",
-			ctx:   context.Background(),
+			ctx:   tracingtest.Context(),
 			email: userDTO.Email,
 			setup: func(ctx context.Context, email string) {
-				mockRepo.EXPECT().GetUserByEmail(ctx, email).Return(testUser, nil)
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(testUser, nil)
 			},
 			expect: func(t *testing.T, u *user.DTO, err error) {
 				assert.NotNil(t, u)
@@ -206,10 +193,10 @@ func TestGetUserByEmail(t *testing.T) {
 		},
 		{
 			name:  "should return 'not found' error",
-			ctx:   context.Background(),
+			ctx:   tracingtest.Context(),
 			email: "not_existent_email",
 			setup: func(ctx context.Context, email string) {
-				mockRepo.EXPECT().GetUserByEmail(ctx, email).Return(nil, user.ErrNotFound)
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(nil, user.ErrNotFound)
 			},
 			expect: func(t *testing.T, u *user.DTO, err error) {
 				assert.Nil(t, u)
@@ -218,10 +205,10 @@ func TestGetUserByEmail(t *testing.T) {
 		},
 		{
 			name:  "should return 'internal error' error",
-			ctx:   context.Background(),
+			ctx:   tracingtest.Context(),
 			email: userDTO.Email,
 			setup: func(ctx context.Context, email string) {
-				mockRepo.EXPECT().GetUserByEmail(ctx, email).Return(nil, errors.NewInternal("internal error"))
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(nil, errors.NewInternal("internal error"))
 			},
 			expect: func(t *testing.T, u *user.DTO, err error) {
 				assert.Nil(t, u)
@@ -255,14 +242,7 @@ func TestCreateUser(t *testing.T) {
 
 	service, _ := user.NewService(mockRepo, mockCred, log)
 
-	var testWallet []*wallet.Wallet
-	testWallet = append(testWallet, &wallet.Wallet{
-		Name:     "BTC",
-		WalletId: "8ebdfa95-484d-11ec-ba92-38d547b6cf94",
-		Address:  "mrgZBqLCicXRGfSjqiSiV39mXgsV3euVZt",
-	})
-
-	testUser, _ := user.NewUser("some@mail.com", &testWallet, &testCred)
+	testUser, _ := user.NewUser("some@mail.com", &testCred)
 	userDTO := user.MapToDTO(testUser)
 	encodedPass := "==WvZitmZDgzSHgAWvKs"
 
@@ -275,7 +255,7 @@ func TestCreateUser(t *testing.T) {
 	}{
 		{
 			name: "should return id of recently created user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto: &user.CreateUserDTO{
 				Email:    userDTO.Email,
 				Password: encodedPass,
@@ -283,8 +263,8 @@ func TestCreateUser(t *testing.T) {
 			setup: func(ctx context.Context, dto *user.CreateUserDTO) {
 				credDTO := credentials.MapToDTO(&testCred)
 
-				mockCred.EXPECT().CreateCredentials(ctx, encodedPass, credentials.NilSecretOTP).Return(credDTO, nil)
-				mockRepo.EXPECT().SaveUser(ctx, gomock.Any()).Return(testUser.ID.Hex(), nil)
+				mockCred.EXPECT().CreateCredentials(tracingtest.FromContext(ctx), encodedPass, credentials.NilSecretOTP).Return(credDTO, nil)
+				mockRepo.EXPECT().SaveUser(tracingtest.FromContext(ctx), gomock.Any()).Return(testUser.ID.Hex(), nil)
 			},
 			expect: func(t *testing.T, id string, err error) {
 				assert.NotEmpty(t, id)
@@ -292,15 +272,36 @@ func TestCreateUser(t *testing.T) {
 				assert.Equal(t, id, userDTO.ID)
 			},
 		},
+		{
+			name: "should create user without locale, so Accept-Language of requests is used",
+			ctx:  tracingtest.Context(),
+			dto: &user.CreateUserDTO{
+				Email:    userDTO.Email,
+				Password: encodedPass,
+			},
+			setup: func(ctx context.Context, dto *user.CreateUserDTO) {
+				credDTO := credentials.MapToDTO(&testCred)
+
+				mockCred.EXPECT().CreateCredentials(tracingtest.FromContext(ctx), encodedPass, credentials.NilSecretOTP).Return(credDTO, nil)
+				mockRepo.EXPECT().SaveUser(tracingtest.FromContext(ctx), gomock.Any()).DoAndReturn(func(_ context.Context, u *user.User) (string, error) {
+					assert.Empty(t, u.Profile.Locale)
+					return u.ID.Hex(), nil
+				})
+			},
+			expect: func(t *testing.T, id string, err error) {
+				assert.NotEmpty(t, id)
+				assert.Nil(t, err)
+			},
+		},
 		{
 			name: "should return decode error",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto: &user.CreateUserDTO{
 				Email:    userDTO.Email,
 				Password: userDTO.Password,
 			},
 			setup: func(ctx context.Context, dto *user.CreateUserDTO) {
-				mockCred.EXPECT().CreateCredentials(ctx, encodedPass, credentials.NilSecretOTP).Return(nil, errors.NewInternal("failed to decode password"))
+				mockCred.EXPECT().CreateCredentials(tracingtest.FromContext(ctx), encodedPass, credentials.NilSecretOTP).Return(nil, errors.NewInternal("failed to decode password"))
 			},
 			expect: func(t *testing.T, id string, err error) {
 				assert.Empty(t, id)
@@ -310,7 +311,7 @@ func TestCreateUser(t *testing.T) {
 		},
 		{
 			name: "should return error while saving user is db",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto: &user.CreateUserDTO{
 				Email:    userDTO.Email,
 				Password: encodedPass,
@@ -318,8 +319,8 @@ func TestCreateUser(t *testing.T) {
 			setup: func(ctx context.Context, dto *user.CreateUserDTO) {
 				credDTO := credentials.MapToDTO(&testCred)
 
-				mockCred.EXPECT().CreateCredentials(ctx, encodedPass, credentials.NilSecretOTP).Return(credDTO, nil)
-				mockRepo.EXPECT().SaveUser(ctx, gomock.Any()).Return("", user.ErrAlreadyExists)
+				mockCred.EXPECT().CreateCredentials(tracingtest.FromContext(ctx), encodedPass, credentials.NilSecretOTP).Return(credDTO, nil)
+				mockRepo.EXPECT().SaveUser(tracingtest.FromContext(ctx), gomock.Any()).Return("", user.ErrAlreadyExists)
 			},
 			expect: func(t *testing.T, id string, err error) {
 				assert.Empty(t, id)
@@ -328,7 +329,7 @@ func TestCreateUser(t *testing.T) {
 		},
 		{
 			name: "should return error while creating a new user",
-			ctx:  context.Background(),
+			ctx:  tracingtest.Context(),
 			dto: &user.CreateUserDTO{
 				Email:    "",
 				Password: encodedPass,
@@ -336,7 +337,7 @@ func TestCreateUser(t *testing.T) {
 			setup: func(ctx context.Context, dto *user.CreateUserDTO) {
 				credDTO := credentials.MapToDTO(&testCred)
 
-				mockCred.EXPECT().CreateCredentials(ctx, encodedPass, credentials.NilSecretOTP).Return(credDTO, nil)
+				mockCred.EXPECT().CreateCredentials(tracingtest.FromContext(ctx), encodedPass, credentials.NilSecretOTP).Return(credDTO, nil)
 			},
 			expect: func(t *testing.T, id string, err error) {
 				assert.Empty(t, id)
@@ -353,3 +354,210 @@ func TestCreateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateProfile(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := mock_user.NewMockRepository(controller)
+	mockCred := mock_credentials.NewMockService(controller)
+	log := logrus.New()
+
+	secretKey := ""
+	var testCred credentials.Credentials
+	testCred.Password = "password"
+	testCred.SecretOTP = &secretKey
+
+	service, _ := user.NewService(mockRepo, mockCred, log)
+
+	displayName := "Satoshi"
+	units := "sats"
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		email  string
+		dto    *user.UpdateProfileDTO
+		setup  func(context.Context, string)
+		expect func(*testing.T, *user.ProfileDTO, error)
+	}{
+		{
+			name:  "should update only sent fields",
+			ctx:   tracingtest.Context(),
+			email: "some@mail.com",
+			dto:   &user.UpdateProfileDTO{DisplayName: &displayName, Units: &units},
+			setup: func(ctx context.Context, email string) {
+				testUser, _ := user.NewUser(email, &testCred)
+
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(testUser, nil)
+				mockRepo.EXPECT().UpdateUserFields(tracingtest.FromContext(ctx), email, testUser.Version, gomock.Any()).Return(nil)
+			},
+			expect: func(t *testing.T, p *user.ProfileDTO, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, &user.ProfileDTO{
+					DisplayName:  displayName,
+					TimeZone:     user.DefaultTimeZone,
+					FiatCurrency: user.DefaultFiatCurrency,
+					Units:        units,
+				}, p)
+			},
+		},
+		{
+			name:  "should return 'not found' error",
+			ctx:   tracingtest.Context(),
+			email: "not_existent_email",
+			dto:   &user.UpdateProfileDTO{DisplayName: &displayName},
+			setup: func(ctx context.Context, email string) {
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(nil, user.ErrNotFound)
+			},
+			expect: func(t *testing.T, p *user.ProfileDTO, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, user.ErrNotFound, err)
+			},
+		},
+		{
+			name:  "should return 'internal error' error",
+			ctx:   tracingtest.Context(),
+			email: "some@mail.com",
+			dto:   &user.UpdateProfileDTO{DisplayName: &displayName},
+			setup: func(ctx context.Context, email string) {
+				testUser, _ := user.NewUser(email, &testCred)
+
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(testUser, nil)
+				mockRepo.EXPECT().UpdateUserFields(tracingtest.FromContext(ctx), email, testUser.Version, gomock.Any()).Return(errors.NewInternal("internal error"))
+			},
+			expect: func(t *testing.T, p *user.ProfileDTO, err error) {
+				assert.Nil(t, p)
+				assert.Equal(t, errors.NewInternal("internal error"), err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup(tc.ctx, tc.email)
+			p, err := service.UpdateProfile(tc.ctx, tc.email, tc.dto)
+			tc.expect(t, p, err)
+		})
+	}
+}
+
+func TestUnitsFormatBTC(t *testing.T) {
+	tests := []struct {
+		units  user.Units
+		amount btcutil.Amount
+		want   string
+	}{
+		{units: user.UnitsBTC, amount: 50000000, want: "0.5 BTC"},
+		{units: user.UnitsMilliBTC, amount: 50000000, want: "500 mBTC"},
+		{units: user.UnitsSatoshi, amount: 50000000, want: "50000000 sats"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.units), func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.units.FormatBTC(tc.amount))
+		})
+	}
+}
+
+func TestModifyUser(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := mock_user.NewMockRepository(controller)
+	mockCred := mock_credentials.NewMockService(controller)
+	log := logrus.New()
+
+	secretKey := ""
+	var testCred credentials.Credentials
+	testCred.Password = "password"
+	testCred.SecretOTP = &secretKey
+
+	service, _ := user.NewService(mockRepo, mockCred, log)
+
+	activate := func(u *user.User) (user.Fields, error) {
+		u.SetToActive()
+		return user.Fields{user.FieldStatus: u.Status}, nil
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		email  string
+		setup  func(context.Context, string)
+		expect func(*testing.T, *user.DTO, error)
+	}{
+		{
+			name:  "should update only changed fields",
+			ctx:   tracingtest.Context(),
+			email: "some@mail.com",
+			setup: func(ctx context.Context, email string) {
+				testUser, _ := user.NewUser(email, &testCred)
+
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(testUser, nil)
+				mockRepo.EXPECT().UpdateUserFields(tracingtest.FromContext(ctx), email, int64(1), user.Fields{user.FieldStatus: user.Active}).Return(nil)
+			},
+			expect: func(t *testing.T, u *user.DTO, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, string(user.Active), u.Status)
+				assert.Equal(t, int64(2), u.Version)
+			},
+		},
+		{
+			name:  "should reload user after conflict",
+			ctx:   tracingtest.Context(),
+			email: "some@mail.com",
+			setup: func(ctx context.Context, email string) {
+				outdatedUser, _ := user.NewUser(email, &testCred)
+				reloadedUser, _ := user.NewUser(email, &testCred)
+				reloadedUser.Version = 2
+
+				gomock.InOrder(
+					mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(outdatedUser, nil),
+					mockRepo.EXPECT().UpdateUserFields(tracingtest.FromContext(ctx), email, int64(1), gomock.Any()).Return(user.ErrConflict),
+					mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(reloadedUser, nil),
+					mockRepo.EXPECT().UpdateUserFields(tracingtest.FromContext(ctx), email, int64(2), gomock.Any()).Return(nil),
+				)
+			},
+			expect: func(t *testing.T, u *user.DTO, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, int64(3), u.Version)
+			},
+		},
+		{
+			name:  "should return 'conflict' error after all attempts",
+			ctx:   tracingtest.Context(),
+			email: "some@mail.com",
+			setup: func(ctx context.Context, email string) {
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).DoAndReturn(func(context.Context, string) (*user.User, error) {
+					return user.NewUser(email, &testCred)
+				}).Times(3)
+				mockRepo.EXPECT().UpdateUserFields(tracingtest.FromContext(ctx), email, int64(1), gomock.Any()).Return(user.ErrConflict).Times(3)
+			},
+			expect: func(t *testing.T, u *user.DTO, err error) {
+				assert.Nil(t, u)
+				assert.Equal(t, user.ErrConflict, err)
+			},
+		},
+		{
+			name:  "should return 'not found' error",
+			ctx:   tracingtest.Context(),
+			email: "not_existent_email",
+			setup: func(ctx context.Context, email string) {
+				mockRepo.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), email).Return(nil, user.ErrNotFound)
+			},
+			expect: func(t *testing.T, u *user.DTO, err error) {
+				assert.Nil(t, u)
+				assert.Equal(t, user.ErrNotFound, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup(tc.ctx, tc.email)
+			u, err := service.ModifyUser(tc.ctx, tc.email, activate)
+			tc.expect(t, u, err)
+		})
+	}
+}
@@ -3,6 +3,12 @@ package user
 type (
 	Status    string
 	SecretOTP *string
+
+	// Fields is a set of user's fields to update, where key is a field path in storage
+	Fields map[string]interface{}
+
+	// ModifyFunc changes loaded user and returns fields which were changed
+	ModifyFunc func(u *User) (Fields, error)
 )
 
 const (
@@ -10,4 +16,15 @@ const (
 	Disabled Status = "disabled"
 )
 
+const (
+	FieldCredentials = "credentials"
+	FieldPassword    = "credentials.password"
+	FieldSecretOTP   = "credentials.secret_otp"
+	FieldStatus      = "status"
+	FieldIsVerified  = "is_verified"
+	FieldProfile     = "profile"
+
+	FieldAntiPhishingPhrase = "anti_phishing_phrase"
+)
+
 var NilSecretOTP SecretOTP = nil
@@ -3,7 +3,6 @@ package user
 import (
 	"nnw_s/internal/user/credentials"
 	"nnw_s/pkg/errors"
-	"nnw_s/pkg/wallet"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,13 +14,19 @@ type User struct {
 	Credentials *credentials.Credentials `bson:"credentials"`
 	Status      Status                   `bson:"status"`
 	IsVerified  bool                     `bson:"is_verified"`
-	Wallet      *[]*wallet.Wallet        `bson:"wallet"`
+	Profile     *Profile                 `bson:"profile"`
+
+	// AntiPhishingPhrase is shown in every email, so user can tell real emails from phishing ones
+	AntiPhishingPhrase string `bson:"anti_phishing_phrase,omitempty"`
+
+	// Version is incremented on every update and is used for optimistic concurrency control
+	Version int64 `bson:"version"`
 
 	CreatedAt time.Time `bson:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at"`
 }
 
-func NewUser(email string, wallet *[]*wallet.Wallet, credentials *credentials.Credentials) (*User, error) {
+func NewUser(email string, credentials *credentials.Credentials) (*User, error) {
 	if email == "" {
 		return nil, errors.WithMessage(ErrInvalidEmail, "should be not empty")
 	}
@@ -29,8 +34,9 @@ func NewUser(email string, wallet *[]*wallet.Wallet, credentials *credentials.Cr
 		ID:          primitive.NewObjectID(),
 		Email:       email,
 		Credentials: credentials,
-		Wallet:      wallet,
+		Profile:     NewProfile(),
 		Status:      Disabled,
+		Version:     1,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}, nil
@@ -50,6 +56,12 @@ func (u *User) SetToActive() {
 	u.UpdatedAt = time.Now()
 }
 
-func (u *User) SetWallet(wallets *[]*wallet.Wallet) {
-	u.Wallet = wallets
+func (u *User) SetProfile(profile *Profile) {
+	u.Profile = profile
+	u.UpdatedAt = time.Now()
+}
+
+func (u *User) SetAntiPhishingPhrase(phrase string) {
+	u.AntiPhishingPhrase = phrase
+	u.UpdatedAt = time.Now()
 }
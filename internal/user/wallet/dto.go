@@ -3,15 +3,15 @@ package wallet
 import (
 	"github.com/go-playground/validator/v10"
 	"math/big"
-	"nnw_s/pkg/errors"
 	"nnw_s/pkg/helpers"
+	"nnw_s/pkg/validation"
 	"time"
 )
 
 const passwordMinLength = 8
 
 func Validate(dto interface{}, shift int) error {
-	validate := validator.New()
+	validate := validation.New()
 
 	_ = validate.RegisterValidation("password", func(fl validator.FieldLevel) bool {
 		password := fl.Field().String()
@@ -29,15 +29,7 @@ func Validate(dto interface{}, shift int) error {
 	})
 
 	if err := validate.Struct(dto); err != nil {
-		if _, ok := err.(*validator.InvalidValidationError); ok {
-			return errors.WithMessage(ErrInvalidRequest, err.Error())
-		}
-
-		validationErr := ErrInvalidRequest
-		for _, err := range err.(validator.ValidationErrors) {
-			validationErr = errors.WithMessage(validationErr, err.Error())
-		}
-		return validationErr
+		return validation.Error(ErrInvalidRequest, err)
 	}
 	return nil
 }
@@ -46,6 +38,7 @@ type BalanceDTO struct {
 	Balance    float64  `json:"balance"`
 	BalanceInt *big.Int `json:"balance_int"`
 	BalanceStr string   `json:"balance_str"`
+	Unit       string   `json:"unit"`
 }
 
 type TxsDTO struct {
@@ -66,13 +59,15 @@ type TxsDTO struct {
 type InputTxDTO struct {
 	//Vin     int64   `json:"vin"`
 	//TxId    string  `json:"txid"`
-	Address string  `json:"address"`
-	Value   float64 `json:"value"`
+	Address  string  `json:"address"`
+	Value    float64 `json:"value"`
+	ValueStr string  `json:"value_str"`
 }
 
 type OutTxDTO struct {
-	Address string  `json:"address"`
-	Value   float64 `json:"value"`
+	Address  string  `json:"address"`
+	Value    float64 `json:"value"`
+	ValueStr string  `json:"value_str"`
 }
 
 type CreateWalletDTO struct {
@@ -81,11 +76,36 @@ type CreateWalletDTO struct {
 	Jwt      string `json:"jwt" validate:"required"`
 }
 
+type WalletDTO struct {
+	WalletId string    `json:"wallet_id"`
+	Chain    string    `json:"chain"`
+	Address  string    `json:"address"`
+	Label    string    `json:"label"`
+	Archived bool      `json:"archived"`
+	Created  time.Time `json:"created_at"`
+}
+
 type GetWalletDTO struct {
 	Jwt      string `json:"jwt" validate:"required"`
 	WalletId string `json:"wallet_id" validate:"required"`
 }
 
+type GetWalletsDTO struct {
+	Jwt             string `json:"jwt" validate:"required"`
+	IncludeArchived bool   `json:"include_archived"`
+}
+
+type RenameWalletDTO struct {
+	Jwt      string `json:"jwt" validate:"required"`
+	WalletId string `json:"wallet_id" validate:"required"`
+	Label    string `json:"label" validate:"required,max=64"`
+}
+
+type ArchiveWalletDTO struct {
+	Jwt      string `json:"jwt" validate:"required"`
+	WalletId string `json:"wallet_id" validate:"required"`
+}
+
 type UnlockWalletDTO struct {
 	Jwt      string `json:"jwt" validate:"required"`
 	Name     string `json:"name" validate:"required"`
@@ -106,12 +126,14 @@ type GetWalletTxDTO struct {
 	Address  string `json:"address" validate:"required"`
 }
 
+// CreateTxDTO has either raw ToAddress or ContactId from user's address book, but not both of them
 type CreateTxDTO struct {
 	Jwt         string  `json:"jwt" validate:"required"`
 	Name        string  `json:"name" validate:"required"`
 	WalletId    string  `json:"wallet_id" validate:"required"`
 	FromAddress string  `json:"from_address" validate:"required"`
-	ToAddress   string  `json:"to_address" validate:"required"`
+	ToAddress   string  `json:"to_address" validate:"required_without=ContactId,excluded_with=ContactId"`
+	ContactId   string  `json:"contact_id" validate:"required_without=ToAddress,excluded_with=ToAddress"`
 	Amount      float64 `json:"amount" validate:"required"`
 }
 
@@ -120,6 +142,7 @@ type SendTxDTO struct {
 	Name        string  `json:"name" validate:"required"`
 	WalletId    string  `json:"wallet_id" validate:"required"`
 	FromAddress string  `json:"from_address" validate:"required"`
+	ToAddress   string  `json:"to_address"`
 	NotSignTx   string  `json:"not_sign_tx" validate:"required"`
 	Amount      float64 `json:"amount" validate:"required"`
 	Password    string  `json:"password" validate:"required,password"`
@@ -6,11 +6,23 @@ import (
 )
 
 const (
-	StatusInvalidRequest errors.Status = "invalid_request"
-	StatusInvalidWallet  errors.Status = "invalid_wallet"
+	StatusInvalidRequest       errors.Status = "invalid_request"
+	StatusInvalidWallet        errors.Status = "invalid_wallet"
+	StatusWalletNotFound       errors.Status = "wallet_not_found"
+	StatusWalletAlreadyExists  errors.Status = "wallet_already_exists"
+	StatusWalletAlreadyArchive errors.Status = "wallet_already_archived"
+	StatusChainDisabled        errors.Status = "chain_disabled"
+	StatusInvalidAddress       errors.Status = "invalid_address"
+	StatusWrongNetwork         errors.Status = "wrong_network_address"
 )
 
 var (
-	ErrInvalidRequest = errors.New(codes.BadRequest, StatusInvalidRequest)
-	ErrInvalidWallet  = errors.New(codes.BadRequest, StatusInvalidWallet)
+	ErrInvalidRequest        = errors.New(codes.BadRequest, StatusInvalidRequest)
+	ErrInvalidWallet         = errors.New(codes.BadRequest, StatusInvalidWallet)
+	ErrNotFound              = errors.New(codes.NotFound, StatusWalletNotFound)
+	ErrAlreadyExists         = errors.New(codes.DuplicateError, StatusWalletAlreadyExists)
+	ErrWalletAlreadyArchived = errors.New(codes.BadRequest, StatusWalletAlreadyArchive)
+	ErrChainDisabled         = errors.New(codes.BadRequest, StatusChainDisabled)
+	ErrInvalidAddress        = errors.New(codes.BadRequest, StatusInvalidAddress)
+	ErrWrongNetwork          = errors.New(codes.BadRequest, StatusWrongNetwork)
 )
@@ -26,6 +26,9 @@ func (h *Handler) SetupRoutes(router *echo.Echo) {
 
 	// Get wallet
 	v1.POST("/get-wallet", h.getWallet)
+	v1.POST("/get-wallets", h.getWallets)
+	v1.POST("/rename-wallet", h.renameWallet)
+	v1.POST("/archive-wallet", h.archiveWallet)
 
 	// Create wallet
 	v1.POST("/create-wallet", h.createWallet)
@@ -43,21 +46,21 @@ func (h *Handler) createWallet(ctx echo.Context) error {
 	var dto CreateWalletDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	walletPayload, err := h.walletSvc.CreateWallet(ctx.Request().Context(), &dto, jwtPayload.Email, h.shift)
 	if err != nil {
-		return ctx.JSON(errors.HTTPCode(err), err)
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, walletPayload)
@@ -67,21 +70,93 @@ func (h *Handler) getWallet(ctx echo.Context) error {
 	var dto GetWalletDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	walletPayload, err := h.walletSvc.GetWallet(ctx.Request().Context(), jwtPayload.Email, dto.WalletId)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, walletPayload)
+}
+
+func (h *Handler) getWallets(ctx echo.Context) error {
+	var dto GetWalletsDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto, h.shift); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	wallets, err := h.walletSvc.ListWallets(ctx.Request().Context(), jwtPayload.Email, dto.IncludeArchived)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, wallets)
+}
+
+func (h *Handler) renameWallet(ctx echo.Context) error {
+	var dto RenameWalletDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto, h.shift); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	walletPayload, err := h.walletSvc.RenameWallet(ctx.Request().Context(), jwtPayload.Email, dto.WalletId, dto.Label)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, walletPayload)
+}
+
+func (h *Handler) archiveWallet(ctx echo.Context) error {
+	var dto ArchiveWalletDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto, h.shift); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	walletPayload, err := h.walletSvc.ArchiveWallet(ctx.Request().Context(), jwtPayload.Email, dto.WalletId)
+	if err != nil {
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, walletPayload)
@@ -91,21 +166,21 @@ func (h *Handler) getBalance(ctx echo.Context) error {
 	var dto GetWalletBalanceDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
-	_, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
-	balance, err := h.walletSvc.GetBalance(ctx.Request().Context(), &dto)
+	balance, err := h.walletSvc.GetBalance(ctx.Request().Context(), &dto, jwtPayload.Email)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, balance)
@@ -115,21 +190,21 @@ func (h *Handler) getTX(ctx echo.Context) error {
 	var dto GetWalletTxDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
-	_, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
-	txs, err := h.walletSvc.GetWalletTx(ctx.Request().Context(), &dto)
+	txs, err := h.walletSvc.GetWalletTx(ctx.Request().Context(), &dto, jwtPayload.Email)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, txs)
@@ -139,21 +214,24 @@ func (h *Handler) createTx(ctx echo.Context) error {
 	var dto CreateTxDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
-	_, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
-	notSignedTx, fee, err := h.walletSvc.CreateTx(ctx.Request().Context(), &dto)
+	notSignedTx, fee, err := h.walletSvc.CreateTx(ctx.Request().Context(), &dto, jwtPayload.Email)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		if _, ok := err.(*errors.Error); ok {
+			return err
+		}
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	return ctx.JSON(200, map[string]interface{}{"from": dto.FromAddress, "to": dto.ToAddress, "amount": dto.Amount, "fee": fee, "nstx": notSignedTx})
@@ -163,21 +241,21 @@ func (h *Handler) sendTx(ctx echo.Context) error {
 	var dto SendTxDTO
 
 	if err := ctx.Bind(&dto); err != nil {
-		return ctx.JSON(http.StatusBadRequest, errors.WithMessage(ErrInvalidRequest, err.Error()))
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
 	}
 
 	if err := Validate(dto, h.shift); err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	txHash, err := h.walletSvc.SendTx(ctx.Request().Context(), &dto, jwtPayload.Email)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, err)
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, map[string]string{"txHash": txHash})
@@ -0,0 +1,20 @@
+package wallet
+
+func MapToDTO(w *Wallet) *WalletDTO {
+	return &WalletDTO{
+		WalletId: w.WalletID,
+		Chain:    w.Chain,
+		Address:  w.Address,
+		Label:    w.Label,
+		Archived: w.Archived,
+		Created:  w.CreatedAt,
+	}
+}
+
+func MapToDTOs(wallets []*Wallet) []*WalletDTO {
+	dtos := make([]*WalletDTO, 0, len(wallets))
+	for _, w := range wallets {
+		dtos = append(dtos, MapToDTO(w))
+	}
+	return dtos
+}
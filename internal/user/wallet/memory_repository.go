@@ -0,0 +1,116 @@
+package wallet
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type memoryRepository struct {
+	mu      sync.RWMutex
+	wallets map[string]Wallet // by wallet id
+	orphans []Orphan
+}
+
+// NewMemoryRepository returns thread-safe repository which keeps wallets in memory
+func NewMemoryRepository() (Repository, error) {
+	return &memoryRepository{wallets: make(map[string]Wallet)}, nil
+}
+
+func (repo *memoryRepository) GetWallet(_ context.Context, userID, walletID string) (*Wallet, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	w, ok := repo.wallets[walletID]
+	if !ok || w.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return &w, nil
+}
+
+func (repo *memoryRepository) GetWallets(_ context.Context, userID string, includeArchived bool) ([]*Wallet, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	wallets := make([]*Wallet, 0)
+	for _, w := range repo.wallets {
+		if w.UserID != userID || (w.Archived && !includeArchived) {
+			continue
+		}
+		found := w
+		wallets = append(wallets, &found)
+	}
+
+	sort.SliceStable(wallets, func(i, j int) bool {
+		return wallets[i].CreatedAt.Before(wallets[j].CreatedAt)
+	})
+	return wallets, nil
+}
+
+func (repo *memoryRepository) GetActiveWallets(_ context.Context) ([]*Wallet, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	wallets := make([]*Wallet, 0)
+	for _, w := range repo.wallets {
+		if w.Archived {
+			continue
+		}
+		found := w
+		wallets = append(wallets, &found)
+	}
+
+	sort.SliceStable(wallets, func(i, j int) bool {
+		return wallets[i].CreatedAt.Before(wallets[j].CreatedAt)
+	})
+	return wallets, nil
+}
+
+func (repo *memoryRepository) SaveWallets(_ context.Context, wallets []*Wallet) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, w := range wallets {
+		if _, ok := repo.wallets[w.WalletID]; ok {
+			return ErrAlreadyExists
+		}
+	}
+
+	for _, w := range wallets {
+		repo.wallets[w.WalletID] = *w
+	}
+	return nil
+}
+
+func (repo *memoryRepository) UpdateWallet(_ context.Context, wallet *Wallet) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	current, ok := repo.wallets[wallet.WalletID]
+	if !ok || current.ID != wallet.ID {
+		return ErrNotFound
+	}
+
+	repo.wallets[wallet.WalletID] = *wallet
+	return nil
+}
+
+func (repo *memoryRepository) SaveOrphan(_ context.Context, orphan *Orphan) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.orphans = append(repo.orphans, *orphan)
+	return nil
+}
+
+func (repo *memoryRepository) GetOrphans(_ context.Context) ([]*Orphan, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	orphans := make([]*Orphan, 0, len(repo.orphans))
+	for _, o := range repo.orphans {
+		found := o
+		orphans = append(orphans, &found)
+	}
+	return orphans, nil
+}
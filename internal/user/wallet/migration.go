@@ -0,0 +1,81 @@
+package wallet
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/wallet"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// legacyUser is a user document which still has wallets embedded
+type legacyUser struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Email     string             `bson:"email"`
+	Wallets   []*wallet.Wallet   `bson:"wallet"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// MigrateEmbeddedWallets moves wallets embedded into user documents to the wallets collection.
+// Wallets are upserted by wallet id, so the migration can be safely run several times.
+func MigrateEmbeddedWallets(ctx context.Context, db *mongo.Database, log *logrus.Logger) error {
+	cursor, err := db.Collection("user").Find(ctx, bson.M{"wallet": bson.M{"$exists": true}})
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to find users with embedded wallets: %v", err)
+		return errors.NewInternal(err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	var migrated int
+	for cursor.Next(ctx) {
+		var u legacyUser
+		if err = cursor.Decode(&u); err != nil {
+			log.WithContext(ctx).Errorf("failed to decode user with embedded wallets: %v", err)
+			return errors.NewInternal(err.Error())
+		}
+
+		for _, w := range u.Wallets {
+			if w == nil || w.WalletId == "" {
+				continue
+			}
+
+			entity, err := NewWallet(u.ID.Hex(), w.Name, w.WalletId, w.Address)
+			if err != nil {
+				log.WithContext(ctx).Errorf("skip invalid embedded wallet of user '%s': %v", u.Email, err)
+				continue
+			}
+			if !u.CreatedAt.IsZero() {
+				entity.CreatedAt = u.CreatedAt
+			}
+
+			_, err = db.Collection(walletsCollection).UpdateOne(ctx,
+				bson.M{"wallet_id": entity.WalletID},
+				bson.M{"$setOnInsert": entity},
+				options.Update().SetUpsert(true))
+			if err != nil {
+				log.WithContext(ctx).Errorf("failed to migrate wallet '%s' of user '%s': %v", w.WalletId, u.Email, err)
+				return errors.NewInternal(err.Error())
+			}
+		}
+
+		if _, err = db.Collection("user").UpdateOne(ctx, bson.M{"_id": u.ID}, bson.M{"$unset": bson.M{"wallet": ""}}); err != nil {
+			log.WithContext(ctx).Errorf("failed to remove embedded wallets of user '%s': %v", u.Email, err)
+			return errors.NewInternal(err.Error())
+		}
+		migrated++
+	}
+
+	if err = cursor.Err(); err != nil {
+		return errors.NewInternal(err.Error())
+	}
+
+	if migrated > 0 {
+		log.WithContext(ctx).Infof("moved embedded wallets of %d users to the wallets collection", migrated)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package wallet
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Orphan is a wallet created on a node for user's wallets which failed to be saved. Nodes can't delete
+// wallets by RPC, BTC wallet is only unloaded and ETH account stays in keystore of the node, so their
+// files are removed by cleanup of orphans.
+type Orphan struct {
+	ID     primitive.ObjectID `bson:"_id"`
+	UserID string             `bson:"user_id"`
+	Chain  string             `bson:"chain"`
+	// NodeWallet is a name of wallet on BTC node or address of account on ETH node
+	NodeWallet string `bson:"node_wallet"`
+
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func NewOrphan(userID, chain, nodeWallet string) *Orphan {
+	return &Orphan{
+		ID:         primitive.NewObjectID(),
+		UserID:     userID,
+		Chain:      chain,
+		NodeWallet: nodeWallet,
+		CreatedAt:  time.Now(),
+	}
+}
@@ -0,0 +1,181 @@
+package wallet
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	walletsCollection = "wallets"
+	orphansCollection = "orphan_wallets"
+)
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository_mock.go
+type Repository interface {
+	GetWallet(ctx context.Context, userID, walletID string) (*Wallet, error)
+	GetWallets(ctx context.Context, userID string, includeArchived bool) ([]*Wallet, error)
+	// GetActiveWallets returns not archived wallets of every user
+	GetActiveWallets(ctx context.Context) ([]*Wallet, error)
+	SaveWallets(ctx context.Context, wallets []*Wallet) error
+	UpdateWallet(ctx context.Context, wallet *Wallet) error
+
+	// SaveOrphan records wallet left on a node by failed creation of user's wallets
+	SaveOrphan(ctx context.Context, orphan *Orphan) error
+	// GetOrphans returns recorded orphans for cleanup of nodes
+	GetOrphans(ctx context.Context) ([]*Orphan, error)
+}
+
+type repository struct {
+	db  *mongo.Database
+	log *logrus.Logger
+}
+
+func NewRepository(db *mongo.Database, log *logrus.Logger) (Repository, error) {
+	if db == nil {
+		return nil, errors.NewInternal("invalid db")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &repository{db: db, log: log}, nil
+}
+
+// CreateIndexes creates indexes of wallets collection, wallet id is unique
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	mods := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "wallet_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "archived", Value: 1}}},
+		{Keys: bson.D{{Key: "chain", Value: 1}}},
+		{Keys: bson.D{{Key: "address", Value: 1}}},
+	}
+
+	return migrations.CreateIndexes(ctx, db.Collection(walletsCollection), mods...)
+}
+
+func (repo *repository) GetWallet(ctx context.Context, userID, walletID string) (*Wallet, error) {
+	var wallet Wallet
+	err := repo.db.
+		Collection(walletsCollection).
+		FindOne(ctx, bson.M{"user_id": userID, "wallet_id": walletID}).
+		Decode(&wallet)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			repo.log.WithContext(ctx).Errorf("unable to find wallet by id '%s': %v", walletID, err)
+			return nil, ErrNotFound
+		}
+
+		repo.log.WithContext(ctx).Errorf("unable to find wallet due to internal error: %v; wallet id: %s", err, walletID)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return &wallet, nil
+}
+
+func (repo *repository) GetWallets(ctx context.Context, userID string, includeArchived bool) ([]*Wallet, error) {
+	filter := bson.M{"user_id": userID}
+	if !includeArchived {
+		filter["archived"] = false
+	}
+
+	cursor, err := repo.db.
+		Collection(walletsCollection).
+		Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to find user wallets due to internal error: %v; user id: %s", err, userID)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	wallets := make([]*Wallet, 0)
+	if err = cursor.All(ctx, &wallets); err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to decode user wallets: %v; user id: %s", err, userID)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return wallets, nil
+}
+
+func (repo *repository) GetActiveWallets(ctx context.Context) ([]*Wallet, error) {
+	cursor, err := repo.db.
+		Collection(walletsCollection).
+		Find(ctx, bson.M{"archived": false}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to find active wallets due to internal error: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	wallets := make([]*Wallet, 0)
+	if err = cursor.All(ctx, &wallets); err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to decode active wallets: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return wallets, nil
+}
+
+func (repo *repository) SaveWallets(ctx context.Context, wallets []*Wallet) error {
+	docs := make([]interface{}, 0, len(wallets))
+	for _, w := range wallets {
+		docs = append(docs, w)
+	}
+
+	if _, err := repo.db.Collection(walletsCollection).InsertMany(ctx, docs); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			repo.log.WithContext(ctx).Errorf("failed to insert wallets to db due to duplicate error: %v", err)
+			return ErrAlreadyExists
+		}
+
+		repo.log.WithContext(ctx).Errorf("failed to insert wallets to db: %v", err)
+		return errors.NewInternal(err.Error())
+	}
+
+	return nil
+}
+
+func (repo *repository) UpdateWallet(ctx context.Context, wallet *Wallet) error {
+	res, err := repo.db.
+		Collection(walletsCollection).
+		UpdateOne(ctx, bson.M{"_id": wallet.ID}, bson.M{"$set": wallet})
+
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to update wallet '%s': %v", wallet.WalletID, err)
+		return errors.NewInternal(err.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (repo *repository) SaveOrphan(ctx context.Context, orphan *Orphan) error {
+	if _, err := repo.db.Collection(orphansCollection).InsertOne(ctx, orphan); err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to record orphan %s wallet '%s': %v", orphan.Chain, orphan.NodeWallet, err)
+		return errors.NewInternal(err.Error())
+	}
+	return nil
+}
+
+func (repo *repository) GetOrphans(ctx context.Context) ([]*Orphan, error) {
+	cursor, err := repo.db.
+		Collection(orphansCollection).
+		Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to find orphan wallets due to internal error: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	orphans := make([]*Orphan, 0)
+	if err = cursor.All(ctx, &orphans); err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to decode orphan wallets: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return orphans, nil
+}
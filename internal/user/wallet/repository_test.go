@@ -0,0 +1,142 @@
+package wallet_test
+
+import (
+	"context"
+	"nnw_s/internal/user/wallet"
+	"nnw_s/pkg/mongodb/mongotest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) wallet.Repository {
+		repo, err := wallet.NewMemoryRepository()
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMongoRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) wallet.Repository {
+		db := mongotest.DB(t)
+		require.Nil(t, wallet.CreateIndexes(context.Background(), db))
+
+		repo, err := wallet.NewRepository(db, logrus.New())
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+// testRepository is a conformance suite which every wallet.Repository implementation should pass
+func testRepository(t *testing.T, newRepo func(t *testing.T) wallet.Repository) {
+	ctx := context.Background()
+
+	newWallet := func(t *testing.T, userID, chain, walletID string) *wallet.Wallet {
+		w, err := wallet.NewWallet(userID, chain, walletID, "address_"+walletID)
+		require.Nil(t, err)
+		return w
+	}
+
+	t.Run("should save and get wallets", func(t *testing.T) {
+		repo := newRepo(t)
+		btc := newWallet(t, "user_id", "BTC", "btc_wallet")
+		eth := newWallet(t, "user_id", "ETH", "eth_wallet")
+
+		require.Nil(t, repo.SaveWallets(ctx, []*wallet.Wallet{btc, eth}))
+
+		loaded, err := repo.GetWallet(ctx, "user_id", btc.WalletID)
+		require.Nil(t, err)
+		assert.Equal(t, btc.ID, loaded.ID)
+		assert.Equal(t, btc.Address, loaded.Address)
+
+		wallets, err := repo.GetWallets(ctx, "user_id", false)
+		require.Nil(t, err)
+		assert.Len(t, wallets, 2)
+	})
+
+	t.Run("should not return wallet of another user", func(t *testing.T) {
+		repo := newRepo(t)
+		w := newWallet(t, "user_id", "BTC", "btc_wallet")
+
+		require.Nil(t, repo.SaveWallets(ctx, []*wallet.Wallet{w}))
+
+		_, err := repo.GetWallet(ctx, "other_user_id", w.WalletID)
+		assert.Equal(t, wallet.ErrNotFound, err)
+
+		wallets, err := repo.GetWallets(ctx, "other_user_id", true)
+		require.Nil(t, err)
+		assert.Empty(t, wallets)
+	})
+
+	t.Run("should return 'already exists' error", func(t *testing.T) {
+		repo := newRepo(t)
+
+		require.Nil(t, repo.SaveWallets(ctx, []*wallet.Wallet{newWallet(t, "user_id", "BTC", "btc_wallet")}))
+
+		err := repo.SaveWallets(ctx, []*wallet.Wallet{newWallet(t, "user_id", "BTC", "btc_wallet")})
+		assert.Equal(t, wallet.ErrAlreadyExists, err)
+	})
+
+	t.Run("should update wallet and skip archived ones", func(t *testing.T) {
+		repo := newRepo(t)
+		w := newWallet(t, "user_id", "BTC", "btc_wallet")
+
+		require.Nil(t, repo.SaveWallets(ctx, []*wallet.Wallet{w}))
+
+		w.Rename("Savings")
+		w.Archive()
+		require.Nil(t, repo.UpdateWallet(ctx, w))
+
+		loaded, err := repo.GetWallet(ctx, "user_id", w.WalletID)
+		require.Nil(t, err)
+		assert.Equal(t, "Savings", loaded.Label)
+		assert.True(t, loaded.Archived)
+
+		wallets, err := repo.GetWallets(ctx, "user_id", false)
+		require.Nil(t, err)
+		assert.Empty(t, wallets)
+
+		wallets, err = repo.GetWallets(ctx, "user_id", true)
+		require.Nil(t, err)
+		assert.Len(t, wallets, 1)
+	})
+
+	t.Run("should return active wallets of every user", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := newWallet(t, "alice_id", "BTC", "alice_wallet")
+		bob := newWallet(t, "bob_id", "BTC", "bob_wallet")
+		archived := newWallet(t, "bob_id", "ETH", "archived_wallet")
+		archived.Archive()
+
+		require.Nil(t, repo.SaveWallets(ctx, []*wallet.Wallet{alice, bob, archived}))
+
+		wallets, err := repo.GetActiveWallets(ctx)
+		require.Nil(t, err)
+		require.Len(t, wallets, 2)
+		ids := []string{wallets[0].WalletID, wallets[1].WalletID}
+		assert.ElementsMatch(t, []string{"alice_wallet", "bob_wallet"}, ids)
+	})
+
+	t.Run("should return 'not found' error on update of unknown wallet", func(t *testing.T) {
+		repo := newRepo(t)
+
+		err := repo.UpdateWallet(ctx, newWallet(t, "user_id", "BTC", "btc_wallet"))
+		assert.Equal(t, wallet.ErrNotFound, err)
+	})
+
+	t.Run("should record orphans", func(t *testing.T) {
+		repo := newRepo(t)
+
+		require.Nil(t, repo.SaveOrphan(ctx, wallet.NewOrphan("user_id", "BTC", "btc_wallet")))
+		require.Nil(t, repo.SaveOrphan(ctx, wallet.NewOrphan("user_id", "ETH", "0xaccount")))
+
+		orphans, err := repo.GetOrphans(ctx)
+		require.Nil(t, err)
+		require.Len(t, orphans, 2)
+		nodeWallets := []string{orphans[0].Chain + ":" + orphans[0].NodeWallet, orphans[1].Chain + ":" + orphans[1].NodeWallet}
+		assert.ElementsMatch(t, []string{"BTC:btc_wallet", "ETH:0xaccount"}, nodeWallets)
+	})
+}
@@ -2,58 +2,93 @@ package wallet
 
 import (
 	"context"
+	stderrors "errors"
 	"github.com/btcsuite/btcutil"
 	"github.com/sirupsen/logrus"
 	"github.com/tyler-smith/go-bip39"
+	"go.opentelemetry.io/otel/attribute"
 	"math/big"
 	"nnw_s/internal/auth/jwt"
 	"nnw_s/internal/auth/twofa"
+	"nnw_s/internal/events"
 	"nnw_s/internal/user"
+	"nnw_s/internal/user/addressbook"
 	"nnw_s/internal/user/credentials"
 	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
 	"nnw_s/pkg/helpers"
+	"nnw_s/pkg/tracing"
+	"nnw_s/pkg/uow"
 	"nnw_s/pkg/wallet"
 	btc_rpc "nnw_s/pkg/wallet/Bitcoin/rpc"
 	btc_transaction "nnw_s/pkg/wallet/Bitcoin/transaction"
 	btc_wallet "nnw_s/pkg/wallet/Bitcoin/wallet"
 	eth_rpc "nnw_s/pkg/wallet/Ethereum/rpc"
 	eth_wallet "nnw_s/pkg/wallet/Ethereum/wallet"
+	"strconv"
 )
 
-//go:generate mockgen -source=wallet_service.go -destination=mocks/wallet_service_mock.go
+//go:generate mockgen -source=service.go -destination=mocks/service_mock.go
 type Service interface {
 	CreateWallet(ctx context.Context, dto *CreateWalletDTO, email string, shift int) (*string, error)
-	GetWallet(ctx context.Context, email string, walletId string) (*wallet.Wallet, error)
-	GetBalance(ctx context.Context, dto *GetWalletBalanceDTO) (*BalanceDTO, error)
-	GetWalletTx(ctx context.Context, dto *GetWalletTxDTO) ([]*TxsDTO, error)
-
-	CreateTx(ctx context.Context, dto *CreateTxDTO) (string, string, error)
+	GetWallet(ctx context.Context, email string, walletId string) (*WalletDTO, error)
+	ListWallets(ctx context.Context, email string, includeArchived bool) ([]*WalletDTO, error)
+	// WatchedWallets returns not archived wallets of every user by email of the owner
+	WatchedWallets(ctx context.Context) (map[string][]*WalletDTO, error)
+	RenameWallet(ctx context.Context, email string, walletId string, label string) (*WalletDTO, error)
+	ArchiveWallet(ctx context.Context, email string, walletId string) (*WalletDTO, error)
+	GetBalance(ctx context.Context, dto *GetWalletBalanceDTO, email string) (*BalanceDTO, error)
+	GetWalletTx(ctx context.Context, dto *GetWalletTxDTO, email string) ([]*TxsDTO, error)
+
+	CreateTx(ctx context.Context, dto *CreateTxDTO, email string) (string, string, error)
 	SendTx(ctx context.Context, dto *SendTxDTO, email string) (string, error)
 }
 
 type walletSvc struct {
+	repo           Repository
 	userSvc        user.Service
+	addressBookSvc addressbook.Service
 	twoFaSvc       twofa.Service
 	jwtSvc         jwt.Service
 	credentialsSvc credentials.Service
+	eventBus       eventbus.Publisher
+	uow            uow.UnitOfWork
+	btcClient      *btc_rpc.Client
+	btcNetwork     *wallet.BTCNetwork
+	ethClient      *eth_rpc.Client
 
 	log *logrus.Logger
 }
 
 type ServiceDeps struct {
+	WalletRepository   Repository
 	UserService        user.Service
+	AddressBookService addressbook.Service
 	TwoFAService       twofa.Service
 	JWTService         jwt.Service
 	CredentialsService credentials.Service
+	EventBus           eventbus.Publisher
+	UnitOfWork         uow.UnitOfWork
+	// BTCClient and ETHClient are clients of nodes, chain is disabled if its client is nil
+	BTCClient *btc_rpc.Client
+	ETHClient *eth_rpc.Client
+	// BTCNetwork defines encoding of BTC addresses and keys, it's required if BTC is enabled
+	BTCNetwork *wallet.BTCNetwork
 }
 
 func NewWalletService(log *logrus.Logger, deps *ServiceDeps) (Service, error) {
 	if deps == nil {
 		return nil, errors.NewInternal("invalid service dependencies")
 	}
+	if deps.WalletRepository == nil {
+		return nil, errors.NewInternal("invalid wallet repository")
+	}
 	if deps.UserService == nil {
 		return nil, errors.NewInternal("invalid user service")
 	}
+	if deps.AddressBookService == nil {
+		return nil, errors.NewInternal("invalid address book service")
+	}
 	if deps.TwoFAService == nil {
 		return nil, errors.NewInternal("invalid TwoFA service")
 	}
@@ -63,39 +98,93 @@ func NewWalletService(log *logrus.Logger, deps *ServiceDeps) (Service, error) {
 	if deps.CredentialsService == nil {
 		return nil, errors.NewInternal("invalid credentials service")
 	}
+	if deps.EventBus == nil {
+		return nil, errors.NewInternal("invalid event bus")
+	}
+	if deps.UnitOfWork == nil {
+		return nil, errors.NewInternal("invalid unit of work")
+	}
+	if deps.BTCClient != nil && deps.BTCNetwork == nil {
+		return nil, errors.NewInternal("invalid BTC network")
+	}
 	if log == nil {
 		return nil, errors.NewInternal("invalid logger")
 	}
 	return &walletSvc{
+		repo:           deps.WalletRepository,
 		userSvc:        deps.UserService,
+		addressBookSvc: deps.AddressBookService,
 		twoFaSvc:       deps.TwoFAService,
 		jwtSvc:         deps.JWTService,
 		credentialsSvc: deps.CredentialsService,
+		eventBus:       deps.EventBus,
+		uow:            deps.UnitOfWork,
+		btcClient:      deps.BTCClient,
+		btcNetwork:     deps.BTCNetwork,
+		ethClient:      deps.ETHClient,
 		log:            log,
 	}, nil
 }
 
+// enabledChains returns names of chains which have node clients
+func (svc *walletSvc) enabledChains() []string {
+	var chains []string
+	if svc.btcClient != nil {
+		chains = append(chains, "BTC")
+	}
+	if svc.ethClient != nil {
+		chains = append(chains, "ETH")
+	}
+	return chains
+}
+
+func (svc *walletSvc) checkChain(chain string) error {
+	if (chain == "BTC" && svc.btcClient == nil) || (chain == "ETH" && svc.ethClient == nil) {
+		return errors.WithMessage(ErrChainDisabled, chain)
+	}
+	return nil
+}
+
+// checkBTCAddress checks that address belongs to the network of BTC node
+func (svc *walletSvc) checkBTCAddress(address string) error {
+	_, err := svc.btcNetwork.DecodeAddress(address)
+	switch {
+	case err == nil:
+		return nil
+	case stderrors.Is(err, wallet.ErrWrongNetwork):
+		return errors.WithMessage(ErrWrongNetwork, err.Error())
+	default:
+		return errors.WithMessage(ErrInvalidAddress, err.Error())
+	}
+}
+
 func (svc *walletSvc) CreateWallet(ctx context.Context, dto *CreateWalletDTO, email string, shift int) (*string, error) {
-	userDTO, _ := svc.userSvc.GetUserByEmail(ctx, email)
+	ctx, span := tracing.Start(ctx, "wallet.CreateWallet")
+	defer span.End()
+
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
 
 	decodePass, err := svc.credentialsSvc.DecodePassword(ctx, dto.Password)
 	if err != nil {
 		return nil, err
 	}
 
-	walletNameMap := []string{"BTC", "ETH"}
-	var wallets []*wallet.Wallet
+	walletNameMap := svc.enabledChains()
+	var wallets []*Wallet
 
 	// Create BTC wallets
 	//var walletPayload *btc_wallet.Payload
 	//if *dto.Backup {
 	//	// need to put user mnemonic
-	//	walletPayload, err = btc_wallet.CreateBTCWallet(*dto.Backup, decodePass, "")
+	//	walletPayload, err = btc_wallet.CreateBTCWallet(ctx, svc.btcClient, svc.orphanRecorder(userDTO.ID, "BTC"), *dto.Backup, decodePass, "")
 	//	if err != nil {
 	//		return nil, err
 	//	}
 	//} else {
-	//	walletPayload, err = btc_wallet.CreateBTCWallet(*dto.Backup, decodePass, "")
+	//	walletPayload, err = btc_wallet.CreateBTCWallet(ctx, svc.btcClient, svc.orphanRecorder(userDTO.ID, "BTC"), *dto.Backup, decodePass, "")
 	//	if err != nil {
 	//		return nil, err
 	//	}
@@ -110,108 +199,211 @@ func (svc *walletSvc) CreateWallet(ctx context.Context, dto *CreateWalletDTO, em
 		return nil, err
 	}
 
-	for _, w := range walletNameMap {
-		switch w {
-		case "BTC":
+	// wallets created on nodes are compensated if saving of wallets fails, nodes can't delete them by RPC,
+	// so they are recorded as orphans for cleanup. Nodes are called before the transaction, so slow nodes
+	// don't hold it open.
+	err = svc.uow.Compensate(ctx, func(ctx context.Context) error {
+		for _, w := range walletNameMap {
+			switch w {
+			case "BTC":
 
-			walletKey, err := wallet.CreateWallet(wallet.BTCCoinType, mnemonic)
-			if err != nil {
-				return nil, err
-			}
+				walletKey, err := svc.btcNetwork.CreateKey(mnemonic)
+				if err != nil {
+					return err
+				}
 
-			walletPayload, err := wallet.ToBTCWallet(walletKey)
-			if err != nil {
-				return nil, err
-			}
+				walletPayload, err := wallet.ToBTCWallet(walletKey, svc.btcNetwork)
+				if err != nil {
+					return err
+				}
 
-			userWalletPayload, err := btc_wallet.CreateBTCWallet(*dto.Backup, decodePass, walletPayload.PrivateKey, walletPayload.Address, mnemonic)
-			if err != nil {
-				return nil, err
-			}
+				userWalletPayload, err := btc_wallet.CreateBTCWallet(ctx, svc.btcClient, svc.orphanRecorder(userDTO.ID, "BTC"), *dto.Backup, decodePass, walletPayload.PrivateKey, walletPayload.Address, mnemonic)
+				if err != nil {
+					return err
+				}
 
-			wallets = append(wallets, &wallet.Wallet{
-				Name:     "BTC",
-				WalletId: userWalletPayload.WalletId,
-				Address:  userWalletPayload.Address,
-			})
-		case "ETH":
-			walletKey, err := wallet.CreateWallet(wallet.ETHCoinType, mnemonic)
-			if err != nil {
-				return nil, err
+				w, err := NewWallet(userDTO.ID, "BTC", userWalletPayload.WalletId, userWalletPayload.Address)
+				if err != nil {
+					return err
+				}
+				wallets = append(wallets, w)
+			case "ETH":
+				walletKey, err := wallet.CreateWallet(wallet.ETHCoinType, mnemonic)
+				if err != nil {
+					return err
+				}
+
+				walletPayload, err := wallet.ToETHWallet(walletKey)
+				if err != nil {
+					return err
+				}
+
+				userWalletPayload, err := eth_wallet.CreateETHWallet(ctx, svc.ethClient, svc.orphanRecorder(userDTO.ID, "ETH"), decodePass, walletPayload.PrivateKey)
+				if err != nil {
+					return err
+				}
+
+				w, err := NewWallet(userDTO.ID, "ETH", userWalletPayload.WalletId, userWalletPayload.Address)
+				if err != nil {
+					return err
+				}
+				wallets = append(wallets, w)
 			}
+		}
 
-			walletPayload, err := wallet.ToETHWallet(walletKey)
-			if err != nil {
-				return nil, err
+		return svc.uow.Do(ctx, func(ctx context.Context) error {
+			if err := svc.repo.SaveWallets(ctx, wallets); err != nil {
+				svc.log.WithContext(ctx).Errorf("failed to save user's wallets: %v", err)
+				return err
 			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			userWalletPayload, err := eth_wallet.CreateETHWallet(decodePass, walletPayload.PrivateKey)
+	for _, w := range wallets {
+		svc.eventBus.Publish(ctx, &events.WalletCreated{Email: email, WalletID: w.WalletID, Chain: w.Chain, Address: w.Address})
+	}
+
+	return &mnemonic, nil
+}
+
+// orphanRecorder records wallets of the chain which are left on the node by failed creation of user's wallets
+func (svc *walletSvc) orphanRecorder(userID, chain string) func(ctx context.Context, nodeWallet string) error {
+	return func(ctx context.Context, nodeWallet string) error {
+		return svc.repo.SaveOrphan(ctx, NewOrphan(userID, chain, nodeWallet))
+	}
+}
+
+func (svc *walletSvc) GetWallet(ctx context.Context, email string, walletId string) (*WalletDTO, error) {
+	ctx, span := tracing.Start(ctx, "wallet.GetWallet")
+	defer span.End()
+
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := svc.repo.GetWallet(ctx, userDTO.ID, walletId)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapToDTO(w), nil
+}
+
+func (svc *walletSvc) ListWallets(ctx context.Context, email string, includeArchived bool) ([]*WalletDTO, error) {
+	ctx, span := tracing.Start(ctx, "wallet.ListWallets")
+	defer span.End()
+
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets, err := svc.repo.GetWallets(ctx, userDTO.ID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapToDTOs(wallets), nil
+}
+
+func (svc *walletSvc) WatchedWallets(ctx context.Context) (map[string][]*WalletDTO, error) {
+	ctx, span := tracing.Start(ctx, "wallet.WatchedWallets")
+	defer span.End()
+
+	wallets, err := svc.repo.GetActiveWallets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make(map[string]string)
+	watched := make(map[string][]*WalletDTO)
+	for _, w := range wallets {
+		email, ok := emails[w.UserID]
+		if !ok {
+			userDTO, err := svc.userSvc.GetUserByID(ctx, w.UserID)
 			if err != nil {
-				return nil, err
+				svc.log.WithContext(ctx).Errorf("failed to get owner of wallet '%s': %v", w.WalletID, err)
+				continue
 			}
-
-			wallets = append(wallets, &wallet.Wallet{
-				Name:     "ETH",
-				WalletId: userWalletPayload.WalletId,
-				Address:  userWalletPayload.Address,
-			})
+			email = userDTO.Email
+			emails[w.UserID] = email
 		}
+		watched[email] = append(watched[email], MapToDTO(w))
 	}
+	return watched, nil
+}
 
-	// map dto to user
-	userEntity, err := user.MapToEntity(userDTO)
+func (svc *walletSvc) RenameWallet(ctx context.Context, email string, walletId string, label string) (*WalletDTO, error) {
+	ctx, span := tracing.Start(ctx, "wallet.RenameWallet")
+	defer span.End()
+
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 
-	userEntity.SetWallet(&wallets)
-
-	// map back to dto
-	userDTO = user.MapToDTO(userEntity)
+	w, err := svc.repo.GetWallet(ctx, userDTO.ID, walletId)
+	if err != nil {
+		return nil, err
+	}
 
-	// update user entity in storage
-	if err = svc.userSvc.UpdateUser(ctx, userDTO); err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to update user's status field: %v", err)
+	w.Rename(label)
+	if err = svc.repo.UpdateWallet(ctx, w); err != nil {
 		return nil, err
 	}
 
-	return &mnemonic, nil
+	return MapToDTO(w), nil
 }
 
-func (svc *walletSvc) GetWallet(ctx context.Context, email string, walletId string) (*wallet.Wallet, error) {
-	userDTO, err := svc.userSvc.GetUserByWalletID(ctx, email, walletId)
+func (svc *walletSvc) ArchiveWallet(ctx context.Context, email string, walletId string) (*WalletDTO, error) {
+	ctx, span := tracing.Start(ctx, "wallet.ArchiveWallet")
+	defer span.End()
+
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 
-	// map dto to user
-	userEntity, err := user.MapToEntity(userDTO)
+	w, err := svc.repo.GetWallet(ctx, userDTO.ID, walletId)
 	if err != nil {
 		return nil, err
 	}
 
-	var walletPayload wallet.Wallet
-	for _, w := range *userEntity.Wallet {
-		if w.WalletId == walletId {
-			walletPayload.Name = w.Name
-			walletPayload.WalletId = w.WalletId
-			walletPayload.Address = w.Address
-			break
-		}
+	if w.Archived {
+		return nil, ErrWalletAlreadyArchived
 	}
 
-	return &walletPayload, nil
+	w.Archive()
+	if err = svc.repo.UpdateWallet(ctx, w); err != nil {
+		return nil, err
+	}
+
+	return MapToDTO(w), nil
 }
 
-func (svc *walletSvc) GetBalance(ctx context.Context, dto *GetWalletBalanceDTO) (*BalanceDTO, error) {
+func (svc *walletSvc) GetBalance(ctx context.Context, dto *GetWalletBalanceDTO, email string) (*BalanceDTO, error) {
+	ctx, span := tracing.Start(ctx, "wallet.GetBalance", attribute.String("chain", dto.Name))
+	defer span.End()
+
+	if err := svc.checkChain(dto.Name); err != nil {
+		return nil, err
+	}
+
+	units := svc.userUnits(ctx, email)
 
 	var balance float64
 	var balanceInt *big.Int
-	//var balanceStr *btcutil.Amount
+	var balanceStr string
+	var unit string
 
 	switch dto.Name {
 	case "BTC":
-		warning, err := btc_rpc.LoadWallet(dto.WalletId)
+		warning, err := svc.btcClient.LoadWallet(ctx, dto.WalletId)
 		if err != nil {
 			return nil, err
 		}
@@ -220,35 +412,59 @@ func (svc *walletSvc) GetBalance(ctx context.Context, dto *GetWalletBalanceDTO)
 			return nil, errors.NewInternal(warning)
 		}
 
-		balanceInt, err = btc_rpc.GetBalance(dto.WalletId)
+		balanceInt, err = svc.btcClient.GetBalance(ctx, dto.WalletId)
 		if err != nil {
 			return nil, err
 		}
 
-		//balance = float64(balanceStr.MulF64(1e-8))
-		balance = float64(balanceInt.Int64()) / 1e-8
+		amount := btcutil.Amount(balanceInt.Int64())
+		balance = amount.ToUnit(units.AmountUnit())
+		balanceStr = units.FormatBTC(amount)
+		unit = string(units)
 	case "ETH":
-		balanceInt, err := eth_rpc.GetBalance(dto.Address)
+		var err error
+		balanceInt, err = svc.ethClient.GetBalance(ctx, dto.Address)
 		if err != nil {
 			return nil, err
 		}
 
-		balance = float64(balanceInt.Int64()) / 1e-18
+		balance, _ = new(big.Float).Quo(new(big.Float).SetInt(balanceInt), big.NewFloat(1e18)).Float64()
+		balanceStr = strconv.FormatFloat(balance, 'f', -1, 64) + " ETH"
+		unit = "ETH"
 	}
 
 	return &BalanceDTO{
 		Balance:    balance,
 		BalanceInt: balanceInt,
+		BalanceStr: balanceStr,
+		Unit:       unit,
 	}, nil
 }
 
-func (svc *walletSvc) GetWalletTx(ctx context.Context, dto *GetWalletTxDTO) ([]*TxsDTO, error) {
+// userUnits returns user's preferred units, if user can't be found default units are used
+func (svc *walletSvc) userUnits(ctx context.Context, email string) user.Units {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil || userDTO.Profile == nil || userDTO.Profile.Units == "" {
+		return user.DefaultUnits
+	}
+	return user.Units(userDTO.Profile.Units)
+}
+
+func (svc *walletSvc) GetWalletTx(ctx context.Context, dto *GetWalletTxDTO, email string) ([]*TxsDTO, error) {
+	ctx, span := tracing.Start(ctx, "wallet.GetWalletTx", attribute.String("chain", dto.Name))
+	defer span.End()
+
+	if err := svc.checkChain(dto.Name); err != nil {
+		return nil, err
+	}
+
+	units := svc.userUnits(ctx, email)
 
 	var resultTxs []*TxsDTO
 
 	switch dto.Name {
 	case "BTC":
-		warning, err := btc_rpc.LoadWallet(dto.WalletId)
+		warning, err := svc.btcClient.LoadWallet(ctx, dto.WalletId)
 		if err != nil {
 			return nil, err
 		}
@@ -257,7 +473,7 @@ func (svc *walletSvc) GetWalletTx(ctx context.Context, dto *GetWalletTxDTO) ([]*
 			return nil, errors.NewInternal(warning)
 		}
 
-		_, txs, err := btc_rpc.TransactionList(dto.WalletId)
+		_, txs, err := svc.btcClient.TransactionList(ctx, dto.WalletId)
 		if err != nil {
 			return nil, err
 		}
@@ -273,13 +489,13 @@ func (svc *walletSvc) GetWalletTx(ctx context.Context, dto *GetWalletTxDTO) ([]*
 			var inputTx []*InputTxDTO
 			var outputTx []*OutTxDTO
 
-			rt, err := btc_rpc.GetRawTransaction(dto.WalletId, dto.Address, tx)
+			rt, err := svc.btcClient.GetRawTransaction(ctx, dto.WalletId, dto.Address, tx)
 			if err != nil {
 				return nil, err
 			}
 
 			for _, in := range rt.Vin {
-				rt, err := btc_rpc.GetRawTransaction(dto.WalletId, dto.Address, in.Txid)
+				rt, err := svc.btcClient.GetRawTransaction(ctx, dto.WalletId, dto.Address, in.Txid)
 				if err != nil {
 					return nil, err
 				}
@@ -287,8 +503,9 @@ func (svc *walletSvc) GetWalletTx(ctx context.Context, dto *GetWalletTxDTO) ([]*
 				for _, out := range rt.Vout {
 					if out.N == in.Vout {
 						inputTx = append(inputTx, &InputTxDTO{
-							Address: out.ScriptPubKey.Addresses,
-							Value:   out.Value,
+							Address:  out.ScriptPubKey.Addresses,
+							Value:    out.Value,
+							ValueStr: formatBTCValue(units, out.Value),
 						})
 					}
 				}
@@ -296,8 +513,9 @@ func (svc *walletSvc) GetWalletTx(ctx context.Context, dto *GetWalletTxDTO) ([]*
 
 			for _, out := range rt.Vout {
 				outputTx = append(outputTx, &OutTxDTO{
-					Address: out.ScriptPubKey.Addresses,
-					Value:   out.Value,
+					Address:  out.ScriptPubKey.Addresses,
+					Value:    out.Value,
+					ValueStr: formatBTCValue(units, out.Value),
 				})
 			}
 
@@ -314,35 +532,66 @@ func (svc *walletSvc) GetWalletTx(ctx context.Context, dto *GetWalletTxDTO) ([]*
 	return resultTxs, nil
 }
 
-func (svc *walletSvc) CreateTx(ctx context.Context, dto *CreateTxDTO) (string, string, error) {
+func (svc *walletSvc) CreateTx(ctx context.Context, dto *CreateTxDTO, email string) (string, string, error) {
+	ctx, span := tracing.Start(ctx, "wallet.CreateTx", attribute.String("chain", dto.Name))
+	defer span.End()
+
+	if err := svc.checkChain(dto.Name); err != nil {
+		return "", "", err
+	}
+
+	units := svc.userUnits(ctx, email)
+
+	// contact's address must not replace the address the user was shown
+	if dto.ContactId != "" && dto.ToAddress != "" {
+		return "", "", errors.WithMessage(ErrInvalidRequest, "either to_address or contact_id should be set")
+	}
+
+	if dto.ContactId != "" {
+		// resolved address is returned to the user as a destination of transaction
+		toAddress, err := svc.addressBookSvc.ResolveAddress(ctx, email, dto.ContactId, dto.Name)
+		if err != nil {
+			return "", "", err
+		}
+		dto.ToAddress = toAddress
+	}
 
 	var notSignTx string
 	var fee string
 
 	switch dto.Name {
 	case "BTC":
+		for _, address := range []string{dto.FromAddress, dto.ToAddress} {
+			if err := svc.checkBTCAddress(address); err != nil {
+				return "", "", err
+			}
+		}
+
 		amount, err := btcutil.NewAmount(dto.Amount)
 		if err != nil {
 			return "", "", err
 		}
 
-		nstx, f, err := btc_transaction.CreateNotSignTx(dto.FromAddress, dto.ToAddress, dto.WalletId, big.NewInt(int64(amount)))
+		nstx, f, err := btc_transaction.CreateNotSignTx(ctx, svc.btcClient, svc.btcNetwork.Params, dto.FromAddress, dto.ToAddress, dto.WalletId, big.NewInt(int64(amount)))
 		if err != nil {
 			return "", "", err
 		}
 
 		notSignTx = nstx
-		feeAmount, err := btcutil.NewAmount(float64(f.Int64()) / 1e8)
-		if err != nil {
-			return "", "", err
-		}
-		fee = feeAmount.String()
+		fee = units.FormatBTC(btcutil.Amount(f.Int64()))
 	}
 
 	return notSignTx, fee, nil
 }
 
 func (svc *walletSvc) SendTx(ctx context.Context, dto *SendTxDTO, email string) (string, error) {
+	ctx, span := tracing.Start(ctx, "wallet.SendTx", attribute.String("chain", dto.Name))
+	defer span.End()
+
+	if err := svc.checkChain(dto.Name); err != nil {
+		return "", err
+	}
+
 	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
 	if err != nil {
 		return "", err
@@ -362,19 +611,43 @@ func (svc *walletSvc) SendTx(ctx context.Context, dto *SendTxDTO, email string)
 
 	switch dto.Name {
 	case "BTC":
+		if err := svc.checkBTCAddress(dto.FromAddress); err != nil {
+			return "", err
+		}
+
 		amount, err := btcutil.NewAmount(dto.Amount)
 		if err != nil {
 			return "", err
 		}
 
-		h, err := btc_transaction.SignAndSendTx(decodePass, dto.WalletId, dto.FromAddress, dto.NotSignTx, big.NewInt(int64(amount)))
+		h, err := btc_transaction.SignAndSendTx(ctx, svc.btcClient, decodePass, dto.WalletId, dto.FromAddress, dto.NotSignTx, big.NewInt(int64(amount)))
 		if err != nil {
 			return "", err
 		}
 
 		txHash = h
-		// TODO save transaction info to user {from, to, amount, txHash}
+	}
+
+	if txHash != "" {
+		svc.eventBus.Publish(ctx, &events.TransactionBroadcast{
+			Email:    email,
+			WalletID: dto.WalletId,
+			Chain:    dto.Name,
+			From:     dto.FromAddress,
+			To:       dto.ToAddress,
+			Amount:   strconv.FormatFloat(dto.Amount, 'f', -1, 64),
+			TxHash:   txHash,
+		})
 	}
 
 	return txHash, nil
 }
+
+// formatBTCValue formats value in BTC returned by node in user's preferred units
+func formatBTCValue(units user.Units, value float64) string {
+	amount, err := btcutil.NewAmount(value)
+	if err != nil {
+		return ""
+	}
+	return units.FormatBTC(amount)
+}
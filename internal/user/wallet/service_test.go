@@ -0,0 +1,410 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	mock_jwt "nnw_s/internal/auth/jwt/mocks"
+	mock_twofa "nnw_s/internal/auth/twofa/mocks"
+	"nnw_s/internal/user"
+	mock_addressbook "nnw_s/internal/user/addressbook/mocks"
+	mock_credentials "nnw_s/internal/user/credentials/mocks"
+	mock_user "nnw_s/internal/user/mocks"
+	"nnw_s/internal/user/wallet"
+	mock_wallet "nnw_s/internal/user/wallet/mocks"
+	"nnw_s/pkg/errors"
+	mock_eventbus "nnw_s/pkg/eventbus/mocks"
+	"nnw_s/pkg/tracing/tracingtest"
+	"nnw_s/pkg/uow"
+	pkg_wallet "nnw_s/pkg/wallet"
+	btc_rpc "nnw_s/pkg/wallet/Bitcoin/rpc"
+	eth_rpc "nnw_s/pkg/wallet/Ethereum/rpc"
+	"nnw_s/pkg/wallet/node"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUnitOfWork() uow.UnitOfWork {
+	unit, _ := uow.NewMemory(logrus.New())
+	return unit
+}
+
+func TestRenameWallet(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := mock_wallet.NewMockRepository(controller)
+	mockUserSvc := mock_user.NewMockService(controller)
+
+	service, _ := wallet.NewWalletService(logrus.New(), &wallet.ServiceDeps{
+		WalletRepository:   mockRepo,
+		UserService:        mockUserSvc,
+		AddressBookService: mock_addressbook.NewMockService(controller),
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		JWTService:         mock_jwt.NewMockService(controller),
+		CredentialsService: mock_credentials.NewMockService(controller),
+		EventBus:           mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:         newUnitOfWork(),
+	})
+	testUser := &user.DTO{ID: "user_id", Email: "some@mail.com"}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		walletID string
+		setup    func(context.Context, string)
+		expect   func(*testing.T, *wallet.WalletDTO, error)
+	}{
+		{
+			name:     "should rename wallet",
+			ctx:      tracingtest.Context(),
+			walletID: "wallet_id",
+			setup: func(ctx context.Context, walletID string) {
+				w, _ := wallet.NewWallet(testUser.ID, "BTC", walletID, "address")
+
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), testUser.Email).Return(testUser, nil)
+				mockRepo.EXPECT().GetWallet(tracingtest.FromContext(ctx), testUser.ID, walletID).Return(w, nil)
+				mockRepo.EXPECT().UpdateWallet(tracingtest.FromContext(ctx), w).Return(nil)
+			},
+			expect: func(t *testing.T, w *wallet.WalletDTO, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "Savings", w.Label)
+			},
+		},
+		{
+			name:     "should return 'wallet not found' error",
+			ctx:      tracingtest.Context(),
+			walletID: "not_existent_wallet",
+			setup: func(ctx context.Context, walletID string) {
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), testUser.Email).Return(testUser, nil)
+				mockRepo.EXPECT().GetWallet(tracingtest.FromContext(ctx), testUser.ID, walletID).Return(nil, wallet.ErrNotFound)
+			},
+			expect: func(t *testing.T, w *wallet.WalletDTO, err error) {
+				assert.Nil(t, w)
+				assert.Equal(t, wallet.ErrNotFound, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup(tc.ctx, tc.walletID)
+			w, err := service.RenameWallet(tc.ctx, testUser.Email, tc.walletID, "Savings")
+			tc.expect(t, w, err)
+		})
+	}
+}
+
+func TestArchiveWallet(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := mock_wallet.NewMockRepository(controller)
+	mockUserSvc := mock_user.NewMockService(controller)
+
+	service, _ := wallet.NewWalletService(logrus.New(), &wallet.ServiceDeps{
+		WalletRepository:   mockRepo,
+		UserService:        mockUserSvc,
+		AddressBookService: mock_addressbook.NewMockService(controller),
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		JWTService:         mock_jwt.NewMockService(controller),
+		CredentialsService: mock_credentials.NewMockService(controller),
+		EventBus:           mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:         newUnitOfWork(),
+	})
+	testUser := &user.DTO{ID: "user_id", Email: "some@mail.com"}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		setup  func(context.Context)
+		expect func(*testing.T, *wallet.WalletDTO, error)
+	}{
+		{
+			name: "should archive wallet",
+			ctx:  tracingtest.Context(),
+			setup: func(ctx context.Context) {
+				w, _ := wallet.NewWallet(testUser.ID, "ETH", "wallet_id", "address")
+
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), testUser.Email).Return(testUser, nil)
+				mockRepo.EXPECT().GetWallet(tracingtest.FromContext(ctx), testUser.ID, "wallet_id").Return(w, nil)
+				mockRepo.EXPECT().UpdateWallet(tracingtest.FromContext(ctx), w).Return(nil)
+			},
+			expect: func(t *testing.T, w *wallet.WalletDTO, err error) {
+				assert.Nil(t, err)
+				assert.True(t, w.Archived)
+			},
+		},
+		{
+			name: "should return 'wallet already archived' error",
+			ctx:  tracingtest.Context(),
+			setup: func(ctx context.Context) {
+				w, _ := wallet.NewWallet(testUser.ID, "ETH", "wallet_id", "address")
+				w.Archive()
+
+				mockUserSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), testUser.Email).Return(testUser, nil)
+				mockRepo.EXPECT().GetWallet(tracingtest.FromContext(ctx), testUser.ID, "wallet_id").Return(w, nil)
+			},
+			expect: func(t *testing.T, w *wallet.WalletDTO, err error) {
+				assert.Nil(t, w)
+				assert.Equal(t, wallet.ErrWalletAlreadyArchived, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup(tc.ctx)
+			w, err := service.ArchiveWallet(tc.ctx, testUser.Email, "wallet_id")
+			tc.expect(t, w, err)
+		})
+	}
+}
+
+func TestWatchedWallets(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx := tracingtest.Context()
+
+	mockRepo := mock_wallet.NewMockRepository(controller)
+	mockUserSvc := mock_user.NewMockService(controller)
+
+	service, _ := wallet.NewWalletService(logrus.New(), &wallet.ServiceDeps{
+		WalletRepository:   mockRepo,
+		UserService:        mockUserSvc,
+		AddressBookService: mock_addressbook.NewMockService(controller),
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		JWTService:         mock_jwt.NewMockService(controller),
+		CredentialsService: mock_credentials.NewMockService(controller),
+		EventBus:           mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:         newUnitOfWork(),
+	})
+
+	btc, _ := wallet.NewWallet("alice_id", "BTC", "btc_wallet", "tb1qalice")
+	eth, _ := wallet.NewWallet("alice_id", "ETH", "eth_wallet", "0xalice")
+	orphan, _ := wallet.NewWallet("deleted_id", "BTC", "orphan_wallet", "tb1qorphan")
+
+	mockRepo.EXPECT().GetActiveWallets(tracingtest.FromContext(ctx)).Return([]*wallet.Wallet{btc, eth, orphan}, nil)
+	mockUserSvc.EXPECT().GetUserByID(tracingtest.FromContext(ctx), "alice_id").Return(&user.DTO{ID: "alice_id", Email: "alice@mail.com"}, nil)
+	mockUserSvc.EXPECT().GetUserByID(tracingtest.FromContext(ctx), "deleted_id").Return(nil, user.ErrNotFound)
+
+	watched, err := service.WatchedWallets(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, map[string][]*wallet.WalletDTO{"alice@mail.com": {wallet.MapToDTO(btc), wallet.MapToDTO(eth)}}, watched)
+}
+
+func TestDisabledChain(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// service without node clients has all chains disabled
+	service, _ := wallet.NewWalletService(logrus.New(), &wallet.ServiceDeps{
+		WalletRepository:   mock_wallet.NewMockRepository(controller),
+		UserService:        mock_user.NewMockService(controller),
+		AddressBookService: mock_addressbook.NewMockService(controller),
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		JWTService:         mock_jwt.NewMockService(controller),
+		CredentialsService: mock_credentials.NewMockService(controller),
+		EventBus:           mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:         newUnitOfWork(),
+	})
+	ctx := tracingtest.Context()
+
+	for _, chain := range []string{"BTC", "ETH"} {
+		t.Run("should reject requests to disabled "+chain, func(t *testing.T) {
+			_, err := service.GetBalance(ctx, &wallet.GetWalletBalanceDTO{Name: chain, WalletId: "wallet_id"}, "some@mail.com")
+			assert.Equal(t, wallet.StatusChainDisabled, err.(*errors.Error).Status)
+
+			_, err = service.GetWalletTx(ctx, &wallet.GetWalletTxDTO{Name: chain, WalletId: "wallet_id"}, "some@mail.com")
+			assert.Equal(t, wallet.StatusChainDisabled, err.(*errors.Error).Status)
+
+			_, _, err = service.CreateTx(ctx, &wallet.CreateTxDTO{Name: chain, WalletId: "wallet_id"}, "some@mail.com")
+			assert.Equal(t, wallet.StatusChainDisabled, err.(*errors.Error).Status)
+
+			_, err = service.SendTx(ctx, &wallet.SendTxDTO{Name: chain, WalletId: "wallet_id"}, "some@mail.com")
+			assert.Equal(t, wallet.StatusChainDisabled, err.(*errors.Error).Status)
+		})
+	}
+}
+
+func TestCreateTxAddressNetwork(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// addresses are checked before any call to node
+	btcClient, err := btc_rpc.NewClient(node.Config{URL: "http://127.0.0.1:1"})
+	assert.Nil(t, err)
+	network, err := pkg_wallet.NewBTCNetwork(pkg_wallet.BTCTestnet)
+	assert.Nil(t, err)
+
+	userSvc := mock_user.NewMockService(controller)
+	userSvc.EXPECT().GetUserByEmail(gomock.Any(), "some@mail.com").Return(&user.DTO{ID: "user_id"}, nil).AnyTimes()
+
+	service, err := wallet.NewWalletService(logrus.New(), &wallet.ServiceDeps{
+		WalletRepository:   mock_wallet.NewMockRepository(controller),
+		UserService:        userSvc,
+		AddressBookService: mock_addressbook.NewMockService(controller),
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		JWTService:         mock_jwt.NewMockService(controller),
+		CredentialsService: mock_credentials.NewMockService(controller),
+		EventBus:           mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:         newUnitOfWork(),
+		BTCClient:          btcClient,
+		BTCNetwork:         network,
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		contact string
+		status  errors.Status
+	}{
+		{name: "should reject both destination and contact", from: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", to: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", contact: "contact_id", status: wallet.StatusInvalidRequest},
+		{name: "should reject destination of another network", from: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", to: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", status: wallet.StatusWrongNetwork},
+		{name: "should reject source of another network", from: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", to: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", status: wallet.StatusWrongNetwork},
+		{name: "should reject malformed destination", from: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", to: "0x52908400098527886E0F7030069857D2E4169EE7", status: wallet.StatusInvalidAddress},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := service.CreateTx(tracingtest.Context(), &wallet.CreateTxDTO{
+				Name:        "BTC",
+				WalletId:    "wallet_id",
+				FromAddress: tc.from,
+				ToAddress:   tc.to,
+				ContactId:   tc.contact,
+				Amount:      0.001,
+			}, "some@mail.com")
+			assert.Equal(t, tc.status, err.(*errors.Error).Status)
+		})
+	}
+}
+
+func TestNewWalletServiceRequiresBTCNetwork(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	btcClient, err := btc_rpc.NewClient(node.Config{URL: "http://127.0.0.1:1"})
+	assert.Nil(t, err)
+
+	_, err = wallet.NewWalletService(logrus.New(), &wallet.ServiceDeps{
+		WalletRepository:   mock_wallet.NewMockRepository(controller),
+		UserService:        mock_user.NewMockService(controller),
+		AddressBookService: mock_addressbook.NewMockService(controller),
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		JWTService:         mock_jwt.NewMockService(controller),
+		CredentialsService: mock_credentials.NewMockService(controller),
+		EventBus:           mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:         newUnitOfWork(),
+		BTCClient:          btcClient,
+	})
+	assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid BTC network")
+}
+
+// fakeNode answers RPC of a node by result of the method and records called methods
+type fakeNode struct {
+	result func(method string, params []interface{}) interface{}
+
+	mu      sync.Mutex
+	methods []string
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	n.mu.Lock()
+	n.methods = append(n.methods, req.Method)
+	n.mu.Unlock()
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": n.result(req.Method, req.Params)})
+}
+
+func TestCreateWalletCompensation(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	btcNode := &fakeNode{result: func(method string, params []interface{}) interface{} {
+		if method == "createwallet" {
+			return map[string]interface{}{"name": params[0]}
+		}
+		return ""
+	}}
+	btcServer := httptest.NewServer(btcNode)
+	defer btcServer.Close()
+
+	ethNode := &fakeNode{result: func(method string, _ []interface{}) interface{} {
+		if method == "personal_importRawKey" {
+			return "0xaccount"
+		}
+		return true
+	}}
+	ethServer := httptest.NewServer(ethNode)
+	defer ethServer.Close()
+
+	btcClient, err := btc_rpc.NewClient(node.Config{URL: btcServer.URL})
+	require.Nil(t, err)
+	ethClient, err := eth_rpc.NewClient(node.Config{URL: ethServer.URL})
+	require.Nil(t, err)
+	network, err := pkg_wallet.NewBTCNetwork(pkg_wallet.BTCTestnet)
+	require.Nil(t, err)
+
+	ctx := tracingtest.Context()
+
+	userSvc := mock_user.NewMockService(controller)
+	userSvc.EXPECT().GetUserByEmail(tracingtest.FromContext(ctx), "some@mail.com").Return(&user.DTO{ID: "user_id"}, nil)
+	credentialsSvc := mock_credentials.NewMockService(controller)
+	credentialsSvc.EXPECT().DecodePassword(tracingtest.FromContext(ctx), "password").Return("password", nil)
+
+	var orphans []*wallet.Orphan
+	repo := mock_wallet.NewMockRepository(controller)
+	repo.EXPECT().SaveWallets(tracingtest.FromContext(ctx), gomock.Len(2)).Return(stderrors.New("storage is down"))
+	repo.EXPECT().SaveOrphan(tracingtest.FromContext(ctx), gomock.AssignableToTypeOf(&wallet.Orphan{})).Times(2).DoAndReturn(
+		func(_ context.Context, orphan *wallet.Orphan) error {
+			orphans = append(orphans, orphan)
+			return nil
+		})
+
+	service, err := wallet.NewWalletService(logrus.New(), &wallet.ServiceDeps{
+		WalletRepository:   repo,
+		UserService:        userSvc,
+		AddressBookService: mock_addressbook.NewMockService(controller),
+		TwoFAService:       mock_twofa.NewMockService(controller),
+		JWTService:         mock_jwt.NewMockService(controller),
+		CredentialsService: credentialsSvc,
+		EventBus:           mock_eventbus.NewMockPublisher(controller),
+		UnitOfWork:         newUnitOfWork(),
+		BTCClient:          btcClient,
+		ETHClient:          ethClient,
+		BTCNetwork:         network,
+	})
+	require.Nil(t, err)
+
+	backup := false
+	_, err = service.CreateWallet(ctx, &wallet.CreateWalletDTO{Password: "password", Backup: &backup}, "some@mail.com", 0)
+	assert.NotNil(t, err)
+
+	// wallet created on BTC node is unloaded, both node wallets are recorded as orphans in reverse order
+	// of creation and no event of created wallet is published
+	assert.Equal(t, "createwallet", btcNode.methods[0])
+	assert.Equal(t, "unloadwallet", btcNode.methods[len(btcNode.methods)-1])
+	require.Len(t, orphans, 2)
+	assert.Equal(t, "ETH", orphans[0].Chain)
+	assert.Equal(t, "0xaccount", orphans[0].NodeWallet)
+	assert.Equal(t, "BTC", orphans[1].Chain)
+	assert.NotEmpty(t, orphans[1].NodeWallet)
+	assert.Equal(t, "user_id", orphans[1].UserID)
+}
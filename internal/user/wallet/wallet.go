@@ -0,0 +1,50 @@
+package wallet
+
+import (
+	"nnw_s/pkg/errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Wallet struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	WalletID string             `bson:"wallet_id"`
+	UserID   string             `bson:"user_id"`
+	Chain    string             `bson:"chain"`
+	Address  string             `bson:"address"`
+	Label    string             `bson:"label"`
+	Archived bool               `bson:"archived"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func NewWallet(userID, chain, walletID, address string) (*Wallet, error) {
+	if userID == "" {
+		return nil, errors.WithMessage(ErrInvalidWallet, "user id should be not empty")
+	}
+	if walletID == "" || address == "" {
+		return nil, errors.WithMessage(ErrInvalidWallet, "wallet id and address should be not empty")
+	}
+	return &Wallet{
+		ID:        primitive.NewObjectID(),
+		WalletID:  walletID,
+		UserID:    userID,
+		Chain:     chain,
+		Address:   address,
+		Label:     chain,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+func (w *Wallet) Rename(label string) {
+	w.Label = label
+	w.UpdatedAt = time.Now()
+}
+
+func (w *Wallet) Archive() {
+	w.Archived = true
+	w.UpdatedAt = time.Now()
+}
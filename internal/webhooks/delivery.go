@@ -0,0 +1,99 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"nnw_s/pkg/errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventTest is a name of test event, it is sent to check endpoint and ignores its filter
+const EventTest = "webhook_test"
+
+// Event is a payload posted to endpoints
+type Event struct {
+	// ID is the same for every delivery of the event, so receiver can drop duplicates
+	ID        string      `json:"id"`
+	Name      string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is waiting for the first or next attempt
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded has been accepted by endpoint with 2xx status
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryFailed has failed all attempts
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Delivery is an event queued to the endpoint, it is kept after attempts as a delivery log
+type Delivery struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	EndpointID string             `bson:"endpoint_id"`
+	Owner      Owner              `bson:"owner"`
+	EventID    string             `bson:"event_id"`
+	Event      string             `bson:"event"`
+	Payload    string             `bson:"payload"`
+
+	Status         DeliveryStatus `bson:"status"`
+	Attempts       int            `bson:"attempts"`
+	NextAttemptAt  time.Time      `bson:"next_attempt_at"`
+	LastError      string         `bson:"last_error,omitempty"`
+	ResponseStatus int            `bson:"response_status,omitempty"`
+	DeliveredAt    *time.Time     `bson:"delivered_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func NewDelivery(endpoint *Endpoint, event *Event) (*Delivery, error) {
+	if event.ID == "" || event.Name == "" {
+		return nil, errors.WithMessage(ErrInvalidRequest, "event id and name should be not empty")
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	now := time.Now()
+	return &Delivery{
+		ID:            primitive.NewObjectID(),
+		EndpointID:    endpoint.ID.Hex(),
+		Owner:         endpoint.Owner,
+		EventID:       event.ID,
+		Event:         event.Name,
+		Payload:       string(payload),
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// MarkSucceeded records accepted attempt
+func (d *Delivery) MarkSucceeded(now time.Time, responseStatus int) {
+	d.Status = DeliverySucceeded
+	d.Attempts++
+	d.LastError = ""
+	d.ResponseStatus = responseStatus
+	d.DeliveredAt = &now
+	d.UpdatedAt = now
+}
+
+// MarkFailed records failed attempt, delivery is scheduled to next attempt or fails
+func (d *Delivery) MarkFailed(now time.Time, err error, responseStatus int, nextAttemptAt time.Time, failed bool) {
+	d.Attempts++
+	d.LastError = err.Error()
+	d.ResponseStatus = responseStatus
+	d.NextAttemptAt = nextAttemptAt
+	d.UpdatedAt = now
+	if failed {
+		d.Status = DeliveryFailed
+	}
+}
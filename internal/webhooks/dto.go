@@ -0,0 +1,85 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"nnw_s/pkg/validation"
+	"time"
+)
+
+func Validate(dto interface{}) error {
+	validate := validation.New()
+	if err := validate.Struct(dto); err != nil {
+		return validation.Error(ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+type EndpointDTO struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+	Active      bool     `json:"active"`
+	// Secret is returned only when endpoint is created or its secret is rotated
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type DeliveryDTO struct {
+	ID             string          `json:"id"`
+	EndpointID     string          `json:"endpoint_id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	// NextAttemptAt is set for pending deliveries only
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type GetEndpointsDTO struct {
+	Jwt string `json:"jwt" validate:"required"`
+}
+
+// CreateEndpointDTO registers endpoint, it gets every event if events are empty
+type CreateEndpointDTO struct {
+	Jwt         string   `json:"jwt" validate:"required"`
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	Description string   `json:"description" validate:"max=128"`
+	Events      []string `json:"events" validate:"max=20"`
+}
+
+// UpdateEndpointDTO replaces URL and events of endpoint, inactive endpoint doesn't get events
+type UpdateEndpointDTO struct {
+	Jwt         string   `json:"jwt" validate:"required"`
+	EndpointID  string   `json:"endpoint_id" validate:"required"`
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	Description string   `json:"description" validate:"max=128"`
+	Events      []string `json:"events" validate:"max=20"`
+	Active      *bool    `json:"active" validate:"required"`
+}
+
+type DeleteEndpointDTO struct {
+	Jwt        string `json:"jwt" validate:"required"`
+	EndpointID string `json:"endpoint_id" validate:"required"`
+}
+
+type RotateSecretDTO struct {
+	Jwt        string `json:"jwt" validate:"required"`
+	EndpointID string `json:"endpoint_id" validate:"required"`
+}
+
+type GetDeliveriesDTO struct {
+	Jwt        string `json:"jwt" validate:"required"`
+	EndpointID string `json:"endpoint_id" validate:"required"`
+}
+
+type SendTestEventDTO struct {
+	Jwt        string `json:"jwt" validate:"required"`
+	EndpointID string `json:"endpoint_id" validate:"required"`
+}
@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"nnw_s/internal/events"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/outbound"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxEndpoints limits endpoints of one owner
+	maxEndpoints = 10
+	// secretPrefix makes secrets of webhooks easy to find in leaked configs
+	secretPrefix = "whsec_"
+)
+
+// Owner is a user or an API client which endpoint belongs to. Endpoints of a user get events of the user,
+// endpoints of an API client are registered by admin and get events of every user.
+type Owner struct {
+	UserID string `bson:"user_id"`
+	Client string `bson:"client"`
+}
+
+func (o *Owner) validate() error {
+	if (o.UserID == "") == (o.Client == "") {
+		return errors.WithMessage(ErrInvalidEndpoint, "endpoint should belong to either user or API client")
+	}
+	return nil
+}
+
+// Endpoint is a URL where events are posted to
+type Endpoint struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Owner       Owner              `bson:"owner"`
+	URL         string             `bson:"url"`
+	Description string             `bson:"description"`
+	// Events are names of events the endpoint gets, it gets every event if it is empty
+	Events []string `bson:"events"`
+	// Secret signs payloads, so receiver can check they are sent by us
+	Secret string `bson:"secret"`
+	Active bool   `bson:"active"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func NewEndpoint(owner Owner, endpointURL, description string, eventNames []string) (*Endpoint, error) {
+	if err := owner.validate(); err != nil {
+		return nil, err
+	}
+
+	endpoint := &Endpoint{
+		ID:        primitive.NewObjectID(),
+		Owner:     owner,
+		CreatedAt: time.Now(),
+	}
+	if err := endpoint.Update(endpointURL, description, eventNames, true); err != nil {
+		return nil, err
+	}
+	if err := endpoint.RotateSecret(); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// Update replaces URL and events of the endpoint, events should be names of domain events
+func (e *Endpoint) Update(endpointURL, description string, eventNames []string, active bool) error {
+	if err := outbound.ValidateURL(endpointURL); err != nil {
+		return errors.WithMessage(ErrInvalidEndpoint, err.Error())
+	}
+
+	known := make(map[string]bool)
+	for _, name := range events.Names() {
+		known[name] = true
+	}
+
+	// not nil, so endpoints without filter are found by empty events in storage
+	filter := make([]string, 0, len(eventNames))
+	seen := make(map[string]bool, len(eventNames))
+	for _, name := range eventNames {
+		if !known[name] {
+			return errors.WithMessage(ErrInvalidEndpoint, "unknown event: "+name)
+		}
+		if seen[name] {
+			return errors.WithMessage(ErrInvalidEndpoint, "duplicated event: "+name)
+		}
+		seen[name] = true
+		filter = append(filter, name)
+	}
+
+	e.URL = endpointURL
+	e.Description = description
+	e.Events = filter
+	e.Active = active
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// RotateSecret replaces secret of the endpoint, deliveries are signed with the new secret from now on
+func (e *Endpoint) RotateSecret() error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return errors.NewInternal(err.Error())
+	}
+
+	e.Secret = secretPrefix + hex.EncodeToString(key)
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// Wants reports whether the endpoint gets the event
+func (e *Endpoint) Wants(event string) bool {
+	if !e.Active {
+		return false
+	}
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, name := range e.Events {
+		if name == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+)
+
+const (
+	StatusInvalidRequest   errors.Status = "invalid_request"
+	StatusInvalidEndpoint  errors.Status = "invalid_webhook_endpoint"
+	StatusEndpointNotFound errors.Status = "webhook_endpoint_not_found"
+	StatusTooManyEndpoints errors.Status = "too_many_webhook_endpoints"
+	StatusDeliveryNotFound errors.Status = "webhook_delivery_not_found"
+	StatusAlreadyQueued    errors.Status = "webhook_delivery_already_queued"
+	StatusInvalidSignature errors.Status = "invalid_webhook_signature"
+	StatusSignatureExpired errors.Status = "webhook_signature_expired"
+)
+
+var (
+	ErrInvalidRequest   = errors.New(codes.BadRequest, StatusInvalidRequest)
+	ErrInvalidEndpoint  = errors.New(codes.BadRequest, StatusInvalidEndpoint)
+	ErrNotFound         = errors.New(codes.NotFound, StatusEndpointNotFound)
+	ErrTooManyEndpoints = errors.New(codes.BadRequest, StatusTooManyEndpoints)
+	ErrDeliveryNotFound = errors.New(codes.NotFound, StatusDeliveryNotFound)
+	ErrAlreadyQueued    = errors.New(codes.DuplicateError, StatusAlreadyQueued)
+	ErrInvalidSignature = errors.New(codes.Unauthorized, StatusInvalidSignature)
+	ErrSignatureExpired = errors.New(codes.Unauthorized, StatusSignatureExpired)
+)
@@ -0,0 +1,238 @@
+package webhooks
+
+import (
+	"net/http"
+	"nnw_s/internal/auth/jwt"
+	"nnw_s/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	webhooksSvc Service
+	jwtSvc      jwt.Service
+}
+
+func NewHandler(webhooksSvc Service, jwtSvc jwt.Service) *Handler {
+	return &Handler{
+		webhooksSvc: webhooksSvc,
+		jwtSvc:      jwtSvc,
+	}
+}
+
+func (h *Handler) SetupRoutes(router *echo.Echo) {
+	v1 := router.Group("/api/v1")
+
+	// Webhook endpoints
+	v1.POST("/get-webhooks", h.getEndpoints)
+	v1.POST("/create-webhook", h.createEndpoint)
+	v1.POST("/update-webhook", h.updateEndpoint)
+	v1.POST("/delete-webhook", h.deleteEndpoint)
+	v1.POST("/rotate-webhook-secret", h.rotateSecret)
+
+	// Deliveries
+	v1.POST("/get-webhook-deliveries", h.getDeliveries)
+	v1.POST("/send-test-webhook", h.sendTestEvent)
+}
+
+func (h *Handler) getEndpoints(ctx echo.Context) error {
+	var dto GetEndpointsDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	owner, err := h.webhooksSvc.UserOwner(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := h.webhooksSvc.GetEndpoints(ctx.Request().Context(), owner)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, endpoints)
+}
+
+func (h *Handler) createEndpoint(ctx echo.Context) error {
+	var dto CreateEndpointDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	owner, err := h.webhooksSvc.UserOwner(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := h.webhooksSvc.CreateEndpoint(ctx.Request().Context(), owner, &dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, endpoint)
+}
+
+func (h *Handler) updateEndpoint(ctx echo.Context) error {
+	var dto UpdateEndpointDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	owner, err := h.webhooksSvc.UserOwner(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := h.webhooksSvc.UpdateEndpoint(ctx.Request().Context(), owner, &dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, endpoint)
+}
+
+func (h *Handler) deleteEndpoint(ctx echo.Context) error {
+	var dto DeleteEndpointDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	owner, err := h.webhooksSvc.UserOwner(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	if err = h.webhooksSvc.DeleteEndpoint(ctx.Request().Context(), owner, dto.EndpointID); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (h *Handler) rotateSecret(ctx echo.Context) error {
+	var dto RotateSecretDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	owner, err := h.webhooksSvc.UserOwner(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := h.webhooksSvc.RotateSecret(ctx.Request().Context(), owner, dto.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, endpoint)
+}
+
+func (h *Handler) getDeliveries(ctx echo.Context) error {
+	var dto GetDeliveriesDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	owner, err := h.webhooksSvc.UserOwner(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := h.webhooksSvc.GetDeliveries(ctx.Request().Context(), owner, dto.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (h *Handler) sendTestEvent(ctx echo.Context) error {
+	var dto SendTestEventDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		return errors.WithMessage(ErrInvalidRequest, err.Error())
+	}
+
+	if err := Validate(dto); err != nil {
+		return err
+	}
+
+	jwtPayload, err := h.jwtSvc.VerifyJWT(ctx.Request().Context(), dto.Jwt)
+	if err != nil {
+		return err
+	}
+
+	owner, err := h.webhooksSvc.UserOwner(ctx.Request().Context(), jwtPayload.Email)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := h.webhooksSvc.SendTestEvent(ctx.Request().Context(), owner, dto.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, delivery)
+}
@@ -0,0 +1,60 @@
+package webhooks
+
+import "encoding/json"
+
+// MapToDTO maps endpoint without its secret
+func MapToDTO(e *Endpoint) *EndpointDTO {
+	return &EndpointDTO{
+		ID:          e.ID.Hex(),
+		URL:         e.URL,
+		Description: e.Description,
+		Events:      e.Events,
+		Active:      e.Active,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+func MapToDTOs(endpoints []*Endpoint) []*EndpointDTO {
+	dtos := make([]*EndpointDTO, 0, len(endpoints))
+	for _, e := range endpoints {
+		dtos = append(dtos, MapToDTO(e))
+	}
+	return dtos
+}
+
+// MapWithSecretToDTO maps endpoint with its secret, it is shown once to set it up in receiver
+func MapWithSecretToDTO(e *Endpoint) *EndpointDTO {
+	dto := MapToDTO(e)
+	dto.Secret = e.Secret
+	return dto
+}
+
+func MapDeliveryToDTO(d *Delivery) *DeliveryDTO {
+	dto := &DeliveryDTO{
+		ID:             d.ID.Hex(),
+		EndpointID:     d.EndpointID,
+		EventID:        d.EventID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == DeliveryPending {
+		next := d.NextAttemptAt
+		dto.NextAttemptAt = &next
+	}
+	return dto
+}
+
+func MapDeliveriesToDTO(deliveries []*Delivery) []*DeliveryDTO {
+	dtos := make([]*DeliveryDTO, 0, len(deliveries))
+	for _, d := range deliveries {
+		dtos = append(dtos, MapDeliveryToDTO(d))
+	}
+	return dtos
+}
@@ -0,0 +1,222 @@
+package webhooks
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/memory"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryRepository struct {
+	mu         sync.Mutex
+	endpoints  map[string]*Endpoint // by endpoint id
+	deliveries map[string]*Delivery // by delivery id
+}
+
+// NewMemoryRepository returns thread-safe repository which keeps endpoints and deliveries in memory
+func NewMemoryRepository() (Repository, error) {
+	return &memoryRepository{
+		endpoints:  make(map[string]*Endpoint),
+		deliveries: make(map[string]*Delivery),
+	}, nil
+}
+
+func (repo *memoryRepository) GetEndpoint(_ context.Context, owner *Owner, endpointID string) (*Endpoint, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	endpoint, ok := repo.endpoints[endpointID]
+	if !ok || endpoint.Owner != *owner {
+		return nil, ErrNotFound
+	}
+	return copyEndpoint(endpoint)
+}
+
+func (repo *memoryRepository) GetEndpoints(_ context.Context, owner *Owner) ([]*Endpoint, error) {
+	return repo.findEndpoints(func(e *Endpoint) bool {
+		return e.Owner == *owner
+	})
+}
+
+func (repo *memoryRepository) GetSubscribedEndpoints(_ context.Context, userID, event string) ([]*Endpoint, error) {
+	return repo.findEndpoints(func(e *Endpoint) bool {
+		return (e.Owner.UserID == userID || e.Owner.Client != "") && e.Wants(event)
+	})
+}
+
+func (repo *memoryRepository) findEndpoints(match func(e *Endpoint) bool) ([]*Endpoint, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	endpoints := make([]*Endpoint, 0)
+	for _, e := range repo.endpoints {
+		if !match(e) {
+			continue
+		}
+
+		copied, err := copyEndpoint(e)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, copied)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt)
+	})
+	return endpoints, nil
+}
+
+func (repo *memoryRepository) SaveEndpoint(_ context.Context, endpoint *Endpoint) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, err := copyEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+	repo.endpoints[endpoint.ID.Hex()] = stored
+	return nil
+}
+
+func (repo *memoryRepository) UpdateEndpoint(_ context.Context, endpoint *Endpoint) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	existing, ok := repo.endpoints[endpoint.ID.Hex()]
+	if !ok || existing.Owner != endpoint.Owner {
+		return ErrNotFound
+	}
+
+	stored, err := copyEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+	repo.endpoints[endpoint.ID.Hex()] = stored
+	return nil
+}
+
+func (repo *memoryRepository) DeleteEndpoint(_ context.Context, owner *Owner, endpointID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	endpoint, ok := repo.endpoints[endpointID]
+	if !ok || endpoint.Owner != *owner {
+		return ErrNotFound
+	}
+	delete(repo.endpoints, endpointID)
+	return nil
+}
+
+func (repo *memoryRepository) EnqueueDelivery(_ context.Context, delivery *Delivery) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, d := range repo.deliveries {
+		if d.ID == delivery.ID || (d.EndpointID == delivery.EndpointID && d.EventID == delivery.EventID) {
+			return ErrAlreadyQueued
+		}
+	}
+
+	stored, err := copyDelivery(delivery)
+	if err != nil {
+		return err
+	}
+	repo.deliveries[delivery.ID.Hex()] = stored
+	return nil
+}
+
+func (repo *memoryRepository) ClaimDueDelivery(_ context.Context, now time.Time, lease time.Duration) (*Delivery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var due *Delivery
+	for _, d := range repo.deliveries {
+		if d.Status != DeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		if due == nil || d.NextAttemptAt.Before(due.NextAttemptAt) {
+			due = d
+		}
+	}
+
+	if due == nil {
+		return nil, nil
+	}
+
+	due.NextAttemptAt = now.Add(lease)
+	return copyDelivery(due)
+}
+
+func (repo *memoryRepository) SaveDelivery(_ context.Context, delivery *Delivery) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.deliveries[delivery.ID.Hex()]; !ok {
+		return ErrDeliveryNotFound
+	}
+
+	stored, err := copyDelivery(delivery)
+	if err != nil {
+		return err
+	}
+	repo.deliveries[delivery.ID.Hex()] = stored
+	return nil
+}
+
+func (repo *memoryRepository) GetDeliveries(_ context.Context, endpointID string, limit int64) ([]*Delivery, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	deliveries := make([]*Delivery, 0)
+	for _, d := range repo.deliveries {
+		if d.EndpointID != endpointID {
+			continue
+		}
+
+		copied, err := copyDelivery(d)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, copied)
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if limit > 0 && int64(len(deliveries)) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (repo *memoryRepository) CountPending(_ context.Context) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var count int64
+	for _, d := range repo.deliveries {
+		if d.Status == DeliveryPending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func copyEndpoint(e *Endpoint) (*Endpoint, error) {
+	var copied Endpoint
+	if err := memory.Copy(e, &copied); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	return &copied, nil
+}
+
+func copyDelivery(d *Delivery) (*Delivery, error) {
+	var copied Delivery
+	if err := memory.Copy(d, &copied); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	return &copied, nil
+}
@@ -0,0 +1,265 @@
+package webhooks
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	endpointsCollection  = "webhook_endpoints"
+	deliveriesCollection = "webhook_deliveries"
+)
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository_mock.go
+type Repository interface {
+	GetEndpoint(ctx context.Context, owner *Owner, endpointID string) (*Endpoint, error)
+	GetEndpoints(ctx context.Context, owner *Owner) ([]*Endpoint, error)
+	// GetSubscribedEndpoints returns active endpoints of the user and of API clients which get the event
+	GetSubscribedEndpoints(ctx context.Context, userID, event string) ([]*Endpoint, error)
+	SaveEndpoint(ctx context.Context, endpoint *Endpoint) error
+	UpdateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	DeleteEndpoint(ctx context.Context, owner *Owner, endpointID string) error
+
+	// EnqueueDelivery saves new delivery, ErrAlreadyQueued is returned if the event is already queued to the endpoint
+	EnqueueDelivery(ctx context.Context, delivery *Delivery) error
+	// ClaimDueDelivery returns pending delivery which is due at now and hides it from other workers for lease.
+	// Nil is returned if there is nothing to deliver.
+	ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (*Delivery, error)
+	SaveDelivery(ctx context.Context, delivery *Delivery) error
+	// GetDeliveries returns the latest deliveries of the endpoint
+	GetDeliveries(ctx context.Context, endpointID string, limit int64) ([]*Delivery, error)
+	// CountPending returns number of deliveries waiting for an attempt
+	CountPending(ctx context.Context) (int64, error)
+}
+
+type repository struct {
+	db  *mongo.Database
+	log *logrus.Logger
+}
+
+func NewRepository(db *mongo.Database, log *logrus.Logger) (Repository, error) {
+	if db == nil {
+		return nil, errors.NewInternal("invalid db")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &repository{db: db, log: log}, nil
+}
+
+// CreateIndexes creates indexes of endpoints and deliveries, an event is delivered to endpoint only once
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	endpointMods := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner.user_id", Value: 1}, {Key: "owner.client", Value: 1}}},
+	}
+	if err := migrations.CreateIndexes(ctx, db.Collection(endpointsCollection), endpointMods...); err != nil {
+		return err
+	}
+
+	deliveryMods := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "endpoint_id", Value: 1}, {Key: "event_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "endpoint_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+	}
+	return migrations.CreateIndexes(ctx, db.Collection(deliveriesCollection), deliveryMods...)
+}
+
+func ownerFilter(owner *Owner) bson.M {
+	return bson.M{"owner.user_id": owner.UserID, "owner.client": owner.Client}
+}
+
+func (repo *repository) GetEndpoint(ctx context.Context, owner *Owner, endpointID string) (*Endpoint, error) {
+	id, err := primitive.ObjectIDFromHex(endpointID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	filter := ownerFilter(owner)
+	filter["_id"] = id
+
+	var endpoint Endpoint
+	if err = repo.db.Collection(endpointsCollection).FindOne(ctx, filter).Decode(&endpoint); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+
+		repo.log.WithContext(ctx).Errorf("unable to find webhook endpoint due to internal error: %v; endpoint id: %s", err, endpointID)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return &endpoint, nil
+}
+
+func (repo *repository) GetEndpoints(ctx context.Context, owner *Owner) ([]*Endpoint, error) {
+	return repo.findEndpoints(ctx, ownerFilter(owner))
+}
+
+func (repo *repository) GetSubscribedEndpoints(ctx context.Context, userID, event string) ([]*Endpoint, error) {
+	return repo.findEndpoints(ctx, bson.M{
+		"active": true,
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"owner.user_id": userID},
+				bson.M{"owner.client": bson.M{"$ne": ""}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"events": bson.M{"$size": 0}},
+				bson.M{"events": event},
+			}},
+		},
+	})
+}
+
+func (repo *repository) findEndpoints(ctx context.Context, filter bson.M) ([]*Endpoint, error) {
+	cursor, err := repo.db.
+		Collection(endpointsCollection).
+		Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to find webhook endpoints due to internal error: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	endpoints := make([]*Endpoint, 0)
+	if err = cursor.All(ctx, &endpoints); err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to decode webhook endpoints: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return endpoints, nil
+}
+
+func (repo *repository) SaveEndpoint(ctx context.Context, endpoint *Endpoint) error {
+	if _, err := repo.db.Collection(endpointsCollection).InsertOne(ctx, endpoint); err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to insert webhook endpoint to db: %v", err)
+		return errors.NewInternal(err.Error())
+	}
+	return nil
+}
+
+func (repo *repository) UpdateEndpoint(ctx context.Context, endpoint *Endpoint) error {
+	filter := ownerFilter(&endpoint.Owner)
+	filter["_id"] = endpoint.ID
+
+	res, err := repo.db.Collection(endpointsCollection).ReplaceOne(ctx, filter, endpoint)
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to update webhook endpoint '%s': %v", endpoint.ID.Hex(), err)
+		return errors.NewInternal(err.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (repo *repository) DeleteEndpoint(ctx context.Context, owner *Owner, endpointID string) error {
+	id, err := primitive.ObjectIDFromHex(endpointID)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	filter := ownerFilter(owner)
+	filter["_id"] = id
+
+	res, err := repo.db.Collection(endpointsCollection).DeleteOne(ctx, filter)
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to delete webhook endpoint '%s': %v", endpointID, err)
+		return errors.NewInternal(err.Error())
+	}
+
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (repo *repository) EnqueueDelivery(ctx context.Context, delivery *Delivery) error {
+	if _, err := repo.db.Collection(deliveriesCollection).InsertOne(ctx, delivery); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyQueued
+		}
+
+		repo.log.WithContext(ctx).Errorf("failed to insert webhook delivery: %v", err)
+		return errors.NewInternal(err.Error())
+	}
+	return nil
+}
+
+func (repo *repository) ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (*Delivery, error) {
+	var delivery Delivery
+	err := repo.db.
+		Collection(deliveriesCollection).
+		FindOneAndUpdate(ctx,
+			bson.M{"status": DeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+				SetReturnDocument(options.After)).
+		Decode(&delivery)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		repo.log.WithContext(ctx).Errorf("failed to claim webhook delivery: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return &delivery, nil
+}
+
+func (repo *repository) SaveDelivery(ctx context.Context, delivery *Delivery) error {
+	res, err := repo.db.
+		Collection(deliveriesCollection).
+		ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery)
+
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to save webhook delivery '%s': %v", delivery.ID.Hex(), err)
+		return errors.NewInternal(err.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func (repo *repository) GetDeliveries(ctx context.Context, endpointID string, limit int64) ([]*Delivery, error) {
+	cursor, err := repo.db.
+		Collection(deliveriesCollection).
+		Find(ctx, bson.M{"endpoint_id": endpointID}, options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}}).
+			SetLimit(limit))
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to find webhook deliveries due to internal error: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	deliveries := make([]*Delivery, 0)
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to decode webhook deliveries: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return deliveries, nil
+}
+
+func (repo *repository) CountPending(ctx context.Context) (int64, error) {
+	count, err := repo.db.
+		Collection(deliveriesCollection).
+		CountDocuments(ctx, bson.M{"status": DeliveryPending})
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to count pending webhook deliveries: %v", err)
+		return 0, errors.NewInternal(err.Error())
+	}
+	return count, nil
+}
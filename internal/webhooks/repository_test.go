@@ -0,0 +1,192 @@
+package webhooks_test
+
+import (
+	"context"
+	"nnw_s/internal/events"
+	"nnw_s/internal/webhooks"
+	"nnw_s/pkg/mongodb/mongotest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) webhooks.Repository {
+		repo, err := webhooks.NewMemoryRepository()
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func TestMongoRepository(t *testing.T) {
+	testRepository(t, func(t *testing.T) webhooks.Repository {
+		db := mongotest.DB(t)
+		require.Nil(t, webhooks.CreateIndexes(context.Background(), db))
+
+		repo, err := webhooks.NewRepository(db, logrus.New())
+		require.Nil(t, err)
+		return repo
+	})
+}
+
+func newEndpoint(t *testing.T, owner webhooks.Owner, eventNames ...string) *webhooks.Endpoint {
+	endpoint, err := webhooks.NewEndpoint(owner, "https://example.com/hook", "", eventNames)
+	require.Nil(t, err)
+	return endpoint
+}
+
+func newEvent(name string) *webhooks.Event {
+	return &webhooks.Event{
+		ID:        primitive.NewObjectID().Hex(),
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		Data:      map[string]string{"email": "user@example.com"},
+	}
+}
+
+// testRepository is a conformance suite which every webhooks.Repository implementation should pass
+func testRepository(t *testing.T, newRepo func(t *testing.T) webhooks.Repository) {
+	ctx := context.Background()
+	alice, bob := webhooks.Owner{UserID: "alice"}, webhooks.Owner{UserID: "bob"}
+
+	t.Run("should get endpoints only of the owner", func(t *testing.T) {
+		repo := newRepo(t)
+		endpoint := newEndpoint(t, alice)
+		require.Nil(t, repo.SaveEndpoint(ctx, endpoint))
+		require.Nil(t, repo.SaveEndpoint(ctx, newEndpoint(t, bob)))
+
+		loaded, err := repo.GetEndpoint(ctx, &alice, endpoint.ID.Hex())
+		require.Nil(t, err)
+		assert.Equal(t, endpoint.Secret, loaded.Secret)
+
+		_, err = repo.GetEndpoint(ctx, &bob, endpoint.ID.Hex())
+		assert.Equal(t, webhooks.ErrNotFound, err)
+
+		endpoints, err := repo.GetEndpoints(ctx, &alice)
+		require.Nil(t, err)
+		require.Len(t, endpoints, 1)
+		assert.Equal(t, endpoint.ID, endpoints[0].ID)
+
+		assert.Equal(t, webhooks.ErrNotFound, repo.DeleteEndpoint(ctx, &bob, endpoint.ID.Hex()))
+		require.Nil(t, repo.DeleteEndpoint(ctx, &alice, endpoint.ID.Hex()))
+		_, err = repo.GetEndpoint(ctx, &alice, endpoint.ID.Hex())
+		assert.Equal(t, webhooks.ErrNotFound, err)
+	})
+
+	t.Run("should get active endpoints of the user and API clients which get the event", func(t *testing.T) {
+		repo := newRepo(t)
+		all := newEndpoint(t, alice)
+		filtered := newEndpoint(t, alice, events.NameWalletCreated)
+		client := newEndpoint(t, webhooks.Owner{Client: "exchange"})
+		inactive := newEndpoint(t, alice)
+		require.Nil(t, inactive.Update(inactive.URL, "", nil, false))
+
+		for _, e := range []*webhooks.Endpoint{all, filtered, client, inactive, newEndpoint(t, bob)} {
+			require.Nil(t, repo.SaveEndpoint(ctx, e))
+		}
+
+		endpoints, err := repo.GetSubscribedEndpoints(ctx, "alice", events.NameUserVerified)
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []primitive.ObjectID{all.ID, client.ID}, endpointIDs(endpoints))
+
+		endpoints, err = repo.GetSubscribedEndpoints(ctx, "alice", events.NameWalletCreated)
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []primitive.ObjectID{all.ID, filtered.ID, client.ID}, endpointIDs(endpoints))
+	})
+
+	t.Run("should queue event to endpoint only once", func(t *testing.T) {
+		repo := newRepo(t)
+		endpoint := newEndpoint(t, alice)
+		event := newEvent(events.NameUserVerified)
+
+		delivery, err := webhooks.NewDelivery(endpoint, event)
+		require.Nil(t, err)
+		require.Nil(t, repo.EnqueueDelivery(ctx, delivery))
+
+		again, err := webhooks.NewDelivery(endpoint, event)
+		require.Nil(t, err)
+		assert.Equal(t, webhooks.ErrAlreadyQueued, repo.EnqueueDelivery(ctx, again))
+	})
+
+	t.Run("should claim due delivery for the lease", func(t *testing.T) {
+		repo := newRepo(t)
+		delivery, err := webhooks.NewDelivery(newEndpoint(t, alice), newEvent(events.NameUserVerified))
+		require.Nil(t, err)
+		require.Nil(t, repo.EnqueueDelivery(ctx, delivery))
+
+		now := delivery.NextAttemptAt.Add(time.Second)
+		claimed, err := repo.ClaimDueDelivery(ctx, now, time.Minute)
+		require.Nil(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, delivery.ID, claimed.ID)
+
+		claimed, err = repo.ClaimDueDelivery(ctx, now, time.Minute)
+		require.Nil(t, err)
+		assert.Nil(t, claimed)
+
+		claimed, err = repo.ClaimDueDelivery(ctx, now.Add(2*time.Minute), time.Minute)
+		require.Nil(t, err)
+		require.NotNil(t, claimed)
+
+		claimed.MarkSucceeded(now, 200)
+		require.Nil(t, repo.SaveDelivery(ctx, claimed))
+
+		claimed, err = repo.ClaimDueDelivery(ctx, now.Add(time.Hour), time.Minute)
+		require.Nil(t, err)
+		assert.Nil(t, claimed)
+	})
+
+	t.Run("should get the latest deliveries of endpoint", func(t *testing.T) {
+		repo := newRepo(t)
+		endpoint := newEndpoint(t, alice)
+
+		var deliveries []*webhooks.Delivery
+		for i := 0; i < 3; i++ {
+			delivery, err := webhooks.NewDelivery(endpoint, newEvent(events.NameUserVerified))
+			require.Nil(t, err)
+			delivery.CreatedAt = time.Date(2022, 1, 1, i, 0, 0, 0, time.UTC)
+			require.Nil(t, repo.EnqueueDelivery(ctx, delivery))
+			deliveries = append(deliveries, delivery)
+		}
+
+		loaded, err := repo.GetDeliveries(ctx, endpoint.ID.Hex(), 2)
+		require.Nil(t, err)
+		require.Len(t, loaded, 2)
+		assert.Equal(t, deliveries[2].ID, loaded[0].ID)
+		assert.Equal(t, deliveries[1].ID, loaded[1].ID)
+
+		loaded, err = repo.GetDeliveries(ctx, newEndpoint(t, alice).ID.Hex(), 2)
+		require.Nil(t, err)
+		assert.Empty(t, loaded)
+	})
+
+	t.Run("should count pending deliveries", func(t *testing.T) {
+		repo := newRepo(t)
+		endpoint := newEndpoint(t, alice)
+
+		for i := 0; i < 2; i++ {
+			delivery, err := webhooks.NewDelivery(endpoint, newEvent(events.NameUserVerified))
+			require.Nil(t, err)
+			if i == 0 {
+				delivery.MarkSucceeded(time.Now(), 200)
+			}
+			require.Nil(t, repo.EnqueueDelivery(ctx, delivery))
+		}
+
+		count, err := repo.CountPending(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func endpointIDs(endpoints []*webhooks.Endpoint) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(endpoints))
+	for _, e := range endpoints {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
@@ -0,0 +1,206 @@
+package webhooks
+
+import (
+	"context"
+	"nnw_s/internal/user"
+	"nnw_s/pkg/errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// deliveriesLimit limits deliveries returned in delivery log of endpoint
+const deliveriesLimit = 50
+
+//go:generate mockgen -source=service.go -destination=mocks/service_mock.go
+type Service interface {
+	// UserOwner returns owner of user's endpoints
+	UserOwner(ctx context.Context, email string) (*Owner, error)
+
+	GetEndpoints(ctx context.Context, owner *Owner) ([]*EndpointDTO, error)
+	CreateEndpoint(ctx context.Context, owner *Owner, dto *CreateEndpointDTO) (*EndpointDTO, error)
+	UpdateEndpoint(ctx context.Context, owner *Owner, dto *UpdateEndpointDTO) (*EndpointDTO, error)
+	DeleteEndpoint(ctx context.Context, owner *Owner, endpointID string) error
+	RotateSecret(ctx context.Context, owner *Owner, endpointID string) (*EndpointDTO, error)
+
+	// GetDeliveries returns delivery log of the endpoint
+	GetDeliveries(ctx context.Context, owner *Owner, endpointID string) ([]*DeliveryDTO, error)
+	// SendTestEvent queues test event to the endpoint, it is delivered like any other event
+	SendTestEvent(ctx context.Context, owner *Owner, endpointID string) (*DeliveryDTO, error)
+
+	// Dispatch queues event of the user to every endpoint which gets it, the same event is queued only once
+	Dispatch(ctx context.Context, email string, event *Event) error
+}
+
+type service struct {
+	repo    Repository
+	userSvc user.Service
+
+	log *logrus.Logger
+}
+
+type ServiceDeps struct {
+	Repository  Repository
+	UserService user.Service
+}
+
+func NewService(log *logrus.Logger, deps *ServiceDeps) (Service, error) {
+	if deps == nil {
+		return nil, errors.NewInternal("invalid service dependencies")
+	}
+	if deps.Repository == nil {
+		return nil, errors.NewInternal("invalid repo")
+	}
+	if deps.UserService == nil {
+		return nil, errors.NewInternal("invalid user service")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &service{
+		repo:    deps.Repository,
+		userSvc: deps.UserService,
+		log:     log,
+	}, nil
+}
+
+func (svc *service) UserOwner(ctx context.Context, email string) (*Owner, error) {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return &Owner{UserID: userDTO.ID}, nil
+}
+
+func (svc *service) GetEndpoints(ctx context.Context, owner *Owner) ([]*EndpointDTO, error) {
+	endpoints, err := svc.repo.GetEndpoints(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	return MapToDTOs(endpoints), nil
+}
+
+func (svc *service) CreateEndpoint(ctx context.Context, owner *Owner, dto *CreateEndpointDTO) (*EndpointDTO, error) {
+	endpoints, err := svc.repo.GetEndpoints(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) >= maxEndpoints {
+		return nil, ErrTooManyEndpoints
+	}
+
+	endpoint, err := NewEndpoint(*owner, dto.URL, dto.Description, dto.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.repo.SaveEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return MapWithSecretToDTO(endpoint), nil
+}
+
+func (svc *service) UpdateEndpoint(ctx context.Context, owner *Owner, dto *UpdateEndpointDTO) (*EndpointDTO, error) {
+	endpoint, err := svc.repo.GetEndpoint(ctx, owner, dto.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = endpoint.Update(dto.URL, dto.Description, dto.Events, dto.Active != nil && *dto.Active); err != nil {
+		return nil, err
+	}
+
+	if err = svc.repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return MapToDTO(endpoint), nil
+}
+
+func (svc *service) DeleteEndpoint(ctx context.Context, owner *Owner, endpointID string) error {
+	return svc.repo.DeleteEndpoint(ctx, owner, endpointID)
+}
+
+func (svc *service) RotateSecret(ctx context.Context, owner *Owner, endpointID string) (*EndpointDTO, error) {
+	endpoint, err := svc.repo.GetEndpoint(ctx, owner, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = endpoint.RotateSecret(); err != nil {
+		return nil, err
+	}
+
+	if err = svc.repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return MapWithSecretToDTO(endpoint), nil
+}
+
+func (svc *service) GetDeliveries(ctx context.Context, owner *Owner, endpointID string) ([]*DeliveryDTO, error) {
+	// deliveries are looked up by endpoint, so owner of the endpoint is checked first
+	endpoint, err := svc.repo.GetEndpoint(ctx, owner, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := svc.repo.GetDeliveries(ctx, endpoint.ID.Hex(), deliveriesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapDeliveriesToDTO(deliveries), nil
+}
+
+func (svc *service) SendTestEvent(ctx context.Context, owner *Owner, endpointID string) (*DeliveryDTO, error) {
+	endpoint, err := svc.repo.GetEndpoint(ctx, owner, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := NewDelivery(endpoint, &Event{
+		ID:        primitive.NewObjectID().Hex(),
+		Name:      EventTest,
+		CreatedAt: time.Now().UTC(),
+		Data:      map[string]string{"endpoint_id": endpoint.ID.Hex()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.repo.EnqueueDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return MapDeliveryToDTO(delivery), nil
+}
+
+func (svc *service) Dispatch(ctx context.Context, email string, event *Event) error {
+	userDTO, err := svc.userSvc.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := svc.repo.GetSubscribedEndpoints(ctx, userDTO.ID, event.Name)
+	if err != nil {
+		return err
+	}
+
+	// one failed endpoint doesn't stop queueing to others, the event can be replayed to retry the rest
+	var dispatchErr error
+	for _, endpoint := range endpoints {
+		delivery, err := NewDelivery(endpoint, event)
+		if err != nil {
+			return err
+		}
+
+		if err = svc.repo.EnqueueDelivery(ctx, delivery); err != nil && err != ErrAlreadyQueued {
+			svc.log.WithContext(ctx).Errorf("failed to queue event '%s' to webhook endpoint '%s': %v", event.ID, endpoint.ID.Hex(), err)
+			dispatchErr = err
+		}
+	}
+	return dispatchErr
+}
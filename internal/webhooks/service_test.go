@@ -0,0 +1,159 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"nnw_s/internal/events"
+	"nnw_s/internal/user"
+	mock_user "nnw_s/internal/user/mocks"
+	"nnw_s/internal/webhooks"
+	"nnw_s/pkg/errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusOf(err error) errors.Status {
+	if e, ok := err.(*errors.Error); ok {
+		return e.Status
+	}
+	return ""
+}
+
+func TestService(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx := context.Background()
+	email := "alice@mail.com"
+	alice := &webhooks.Owner{UserID: "alice"}
+
+	setup := func(t *testing.T) (webhooks.Service, webhooks.Repository) {
+		repo, err := webhooks.NewMemoryRepository()
+		require.Nil(t, err)
+
+		mockUserSvc := mock_user.NewMockService(controller)
+		mockUserSvc.EXPECT().GetUserByEmail(gomock.Any(), email).Return(&user.DTO{ID: "alice", Email: email}, nil).AnyTimes()
+
+		service, err := webhooks.NewService(logrus.New(), &webhooks.ServiceDeps{Repository: repo, UserService: mockUserSvc})
+		require.Nil(t, err)
+		return service, repo
+	}
+
+	t.Run("should return secret only on create", func(t *testing.T) {
+		service, _ := setup(t)
+
+		created, err := service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{
+			URL:    "https://example.com/hook",
+			Events: []string{events.NameWalletCreated},
+		})
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+		assert.True(t, created.Active)
+
+		endpoints, err := service.GetEndpoints(ctx, alice)
+		require.Nil(t, err)
+		require.Len(t, endpoints, 1)
+		assert.Equal(t, created.ID, endpoints[0].ID)
+		assert.Empty(t, endpoints[0].Secret)
+	})
+
+	t.Run("should reject unknown events and too many endpoints", func(t *testing.T) {
+		service, _ := setup(t)
+
+		_, err := service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{URL: "https://example.com/hook", Events: []string{"unknown"}})
+		assert.Equal(t, webhooks.StatusInvalidEndpoint, statusOf(err))
+
+		for i := 0; i < 10; i++ {
+			_, err = service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{URL: "https://example.com/hook"})
+			require.Nil(t, err)
+		}
+		_, err = service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{URL: "https://example.com/hook"})
+		assert.Equal(t, webhooks.ErrTooManyEndpoints, err)
+	})
+
+	t.Run("should reject plain http and private hosts", func(t *testing.T) {
+		service, _ := setup(t)
+
+		for _, url := range []string{"http://example.com/hook", "https://localhost/hook", "https://169.254.169.254/latest", "https://10.0.0.1/hook"} {
+			_, err := service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{URL: url})
+			assert.Equal(t, webhooks.StatusInvalidEndpoint, statusOf(err), url)
+		}
+	})
+
+	t.Run("should queue event once to endpoints which get it", func(t *testing.T) {
+		service, repo := setup(t)
+
+		wallets, err := service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{URL: "https://example.com/wallets", Events: []string{events.NameWalletCreated}})
+		require.Nil(t, err)
+		all, err := service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{URL: "https://example.com/all"})
+		require.Nil(t, err)
+
+		event := newEvent(events.NameUserVerified)
+		require.Nil(t, service.Dispatch(ctx, email, event))
+		// replayed event is not queued again
+		require.Nil(t, service.Dispatch(ctx, email, event))
+
+		deliveries, err := repo.GetDeliveries(ctx, all.ID, 10)
+		require.Nil(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, event.ID, deliveries[0].EventID)
+
+		var payload map[string]interface{}
+		require.Nil(t, json.Unmarshal([]byte(deliveries[0].Payload), &payload))
+		assert.Equal(t, events.NameUserVerified, payload["event"])
+
+		deliveries, err = repo.GetDeliveries(ctx, wallets.ID, 10)
+		require.Nil(t, err)
+		assert.Empty(t, deliveries)
+	})
+
+	t.Run("should queue chain events to endpoints filtered by them", func(t *testing.T) {
+		service, repo := setup(t)
+
+		deposits, err := service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{
+			URL:    "https://example.com/deposits",
+			Events: []string{events.NameDepositReceived, events.NameTransactionConfirmed},
+		})
+		require.Nil(t, err)
+
+		require.Nil(t, service.Dispatch(ctx, email, newEvent(events.NameDepositReceived)))
+		require.Nil(t, service.Dispatch(ctx, email, newEvent(events.NameTransactionConfirmed)))
+		require.Nil(t, service.Dispatch(ctx, email, newEvent(events.NameWalletCreated)))
+
+		deliveries, err := repo.GetDeliveries(ctx, deposits.ID, 10)
+		require.Nil(t, err)
+		assert.Len(t, deliveries, 2)
+	})
+
+	t.Run("should send test event and show it in delivery log", func(t *testing.T) {
+		service, _ := setup(t)
+
+		endpoint, err := service.CreateEndpoint(ctx, alice, &webhooks.CreateEndpointDTO{URL: "https://example.com/hook", Events: []string{events.NameWalletCreated}})
+		require.Nil(t, err)
+
+		sent, err := service.SendTestEvent(ctx, alice, endpoint.ID)
+		require.Nil(t, err)
+		assert.Equal(t, webhooks.EventTest, sent.Event)
+		assert.Equal(t, webhooks.DeliveryPending, sent.Status)
+
+		deliveries, err := service.GetDeliveries(ctx, alice, endpoint.ID)
+		require.Nil(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, sent.ID, deliveries[0].ID)
+
+		_, err = service.GetDeliveries(ctx, &webhooks.Owner{UserID: "bob"}, endpoint.ID)
+		assert.Equal(t, webhooks.ErrNotFound, err)
+		_, err = service.SendTestEvent(ctx, &webhooks.Owner{Client: "exchange"}, endpoint.ID)
+		assert.Equal(t, webhooks.ErrNotFound, err)
+	})
+}
+
+func TestNewService(t *testing.T) {
+	_, err := webhooks.NewService(logrus.New(), &webhooks.ServiceDeps{})
+	assert.EqualError(t, err, "code: 500; status: internal_error; message: invalid repo")
+}
@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"context"
+	"nnw_s/internal/events"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/eventbus"
+)
+
+const subscriberName = "webhooks"
+
+// Subscriber queues domain events to webhook endpoints
+type Subscriber struct {
+	webhooksSvc Service
+}
+
+func NewSubscriber(webhooksSvc Service) (*Subscriber, error) {
+	if webhooksSvc == nil {
+		return nil, errors.NewInternal("invalid webhooks service")
+	}
+	return &Subscriber{webhooksSvc: webhooksSvc}, nil
+}
+
+func (s *Subscriber) Subscribe(bus *eventbus.Bus) {
+	bus.Subscribe(subscriberName, s.handle)
+}
+
+// handle queues the event with id of the event log, so replayed event is not delivered twice
+func (s *Subscriber) handle(ctx context.Context, event eventbus.Event) error {
+	userEvent, ok := event.(events.UserEvent)
+	if !ok {
+		return nil
+	}
+
+	meta, ok := eventbus.MetaFromContext(ctx)
+	if !ok {
+		return errors.NewInternal("event without metadata")
+	}
+
+	return s.webhooksSvc.Dispatch(ctx, userEvent.UserEmail(), &Event{
+		ID:        meta.ID,
+		Name:      event.EventName(),
+		CreatedAt: meta.OccurredAt,
+		Data:      event,
+	})
+}
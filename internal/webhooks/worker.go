@@ -0,0 +1,199 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultMaxAttempts  = 10
+	defaultBaseBackoff  = 30 * time.Second
+	defaultMaxBackoff   = 6 * time.Hour
+	defaultSendTimeout  = 10 * time.Second
+
+	// maxDrainBody limits response body read to reuse the connection
+	maxDrainBody = 4 << 10
+	userAgent    = "NNW-Webhooks/1.0"
+)
+
+type WorkerOptions struct {
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	SendTimeout  time.Duration
+}
+
+// Worker posts queued deliveries to endpoints, failed deliveries are retried with exponential backoff
+// and fail after MaxAttempts. Every attempt is signed with the current time and secret of the endpoint.
+type Worker struct {
+	repo   Repository
+	client *http.Client
+	clock  clock.Clock
+	opts   WorkerOptions
+
+	log *logrus.Logger
+}
+
+func NewWorker(log *logrus.Logger, repo Repository, client *http.Client, clk clock.Clock, opts WorkerOptions) (*Worker, error) {
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	if repo == nil {
+		return nil, errors.NewInternal("invalid repo")
+	}
+	if client == nil {
+		return nil, errors.NewInternal("invalid http client")
+	}
+	if clk == nil {
+		return nil, errors.NewInternal("invalid clock")
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = defaultBaseBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultMaxBackoff
+	}
+	if opts.SendTimeout <= 0 {
+		opts.SendTimeout = defaultSendTimeout
+	}
+
+	return &Worker{repo: repo, client: client, clock: clk, opts: opts, log: log}, nil
+}
+
+// Run delivers due events until ctx is done
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.ProcessDue(ctx); err != nil {
+			w.log.WithContext(ctx).Errorf("failed to process webhook deliveries: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue delivers all events which are due now and returns number of processed deliveries
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	var processed int
+	for ctx.Err() == nil {
+		// lease is longer than send timeout, so delivery is not claimed twice while it is being sent
+		delivery, err := w.repo.ClaimDueDelivery(ctx, w.clock.Now(), 2*w.opts.SendTimeout)
+		if err != nil {
+			return processed, err
+		}
+		if delivery == nil {
+			return processed, nil
+		}
+
+		if err = w.deliver(ctx, delivery); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery *Delivery) error {
+	endpoint, err := w.repo.GetEndpoint(ctx, &delivery.Owner, delivery.EndpointID)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+
+	now := w.clock.Now()
+
+	// events are not sent to deleted or disabled endpoints, they fail at once
+	if err == ErrNotFound || !endpoint.Active {
+		delivery.MarkFailed(now, fmt.Errorf("endpoint is deleted or disabled"), 0, now, true)
+		return w.repo.SaveDelivery(ctx, delivery)
+	}
+
+	status, sendErr := w.send(ctx, endpoint, delivery)
+	now = w.clock.Now()
+
+	if sendErr == nil {
+		delivery.MarkSucceeded(now, status)
+		w.log.WithContext(ctx).Infof("webhook delivery '%s' of event '%s' is succeeded", delivery.ID.Hex(), delivery.EventID)
+		return w.repo.SaveDelivery(ctx, delivery)
+	}
+
+	failed := delivery.Attempts+1 >= w.opts.MaxAttempts || status == http.StatusGone
+	delivery.MarkFailed(now, sendErr, status, now.Add(w.backoff(delivery.Attempts+1)), failed)
+
+	if failed {
+		w.log.WithContext(ctx).Errorf("webhook delivery '%s' is failed after %d attempts: %v", delivery.ID.Hex(), delivery.Attempts, sendErr)
+	} else {
+		w.log.WithContext(ctx).Warnf("failed to deliver webhook '%s', attempt %d: %v", delivery.ID.Hex(), delivery.Attempts, sendErr)
+	}
+	return w.repo.SaveDelivery(ctx, delivery)
+}
+
+// send posts signed payload to endpoint and returns status of the response, it is 0 if there is no response
+func (w *Worker) send(ctx context.Context, endpoint *Endpoint, delivery *Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.opts.SendTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, w.clock.Now(), body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		// url of endpoint may contain credentials, so only host gets to the delivery log
+		if urlErr, ok := err.(*neturl.Error); ok {
+			return 0, fmt.Errorf("%s %s: %w", urlErr.Op, req.URL.Host, urlErr.Err)
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// body of the response is never kept, so the delivery log can't be used to read responses of endpoints
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDrainBody))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, fmt.Errorf("%s responded with %d", req.URL.Host, resp.StatusCode)
+}
+
+// backoff returns delay before the next attempt: base, 2*base, 4*base... but not more than max
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.opts.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= w.opts.MaxBackoff {
+			return w.opts.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,172 @@
+package webhooks_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/internal/events"
+	"nnw_s/internal/webhooks"
+	"nnw_s/pkg/clock"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a webhook endpoint which checks signatures the way receivers should do it
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	now      func() time.Time
+	status   int
+	received []string
+	errs     []error
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := ioutil.ReadAll(req.Body)
+	if err := webhooks.Verify(r.secret, req.Header.Get(webhooks.HeaderSignature), body, r.now(), webhooks.DefaultTolerance); err != nil {
+		r.errs = append(r.errs, err)
+	}
+	r.received = append(r.received, req.Header.Get(webhooks.HeaderEvent))
+	w.WriteHeader(r.status)
+	_, _ = w.Write([]byte("internal response"))
+}
+
+func TestWorker(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, status int) (webhooks.Repository, *webhooks.Worker, *clock.Mock, *receiver, *webhooks.Delivery) {
+		repo, err := webhooks.NewMemoryRepository()
+		require.Nil(t, err)
+
+		endpoint := newEndpoint(t, webhooks.Owner{UserID: "alice"})
+		delivery, err := webhooks.NewDelivery(endpoint, newEvent(events.NameUserVerified))
+		require.Nil(t, err)
+		clk := clock.NewMock(delivery.NextAttemptAt)
+
+		rcv := &receiver{secret: endpoint.Secret, now: clk.Now, status: status}
+		server := httptest.NewServer(rcv)
+		t.Cleanup(server.Close)
+
+		endpoint.URL = server.URL
+		require.Nil(t, repo.SaveEndpoint(ctx, endpoint))
+		require.Nil(t, repo.EnqueueDelivery(ctx, delivery))
+
+		worker, err := webhooks.NewWorker(logrus.New(), repo, server.Client(), clk, webhooks.WorkerOptions{
+			MaxAttempts: 3,
+			BaseBackoff: time.Minute,
+			MaxBackoff:  time.Hour,
+		})
+		require.Nil(t, err)
+
+		return repo, worker, clk, rcv, delivery
+	}
+
+	t.Run("should post signed event once", func(t *testing.T) {
+		repo, worker, clk, rcv, delivery := setup(t, http.StatusNoContent)
+
+		processed, err := worker.ProcessDue(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 1, processed)
+
+		clk.Add(time.Hour)
+		processed, err = worker.ProcessDue(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 0, processed)
+
+		assert.Equal(t, []string{events.NameUserVerified}, rcv.received)
+		assert.Empty(t, rcv.errs)
+
+		deliveries, err := repo.GetDeliveries(ctx, delivery.EndpointID, 10)
+		require.Nil(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, webhooks.DeliverySucceeded, deliveries[0].Status)
+		assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+	})
+
+	t.Run("should retry with backoff and fail after max attempts", func(t *testing.T) {
+		repo, worker, clk, rcv, delivery := setup(t, http.StatusInternalServerError)
+
+		for _, wait := range []time.Duration{0, time.Minute, 2 * time.Minute} {
+			clk.Add(wait - time.Second)
+			processed, err := worker.ProcessDue(ctx)
+			require.Nil(t, err)
+			assert.Equal(t, 0, processed, "delivery is retried before backoff")
+
+			clk.Add(time.Second)
+			processed, err = worker.ProcessDue(ctx)
+			require.Nil(t, err)
+			assert.Equal(t, 1, processed)
+		}
+
+		clk.Add(time.Hour)
+		processed, err := worker.ProcessDue(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 0, processed)
+		assert.Len(t, rcv.received, 3)
+
+		deliveries, err := repo.GetDeliveries(ctx, delivery.EndpointID, 10)
+		require.Nil(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, webhooks.DeliveryFailed, deliveries[0].Status)
+		assert.Equal(t, http.StatusInternalServerError, deliveries[0].ResponseStatus)
+		assert.Contains(t, deliveries[0].LastError, "responded with 500")
+		assert.NotContains(t, deliveries[0].LastError, "internal response")
+	})
+
+	t.Run("should fail delivery to deleted endpoint at once", func(t *testing.T) {
+		repo, worker, _, rcv, delivery := setup(t, http.StatusOK)
+		require.Nil(t, repo.DeleteEndpoint(ctx, &delivery.Owner, delivery.EndpointID))
+
+		processed, err := worker.ProcessDue(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Empty(t, rcv.received)
+
+		deliveries, err := repo.GetDeliveries(ctx, delivery.EndpointID, 10)
+		require.Nil(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, webhooks.DeliveryFailed, deliveries[0].Status)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1640995200, 0)
+	body := []byte(`{"event":"user_verified"}`)
+	header := webhooks.Sign("whsec_secret", now, body)
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		err    error
+	}{
+		{name: "should accept valid signature", secret: "whsec_secret", header: header, body: body, now: now.Add(time.Minute)},
+		{name: "should reject other secret", secret: "whsec_other", header: header, body: body, now: now, err: webhooks.ErrInvalidSignature},
+		{name: "should reject changed body", secret: "whsec_secret", header: header, body: []byte(`{}`), now: now, err: webhooks.ErrInvalidSignature},
+		{name: "should reject malformed header", secret: "whsec_secret", header: "v1=abc", body: body, now: now, err: webhooks.ErrInvalidSignature},
+		{name: "should reject old signature", secret: "whsec_secret", header: header, body: body, now: now.Add(time.Hour), err: webhooks.ErrSignatureExpired},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := webhooks.Verify(tc.secret, tc.header, tc.body, tc.now, webhooks.DefaultTolerance)
+			if tc.err == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, tc.err, err)
+			}
+		})
+	}
+}
@@ -7,9 +7,16 @@ import (
 )
 
 type Error struct {
-	Code    codes.Code `json:"code"`
-	Status  Status     `json:"status"`
-	Message string     `json:"message,omitempty"`
+	Code    codes.Code   `json:"code"`
+	Status  Status       `json:"status"`
+	Message string       `json:"message,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes why a field of request is invalid
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
 }
 
 func (err Error) Error() string {
@@ -17,6 +24,9 @@ func (err Error) Error() string {
 	if err.Message != "" {
 		repr += "; message: " + err.Message
 	}
+	for _, f := range err.Fields {
+		repr += fmt.Sprintf("; %s: %s", f.Field, f.Reason)
+	}
 	return repr
 }
 
@@ -33,6 +43,21 @@ func WithMessage(target error, msg string, args ...interface{}) error {
 		Code:    err.Code,
 		Status:  err.Status,
 		Message: fmt.Sprintf(msg, args...),
+		Fields:  err.Fields,
+	}
+}
+
+// WithFields returns copy of target error with invalid fields of request
+func WithFields(target error, fields ...FieldError) error {
+	err, ok := target.(*Error)
+	if !ok {
+		return target
+	}
+	return &Error{
+		Code:    err.Code,
+		Status:  err.Status,
+		Message: err.Message,
+		Fields:  append(append([]FieldError(nil), err.Fields...), fields...),
 	}
 }
 
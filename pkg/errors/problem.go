@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MIMEProblemJSON is a content type of problem details, RFC 7807
+const MIMEProblemJSON = "application/problem+json"
+
+// ProblemTypePrefix is a prefix of types of problems, type of a problem is the prefix and its code,
+// e.g. urn:nnw:problem:invalid_request, so clients can rely on it
+const ProblemTypePrefix = "urn:nnw:problem:"
+
+// Problem is a response of failed request in format of RFC 7807
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Code is a machine-readable status of the error, e.g. invalid_request
+	Code          Status       `json:"code"`
+	InvalidParams []FieldError `json:"invalid_params,omitempty"`
+	TraceID       string       `json:"trace_id,omitempty"`
+	RequestID     string       `json:"request_id,omitempty"`
+}
+
+// ProblemOf maps err to problem: status of domain errors is their code, errors of echo keep their status
+// and other errors are internal. Details of internal errors are not exposed, they are only logged.
+func ProblemOf(err error) *Problem {
+	var (
+		domainErr *Error
+		httpErr   *echo.HTTPError
+		problem   Problem
+	)
+
+	switch {
+	case stderrors.As(err, &domainErr):
+		problem.Status = HTTPCode(domainErr)
+		problem.Code = domainErr.Status
+		problem.Detail = domainErr.Message
+		problem.InvalidParams = domainErr.Fields
+	case stderrors.As(err, &httpErr):
+		problem.Status = httpErr.Code
+		problem.Code = Status(strings.ReplaceAll(strings.ToLower(http.StatusText(httpErr.Code)), " ", "_"))
+		if msg, ok := httpErr.Message.(string); ok && msg != http.StatusText(httpErr.Code) {
+			problem.Detail = msg
+		}
+	}
+
+	if problem.Status < http.StatusBadRequest || problem.Status > 599 || problem.Code == "" {
+		problem = Problem{Status: http.StatusInternalServerError, Code: statusInternalError}
+	}
+	if problem.Status >= http.StatusInternalServerError {
+		problem.Detail = ""
+	}
+
+	problem.Type = ProblemTypePrefix + string(problem.Code)
+	problem.Title = titleOf(problem.Code)
+	return &problem
+}
+
+// titleOf returns human-readable summary of code, e.g. "Invalid request" of invalid_request
+func titleOf(code Status) string {
+	title := strings.ReplaceAll(string(code), "_", " ")
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// HTTPErrorHandler writes errors returned by handlers as problems. The problem carries ids of trace and request,
+// so the failure is found in traces and logs.
+func HTTPErrorHandler(log *logrus.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		ctx := c.Request().Context()
+		problem := ProblemOf(err)
+		if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+			problem.TraceID = span.TraceID().String()
+		}
+		problem.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(problem.Status)
+		} else {
+			var body []byte
+			if body, err = json.Marshal(problem); err == nil {
+				err = c.Blob(problem.Status, MIMEProblemJSON, body)
+			}
+		}
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to write error response: %v", err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const statusInvalidRequest errors.Status = "invalid_request"
+
+var errInvalidRequest = errors.New(codes.BadRequest, statusInvalidRequest)
+
+func TestProblemOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected *errors.Problem
+	}{
+		{
+			name: "should keep code, message and fields of domain error",
+			err: errors.WithFields(errors.WithMessage(errInvalidRequest, "invalid fields: email"),
+				errors.FieldError{Field: "email", Reason: "email"}),
+			expected: &errors.Problem{
+				Type:          "urn:nnw:problem:invalid_request",
+				Title:         "Invalid request",
+				Status:        http.StatusBadRequest,
+				Detail:        "invalid fields: email",
+				Code:          statusInvalidRequest,
+				InvalidParams: []errors.FieldError{{Field: "email", Reason: "email"}},
+			},
+		},
+		{
+			name: "should find wrapped domain error",
+			err:  fmt.Errorf("failed to register user: %w", errors.New(codes.DuplicateError, "user_already_exists")),
+			expected: &errors.Problem{
+				Type:   "urn:nnw:problem:user_already_exists",
+				Title:  "User already exists",
+				Status: http.StatusConflict,
+				Code:   "user_already_exists",
+			},
+		},
+		{
+			name: "should hide message of internal error",
+			err:  errors.NewInternal("connection to mongo is refused"),
+			expected: &errors.Problem{
+				Type:   "urn:nnw:problem:internal_error",
+				Title:  "Internal error",
+				Status: http.StatusInternalServerError,
+				Code:   "internal_error",
+			},
+		},
+		{
+			name: "should map errors of echo by status",
+			err:  echo.NewHTTPError(http.StatusMethodNotAllowed),
+			expected: &errors.Problem{
+				Type:   "urn:nnw:problem:method_not_allowed",
+				Title:  "Method not allowed",
+				Status: http.StatusMethodNotAllowed,
+				Code:   "method_not_allowed",
+			},
+		},
+		{
+			name: "should map unknown error to internal error",
+			err:  fmt.Errorf("unexpected"),
+			expected: &errors.Problem{
+				Type:   "urn:nnw:problem:internal_error",
+				Title:  "Internal error",
+				Status: http.StatusInternalServerError,
+				Code:   "internal_error",
+			},
+		},
+		{
+			name: "should map domain error without http code to internal error",
+			err:  errors.New(0, "invalid_code"),
+			expected: &errors.Problem{
+				Type:   "urn:nnw:problem:internal_error",
+				Title:  "Internal error",
+				Status: http.StatusInternalServerError,
+				Code:   "internal_error",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, errors.ProblemOf(test.err))
+		})
+	}
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+
+	router := echo.New()
+	router.HTTPErrorHandler = errors.HTTPErrorHandler(logrus.New())
+	router.POST("/register-user", func(c echo.Context) error {
+		ctx := trace.ContextWithSpanContext(c.Request().Context(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  trace.SpanID{1},
+		}))
+		c.SetRequest(c.Request().WithContext(ctx))
+		c.Response().Header().Set(echo.HeaderXRequestID, "req-1")
+		return errors.WithMessage(errInvalidRequest, "invalid fields: email")
+	})
+
+	t.Run("should write problem of error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/register-user", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errors.MIMEProblemJSON, rec.Header().Get(echo.HeaderContentType))
+
+		var problem errors.Problem
+		require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, statusInvalidRequest, problem.Code)
+		assert.Equal(t, "invalid fields: email", problem.Detail)
+		assert.Equal(t, traceID.String(), problem.TraceID)
+		assert.Equal(t, "req-1", problem.RequestID)
+	})
+
+	t.Run("should write problem of unknown route", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/random/path", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		var problem errors.Problem
+		require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "urn:nnw:problem:not_found", problem.Type)
+		assert.Empty(t, problem.TraceID)
+	})
+}
@@ -0,0 +1,153 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"nnw_s/pkg/errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event is a domain event, its name identifies the event in subscriptions and in the event log
+type Event interface {
+	EventName() string
+}
+
+// Handler handles published event, its error is logged and doesn't affect the publisher
+type Handler func(ctx context.Context, event Event) error
+
+//go:generate mockgen -source=bus.go -destination=mocks/bus_mock.go
+type Publisher interface {
+	// Publish appends event to the event log and passes it to subscribers
+	Publish(ctx context.Context, event Event)
+}
+
+type subscription struct {
+	subscriber string
+	events     map[string]bool // all events if it is empty
+	handler    Handler
+}
+
+func (s *subscription) wants(name string) bool {
+	return len(s.events) == 0 || s.events[name]
+}
+
+// Bus is an in-process event bus. Subscribers are called synchronously in order of subscription,
+// so side effects of the event are done once the service call returns.
+type Bus struct {
+	log   *logrus.Logger
+	store Store
+
+	mu            sync.RWMutex
+	subscriptions []*subscription
+	factories     map[string]func() Event
+}
+
+// NewBus returns event bus, events are kept in the store if it is not nil, so they can be replayed
+func NewBus(log *logrus.Logger, store Store) (*Bus, error) {
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &Bus{log: log, store: store, factories: make(map[string]func() Event)}, nil
+}
+
+// Register makes events known to the bus, so they can be decoded from the event log on replay
+func (b *Bus) Register(factories ...func() Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, factory := range factories {
+		b.factories[factory().EventName()] = factory
+	}
+}
+
+// Subscribe calls handler on the events, handler gets every event if no events are given
+func (b *Bus) Subscribe(subscriber string, handler Handler, events ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &subscription{subscriber: subscriber, events: make(map[string]bool, len(events)), handler: handler}
+	for _, e := range events {
+		s.events[e] = true
+	}
+	b.subscriptions = append(b.subscriptions, s)
+}
+
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	record, err := NewRecord(event)
+	if err != nil {
+		b.log.WithContext(ctx).Errorf("failed to encode event '%s': %v", event.EventName(), err)
+		record = &Record{ID: primitive.NewObjectID(), Name: event.EventName(), OccurredAt: time.Now().UTC()}
+	} else if b.store != nil {
+		if err = b.store.Append(ctx, record); err != nil {
+			b.log.WithContext(ctx).Errorf("failed to append event '%s' to event log: %v", event.EventName(), err)
+		}
+	}
+
+	b.dispatch(withMeta(ctx, record, false), event, "")
+}
+
+// Replay passes events of the log to the subscriber or to every subscriber if it is empty,
+// handlers should be idempotent, e.g. notifications are deduplicated by idempotency key
+func (b *Bus) Replay(ctx context.Context, filter *ReplayFilter) (int, error) {
+	if b.store == nil {
+		return 0, ErrNoEventLog
+	}
+
+	records, err := b.store.Records(ctx, filter.Since, filter.Limit)
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, record := range records {
+		b.mu.RLock()
+		factory, ok := b.factories[record.Name]
+		b.mu.RUnlock()
+		if !ok {
+			b.log.WithContext(ctx).Warnf("skip replay of unknown event '%s' %s", record.Name, record.ID.Hex())
+			continue
+		}
+
+		event := factory()
+		if err = record.Decode(event); err != nil {
+			b.log.WithContext(ctx).Errorf("failed to decode event '%s' %s: %v", record.Name, record.ID.Hex(), err)
+			continue
+		}
+
+		b.dispatch(withMeta(ctx, record, true), event, filter.Subscriber)
+		replayed++
+	}
+	return replayed, nil
+}
+
+// dispatch calls handlers of the event, only handlers of the subscriber are called if it is not empty
+func (b *Bus) dispatch(ctx context.Context, event Event, subscriber string) {
+	b.mu.RLock()
+	subscriptions := make([]*subscription, 0, len(b.subscriptions))
+	for _, s := range b.subscriptions {
+		if s.wants(event.EventName()) && (subscriber == "" || s.subscriber == subscriber) {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, s := range subscriptions {
+		if err := b.handle(ctx, s, event); err != nil {
+			b.log.WithContext(ctx).Errorf("subscriber '%s' failed to handle event '%s': %v", s.subscriber, event.EventName(), err)
+		}
+	}
+}
+
+// handle calls handler of the subscription, panic of one subscriber doesn't break others
+func (b *Bus) handle(ctx context.Context, s *subscription, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.handler(ctx, event)
+}
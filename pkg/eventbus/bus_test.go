@@ -0,0 +1,183 @@
+package eventbus_test
+
+import (
+	"context"
+	"fmt"
+	"nnw_s/pkg/eventbus"
+	"nnw_s/pkg/mongodb/mongotest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userCreated struct {
+	Email string `bson:"email"`
+}
+
+func (*userCreated) EventName() string { return "user_created" }
+
+type userDeleted struct {
+	Email string `bson:"email"`
+}
+
+func (*userDeleted) EventName() string { return "user_deleted" }
+
+// recorder is a subscriber which records handled events
+type recorder struct {
+	events []eventbus.Event
+	err    error
+}
+
+func (r *recorder) handle(_ context.Context, event eventbus.Event) error {
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func TestBus(t *testing.T) {
+	ctx := context.Background()
+
+	newBus := func(t *testing.T) *eventbus.Bus {
+		store, err := eventbus.NewMemoryStore()
+		require.Nil(t, err)
+
+		bus, err := eventbus.NewBus(logrus.New(), store)
+		require.Nil(t, err)
+		bus.Register(
+			func() eventbus.Event { return &userCreated{} },
+			func() eventbus.Event { return &userDeleted{} },
+		)
+		return bus
+	}
+
+	t.Run("should pass events only to subscribed handlers", func(t *testing.T) {
+		bus := newBus(t)
+		all, created := &recorder{}, &recorder{}
+		bus.Subscribe("audit", all.handle)
+		bus.Subscribe("welcome", created.handle, "user_created")
+
+		bus.Publish(ctx, &userCreated{Email: "a@mail.com"})
+		bus.Publish(ctx, &userDeleted{Email: "a@mail.com"})
+
+		assert.Len(t, all.events, 2)
+		assert.Equal(t, []eventbus.Event{&userCreated{Email: "a@mail.com"}}, created.events)
+	})
+
+	t.Run("should call every subscriber even if one fails or panics", func(t *testing.T) {
+		bus := newBus(t)
+		failing, last := &recorder{err: fmt.Errorf("smtp is down")}, &recorder{}
+		bus.Subscribe("failing", failing.handle)
+		bus.Subscribe("panicking", func(context.Context, eventbus.Event) error { panic("nil pointer") })
+		bus.Subscribe("last", last.handle)
+
+		bus.Publish(ctx, &userCreated{Email: "a@mail.com"})
+
+		assert.Len(t, failing.events, 1)
+		assert.Len(t, last.events, 1)
+	})
+
+	t.Run("should replay logged events to the subscriber", func(t *testing.T) {
+		bus := newBus(t)
+		bus.Publish(ctx, &userCreated{Email: "a@mail.com"})
+		bus.Publish(ctx, &userDeleted{Email: "a@mail.com"})
+
+		webhooks, audit := &recorder{}, &recorder{}
+		bus.Subscribe("webhooks", webhooks.handle)
+		bus.Subscribe("audit", audit.handle)
+
+		replayed, err := bus.Replay(ctx, &eventbus.ReplayFilter{Subscriber: "webhooks"})
+		require.Nil(t, err)
+		assert.Equal(t, 2, replayed)
+		assert.Equal(t, []eventbus.Event{&userCreated{Email: "a@mail.com"}, &userDeleted{Email: "a@mail.com"}}, webhooks.events)
+		assert.Empty(t, audit.events)
+
+		replayed, err = bus.Replay(ctx, &eventbus.ReplayFilter{Since: time.Now().Add(time.Hour)})
+		require.Nil(t, err)
+		assert.Equal(t, 0, replayed)
+	})
+
+	t.Run("should pass the same event id on replay", func(t *testing.T) {
+		bus := newBus(t)
+
+		var metas []eventbus.Meta
+		bus.Subscribe("webhooks", func(ctx context.Context, _ eventbus.Event) error {
+			meta, ok := eventbus.MetaFromContext(ctx)
+			require.True(t, ok)
+			metas = append(metas, meta)
+			return nil
+		})
+
+		bus.Publish(ctx, &userCreated{Email: "a@mail.com"})
+		_, err := bus.Replay(ctx, &eventbus.ReplayFilter{})
+		require.Nil(t, err)
+
+		require.Len(t, metas, 2)
+		assert.NotEmpty(t, metas[0].ID)
+		assert.Equal(t, metas[0].ID, metas[1].ID)
+		assert.False(t, metas[0].Replayed)
+		assert.True(t, metas[1].Replayed)
+	})
+
+	t.Run("should return error on replay without event log", func(t *testing.T) {
+		bus, err := eventbus.NewBus(logrus.New(), nil)
+		require.Nil(t, err)
+		bus.Publish(ctx, &userCreated{Email: "a@mail.com"})
+
+		_, err = bus.Replay(ctx, &eventbus.ReplayFilter{})
+		assert.Equal(t, eventbus.ErrNoEventLog, err)
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, func(t *testing.T) eventbus.Store {
+		store, err := eventbus.NewMemoryStore()
+		require.Nil(t, err)
+		return store
+	})
+}
+
+func TestMongoStore(t *testing.T) {
+	testStore(t, func(t *testing.T) eventbus.Store {
+		db := mongotest.DB(t)
+		require.Nil(t, eventbus.CreateIndexes(context.Background(), db))
+
+		store, err := eventbus.NewStore(db, logrus.New())
+		require.Nil(t, err)
+		return store
+	})
+}
+
+// testStore is a conformance suite which every eventbus.Store implementation should pass
+func testStore(t *testing.T, newStore func(t *testing.T) eventbus.Store) {
+	ctx := context.Background()
+
+	t.Run("should return events since the time in order of occurrence", func(t *testing.T) {
+		store := newStore(t)
+
+		var records []*eventbus.Record
+		for i, email := range []string{"a@mail.com", "b@mail.com", "c@mail.com"} {
+			record, err := eventbus.NewRecord(&userCreated{Email: email})
+			require.Nil(t, err)
+			record.OccurredAt = time.Date(2022, 1, 1, i, 0, 0, 0, time.UTC)
+			require.Nil(t, store.Append(ctx, record))
+			records = append(records, record)
+		}
+
+		loaded, err := store.Records(ctx, records[1].OccurredAt, 10)
+		require.Nil(t, err)
+		require.Len(t, loaded, 2)
+		assert.Equal(t, records[1].ID, loaded[0].ID)
+		assert.Equal(t, records[2].ID, loaded[1].ID)
+
+		var event userCreated
+		require.Nil(t, loaded[0].Decode(&event))
+		assert.Equal(t, "b@mail.com", event.Email)
+
+		loaded, err = store.Records(ctx, time.Time{}, 1)
+		require.Nil(t, err)
+		require.Len(t, loaded, 1)
+		assert.Equal(t, records[0].ID, loaded[0].ID)
+	})
+}
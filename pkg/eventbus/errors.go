@@ -0,0 +1,10 @@
+package eventbus
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+)
+
+const StatusNoEventLog errors.Status = "event_log_disabled"
+
+var ErrNoEventLog = errors.New(codes.BadRequest, StatusNoEventLog)
@@ -0,0 +1,66 @@
+package eventbus
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/memory"
+	"sync"
+	"time"
+)
+
+type memoryStore struct {
+	mu      sync.RWMutex
+	records []*Record
+}
+
+// NewMemoryStore returns thread-safe event log which keeps events in memory
+func NewMemoryStore() (Store, error) {
+	return &memoryStore{}, nil
+}
+
+func (s *memoryStore) Append(_ context.Context, record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := copyRecord(record)
+	if err != nil {
+		return err
+	}
+	s.records = append(s.records, stored)
+	return nil
+}
+
+func (s *memoryStore) Records(_ context.Context, since time.Time, limit int) ([]*Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if limit <= 0 {
+		limit = defaultReplayLimit
+	}
+
+	// records are appended in order of occurrence
+	records := make([]*Record, 0)
+	for _, r := range s.records {
+		if r.OccurredAt.Before(since) {
+			continue
+		}
+		if len(records) == limit {
+			break
+		}
+
+		copied, err := copyRecord(r)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, copied)
+	}
+	return records, nil
+}
+
+func copyRecord(r *Record) (*Record, error) {
+	var copied Record
+	if err := memory.Copy(r, &copied); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	return &copied, nil
+}
@@ -0,0 +1,27 @@
+package eventbus
+
+import (
+	"context"
+	"time"
+)
+
+// Meta is metadata of the event passed to handler
+type Meta struct {
+	// ID is the same on replay of the event, so handlers can use it to deduplicate side effects
+	ID         string
+	OccurredAt time.Time
+	// Replayed is set if the event is passed again by Replay, e.g. metrics do not count it twice
+	Replayed bool
+}
+
+type metaKey struct{}
+
+func withMeta(ctx context.Context, record *Record, replayed bool) context.Context {
+	return context.WithValue(ctx, metaKey{}, Meta{ID: record.ID.Hex(), OccurredAt: record.OccurredAt, Replayed: replayed})
+}
+
+// MetaFromContext returns metadata of the event being handled
+func MetaFromContext(ctx context.Context) (Meta, bool) {
+	meta, ok := ctx.Value(metaKey{}).(Meta)
+	return meta, ok
+}
@@ -0,0 +1,44 @@
+package eventbus
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Record is an event stored in the event log
+type Record struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	Name       string             `bson:"name"`
+	Payload    bson.Raw           `bson:"payload"`
+	OccurredAt time.Time          `bson:"occurred_at"`
+}
+
+func NewRecord(event Event) (*Record, error) {
+	payload, err := bson.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		ID:         primitive.NewObjectID(),
+		Name:       event.EventName(),
+		Payload:    payload,
+		OccurredAt: time.Now().UTC(),
+	}, nil
+}
+
+// Decode decodes payload of the record to the event
+func (r *Record) Decode(event Event) error {
+	return bson.Unmarshal(r.Payload, event)
+}
+
+// ReplayFilter selects events of the log to replay
+type ReplayFilter struct {
+	// Subscriber gets replayed events, every subscriber gets them if it is empty
+	Subscriber string
+	// Since is time of the first event to replay
+	Since time.Time
+	Limit int
+}
@@ -0,0 +1,76 @@
+package eventbus
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	eventLogCollection = "event_log"
+	defaultReplayLimit = 1000
+)
+
+// Store is a durable event log, events are never changed once they are appended
+type Store interface {
+	Append(ctx context.Context, record *Record) error
+	// Records returns events which occurred since the time in order of occurrence
+	Records(ctx context.Context, since time.Time, limit int) ([]*Record, error)
+}
+
+type store struct {
+	db  *mongo.Database
+	log *logrus.Logger
+}
+
+func NewStore(db *mongo.Database, log *logrus.Logger) (Store, error) {
+	if db == nil {
+		return nil, errors.NewInternal("invalid db")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &store{db: db, log: log}, nil
+}
+
+// CreateIndexes creates index of event log which is read in order of occurrence on replay
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	mod := mongo.IndexModel{Keys: bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}}
+	return migrations.CreateIndexes(ctx, db.Collection(eventLogCollection), mod)
+}
+
+func (s *store) Append(ctx context.Context, record *Record) error {
+	if _, err := s.db.Collection(eventLogCollection).InsertOne(ctx, record); err != nil {
+		return errors.NewInternal(err.Error())
+	}
+	return nil
+}
+
+func (s *store) Records(ctx context.Context, since time.Time, limit int) ([]*Record, error) {
+	if limit <= 0 {
+		limit = defaultReplayLimit
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := s.db.Collection(eventLogCollection).Find(ctx, bson.M{"occurred_at": bson.M{"$gte": since}}, opts)
+	if err != nil {
+		s.log.WithContext(ctx).Errorf("unable to find events since %s: %v", since, err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	records := make([]*Record, 0)
+	if err = cursor.All(ctx, &records); err != nil {
+		s.log.WithContext(ctx).Errorf("unable to decode events: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+	return records, nil
+}
@@ -0,0 +1,44 @@
+package httpx
+
+import (
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// routes are route patterns of echo instances. Routes are registered after middleware,
+// so they are collected on the first request of the instance.
+var routes sync.Map // *echo.Echo -> map[string]bool
+
+// RouteOf returns route pattern of the request, e.g. /wallet/:id, or empty route if the request has not matched
+// any route. Echo keeps the requested path of unmatched requests, so it must not get to labels and span names.
+func RouteOf(c echo.Context) string {
+	known, ok := routes.Load(c.Echo())
+	if !ok {
+		patterns := make(map[string]bool)
+		for _, r := range c.Echo().Routes() {
+			patterns[r.Path] = true
+		}
+		known, _ = routes.LoadOrStore(c.Echo(), patterns)
+	}
+
+	if route := c.Path(); known.(map[string]bool)[route] {
+		return route
+	}
+	return ""
+}
+
+// ErrorResponse writes errors of next handlers by the error handler of echo, so middleware registered before it
+// sees status of the error response. The error is still returned, so it is logged and recorded in spans.
+// The error handler skips committed responses, echo does not write the error again.
+func ErrorResponse() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			if err != nil && !c.Response().Committed {
+				c.Error(err)
+			}
+			return err
+		}
+	}
+}
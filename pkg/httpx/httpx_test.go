@@ -0,0 +1,63 @@
+package httpx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/pkg/httpx"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteOf(t *testing.T) {
+	var route string
+
+	router := echo.New()
+	router.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			route = httpx.RouteOf(c)
+			return err
+		}
+	})
+	router.GET("/wallet/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/wallet/1", nil))
+	assert.Equal(t, "/wallet/:id", route)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/random/path", nil))
+	assert.Empty(t, route)
+}
+
+func TestErrorResponse(t *testing.T) {
+	var status, written int
+
+	router := echo.New()
+	router.HTTPErrorHandler = func(err error, c echo.Context) {
+		if !c.Response().Committed {
+			written++
+		}
+		router.DefaultHTTPErrorHandler(err, c)
+	}
+	router.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			status = c.Response().Status
+			return err
+		}
+	})
+	router.Use(httpx.ErrorResponse())
+	router.GET("/wallet/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallet/1", nil))
+
+	assert.Equal(t, http.StatusBadRequest, status, "middleware before should see status of error response")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 1, written, "error response should be written once")
+}
@@ -0,0 +1,153 @@
+// Package i18n contains message catalogs of supported languages and helpers
+// to pick a language, pluralize messages and format numbers and dates.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"nnw_s/pkg/errors"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is used when none of preferred languages is supported,
+// its catalog is a fallback for messages missing in other catalogs.
+const DefaultLanguage = "en"
+
+// Catalogs are compiled into the binary, every file is named <language>.json and contains
+// messages by key. Message is either a string or an object of plural forms, see Localizer.Plural.
+//
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// catalog contains messages of the language
+type catalog struct {
+	tag      language.Tag
+	messages map[string]*entry
+}
+
+// entry is a message of the catalog, it has "other" form only unless it depends on a number
+type entry struct {
+	forms map[string]string
+}
+
+func (m *entry) UnmarshalJSON(data []byte) error {
+	var other string
+	if err := json.Unmarshal(data, &other); err == nil {
+		m.forms = map[string]string{formOther: other}
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &m.forms); err != nil {
+		return err
+	}
+	if _, ok := m.forms[formOther]; !ok {
+		return errors.NewInternal("plural message should have 'other' form")
+	}
+	return nil
+}
+
+// Bundle keeps catalogs of all supported languages
+type Bundle struct {
+	catalogs []*catalog
+	matcher  language.Matcher
+}
+
+// NewBundle loads catalogs of supported languages, the default language goes first
+func NewBundle() (*Bundle, error) {
+	files, err := localesFS.ReadDir("locales")
+	if err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	bundle := &Bundle{}
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+
+		tag, err := language.Parse(name)
+		if err != nil {
+			return nil, errors.NewInternal("invalid catalog language " + name + ": " + err.Error())
+		}
+
+		data, err := localesFS.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			return nil, errors.NewInternal(err.Error())
+		}
+
+		c := &catalog{tag: tag}
+		if err = json.Unmarshal(data, &c.messages); err != nil {
+			return nil, errors.NewInternal("invalid catalog " + file.Name() + ": " + err.Error())
+		}
+
+		if name == DefaultLanguage {
+			bundle.catalogs = append([]*catalog{c}, bundle.catalogs...)
+		} else {
+			bundle.catalogs = append(bundle.catalogs, c)
+		}
+	}
+
+	if len(bundle.catalogs) == 0 || bundle.catalogs[0].tag.String() != DefaultLanguage {
+		return nil, errors.NewInternal("catalog of default language is not found")
+	}
+
+	tags := make([]language.Tag, 0, len(bundle.catalogs))
+	for _, c := range bundle.catalogs {
+		tags = append(tags, c.tag)
+	}
+	bundle.matcher = language.NewMatcher(tags)
+
+	return bundle, nil
+}
+
+// Languages returns supported languages, the default language goes first
+func (b *Bundle) Languages() []string {
+	languages := make([]string, 0, len(b.catalogs))
+	for _, c := range b.catalogs {
+		languages = append(languages, c.tag.String())
+	}
+	return languages
+}
+
+// Localizer returns localizer of the best supported language for the preferences given in order of priority.
+// Every preference is a language tag, e.g. user's stored locale, or a value of Accept-Language header.
+// Regional variants fall back to the base language, e.g. "ru-UA" to "ru", unknown ones to DefaultLanguage.
+func (b *Bundle) Localizer(preferences ...string) *Localizer {
+	var preferred []language.Tag
+	for _, p := range preferences {
+		if p == "" {
+			continue
+		}
+		// Accept-Language sorts tags by quality, a single tag is a valid header too
+		tags, _, err := language.ParseAcceptLanguage(p)
+		if err != nil {
+			continue
+		}
+		preferred = append(preferred, tags...)
+	}
+
+	c := b.catalogs[0]
+	if len(preferred) > 0 {
+		if _, index, confidence := b.matcher.Match(preferred...); confidence != language.No {
+			c = b.catalogs[index]
+		}
+	}
+
+	return newLocalizer(c, b.catalogs[0])
+}
+
+// PreferredLanguage returns the most preferred language of Accept-Language header value,
+// e.g. "ru" for "ru-RU,ru;q=0.9,en;q=0.8". It doesn't have to be supported yet.
+func PreferredLanguage(acceptLanguage string) (string, bool) {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return "", false
+	}
+
+	base, confidence := tags[0].Base()
+	if confidence == language.No {
+		return "", false
+	}
+	return base.String(), true
+}
@@ -0,0 +1,119 @@
+package i18n_test
+
+import (
+	"nnw_s/pkg/i18n"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBundle(t *testing.T) *i18n.Bundle {
+	bundle, err := i18n.NewBundle()
+	require.Nil(t, err)
+	return bundle
+}
+
+func TestLocalizer(t *testing.T) {
+	bundle := newBundle(t)
+
+	tests := []struct {
+		name        string
+		preferences []string
+		want        string
+	}{
+		{name: "should use default language without preferences", want: i18n.DefaultLanguage},
+		{name: "should use stored locale", preferences: []string{"ru"}, want: "ru"},
+		{name: "should use Accept-Language", preferences: []string{"", "uk-UA,uk;q=0.9,en;q=0.8"}, want: "uk"},
+		{name: "should prefer the first preference", preferences: []string{"uk", "ru"}, want: "uk"},
+		{name: "should skip not supported languages", preferences: []string{"de", "fr;q=0.9,ru;q=0.5"}, want: "ru"},
+		{name: "should fall back to base language", preferences: []string{"ru-KZ"}, want: "ru"},
+		{name: "should fall back to default language", preferences: []string{"de-DE,fr;q=0.5"}, want: i18n.DefaultLanguage},
+		{name: "should ignore invalid preferences", preferences: []string{"not a language;;"}, want: i18n.DefaultLanguage},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, bundle.Localizer(tc.preferences...).Language())
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	bundle := newBundle(t)
+	en, ru, uk := bundle.Localizer("en"), bundle.Localizer("ru"), bundle.Localizer("uk")
+
+	t.Run("should translate and format message", func(t *testing.T) {
+		assert.Equal(t, "Hi, Satoshi!", en.T("greeting", "Satoshi"))
+		assert.Equal(t, "Здравствуйте, Сатоши!", ru.T("greeting", "Сатоши"))
+	})
+
+	t.Run("should return key of unknown message", func(t *testing.T) {
+		assert.Equal(t, "unknown.key", ru.T("unknown.key"))
+	})
+
+	t.Run("should pluralize message by rules of the language", func(t *testing.T) {
+		cases := []struct {
+			n      int
+			en, ru string
+		}{
+			{1, "The code expires in 1 minute.", "Код действителен 1 минуту."},
+			{3, "The code expires in 3 minutes.", "Код действителен 3 минуты."},
+			{5, "The code expires in 5 minutes.", "Код действителен 5 минут."},
+			{21, "The code expires in 21 minutes.", "Код действителен 21 минуту."},
+			{11, "The code expires in 11 minutes.", "Код действителен 11 минут."},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.en, en.Plural("code.expires", c.n))
+			assert.Equal(t, c.ru, ru.Plural("code.expires", c.n))
+		}
+		assert.Equal(t, "Код дійсний 2 хвилини.", uk.Plural("code.expires", 2))
+	})
+
+	t.Run("should format numbers and dates", func(t *testing.T) {
+		assert.Equal(t, "1,234.5", en.Number(1234.5))
+		assert.Equal(t, "1\u00a0234,5", ru.Number(1234.5))
+
+		date := time.Date(2022, 3, 4, 5, 6, 0, 0, time.UTC)
+		assert.Equal(t, "Mar 4, 2022", en.Date(date))
+		assert.Equal(t, "04.03.2022", ru.Date(date))
+		assert.Equal(t, "04.03.2022 05:06 UTC", uk.DateTime(date))
+	})
+}
+
+func TestCatalogs(t *testing.T) {
+	bundle := newBundle(t)
+	assert.Equal(t, i18n.DefaultLanguage, bundle.Languages()[0])
+
+	// every message of the default catalog is translated, so it doesn't fall back to English silently
+	keys := []string{
+		"format.date", "format.datetime", "greeting", "footer.help", "footer.help_center", "code.copy",
+		"verify_email.title", "verify_email.body", "reset_password.title", "reset_password.body",
+		"reset_password.ignore", "new_login.title", "new_login.body", "new_login.ip", "new_login.device",
+		"new_login.warning", "password_changed.title", "password_changed.body", "password_changed.warning",
+		"tx.hash", "tx_sent.title", "tx_sent.body", "tx_received.title", "tx_received.body",
+	}
+	for _, lang := range bundle.Languages() {
+		l := bundle.Localizer(lang)
+		for _, key := range keys {
+			assert.NotEqual(t, key, l.T(key), "%s: %s", lang, key)
+			if lang != i18n.DefaultLanguage {
+				assert.NotEqual(t, bundle.Localizer(i18n.DefaultLanguage).T(key), l.T(key), "%s: %s is not translated", lang, key)
+			}
+		}
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	lang, ok := i18n.PreferredLanguage("ru-RU,ru;q=0.9,en;q=0.8")
+	assert.True(t, ok)
+	assert.Equal(t, "ru", lang)
+
+	lang, ok = i18n.PreferredLanguage("en;q=0.5,de-AT")
+	assert.True(t, ok)
+	assert.Equal(t, "de", lang)
+
+	_, ok = i18n.PreferredLanguage("")
+	assert.False(t, ok)
+}
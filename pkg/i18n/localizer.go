@@ -0,0 +1,106 @@
+package i18n
+
+import (
+	"time"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// CLDR plural forms which can be used in catalogs
+const (
+	formZero  = "zero"
+	formOne   = "one"
+	formTwo   = "two"
+	formFew   = "few"
+	formMany  = "many"
+	formOther = "other"
+)
+
+// layouts of dates are messages of catalogs, so every language has its own date format
+const (
+	keyDateLayout     = "format.date"
+	keyDateTimeLayout = "format.datetime"
+)
+
+// Localizer translates messages to the selected language
+type Localizer struct {
+	catalog  *catalog
+	fallback *catalog
+	printer  *message.Printer
+}
+
+func newLocalizer(c, fallback *catalog) *Localizer {
+	return &Localizer{catalog: c, fallback: fallback, printer: message.NewPrinter(c.tag)}
+}
+
+// Language returns tag of the selected language, e.g. "en"
+func (l *Localizer) Language() string {
+	return l.catalog.tag.String()
+}
+
+// T translates message by key and formats it with args, numbers are formatted by rules of the language.
+// Message missing in the catalog is taken from the default language, key is returned if it is missing there too.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	return l.format(key, formOther, args...)
+}
+
+// Plural translates message by key in plural form matching n, n is the first argument of the message.
+// E.g. {"one": "%d minute", "few": "%d minutes", "many": "%d minutes", "other": "%d minutes"}.
+func (l *Localizer) Plural(key string, n int, args ...interface{}) string {
+	form := formOf(plural.Cardinal.MatchPlural(l.catalog.tag, n, 0, 0, 0, 0))
+	return l.format(key, form, append([]interface{}{n}, args...)...)
+}
+
+// Number formats number with decimal and group separators of the language, e.g. "1,234.5" or "1 234,5"
+func (l *Localizer) Number(v interface{}) string {
+	return l.printer.Sprint(number.Decimal(v))
+}
+
+// Date formats date by layout of the language
+func (l *Localizer) Date(t time.Time) string {
+	return t.Format(l.T(keyDateLayout))
+}
+
+// DateTime formats date and time by layout of the language
+func (l *Localizer) DateTime(t time.Time) string {
+	return t.Format(l.T(keyDateTimeLayout))
+}
+
+func (l *Localizer) format(key, form string, args ...interface{}) string {
+	m, ok := l.catalog.messages[key]
+	if !ok {
+		m, ok = l.fallback.messages[key]
+	}
+	if !ok {
+		return key
+	}
+
+	text, ok := m.forms[form]
+	if !ok {
+		text = m.forms[formOther]
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+	return l.printer.Sprintf(text, args...)
+}
+
+func formOf(form plural.Form) string {
+	switch form {
+	case plural.Zero:
+		return formZero
+	case plural.One:
+		return formOne
+	case plural.Two:
+		return formTwo
+	case plural.Few:
+		return formFew
+	case plural.Many:
+		return formMany
+	default:
+		return formOther
+	}
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// requestFields are fields of logs of a request, user is known only after the token of the request is verified
+type requestFields struct {
+	mu        sync.RWMutex
+	requestID string
+	route     string
+	userID    string
+}
+
+// WithRequest returns context of request, entries logged with log.WithContext(ctx) have its id and route
+func WithRequest(ctx context.Context, requestID, route string) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestFields{requestID: requestID, route: route})
+}
+
+// SetUserID adds ObjectID of user to logs of request of ctx, it does nothing if ctx is not a context of request.
+// Email of user is personal data, so it must not be used as user id.
+func SetUserID(ctx context.Context, userID string) {
+	fields, ok := ctx.Value(contextKey{}).(*requestFields)
+	if !ok {
+		return
+	}
+
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+	fields.userID = userID
+}
+
+// RequestID returns id of request of ctx or empty string
+func RequestID(ctx context.Context) string {
+	fields, ok := ctx.Value(contextKey{}).(*requestFields)
+	if !ok {
+		return ""
+	}
+
+	fields.mu.RLock()
+	defer fields.mu.RUnlock()
+	return fields.requestID
+}
+
+func fieldsOf(ctx context.Context) logrus.Fields {
+	fields, ok := ctx.Value(contextKey{}).(*requestFields)
+	if !ok {
+		return nil
+	}
+
+	fields.mu.RLock()
+	defer fields.mu.RUnlock()
+
+	res := logrus.Fields{FieldRequestID: fields.requestID}
+	if fields.route != "" {
+		res[FieldRoute] = fields.route
+	}
+	if fields.userID != "" {
+		res[FieldUserID] = fields.userID
+	}
+	return res
+}
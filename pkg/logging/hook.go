@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Hook adds fields of request to entries logged with log.WithContext(ctx) and redacts secrets of entries
+type Hook struct{}
+
+func NewHook() *Hook {
+	return &Hook{}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire is called with a copy of the entry, so fields of the logger are not changed
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	if entry.Context != nil {
+		for key, value := range fieldsOf(entry.Context) {
+			if _, ok := entry.Data[key]; !ok {
+				entry.Data[key] = value
+			}
+		}
+		if span := trace.SpanContextFromContext(entry.Context); span.IsValid() {
+			entry.Data[FieldTraceID] = span.TraceID().String()
+		}
+	}
+
+	for key, value := range entry.Data {
+		if isSecretKey(key) {
+			entry.Data[key] = Redacted
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = Redact(v)
+		case error:
+			entry.Data[key] = Redact(v.Error())
+		}
+	}
+	entry.Message = Redact(entry.Message)
+	return nil
+}
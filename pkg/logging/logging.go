@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// Fields which are added to entries logged with context of request
+const (
+	FieldRequestID = "request_id"
+	FieldRoute     = "route"
+	FieldUserID    = "user_id"
+	FieldTraceID   = "trace_id"
+)
+
+// envDevelopment is APP_ENV of local development, logs are human-readable text there
+const envDevelopment = "development"
+
+// New returns logger of the app for APP_ENV. Logs are JSON lines outside of local development,
+// so they are parsed by log collectors. Secrets are redacted from logs of every environment.
+func New(env string) *logrus.Logger {
+	logger := logrus.New()
+	if env != envDevelopment {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	}
+	logger.AddHook(NewHook())
+	return logger
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"net/http"
+	"nnw_s/pkg/httpx"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// requestIDRegexp limits ids of callers, so they can not inject text to logs
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Middleware assigns id to every request and logs requests once they are handled. The X-Request-ID header
+// of the caller is kept, so its logs are correlated with logs of the server, and the id is returned in the response.
+// Context of the request carries the id, entries logged with log.WithContext(ctx) have it.
+// It is registered before httpx.ErrorResponse, so status of error responses is known.
+func Middleware(log *logrus.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if !requestIDRegexp.MatchString(requestID) {
+				requestID = uuid.NewString()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			ctx := WithRequest(req.Context(), requestID, httpx.RouteOf(c))
+			c.SetRequest(req.WithContext(ctx))
+
+			start := time.Now()
+			err := next(c)
+			status := c.Response().Status
+
+			// query is not logged, streams are authorized by token of query
+			entry := log.WithContext(ctx).WithFields(logrus.Fields{
+				"method":   req.Method,
+				"path":     req.URL.Path,
+				"status":   status,
+				"duration": time.Since(start).String(),
+			})
+			if err != nil {
+				entry = entry.WithError(err)
+			}
+
+			if status >= http.StatusInternalServerError {
+				entry.Error("request is handled")
+			} else {
+				entry.Info("request is handled")
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Redacted replaces secrets in logs
+const Redacted = "[REDACTED]"
+
+// minMnemonicWords is a number of words of the shortest BIP-39 mnemonic
+const minMnemonicWords = 12
+
+// secretKeys are parts of names of fields and of key=value pairs of messages which hold secrets
+var secretKeys = []string{"password", "mnemonic", "wif", "privatekey", "secret", "token", "jwt"}
+
+var (
+	// keyValueRegexp matches values of pairs like password=..., "token": "..."
+	keyValueRegexp = regexp.MustCompile(`(?i)\b([a-z_]*(?:password|mnemonic|wif|private_?key|secret|token|jwt)[a-z_]*"?)(\s*[:=]\s*)("[^"]*"|'[^']*'|[^\s,;]+)`)
+	// jwtRegexp matches encoded JWT
+	jwtRegexp = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	// wifRegexp matches bitcoin private keys in wallet import format of mainnet and testnet
+	wifRegexp = regexp.MustCompile(`\b[5KLc9][1-9A-HJ-NP-Za-km-z]{50,51}\b`)
+	// wordsRegexp matches runs of words which may be a mnemonic
+	wordsRegexp = regexp.MustCompile(`\b[a-z]+(?: [a-z]+){11,}\b`)
+)
+
+func isSecretKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces passwords, mnemonics, private keys and tokens of s
+func Redact(s string) string {
+	// mnemonic is redacted first, otherwise only its first word is taken as value of key=value pair
+	s = wordsRegexp.ReplaceAllStringFunc(s, redactMnemonic)
+	s = keyValueRegexp.ReplaceAllString(s, "${1}${2}"+Redacted)
+	s = jwtRegexp.ReplaceAllString(s, Redacted)
+	return wifRegexp.ReplaceAllString(s, Redacted)
+}
+
+// redactMnemonic replaces runs of at least 12 words of BIP-39 word list
+func redactMnemonic(s string) string {
+	words := strings.Split(s, " ")
+	res := make([]string, 0, len(words))
+
+	run := 0
+	flush := func() {
+		if run >= minMnemonicWords {
+			res = append(res[:len(res)-run], Redacted)
+		}
+		run = 0
+	}
+
+	for _, word := range words {
+		if _, ok := bip39.GetWordIndex(word); ok {
+			run++
+		} else {
+			flush()
+		}
+		res = append(res, word)
+	}
+	flush()
+	return strings.Join(res, " ")
+}
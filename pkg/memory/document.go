@@ -0,0 +1,54 @@
+// Package memory contains helpers for in-memory repositories.
+// Entities are stored as copies made through bson, so the stored data
+// looks exactly like it would be read back from mongo.
+package memory
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Copy copies src to dst through bson encoding, dst must be a pointer
+func Copy(src, dst interface{}) error {
+	data, err := bson.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, dst)
+}
+
+// SetFields applies mongo-like "$set" of the fields to the entity, where keys are dotted field paths
+func SetFields(entity interface{}, fields map[string]interface{}) error {
+	doc := bson.M{}
+	if err := Copy(entity, &doc); err != nil {
+		return err
+	}
+
+	for path, value := range fields {
+		setPath(doc, strings.Split(path, "."), value)
+	}
+
+	return Copy(doc, entity)
+}
+
+func setPath(doc bson.M, path []string, value interface{}) {
+	if len(path) == 1 {
+		doc[path[0]] = value
+		return
+	}
+
+	var next bson.M
+	switch nested := doc[path[0]].(type) {
+	case bson.M:
+		next = nested
+	case primitive.D:
+		next = nested.Map()
+	default:
+		next = bson.M{}
+	}
+
+	setPath(next, path[1:], value)
+	doc[path[0]] = next
+}
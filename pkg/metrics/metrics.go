@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "nnw"
+
+// Values of result label
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	// HTTPRequestDuration is latency of HTTP requests by method, route pattern and response status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// Events counts published domain events by name, e.g. successful logins are user_logged_in events
+	Events = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "published_total",
+		Help:      "Published domain events by name.",
+	}, []string{"event"})
+
+	// LoginFailures counts rejected attempts to log in
+	LoginFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "login_failures_total",
+		Help:      "Rejected login attempts.",
+	})
+
+	// TwoFAFailures counts rejected 2FA codes
+	TwoFAFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "twofa_failures_total",
+		Help:      "Rejected 2FA codes.",
+	})
+
+	// Notifications counts delivery attempts of notifications by channel and result,
+	// emails are the notifications of email channel
+	Notifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "deliveries_total",
+		Help:      "Delivery attempts of notifications by channel and result.",
+	}, []string{"channel", "result"})
+
+	// RPCDuration is latency of requests to blockchain nodes by chain and JSON RPC method
+	RPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests to blockchain nodes by chain and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"chain", "method"})
+
+	// RPCErrors counts failed requests to blockchain nodes by chain and JSON RPC method
+	RPCErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "errors_total",
+		Help:      "Failed requests to blockchain nodes by chain and method.",
+	}, []string{"chain", "method"})
+)
+
+// Register registers collectors of the package, so they are exposed by handler of reg
+func Register(reg prometheus.Registerer) error {
+	collectors := []prometheus.Collector{
+		HTTPRequestDuration,
+		Events,
+		LoginFailures,
+		TwoFAFailures,
+		Notifications,
+		RPCDuration,
+		RPCErrors,
+	}
+
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Result returns value of result label for err
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
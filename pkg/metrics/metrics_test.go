@@ -0,0 +1,104 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/pkg/httpx"
+	"nnw_s/pkg/metrics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	router := echo.New()
+	router.Use(metrics.Middleware())
+	router.Use(httpx.ErrorResponse())
+	router.GET("/wallet/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	router.POST("/wallet/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		route  string
+		status string
+	}{
+		{name: "should label request with route pattern", method: http.MethodGet, path: "/wallet/1", route: "/wallet/:id", status: "200"},
+		{name: "should label request with status of error", method: http.MethodPost, path: "/wallet/2", route: "/wallet/:id", status: "400"},
+		{name: "should not label request with unknown path", method: http.MethodGet, path: "/random/path", route: "unmatched", status: "404"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tc.method, tc.path, nil))
+
+			requests := observedRequests(t)
+			assert.Equal(t, uint64(1), requests[tc.method+" "+tc.route+" "+tc.status])
+			assert.NotContains(t, requests, tc.method+" "+tc.path+" "+tc.status)
+		})
+	}
+}
+
+func TestQueueCollector(t *testing.T) {
+	collector := metrics.NewQueueCollector(time.Second)
+	collector.Add("outbox", func(context.Context) (int64, error) { return 3, nil })
+	collector.Add("webhooks", func(context.Context) (int64, error) { return 0, nil })
+
+	expected := `
+		# HELP nnw_queue_size Pending items of background worker queues.
+		# TYPE nnw_queue_size gauge
+		nnw_queue_size{queue="outbox"} 3
+		nnw_queue_size{queue="webhooks"} 0
+	`
+	assert.Nil(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	t.Run("should fail scrape if queue is not counted", func(t *testing.T) {
+		collector.Add("broken", func(context.Context) (int64, error) { return 0, errors.New("storage is down") })
+
+		reg := prometheus.NewPedanticRegistry()
+		require.Nil(t, reg.Register(collector))
+		_, err := reg.Gather()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	require.Nil(t, metrics.Register(reg))
+	assert.NotNil(t, metrics.Register(reg), "collectors should be registered once")
+}
+
+// observedRequests returns number of observed requests by "method route status"
+func observedRequests(t *testing.T) map[string]uint64 {
+	t.Helper()
+
+	reg := prometheus.NewRegistry()
+	require.Nil(t, reg.Register(metrics.HTTPRequestDuration))
+	families, err := reg.Gather()
+	require.Nil(t, err)
+
+	requests := make(map[string]uint64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			requests[labels["method"]+" "+labels["route"]+" "+labels["status"]] = m.GetHistogram().GetSampleCount()
+		}
+	}
+	return requests
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"nnw_s/pkg/httpx"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// unmatchedRoute is route label of requests which have not matched any route,
+// so scanning of random urls does not create new series
+const unmatchedRoute = "unmatched"
+
+// Middleware records HTTP requests to HTTPRequestDuration, the route label is the route
+// pattern, e.g. /wallet/:id, not the requested path. It is registered before httpx.ErrorResponse,
+// so status of error responses is known.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			status := c.Response().Status
+
+			route := httpx.RouteOf(c)
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			HTTPRequestDuration.
+				WithLabelValues(c.Request().Method, route, strconv.Itoa(status)).
+				Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CountFunc returns size of a queue, e.g. number of pending messages in storage
+type CountFunc func(ctx context.Context) (int64, error)
+
+var queueSizeDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "queue", "size"),
+	"Pending items of background worker queues.",
+	[]string{"queue"}, nil,
+)
+
+// QueueCollector reports sizes of queues of background workers. Sizes are counted on scrape,
+// so they are right for all instances of the server which share the storage.
+type QueueCollector struct {
+	timeout time.Duration
+	queues  map[string]CountFunc
+}
+
+// NewQueueCollector returns collector which counts every queue within timeout
+func NewQueueCollector(timeout time.Duration) *QueueCollector {
+	return &QueueCollector{timeout: timeout, queues: make(map[string]CountFunc)}
+}
+
+// Add adds queue, it should be called before the collector is registered
+func (c *QueueCollector) Add(name string, count CountFunc) {
+	c.queues[name] = count
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueSizeDesc
+}
+
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	names := make([]string, 0, len(c.queues))
+	for name := range c.queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		size, err := c.queues[name](ctx)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(queueSizeDesc, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(queueSizeDesc, prometheus.GaugeValue, float64(size), name)
+	}
+}
@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"nnw_s/config"
+	"nnw_s/pkg/tracing"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -11,12 +12,14 @@ import (
 )
 
 func NewConn(cfg *config.Config) (*mongo.Database, error) {
-	client, err := mongo.NewClient(options.Client().ApplyURI(cfg.MongoDbUrl))
+	client, err := mongo.NewClient(options.Client().ApplyURI(cfg.MongoDbUrl).SetMonitor(tracing.MongoMonitor()))
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
 	err = client.Connect(ctx)
 	if err != nil {
 		return nil, err
@@ -24,7 +27,7 @@ func NewConn(cfg *config.Config) (*mongo.Database, error) {
 
 	err = client.Ping(ctx, readpref.Primary())
 	if err != nil {
-		defer client.Disconnect(ctx)
+		_ = client.Disconnect(context.Background())
 		return nil, err
 	}
 
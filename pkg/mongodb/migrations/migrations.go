@@ -0,0 +1,157 @@
+// Package migrations applies versioned changes of schema and data of mongo storage.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/errors"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection stores versions of applied migrations
+const Collection = "schema_migrations"
+
+// Step changes schema or data of the database. A step must be idempotent: it is run again if the server
+// stops before its version is recorded, and two instances may run it at once on start.
+type Step func(ctx context.Context, db *mongo.Database) error
+
+// Migration is a step with version, migrations are applied in order of versions.
+// Applied migrations must not be changed, a change of an index is a new migration.
+type Migration struct {
+	Version     int
+	Description string
+	Up          Step
+}
+
+// Record is a migration applied to the database
+type Record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+type Migrator interface {
+	// Pending returns migrations which are not applied yet in order of versions, it is a dry-run of Up
+	Pending(ctx context.Context) ([]*Migration, error)
+	// Up applies pending migrations in order of versions and returns applied ones,
+	// it stops on the first failed migration
+	Up(ctx context.Context) ([]*Migration, error)
+}
+
+type migrator struct {
+	db         *mongo.Database
+	log        *logrus.Logger
+	clk        clock.Clock
+	migrations []*Migration
+}
+
+func NewMigrator(db *mongo.Database, log *logrus.Logger, clk clock.Clock, migrations []*Migration) (Migrator, error) {
+	if db == nil {
+		return nil, errors.NewInternal("invalid db")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	if clk == nil {
+		return nil, errors.NewInternal("invalid clock")
+	}
+
+	sorted, err := sortMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+	return &migrator{db: db, log: log, clk: clk, migrations: sorted}, nil
+}
+
+// sortMigrations validates migrations and returns them in order of versions
+func sortMigrations(migrations []*Migration) ([]*Migration, error) {
+	sorted := make([]*Migration, 0, len(migrations))
+	versions := make(map[int]bool, len(migrations))
+
+	for _, m := range migrations {
+		if m == nil || m.Up == nil {
+			return nil, errors.NewInternal("invalid migration")
+		}
+		if m.Version <= 0 {
+			return nil, errors.NewInternal(fmt.Sprintf("invalid version %d of migration '%s'", m.Version, m.Description))
+		}
+		if versions[m.Version] {
+			return nil, errors.NewInternal(fmt.Sprintf("duplicate version %d of migration '%s'", m.Version, m.Description))
+		}
+		versions[m.Version] = true
+		sorted = append(sorted, m)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return sorted, nil
+}
+
+func (m *migrator) Pending(ctx context.Context) ([]*Migration, error) {
+	cursor, err := m.db.Collection(Collection).Find(ctx, bson.M{})
+	if err != nil {
+		m.log.WithContext(ctx).Errorf("failed to find applied migrations: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	var records []*Record
+	if err = cursor.All(ctx, &records); err != nil {
+		m.log.WithContext(ctx).Errorf("failed to decode applied migrations: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, r := range records {
+		applied[r.Version] = true
+	}
+
+	var pending []*Migration
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			delete(applied, migration.Version)
+			continue
+		}
+		pending = append(pending, migration)
+	}
+
+	// the database is migrated by a newer version of the server, e.g. before a rollback
+	for version := range applied {
+		m.log.WithContext(ctx).Warnf("migration %d is applied, but it is unknown to this version of the server", version)
+	}
+	return pending, nil
+}
+
+func (m *migrator) Up(ctx context.Context) ([]*Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]*Migration, 0, len(pending))
+	for _, migration := range pending {
+		m.log.WithContext(ctx).Infof("applying migration %d: %s", migration.Version, migration.Description)
+
+		if err = migration.Up(ctx, m.db); err != nil {
+			m.log.WithContext(ctx).Errorf("failed to apply migration %d: %v", migration.Version, err)
+			return applied, errors.NewInternal(fmt.Sprintf("migration %d: %v", migration.Version, err))
+		}
+
+		// another instance may record the migration at the same time, then upsert fails on duplicate id
+		_, err = m.db.Collection(Collection).UpdateOne(ctx,
+			bson.M{"_id": migration.Version},
+			bson.M{"$setOnInsert": bson.M{"description": migration.Description, "applied_at": m.clk.Now()}},
+			options.Update().SetUpsert(true))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			m.log.WithContext(ctx).Errorf("failed to record migration %d: %v", migration.Version, err)
+			return applied, errors.NewInternal(err.Error())
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
@@ -0,0 +1,163 @@
+package migrations_test
+
+import (
+	"context"
+	"errors"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/mongodb/migrations"
+	"nnw_s/pkg/mongodb/mongotest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func noop(context.Context, *mongo.Database) error {
+	return nil
+}
+
+func TestNewMigrator(t *testing.T) {
+	db := &mongo.Database{}
+
+	tests := []struct {
+		name       string
+		migrations []*migrations.Migration
+	}{
+		{
+			name:       "should fail on duplicate version",
+			migrations: []*migrations.Migration{{Version: 1, Up: noop}, {Version: 1, Up: noop}},
+		},
+		{
+			name:       "should fail on invalid version",
+			migrations: []*migrations.Migration{{Version: 0, Up: noop}},
+		},
+		{
+			name:       "should fail on migration without step",
+			migrations: []*migrations.Migration{{Version: 1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := migrations.NewMigrator(db, logrus.New(), clock.New(), test.migrations)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// step records its version in order of application
+	var calls []int
+	step := func(version int) migrations.Step {
+		return func(context.Context, *mongo.Database) error {
+			calls = append(calls, version)
+			return nil
+		}
+	}
+
+	t.Run("should apply pending migrations in order of versions", func(t *testing.T) {
+		db := mongotest.DB(t)
+		calls = nil
+
+		migrator, err := migrations.NewMigrator(db, logrus.New(), clock.NewMock(now), []*migrations.Migration{
+			{Version: 2, Description: "second", Up: step(2)},
+			{Version: 1, Description: "first", Up: step(1)},
+		})
+		require.Nil(t, err)
+
+		pending, err := migrator.Pending(ctx)
+		require.Nil(t, err)
+		require.Len(t, pending, 2)
+		assert.Empty(t, calls, "dry-run does not apply migrations")
+
+		applied, err := migrator.Up(ctx)
+		require.Nil(t, err)
+		assert.Len(t, applied, 2)
+		assert.Equal(t, []int{1, 2}, calls)
+
+		var record migrations.Record
+		require.Nil(t, db.Collection(migrations.Collection).FindOne(ctx, bson.M{"_id": 1}).Decode(&record))
+		assert.Equal(t, "first", record.Description)
+		assert.True(t, now.Equal(record.AppliedAt))
+
+		applied, err = migrator.Up(ctx)
+		require.Nil(t, err)
+		assert.Empty(t, applied)
+		assert.Equal(t, []int{1, 2}, calls, "applied migrations are not run again")
+	})
+
+	t.Run("should stop on failed migration", func(t *testing.T) {
+		db := mongotest.DB(t)
+		calls = nil
+
+		migrator, err := migrations.NewMigrator(db, logrus.New(), clock.New(), []*migrations.Migration{
+			{Version: 1, Up: step(1)},
+			{Version: 2, Up: func(context.Context, *mongo.Database) error { return errors.New("failed") }},
+			{Version: 3, Up: step(3)},
+		})
+		require.Nil(t, err)
+
+		applied, err := migrator.Up(ctx)
+		assert.NotNil(t, err)
+		assert.Len(t, applied, 1)
+		assert.Equal(t, []int{1}, calls)
+
+		pending, err := migrator.Pending(ctx)
+		require.Nil(t, err)
+		require.Len(t, pending, 2)
+		assert.Equal(t, 2, pending[0].Version)
+	})
+}
+
+func TestSetTTL(t *testing.T) {
+	ctx := context.Background()
+	db := mongotest.DB(t)
+	collection := db.Collection("codes")
+
+	ttlOf := func(t *testing.T) int32 {
+		cursor, err := collection.Indexes().List(ctx)
+		require.Nil(t, err)
+
+		var indexes []struct {
+			Name               string `bson:"name"`
+			ExpireAfterSeconds int32  `bson:"expireAfterSeconds"`
+		}
+		require.Nil(t, cursor.All(ctx, &indexes))
+		for _, idx := range indexes {
+			if idx.Name == "created_at_1" {
+				return idx.ExpireAfterSeconds
+			}
+		}
+		t.Fatal("ttl index is not found")
+		return 0
+	}
+
+	require.Nil(t, migrations.SetTTL(ctx, collection, "created_at", 10*time.Minute))
+	assert.Equal(t, int32(600), ttlOf(t))
+
+	require.Nil(t, migrations.SetTTL(ctx, collection, "created_at", 10*time.Minute), "same ttl is kept")
+	require.Nil(t, migrations.SetTTL(ctx, collection, "created_at", 5*time.Minute), "ttl of existing index is changed")
+	assert.Equal(t, int32(300), ttlOf(t))
+}
+
+func TestRenameCollection(t *testing.T) {
+	ctx := context.Background()
+	db := mongotest.DB(t)
+
+	_, err := db.Collection("user").InsertOne(ctx, bson.M{"email": "user@nnw.com"})
+	require.Nil(t, err)
+
+	require.Nil(t, migrations.RenameCollection(ctx, db, "user", "users"))
+	require.Nil(t, migrations.RenameCollection(ctx, db, "user", "users"), "renamed collection is skipped")
+
+	count, err := db.Collection("users").CountDocuments(ctx, bson.M{})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
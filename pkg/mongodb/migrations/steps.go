@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateIndexes creates indexes of collection, indexes which already exist with the same options are kept
+func CreateIndexes(ctx context.Context, collection *mongo.Collection, models ...mongo.IndexModel) error {
+	_, err := collection.Indexes().CreateMany(ctx, models)
+	return err
+}
+
+// index is a description of existing index
+type index struct {
+	Key                bson.D   `bson:"key"`
+	ExpireAfterSeconds *float64 `bson:"expireAfterSeconds"`
+}
+
+// SetTTL creates TTL index of field of collection or changes TTL of the existing one.
+// Mongo rejects creation of an index which exists with other options, so TTL is changed with collMod.
+func SetTTL(ctx context.Context, collection *mongo.Collection, field string, ttl time.Duration) error {
+	seconds := int32(ttl.Seconds())
+
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var indexes []*index
+	if err = cursor.All(ctx, &indexes); err != nil {
+		return err
+	}
+
+	for _, idx := range indexes {
+		if len(idx.Key) != 1 || idx.Key[0].Key != field {
+			continue
+		}
+		if idx.ExpireAfterSeconds != nil && int32(*idx.ExpireAfterSeconds) == seconds {
+			return nil
+		}
+
+		return collection.Database().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: collection.Name()},
+			{Key: "index", Value: bson.D{
+				{Key: "keyPattern", Value: idx.Key},
+				{Key: "expireAfterSeconds", Value: seconds},
+			}},
+		}).Err()
+	}
+
+	return CreateIndexes(ctx, collection, mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(seconds),
+	})
+}
+
+// RenameCollection renames collection of db, it does nothing if the collection is already renamed
+func RenameCollection(ctx context.Context, db *mongo.Database, from, to string) error {
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": from})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	// renameCollection is a command of admin database with full names of collections
+	return db.Client().Database("admin").RunCommand(ctx, bson.D{
+		{Key: "renameCollection", Value: fmt.Sprintf("%s.%s", db.Name(), from)},
+		{Key: "to", Value: fmt.Sprintf("%s.%s", db.Name(), to)},
+	}).Err()
+}
@@ -0,0 +1,46 @@
+// Package mongotest provides a mongo database for repository tests.
+package mongotest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// URLEnv is an environment variable with url of mongo server used in tests
+const URLEnv = "MONGO_TEST_URL"
+
+// DB connects to the mongo server set by MONGO_TEST_URL and returns a new empty database
+// which is dropped when the test finishes. Test is skipped if MONGO_TEST_URL is not set.
+func DB(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	url := os.Getenv(URLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", URLEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+	if err != nil {
+		t.Fatalf("failed to connect to mongo: %v", err)
+	}
+
+	db := client.Database(fmt.Sprintf("nnw_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		_ = db.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+
+	return db
+}
@@ -0,0 +1,105 @@
+package notificator
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+)
+
+// Channel is a way the notification reaches the recipient
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelTelegram Channel = "telegram"
+	ChannelWebhook  Channel = "webhook"
+	ChannelPush     Channel = "push"
+)
+
+// Channels returns all known channels
+func Channels() []Channel {
+	return []Channel{ChannelEmail, ChannelTelegram, ChannelWebhook, ChannelPush}
+}
+
+func (c Channel) Valid() bool {
+	switch c {
+	case ChannelEmail, ChannelTelegram, ChannelWebhook, ChannelPush:
+		return true
+	}
+	return false
+}
+
+// Transport delivers message of its channel, message body is already in the format of the channel
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// DefaultChannels are used for the event if the recipient hasn't chosen channels
+func DefaultChannels(event Event) []Channel {
+	return []Channel{ChannelEmail}
+}
+
+// Configurable reports whether recipient can choose channels of the event.
+// Events with secret codes are sent by email only, so codes don't leak to chats or third party servers.
+func (e Event) Configurable() bool {
+	switch e {
+	case EventVerifyEmail, EventResetPassword:
+		return false
+	}
+	return true
+}
+
+// Security reports whether the event is about account security. Such events are always sent by email too,
+// so a stolen session can't hide them by changing recipient's channels.
+func (e Event) Security() bool {
+	switch e {
+	case EventNewLogin, EventPasswordChanged:
+		return true
+	}
+	return false
+}
+
+// eventChannels returns channels the event is sent to, chosen channels are used only for configurable events
+func eventChannels(event Event, chosen []Channel) []Channel {
+	if len(chosen) == 0 || !event.Configurable() {
+		return DefaultChannels(event)
+	}
+	if !event.Security() {
+		return chosen
+	}
+
+	for _, c := range chosen {
+		if c == ChannelEmail {
+			return chosen
+		}
+	}
+	return append([]Channel{ChannelEmail}, chosen...)
+}
+
+// permanentError is an error which doesn't go away on retry, e.g. channel is not configured
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks error of transport as permanent, message with such error is not retried
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err or any error it wraps is marked as permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return stderrors.As(err, &permanent)
+}
+
+func errNoTransport(c Channel) error {
+	return Permanent(fmt.Errorf("channel '%s' is not configured", c))
+}
@@ -0,0 +1,273 @@
+package notificator_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/notificator"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubServer records requests of a channel API and responds with the given status
+type stubServer struct {
+	*httptest.Server
+	status   int
+	requests []*http.Request
+	bodies   []map[string]interface{}
+}
+
+func newStubServer(t *testing.T) *stubServer {
+	stub := &stubServer{status: http.StatusOK}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		require.Nil(t, err)
+
+		var body map[string]interface{}
+		require.Nil(t, json.Unmarshal(data, &body))
+
+		stub.requests = append(stub.requests, r)
+		stub.bodies = append(stub.bodies, body)
+		w.WriteHeader(stub.status)
+		_, _ = fmt.Fprint(w, `{"ok":false,"description":"error of stub"}`)
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func TestTransports(t *testing.T) {
+	ctx := context.Background()
+
+	msg, err := notificator.NewMessage("key", "from@mail.com", []string{"42"}, "New login", "Your account was signed in")
+	require.Nil(t, err)
+	msg.Template = "new_login.v1"
+
+	t.Run("telegram should send message to chat", func(t *testing.T) {
+		stub := newStubServer(t)
+		transport, err := notificator.NewTelegramTransport(stub.URL, "secret_token", stub.Client())
+		require.Nil(t, err)
+
+		require.Nil(t, transport.Send(ctx, msg))
+		require.Len(t, stub.requests, 1)
+		assert.Equal(t, "/botsecret_token/sendMessage", stub.requests[0].URL.Path)
+		assert.Equal(t, "42", stub.bodies[0]["chat_id"])
+		assert.Equal(t, msg.Body, stub.bodies[0]["text"])
+	})
+
+	t.Run("push should send notification to all devices", func(t *testing.T) {
+		stub := newStubServer(t)
+		transport, err := notificator.NewPushTransport(stub.URL, "server_key", stub.Client())
+		require.Nil(t, err)
+
+		push := *msg
+		push.Recipients = []string{"device_1", "device_2"}
+		require.Nil(t, transport.Send(ctx, &push))
+
+		require.Len(t, stub.requests, 1)
+		assert.Equal(t, "key=server_key", stub.requests[0].Header.Get("Authorization"))
+		assert.Equal(t, []interface{}{"device_1", "device_2"}, stub.bodies[0]["registration_ids"])
+		assert.Equal(t, map[string]interface{}{"title": "New login", "body": msg.Body}, stub.bodies[0]["notification"])
+	})
+
+	t.Run("webhook should post message body with idempotency key", func(t *testing.T) {
+		stub := newStubServer(t)
+		transport, err := notificator.NewWebhookTransport(stub.Client())
+		require.Nil(t, err)
+
+		hook := *msg
+		hook.Recipients = []string{stub.URL + "/hook"}
+		hook.Body = `{"event":"new_login"}`
+		require.Nil(t, transport.Send(ctx, &hook))
+
+		require.Len(t, stub.requests, 1)
+		assert.Equal(t, "/hook", stub.requests[0].URL.Path)
+		assert.Equal(t, "key", stub.requests[0].Header.Get("Idempotency-Key"))
+		assert.Equal(t, "application/json", stub.requests[0].Header.Get("Content-Type"))
+		assert.Equal(t, "new_login", stub.bodies[0]["event"])
+	})
+
+	t.Run("should classify errors of channel API", func(t *testing.T) {
+		stub := newStubServer(t)
+		transport, err := notificator.NewTelegramTransport(stub.URL, "secret_token", stub.Client())
+		require.Nil(t, err)
+
+		stub.status = http.StatusBadRequest
+		err = transport.Send(ctx, msg)
+		require.NotNil(t, err)
+		assert.True(t, notificator.IsPermanent(err))
+		assert.NotContains(t, err.Error(), "secret_token")
+
+		stub.status = http.StatusTooManyRequests
+		err = transport.Send(ctx, msg)
+		require.NotNil(t, err)
+		assert.False(t, notificator.IsPermanent(err))
+
+		stub.status = http.StatusBadGateway
+		err = transport.Send(ctx, msg)
+		require.NotNil(t, err)
+		assert.False(t, notificator.IsPermanent(err))
+
+		stub.Close()
+		err = transport.Send(ctx, msg)
+		require.NotNil(t, err)
+		assert.False(t, notificator.IsPermanent(err))
+		assert.NotContains(t, err.Error(), "secret_token")
+	})
+}
+
+func TestNotify(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (notificator.Service, notificator.OutboxRepository) {
+		outbox, err := notificator.NewOutboxMemoryRepository()
+		require.Nil(t, err)
+
+		now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+		svc, err := notificator.NewService(logrus.New(), outbox, newRegistry(t), clock.NewMock(now))
+		require.Nil(t, err)
+		return svc, outbox
+	}
+
+	channelsOf := func(t *testing.T, outbox notificator.OutboxRepository) map[notificator.Channel]*notificator.Message {
+		messages, err := outbox.GetMessages(ctx, notificator.MessagePending, 10)
+		require.Nil(t, err)
+
+		channels := map[notificator.Channel]*notificator.Message{}
+		for _, m := range messages {
+			channels[m.GetChannel()] = m
+		}
+		return channels
+	}
+
+	recipient := func() *notificator.Recipient {
+		return &notificator.Recipient{
+			Email:          "to@mail.com",
+			TelegramChatID: "42",
+			WebhookURL:     "https://partner.com/hook",
+			PushTokens:     []string{"device_1"},
+			Languages:      []string{"ru"},
+
+			AntiPhishingPhrase: "blue whale",
+		}
+	}
+
+	data := &notificator.TxReceivedData{Chain: "BTC", Amount: "0.5", From: "tb1qsender", TxHash: "hash"}
+
+	t.Run("should send to default channels if recipient hasn't chosen them", func(t *testing.T) {
+		svc, outbox := setup(t)
+
+		require.Nil(t, svc.Notify(ctx, &notificator.Notification{Sender: "from@nnw.com", Recipient: recipient(), Data: data, IdempotencyKey: "tx:hash"}))
+
+		channels := channelsOf(t, outbox)
+		require.Len(t, channels, 1)
+		assert.Equal(t, "tx:hash:email", channels[notificator.ChannelEmail].IdempotencyKey)
+		assert.Equal(t, []string{"to@mail.com"}, channels[notificator.ChannelEmail].Recipients)
+	})
+
+	t.Run("should send to every chosen channel in format of the channel", func(t *testing.T) {
+		svc, outbox := setup(t)
+
+		r := recipient()
+		r.Channels = []notificator.Channel{notificator.ChannelTelegram, notificator.ChannelWebhook, notificator.ChannelPush}
+		require.Nil(t, svc.Notify(ctx, &notificator.Notification{Sender: "from@nnw.com", Recipient: r, Data: data, IdempotencyKey: "tx:hash"}))
+
+		channels := channelsOf(t, outbox)
+		require.Len(t, channels, 3)
+
+		telegram := channels[notificator.ChannelTelegram]
+		assert.Equal(t, []string{"42"}, telegram.Recipients)
+		assert.Equal(t, "BTC получено\\n\\nВы получили 0.5 BTC с адреса tb1qsender.", strings.ReplaceAll(telegram.Body, "\n", "\\n"))
+
+		push := channels[notificator.ChannelPush]
+		assert.Equal(t, []string{"device_1"}, push.Recipients)
+		assert.Equal(t, "BTC получено", push.Subject)
+		assert.Equal(t, "Вы получили 0.5 BTC с адреса tb1qsender.", push.Body)
+
+		webhook := channels[notificator.ChannelWebhook]
+		assert.Equal(t, []string{"https://partner.com/hook"}, webhook.Recipients)
+
+		var payload map[string]interface{}
+		require.Nil(t, json.Unmarshal([]byte(webhook.Body), &payload))
+		assert.Equal(t, "tx_received", payload["event"])
+		assert.Equal(t, "tx:hash", payload["idempotency_key"])
+		assert.Equal(t, "ru", payload["language"])
+		assert.Equal(t, "hash", payload["data"].(map[string]interface{})["tx_hash"])
+		assert.NotContains(t, webhook.Body, "blue whale")
+	})
+
+	t.Run("should show anti-phishing phrase in email only", func(t *testing.T) {
+		svc, outbox := setup(t)
+
+		r := recipient()
+		r.Channels = []notificator.Channel{notificator.ChannelEmail, notificator.ChannelTelegram, notificator.ChannelWebhook, notificator.ChannelPush}
+		require.Nil(t, svc.Notify(ctx, &notificator.Notification{Sender: "from@nnw.com", Recipient: r, Data: data, IdempotencyKey: "tx:hash"}))
+
+		channels := channelsOf(t, outbox)
+		require.Len(t, channels, 4)
+		assert.Contains(t, channels[notificator.ChannelEmail].Body, "blue whale")
+		for _, channel := range []notificator.Channel{notificator.ChannelTelegram, notificator.ChannelWebhook, notificator.ChannelPush} {
+			assert.NotContains(t, channels[channel].Body, "blue whale", channel)
+			assert.NotContains(t, channels[channel].Subject, "blue whale", channel)
+		}
+		assert.Empty(t, data.AntiPhishingPhrase)
+	})
+
+	t.Run("should skip channels without recipient's address", func(t *testing.T) {
+		svc, outbox := setup(t)
+
+		r := &notificator.Recipient{Email: "to@mail.com", Channels: []notificator.Channel{notificator.ChannelEmail, notificator.ChannelTelegram}}
+		require.Nil(t, svc.Notify(ctx, &notificator.Notification{Sender: "from@nnw.com", Recipient: r, Data: data, IdempotencyKey: "tx:hash"}))
+
+		channels := channelsOf(t, outbox)
+		require.Len(t, channels, 1)
+		assert.Contains(t, channels, notificator.ChannelEmail)
+	})
+
+	t.Run("should send secret codes by email only", func(t *testing.T) {
+		svc, outbox := setup(t)
+
+		r := recipient()
+		r.Channels = []notificator.Channel{notificator.ChannelTelegram, notificator.ChannelWebhook}
+		code := &notificator.ResetPasswordData{Code: "123456", ExpiresInMinutes: 5}
+		require.Nil(t, svc.Notify(ctx, &notificator.Notification{Sender: "from@nnw.com", Recipient: r, Data: code, IdempotencyKey: "reset"}))
+
+		channels := channelsOf(t, outbox)
+		require.Len(t, channels, 1)
+		assert.Contains(t, channels, notificator.ChannelEmail)
+	})
+
+	t.Run("should always send security events by email", func(t *testing.T) {
+		svc, outbox := setup(t)
+
+		r := recipient()
+		r.Channels = []notificator.Channel{notificator.ChannelTelegram}
+		login := &notificator.NewLoginData{IP: "127.0.0.1", UserAgent: "curl", Time: time.Now()}
+		require.Nil(t, svc.Notify(ctx, &notificator.Notification{Sender: "from@nnw.com", Recipient: r, Data: login, IdempotencyKey: "login"}))
+
+		channels := channelsOf(t, outbox)
+		require.Len(t, channels, 2)
+		assert.Contains(t, channels, notificator.ChannelEmail)
+		assert.Contains(t, channels, notificator.ChannelTelegram)
+	})
+
+	t.Run("should not queue the same notification twice", func(t *testing.T) {
+		svc, outbox := setup(t)
+
+		n := &notificator.Notification{Sender: "from@nnw.com", Recipient: recipient(), Data: data, IdempotencyKey: "tx:hash"}
+		require.Nil(t, svc.Notify(ctx, n))
+		require.Nil(t, svc.Notify(ctx, n))
+
+		messages, err := outbox.GetMessages(ctx, notificator.MessagePending, 10)
+		require.Nil(t, err)
+		assert.Len(t, messages, 1)
+	})
+}
@@ -0,0 +1,46 @@
+package notificator
+
+import "time"
+
+// MessageDTO is an outbox message without body, as it may contain secret codes
+type MessageDTO struct {
+	ID             string     `json:"id"`
+	IdempotencyKey string     `json:"idempotency_key"`
+	Channel        string     `json:"channel"`
+	Recipients     []string   `json:"recipients"`
+	Subject        string     `json:"subject"`
+	Template       string     `json:"template,omitempty"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastError      string     `json:"last_error,omitempty"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func MapMessageToDTO(m *Message) *MessageDTO {
+	return &MessageDTO{
+		ID:             m.ID.Hex(),
+		IdempotencyKey: m.IdempotencyKey,
+		Channel:        string(m.GetChannel()),
+		Recipients:     m.Recipients,
+		Subject:        m.Subject,
+		Template:       m.Template,
+		Status:         string(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastError:      m.LastError,
+		SentAt:         m.SentAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func MapMessagesToDTO(messages []*Message) []*MessageDTO {
+	dtos := make([]*MessageDTO, 0, len(messages))
+	for _, m := range messages {
+		dtos = append(dtos, MapMessageToDTO(m))
+	}
+	return dtos
+}
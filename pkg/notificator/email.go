@@ -1,9 +1,14 @@
 package notificator
 
 type Email struct {
-	Subject   string
 	Recipient string
 	Sender    string
-	Template  string
-	Data      map[string]interface{}
+	// Data selects the event template and is rendered with it, subject is the title of the template
+	Data TemplateData
+	// Languages are recipient's preferred languages in order of priority, e.g. user's locale
+	// and Accept-Language of the request, the email is rendered in the best supported one
+	Languages []string
+
+	// IdempotencyKey identifies the email in the outbox, email with already queued key is not sent twice
+	IdempotencyKey string
 }
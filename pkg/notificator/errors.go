@@ -0,0 +1,24 @@
+package notificator
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+)
+
+const (
+	StatusMessageNotFound   errors.Status = "outbox_message_not_found"
+	StatusMessageNotFailed  errors.Status = "outbox_message_not_failed"
+	StatusInvalidMessage    errors.Status = "invalid_outbox_message"
+	StatusInvalidStatusList errors.Status = "invalid_outbox_status"
+	StatusAlreadyQueued     errors.Status = "outbox_message_already_queued"
+	StatusTemplateNotFound  errors.Status = "email_template_not_found"
+)
+
+var (
+	ErrMessageNotFound  = errors.New(codes.NotFound, StatusMessageNotFound)
+	ErrMessageNotFailed = errors.New(codes.BadRequest, StatusMessageNotFailed)
+	ErrInvalidMessage   = errors.New(codes.BadRequest, StatusInvalidMessage)
+	ErrInvalidStatus    = errors.New(codes.BadRequest, StatusInvalidStatusList)
+	ErrAlreadyQueued    = errors.New(codes.DuplicateError, StatusAlreadyQueued)
+	ErrTemplateNotFound = errors.New(codes.InternalError, StatusTemplateNotFound)
+)
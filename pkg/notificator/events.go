@@ -0,0 +1,106 @@
+package notificator
+
+import "time"
+
+// Event is a kind of notification, every event has its own template and data
+type Event string
+
+const (
+	EventVerifyEmail     Event = "verify_email"
+	EventResetPassword   Event = "reset_password"
+	EventNewLogin        Event = "new_login"
+	EventPasswordChanged Event = "password_changed"
+	EventTxSent          Event = "tx_sent"
+	EventTxReceived      Event = "tx_received"
+)
+
+// Events returns all known events
+func Events() []Event {
+	return []Event{EventVerifyEmail, EventResetPassword, EventNewLogin, EventPasswordChanged, EventTxSent, EventTxReceived}
+}
+
+func (e Event) Valid() bool {
+	for _, event := range Events() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateData is data of the event template
+type TemplateData interface {
+	Event() Event
+	// SetName sets recipient's name, so the template can greet the recipient
+	SetName(name string)
+	// SetAntiPhishingPhrase sets recipient's phrase which is shown in the email header
+	SetAntiPhishingPhrase(phrase string)
+}
+
+// Greeting is embedded into every template data and is rendered by the header partial.
+// Template data is sent to webhooks as it is, so its fields have json names.
+type Greeting struct {
+	Name string `json:"name,omitempty"`
+	// AntiPhishingPhrase proves the email is sent by us, it is never sent to other channels
+	AntiPhishingPhrase string `json:"-"`
+}
+
+func (g *Greeting) SetName(name string) {
+	g.Name = name
+}
+
+func (g *Greeting) SetAntiPhishingPhrase(phrase string) {
+	g.AntiPhishingPhrase = phrase
+}
+
+type VerifyEmailData struct {
+	Greeting
+	Code             string `json:"code"`
+	ExpiresInMinutes int    `json:"expires_in_minutes"`
+}
+
+func (*VerifyEmailData) Event() Event { return EventVerifyEmail }
+
+type ResetPasswordData struct {
+	Greeting
+	Code             string `json:"code"`
+	ExpiresInMinutes int    `json:"expires_in_minutes"`
+}
+
+func (*ResetPasswordData) Event() Event { return EventResetPassword }
+
+type NewLoginData struct {
+	Greeting
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Time      time.Time `json:"time"`
+}
+
+func (*NewLoginData) Event() Event { return EventNewLogin }
+
+type PasswordChangedData struct {
+	Greeting
+	Time time.Time `json:"time"`
+}
+
+func (*PasswordChangedData) Event() Event { return EventPasswordChanged }
+
+type TxSentData struct {
+	Greeting
+	Chain  string `json:"chain"`
+	Amount string `json:"amount"`
+	To     string `json:"to"`
+	TxHash string `json:"tx_hash"`
+}
+
+func (*TxSentData) Event() Event { return EventTxSent }
+
+type TxReceivedData struct {
+	Greeting
+	Chain  string `json:"chain"`
+	Amount string `json:"amount"`
+	From   string `json:"from"`
+	TxHash string `json:"tx_hash"`
+}
+
+func (*TxReceivedData) Event() Event { return EventTxReceived }
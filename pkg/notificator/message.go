@@ -0,0 +1,108 @@
+package notificator
+
+import (
+	"nnw_s/pkg/errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MessageStatus string
+
+const (
+	// MessagePending is waiting for the first or next delivery attempt
+	MessagePending MessageStatus = "pending"
+	// MessageSent has been delivered to SMTP server
+	MessageSent MessageStatus = "sent"
+	// MessageDead has failed all delivery attempts and can be only requeued by admin
+	MessageDead MessageStatus = "dead"
+)
+
+// Message is a rendered notification stored in the outbox until it is delivered
+type Message struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	IdempotencyKey string             `bson:"idempotency_key"`
+	// Channel is empty for messages queued before channels were introduced, they are emails
+	Channel Channel `bson:"channel,omitempty"`
+	Sender  string  `bson:"sender"`
+	// Recipients are addresses in the channel: emails, telegram chat, webhook URL or push tokens
+	Recipients []string `bson:"recipients"`
+	Subject    string   `bson:"subject"`
+	Body       string   `bson:"body"`
+	// Template is a name and version of template the body was rendered with
+	Template string `bson:"template,omitempty"`
+
+	Status        MessageStatus `bson:"status"`
+	Attempts      int           `bson:"attempts"`
+	NextAttemptAt time.Time     `bson:"next_attempt_at"`
+	LastError     string        `bson:"last_error,omitempty"`
+	SentAt        *time.Time    `bson:"sent_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// NewMessage creates email message, message of other channel is created by setting its Channel
+func NewMessage(idempotencyKey, sender string, recipients []string, subject, body string) (*Message, error) {
+	if idempotencyKey == "" {
+		return nil, errors.WithMessage(ErrInvalidMessage, "idempotency key should be not empty")
+	}
+	if sender == "" || len(recipients) == 0 {
+		return nil, errors.WithMessage(ErrInvalidMessage, "sender and recipients should be not empty")
+	}
+
+	now := time.Now()
+	return &Message{
+		ID:             primitive.NewObjectID(),
+		IdempotencyKey: idempotencyKey,
+		Channel:        ChannelEmail,
+		Sender:         sender,
+		Recipients:     recipients,
+		Subject:        subject,
+		Body:           body,
+		Status:         MessagePending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// GetChannel returns channel of the message, messages without channel are emails
+func (m *Message) GetChannel() Channel {
+	if m.Channel == "" {
+		return ChannelEmail
+	}
+	return m.Channel
+}
+
+// MarkSent marks message as delivered
+func (m *Message) MarkSent(now time.Time) {
+	m.Status = MessageSent
+	m.Attempts++
+	m.LastError = ""
+	m.SentAt = &now
+	m.UpdatedAt = now
+}
+
+// MarkFailed records failed attempt, message is scheduled to next attempt or becomes dead
+func (m *Message) MarkFailed(now time.Time, err error, nextAttemptAt time.Time, dead bool) {
+	m.Attempts++
+	m.LastError = err.Error()
+	m.NextAttemptAt = nextAttemptAt
+	m.UpdatedAt = now
+	if dead {
+		m.Status = MessageDead
+	}
+}
+
+// Requeue returns dead message to the queue with a fresh set of attempts
+func (m *Message) Requeue(now time.Time) error {
+	if m.Status != MessageDead {
+		return ErrMessageNotFailed
+	}
+	m.Status = MessagePending
+	m.Attempts = 0
+	m.NextAttemptAt = now
+	m.UpdatedAt = now
+	return nil
+}
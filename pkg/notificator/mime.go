@@ -0,0 +1,96 @@
+package notificator
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mailMessage is a message composed of html and plain text alternatives
+type mailMessage struct {
+	From      string
+	To        []string
+	Subject   string
+	HTML      string
+	Text      string
+	Date      time.Time
+	MessageID string
+}
+
+// newMessageID generates RFC 5322 Message-ID at the sender's domain
+func newMessageID(sender string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(sender, "@"); at >= 0 && at < len(sender)-1 {
+		domain = sender[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", primitive.NewObjectID().Hex(), domain)
+}
+
+// Bytes composes RFC 5322 multipart/alternative message with CRLF line endings
+func (m *mailMessage) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	to := make([]string, 0, len(m.To))
+	for _, addr := range m.To {
+		to = append(to, (&mail.Address{Address: addr}).String())
+	}
+
+	headers := []struct{ key, value string }{
+		{"From", (&mail.Address{Address: m.From}).String()},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("UTF-8", m.Subject)},
+		{"Date", m.Date.Format(time.RFC1123Z)},
+		{"Message-ID", m.MessageID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": body.Boundary()})},
+	}
+
+	var msg bytes.Buffer
+	for _, h := range headers {
+		msg.WriteString(h.key + ": " + h.value + "\r\n")
+	}
+	msg.WriteString("\r\n")
+
+	// plain text goes first, clients show the last alternative they support
+	if err := writePart(body, "text/plain", m.Text); err != nil {
+		return nil, err
+	}
+	if err := writePart(body, "text/html", m.HTML); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	msg.Write(buf.Bytes())
+	return msg.Bytes(), nil
+}
+
+func writePart(w *multipart.Writer, contentType, content string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"charset": "UTF-8"})},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err = qp.Write([]byte(toCRLF(content))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func toCRLF(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
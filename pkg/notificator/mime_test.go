@@ -0,0 +1,120 @@
+package notificator_test
+
+import (
+	"context"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/notificator"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueEmail(t *testing.T) {
+	ctx := context.Background()
+
+	outbox, err := notificator.NewOutboxMemoryRepository()
+	require.Nil(t, err)
+
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	svc, err := notificator.NewService(logrus.New(), outbox, newRegistry(t), clock.NewMock(now))
+	require.Nil(t, err)
+
+	err = svc.QueueEmail(ctx, &notificator.Email{
+		Recipient: "to@mail.com",
+		Sender:    "from@nnw.com",
+		Data: &notificator.TxReceivedData{
+			Chain:  "Эфир",
+			Amount: "1.5",
+			From:   "<script>alert(1)</script>",
+			TxHash: "123456",
+		},
+		IdempotencyKey: "key",
+	})
+	require.Nil(t, err)
+
+	messages, err := outbox.GetMessages(ctx, notificator.MessagePending, 10)
+	require.Nil(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "tx_received.v1", messages[0].Template)
+
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0].Body))
+	require.Nil(t, err)
+
+	t.Run("should have RFC 5322 headers", func(t *testing.T) {
+		assert.Equal(t, "<from@nnw.com>", msg.Header.Get("From"))
+		assert.Equal(t, "<to@mail.com>", msg.Header.Get("To"))
+		assert.Equal(t, "1.0", msg.Header.Get("MIME-Version"))
+		assert.True(t, strings.HasSuffix(msg.Header.Get("Message-ID"), "@nnw.com>"))
+
+		date, err := msg.Header.Date()
+		require.Nil(t, err)
+		assert.True(t, now.Equal(date))
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		require.Nil(t, err)
+		assert.Equal(t, "Эфир received", subject)
+		assert.NotEqual(t, "Эфир received", msg.Header.Get("Subject"))
+	})
+
+	t.Run("should have plain text and escaped html alternatives", func(t *testing.T) {
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		require.Nil(t, err)
+		require.Equal(t, "multipart/alternative", mediaType)
+
+		parts := map[string]string{}
+		reader := multipart.NewReader(msg.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+
+			partType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			require.Nil(t, err)
+
+			// multipart reader decodes quoted-printable itself and removes the header
+			content, err := ioutil.ReadAll(part)
+			require.Nil(t, err)
+			if part.Header.Get("Content-Transfer-Encoding") == "quoted-printable" {
+				content, err = ioutil.ReadAll(quotedprintable.NewReader(strings.NewReader(string(content))))
+				require.Nil(t, err)
+			}
+			parts[partType] = string(content)
+		}
+
+		require.Contains(t, parts, "text/plain")
+		require.Contains(t, parts, "text/html")
+
+		assert.Contains(t, parts["text/plain"], "123456")
+		assert.Contains(t, parts["text/plain"], "<script>alert(1)</script>")
+
+		assert.Contains(t, parts["text/html"], "123456")
+		assert.NotContains(t, parts["text/html"], "<script>")
+		assert.Contains(t, parts["text/html"], "&lt;script&gt;")
+	})
+}
+
+func TestQueueEmailUnknownTemplate(t *testing.T) {
+	outbox, err := notificator.NewOutboxMemoryRepository()
+	require.Nil(t, err)
+
+	svc, err := notificator.NewService(logrus.New(), outbox, newRegistry(t), clock.New())
+	require.Nil(t, err)
+
+	err = svc.QueueEmail(context.Background(), &notificator.Email{
+		Recipient:      "to@mail.com",
+		Sender:         "from@nnw.com",
+		Data:           &unknownData{},
+		IdempotencyKey: "key",
+	})
+	assert.Contains(t, err.Error(), string(notificator.StatusTemplateNotFound))
+}
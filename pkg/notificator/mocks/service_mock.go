@@ -35,16 +35,60 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
-// SendEmail mocks base method.
-func (m *MockService) SendEmail(ctx context.Context, email *notificator.Email) error {
+// GetMessages mocks base method.
+func (m *MockService) GetMessages(ctx context.Context, status notificator.MessageStatus) ([]*notificator.MessageDTO, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendEmail", ctx, email)
+	ret := m.ctrl.Call(m, "GetMessages", ctx, status)
+	ret0, _ := ret[0].([]*notificator.MessageDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessages indicates an expected call of GetMessages.
+func (mr *MockServiceMockRecorder) GetMessages(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockService)(nil).GetMessages), ctx, status)
+}
+
+// Notify mocks base method.
+func (m *MockService) Notify(ctx context.Context, n *notificator.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, n)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockServiceMockRecorder) Notify(ctx, n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockService)(nil).Notify), ctx, n)
+}
+
+// QueueEmail mocks base method.
+func (m *MockService) QueueEmail(ctx context.Context, email *notificator.Email) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueEmail", ctx, email)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SendEmail indicates an expected call of SendEmail.
-func (mr *MockServiceMockRecorder) SendEmail(ctx, email interface{}) *gomock.Call {
+// QueueEmail indicates an expected call of QueueEmail.
+func (mr *MockServiceMockRecorder) QueueEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueEmail", reflect.TypeOf((*MockService)(nil).QueueEmail), ctx, email)
+}
+
+// RequeueMessage mocks base method.
+func (m *MockService) RequeueMessage(ctx context.Context, id string) (*notificator.MessageDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueMessage", ctx, id)
+	ret0, _ := ret[0].(*notificator.MessageDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueMessage indicates an expected call of RequeueMessage.
+func (mr *MockServiceMockRecorder) RequeueMessage(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockService)(nil).SendEmail), ctx, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueMessage", reflect.TypeOf((*MockService)(nil).RequeueMessage), ctx, id)
 }
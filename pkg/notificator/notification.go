@@ -0,0 +1,86 @@
+package notificator
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Recipient is the user's addresses in channels and chosen channels of the event
+type Recipient struct {
+	Email string
+	// AntiPhishingPhrase is rendered only into emails, other channels are third parties and never get it
+	AntiPhishingPhrase string
+
+	TelegramChatID string
+	WebhookURL     string
+	PushTokens     []string
+
+	// Languages are recipient's preferred languages in order of priority
+	Languages []string
+	// Channels are channels the recipient wants to get the event in, DefaultChannels are used if it is empty
+	Channels []Channel
+}
+
+// address returns recipient's addresses in the channel, channel without addresses is skipped
+func (r *Recipient) address(c Channel) []string {
+	switch c {
+	case ChannelEmail:
+		if r.Email != "" {
+			return []string{r.Email}
+		}
+	case ChannelTelegram:
+		if r.TelegramChatID != "" {
+			return []string{r.TelegramChatID}
+		}
+	case ChannelWebhook:
+		if r.WebhookURL != "" {
+			return []string{r.WebhookURL}
+		}
+	case ChannelPush:
+		return r.PushTokens
+	}
+	return nil
+}
+
+// Notification is an event sent to every chosen channel of the recipient
+type Notification struct {
+	Sender    string
+	Recipient *Recipient
+	Data      TemplateData
+
+	// IdempotencyKey identifies the notification, it is suffixed by channel in the outbox
+	IdempotencyKey string
+}
+
+// webhookPayload is a body of webhook message
+type webhookPayload struct {
+	Event          Event        `json:"event"`
+	IdempotencyKey string       `json:"idempotency_key"`
+	Language       string       `json:"language"`
+	Subject        string       `json:"subject"`
+	Summary        string       `json:"summary"`
+	Data           TemplateData `json:"data"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// channelBody returns subject and body of the message in format of the channel
+func channelBody(c Channel, n *Notification, rendered *Rendered, now time.Time) (string, string, error) {
+	switch c {
+	case ChannelTelegram:
+		return rendered.Subject, rendered.Subject + "\n\n" + rendered.Summary, nil
+	case ChannelPush:
+		return rendered.Subject, rendered.Summary, nil
+	case ChannelWebhook:
+		body, err := json.Marshal(&webhookPayload{
+			Event:          n.Data.Event(),
+			IdempotencyKey: n.IdempotencyKey,
+			Language:       rendered.Language,
+			Subject:        rendered.Subject,
+			Summary:        rendered.Summary,
+			Data:           n.Data,
+			CreatedAt:      now,
+		})
+		return rendered.Subject, string(body), err
+	}
+	return "", "", errNoTransport(c)
+}
@@ -0,0 +1,134 @@
+package notificator
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/memory"
+	"sort"
+	"sync"
+	"time"
+)
+
+type outboxMemoryRepository struct {
+	mu       sync.Mutex
+	messages map[string]*Message // by id
+}
+
+// NewOutboxMemoryRepository returns thread-safe outbox which keeps messages in memory
+func NewOutboxMemoryRepository() (OutboxRepository, error) {
+	return &outboxMemoryRepository{messages: make(map[string]*Message)}, nil
+}
+
+func (repo *outboxMemoryRepository) Enqueue(_ context.Context, msg *Message) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, m := range repo.messages {
+		if m.IdempotencyKey == msg.IdempotencyKey || m.ID == msg.ID {
+			return ErrAlreadyQueued
+		}
+	}
+
+	stored, err := copyMessage(msg)
+	if err != nil {
+		return err
+	}
+	repo.messages[msg.ID.Hex()] = stored
+	return nil
+}
+
+func (repo *outboxMemoryRepository) ClaimDue(_ context.Context, now time.Time, lease time.Duration) (*Message, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var due *Message
+	for _, m := range repo.messages {
+		if m.Status != MessagePending || m.NextAttemptAt.After(now) {
+			continue
+		}
+		if due == nil || m.NextAttemptAt.Before(due.NextAttemptAt) {
+			due = m
+		}
+	}
+
+	if due == nil {
+		return nil, nil
+	}
+
+	due.NextAttemptAt = now.Add(lease)
+	return copyMessage(due)
+}
+
+func (repo *outboxMemoryRepository) SaveMessage(_ context.Context, msg *Message) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.messages[msg.ID.Hex()]; !ok {
+		return ErrMessageNotFound
+	}
+
+	stored, err := copyMessage(msg)
+	if err != nil {
+		return err
+	}
+	repo.messages[msg.ID.Hex()] = stored
+	return nil
+}
+
+func (repo *outboxMemoryRepository) GetMessage(_ context.Context, id string) (*Message, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	msg, ok := repo.messages[id]
+	if !ok {
+		return nil, ErrMessageNotFound
+	}
+	return copyMessage(msg)
+}
+
+func (repo *outboxMemoryRepository) GetMessages(_ context.Context, status MessageStatus, limit int64) ([]*Message, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	messages := make([]*Message, 0)
+	for _, m := range repo.messages {
+		if m.Status != status {
+			continue
+		}
+
+		copied, err := copyMessage(m)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, copied)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].UpdatedAt.After(messages[j].UpdatedAt)
+	})
+	if limit > 0 && int64(len(messages)) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+func (repo *outboxMemoryRepository) CountPending(_ context.Context) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var count int64
+	for _, m := range repo.messages {
+		if m.Status == MessagePending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func copyMessage(m *Message) (*Message, error) {
+	var copied Message
+	if err := memory.Copy(m, &copied); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	return &copied, nil
+}
@@ -0,0 +1,157 @@
+package notificator
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/mongodb/migrations"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const outboxCollection = "outbox"
+
+//go:generate mockgen -source=outbox_repository.go -destination=mocks/outbox_repository_mock.go
+type OutboxRepository interface {
+	// Enqueue saves new message, ErrAlreadyQueued is returned if message with the same idempotency key exists
+	Enqueue(ctx context.Context, msg *Message) error
+	// ClaimDue returns pending message which is due at now and hides it from other workers for lease,
+	// so a message of crashed worker is delivered again after lease. Nil is returned if there is nothing to send.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*Message, error)
+	SaveMessage(ctx context.Context, msg *Message) error
+	GetMessage(ctx context.Context, id string) (*Message, error)
+	GetMessages(ctx context.Context, status MessageStatus, limit int64) ([]*Message, error)
+	// CountPending returns number of messages waiting for an attempt
+	CountPending(ctx context.Context) (int64, error)
+}
+
+type outboxRepository struct {
+	db  *mongo.Database
+	log *logrus.Logger
+}
+
+func NewOutboxRepository(db *mongo.Database, log *logrus.Logger) (OutboxRepository, error) {
+	if db == nil {
+		return nil, errors.NewInternal("invalid db")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &outboxRepository{db: db, log: log}, nil
+}
+
+// CreateIndexes creates indexes of outbox, a message is enqueued only once by its idempotency key
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	mods := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "idempotency_key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+	}
+
+	return migrations.CreateIndexes(ctx, db.Collection(outboxCollection), mods...)
+}
+
+func (repo *outboxRepository) Enqueue(ctx context.Context, msg *Message) error {
+	if _, err := repo.db.Collection(outboxCollection).InsertOne(ctx, msg); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyQueued
+		}
+
+		repo.log.WithContext(ctx).Errorf("failed to insert message to outbox: %v", err)
+		return errors.NewInternal(err.Error())
+	}
+	return nil
+}
+
+func (repo *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*Message, error) {
+	var msg Message
+	err := repo.db.
+		Collection(outboxCollection).
+		FindOneAndUpdate(ctx,
+			bson.M{"status": MessagePending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+				SetReturnDocument(options.After)).
+		Decode(&msg)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+
+		repo.log.WithContext(ctx).Errorf("failed to claim outbox message: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return &msg, nil
+}
+
+func (repo *outboxRepository) SaveMessage(ctx context.Context, msg *Message) error {
+	res, err := repo.db.
+		Collection(outboxCollection).
+		ReplaceOne(ctx, bson.M{"_id": msg.ID}, msg)
+
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("failed to save outbox message '%s': %v", msg.ID.Hex(), err)
+		return errors.NewInternal(err.Error())
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrMessageNotFound
+	}
+	return nil
+}
+
+func (repo *outboxRepository) GetMessage(ctx context.Context, id string) (*Message, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	var msg Message
+	if err = repo.db.Collection(outboxCollection).FindOne(ctx, bson.M{"_id": objectID}).Decode(&msg); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrMessageNotFound
+		}
+
+		repo.log.WithContext(ctx).Errorf("unable to find outbox message due to internal error: %v; id: %s", err, id)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return &msg, nil
+}
+
+func (repo *outboxRepository) GetMessages(ctx context.Context, status MessageStatus, limit int64) ([]*Message, error) {
+	cursor, err := repo.db.
+		Collection(outboxCollection).
+		Find(ctx, bson.M{"status": status}, options.Find().
+			SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+			SetLimit(limit))
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to find outbox messages due to internal error: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	messages := make([]*Message, 0)
+	if err = cursor.All(ctx, &messages); err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to decode outbox messages: %v", err)
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	return messages, nil
+}
+
+func (repo *outboxRepository) CountPending(ctx context.Context) (int64, error) {
+	count, err := repo.db.
+		Collection(outboxCollection).
+		CountDocuments(ctx, bson.M{"status": MessagePending})
+	if err != nil {
+		repo.log.WithContext(ctx).Errorf("unable to count pending outbox messages: %v", err)
+		return 0, errors.NewInternal(err.Error())
+	}
+	return count, nil
+}
@@ -0,0 +1,221 @@
+package notificator
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/i18n"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Templates are compiled into the binary. Every event has html and plain text parts named
+// <event>.<version>.html and <event>.<version>.txt, which define "title" and "content" of the email.
+// Plain text part of the events which can be sent to other channels defines "summary" as well.
+// The parts are rendered into layout.html and layout.txt together with partials, e.g. header and footer.
+// Text of templates comes from i18n catalogs through "t" and "plural" functions, so templates don't
+// contain English text. An incompatible change of template data goes to a new version of the template.
+//
+//go:embed templates
+var templatesFS embed.FS
+
+// eventTemplate describes template of the event and sample data used to preview it
+type eventTemplate struct {
+	version string
+	sample  func() TemplateData
+}
+
+var sampleTime = time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+var eventTemplates = map[Event]eventTemplate{
+	EventVerifyEmail: {version: "v1", sample: func() TemplateData {
+		return &VerifyEmailData{Greeting: Greeting{Name: "Satoshi"}, Code: "123456", ExpiresInMinutes: 10}
+	}},
+	EventResetPassword: {version: "v1", sample: func() TemplateData {
+		return &ResetPasswordData{Greeting: Greeting{Name: "Satoshi"}, Code: "654321", ExpiresInMinutes: 5}
+	}},
+	EventNewLogin: {version: "v1", sample: func() TemplateData {
+		return &NewLoginData{
+			Greeting:  Greeting{Name: "Satoshi"},
+			IP:        "203.0.113.7",
+			UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) Firefox/96.0",
+			Time:      sampleTime,
+		}
+	}},
+	EventPasswordChanged: {version: "v1", sample: func() TemplateData {
+		return &PasswordChangedData{Greeting: Greeting{Name: "Satoshi"}, Time: sampleTime}
+	}},
+	EventTxSent: {version: "v1", sample: func() TemplateData {
+		return &TxSentData{
+			Greeting: Greeting{Name: "Satoshi"},
+			Chain:    "BTC",
+			Amount:   "0.015",
+			To:       "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+			TxHash:   "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16",
+		}
+	}},
+	EventTxReceived: {version: "v1", sample: func() TemplateData {
+		return &TxReceivedData{
+			Greeting: Greeting{Name: "Satoshi"},
+			Chain:    "ETH",
+			Amount:   "1.25",
+			From:     "0x52908400098527886E0F7030069857D2E4169EE7",
+			TxHash:   "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+		}
+	}},
+}
+
+// Rendered is a rendered notification of the event
+type Rendered struct {
+	// Template is a name and version of template the email was rendered with, e.g. "verify_email.v1"
+	Template string
+	// Language is a language the email was rendered in, e.g. "en"
+	Language string
+	Subject  string
+	HTML     string
+	Text     string
+	// Summary is a short text for chats and push notifications, it is empty if template has no "summary"
+	Summary string
+}
+
+type parsedTemplate struct {
+	name string
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// Registry keeps parsed templates of all events in all supported languages
+type Registry struct {
+	translations *i18n.Bundle
+	// templates by event and language
+	templates map[Event]map[string]*parsedTemplate
+}
+
+// NewRegistry parses templates of all events, so a broken template fails on start instead of sending
+func NewRegistry(translations *i18n.Bundle) (*Registry, error) {
+	if translations == nil {
+		return nil, errors.NewInternal("invalid translations")
+	}
+
+	registry := &Registry{
+		translations: translations,
+		templates:    make(map[Event]map[string]*parsedTemplate, len(eventTemplates)),
+	}
+	for event := range eventTemplates {
+		registry.templates[event] = make(map[string]*parsedTemplate)
+	}
+
+	for _, lang := range translations.Languages() {
+		funcs := templateFuncs(translations.Localizer(lang))
+
+		htmlBase, err := htmltemplate.New("base").Funcs(funcs).ParseFS(templatesFS, "templates/layout.html", "templates/partials/*.html")
+		if err != nil {
+			return nil, errors.NewInternal(err.Error())
+		}
+		textBase, err := texttemplate.New("base").Funcs(funcs).ParseFS(templatesFS, "templates/layout.txt", "templates/partials/*.txt")
+		if err != nil {
+			return nil, errors.NewInternal(err.Error())
+		}
+
+		for event, et := range eventTemplates {
+			name := string(event) + "." + et.version
+
+			htmlBaseClone, err := htmlBase.Clone()
+			if err != nil {
+				return nil, errors.NewInternal(err.Error())
+			}
+			htmlTmpl, err := htmlBaseClone.ParseFS(templatesFS, "templates/"+name+".html")
+			if err != nil {
+				return nil, errors.WithMessage(ErrTemplateNotFound, "%s: %v", name, err)
+			}
+
+			textBaseClone, err := textBase.Clone()
+			if err != nil {
+				return nil, errors.NewInternal(err.Error())
+			}
+			textTmpl, err := textBaseClone.ParseFS(templatesFS, "templates/"+name+".txt")
+			if err != nil {
+				return nil, errors.WithMessage(ErrTemplateNotFound, "%s: %v", name, err)
+			}
+
+			registry.templates[event][lang] = &parsedTemplate{name: name, html: htmlTmpl, text: textTmpl}
+		}
+	}
+
+	return registry, nil
+}
+
+// templateFuncs binds template functions to the language of localizer
+func templateFuncs(l *i18n.Localizer) map[string]interface{} {
+	return map[string]interface{}{
+		"lang":     l.Language,
+		"t":        l.T,
+		"plural":   l.Plural,
+		"number":   l.Number,
+		"date":     l.Date,
+		"datetime": l.DateTime,
+	}
+}
+
+// Events returns all events which have templates sorted by name
+func (r *Registry) Events() []Event {
+	events := make([]Event, 0, len(r.templates))
+	for event := range r.templates {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
+}
+
+// Sample returns sample data of the event template
+func (r *Registry) Sample(event Event) (TemplateData, error) {
+	et, ok := eventTemplates[event]
+	if !ok {
+		return nil, errors.WithMessage(ErrTemplateNotFound, "unknown event: %s", event)
+	}
+	return et.sample(), nil
+}
+
+// Render renders subject, html, plain text and summary of the event template in the best language
+// for the preferences given in order of priority, see i18n.Bundle.Localizer.
+func (r *Registry) Render(data TemplateData, languages ...string) (*Rendered, error) {
+	if data == nil {
+		return nil, errors.WithMessage(ErrTemplateNotFound, "template data should be not empty")
+	}
+
+	templates, ok := r.templates[data.Event()]
+	if !ok {
+		return nil, errors.WithMessage(ErrTemplateNotFound, "unknown event: %s", data.Event())
+	}
+
+	lang := r.translations.Localizer(languages...).Language()
+	tmpl := templates[lang]
+
+	var subject, html, text, summary bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "title", data); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "layout", data); err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+	if tmpl.text.Lookup("summary") != nil {
+		if err := tmpl.text.ExecuteTemplate(&summary, "summary", data); err != nil {
+			return nil, errors.NewInternal(err.Error())
+		}
+	}
+
+	return &Rendered{
+		Template: tmpl.name,
+		Language: lang,
+		Subject:  strings.TrimSpace(subject.String()),
+		HTML:     html.String(),
+		Text:     text.String(),
+		Summary:  strings.TrimSpace(summary.String()),
+	}, nil
+}
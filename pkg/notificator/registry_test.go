@@ -0,0 +1,158 @@
+package notificator_test
+
+import (
+	"nnw_s/pkg/i18n"
+	"nnw_s/pkg/notificator"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type unknownData struct {
+	notificator.Greeting
+}
+
+func (*unknownData) Event() notificator.Event { return "unknown" }
+
+func newRegistry(t *testing.T) *notificator.Registry {
+	translations, err := i18n.NewBundle()
+	require.Nil(t, err)
+
+	registry, err := notificator.NewRegistry(translations)
+	require.Nil(t, err)
+	return registry
+}
+
+func TestRegistryRender(t *testing.T) {
+	registry := newRegistry(t)
+
+	events := registry.Events()
+	require.NotEmpty(t, events)
+
+	titles := map[string]notificator.Event{}
+	for _, event := range events {
+		t.Run(string(event), func(t *testing.T) {
+			data, err := registry.Sample(event)
+			require.Nil(t, err)
+			data.SetName("Satoshi")
+
+			rendered, err := registry.Render(data)
+			require.Nil(t, err)
+
+			assert.Equal(t, string(event)+".v1", rendered.Template)
+			assert.Equal(t, i18n.DefaultLanguage, rendered.Language)
+			assert.NotEmpty(t, rendered.Subject)
+			assert.NotContains(t, rendered.Subject, "\n")
+
+			// every event has its own title in the page, header and plain text part
+			assert.Contains(t, rendered.HTML, "<title>"+rendered.Subject+"</title>")
+			assert.Contains(t, rendered.Text, rendered.Subject)
+			assert.Contains(t, rendered.HTML, "Hi, Satoshi!")
+			assert.Contains(t, rendered.Text, "Hi, Satoshi!")
+
+			// branding partials
+			assert.Contains(t, rendered.HTML, "NoName Wallet</h1>")
+			assert.Contains(t, rendered.Text, "https://nonamewallet.vercel.app/")
+
+			other, ok := titles[rendered.Subject]
+			assert.False(t, ok, "event %s has the same title as %s", event, other)
+			titles[rendered.Subject] = event
+		})
+	}
+
+	t.Run("should render every event in every language", func(t *testing.T) {
+		for _, event := range events {
+			for _, lang := range []string{"ru", "uk"} {
+				data, err := registry.Sample(event)
+				require.Nil(t, err)
+
+				rendered, err := registry.Render(data, lang)
+				require.Nil(t, err)
+				assert.Equal(t, lang, rendered.Language)
+				assert.Contains(t, rendered.HTML, `<html lang="`+lang+`">`)
+
+				// text of templates comes from catalogs, missing message would be rendered as its key
+				assert.NotContains(t, rendered.Text, string(event)+".")
+				assert.NotContains(t, rendered.Text, "footer.")
+			}
+		}
+	})
+
+	t.Run("should render email in preferred language", func(t *testing.T) {
+		data := &notificator.ResetPasswordData{Greeting: notificator.Greeting{Name: "Тарас"}, Code: "654321", ExpiresInMinutes: 5}
+
+		rendered, err := registry.Render(data, "", "uk-UA,uk;q=0.9,en;q=0.8")
+		require.Nil(t, err)
+		assert.Equal(t, "uk", rendered.Language)
+		assert.Equal(t, "Скидання пароля", rendered.Subject)
+		assert.Contains(t, rendered.Text, "Вітаємо, Тарас!")
+		assert.Contains(t, rendered.Text, "Код дійсний 5 хвилин.")
+
+		// stored preference goes before Accept-Language
+		rendered, err = registry.Render(data, "ru", "uk")
+		require.Nil(t, err)
+		assert.Equal(t, "Сброс пароля", rendered.Subject)
+
+		rendered, err = registry.Render(data, "de-DE")
+		require.Nil(t, err)
+		assert.Equal(t, "Reset your password", rendered.Subject)
+		assert.Contains(t, rendered.Text, "The code expires in 5 minutes.")
+	})
+
+	t.Run("should format date in language of email", func(t *testing.T) {
+		data := &notificator.PasswordChangedData{Time: time.Date(2022, 3, 4, 5, 6, 0, 0, time.UTC)}
+
+		rendered, err := registry.Render(data, "ru")
+		require.Nil(t, err)
+		assert.True(t, strings.Contains(rendered.Text, "04.03.2022 05:06 UTC"), rendered.Text)
+
+		rendered, err = registry.Render(data, "en")
+		require.Nil(t, err)
+		assert.True(t, strings.Contains(rendered.Text, "Mar 4, 2022 05:06 UTC"), rendered.Text)
+	})
+
+	t.Run("should not greet recipient without name", func(t *testing.T) {
+		rendered, err := registry.Render(&notificator.VerifyEmailData{Code: "123456"})
+		require.Nil(t, err)
+		assert.NotContains(t, rendered.HTML, "Hi,")
+		assert.NotContains(t, rendered.Text, "Hi,")
+		assert.Contains(t, rendered.Text, "123456")
+	})
+
+	t.Run("should not mention destination address if it is unknown", func(t *testing.T) {
+		rendered, err := registry.Render(&notificator.TxSentData{Chain: "BTC", Amount: "0.5", TxHash: "hash"})
+		require.Nil(t, err)
+		assert.Contains(t, rendered.Text, "You sent 0.5 BTC.")
+		assert.Contains(t, rendered.HTML, "You sent 0.5 BTC.")
+	})
+
+	t.Run("should show anti-phishing phrase in every part of email", func(t *testing.T) {
+		data := &notificator.NewLoginData{IP: "127.0.0.1", Time: time.Now()}
+
+		rendered, err := registry.Render(data)
+		require.Nil(t, err)
+		assert.Contains(t, rendered.Text, "Set an anti-phishing code")
+		assert.NotContains(t, rendered.Text, "Your anti-phishing code:")
+
+		data.SetAntiPhishingPhrase("<blue whale>")
+		rendered, err = registry.Render(data)
+		require.Nil(t, err)
+		assert.Contains(t, rendered.Text, "Your anti-phishing code: <blue whale>")
+		assert.Contains(t, rendered.HTML, "&lt;blue whale&gt;</span>")
+		assert.NotContains(t, rendered.Text, "Set an anti-phishing code")
+	})
+
+	t.Run("should return 'template not found' error", func(t *testing.T) {
+		_, err := registry.Render(&unknownData{})
+		assert.Contains(t, err.Error(), string(notificator.StatusTemplateNotFound))
+
+		_, err = registry.Render(nil)
+		assert.Contains(t, err.Error(), string(notificator.StatusTemplateNotFound))
+
+		_, err = registry.Sample("unknown")
+		assert.Contains(t, err.Error(), string(notificator.StatusTemplateNotFound))
+	})
+}
@@ -1,98 +1,204 @@
 package notificator
 
 import (
-	"bytes"
 	"context"
-	"fmt"
+	"nnw_s/pkg/clock"
 	"nnw_s/pkg/errors"
-	"nnw_s/pkg/smtp"
-	"os"
-	"path"
-	"text/template"
-	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-//go:generate mockgen -source=service.go -destination=mocks/service_mock.go
-const (
-	mimeHeaders      = "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-	sendEmailTimeout = 5 * time.Second
-)
+const defaultMessagesLimit = 100
 
+//go:generate mockgen -source=service.go -destination=mocks/service_mock.go
 type Service interface {
-	SendEmail(ctx context.Context, email *Email) error
+	// QueueEmail renders email and puts it to the outbox, it is delivered later by Worker
+	QueueEmail(ctx context.Context, email *Email) error
+	// Notify renders notification and puts a message to the outbox for every chosen channel of the recipient
+	Notify(ctx context.Context, n *Notification) error
+
+	GetMessages(ctx context.Context, status MessageStatus) ([]*MessageDTO, error)
+	RequeueMessage(ctx context.Context, id string) (*MessageDTO, error)
 }
 
 type service struct {
-	log        *logrus.Logger
-	smtpClient *smtp.Client
+	log       *logrus.Logger
+	outbox    OutboxRepository
+	templates *Registry
+	clock     clock.Clock
 }
 
-func NewService(log *logrus.Logger, smtpClient *smtp.Client) (Service, error) {
+func NewService(log *logrus.Logger, outbox OutboxRepository, templates *Registry, clk clock.Clock) (Service, error) {
 	if log == nil {
 		return nil, errors.NewInternal("invalid logger")
 	}
-	if smtpClient == nil {
-		return nil, errors.NewInternal("invalid SMTP client")
+	if outbox == nil {
+		return nil, errors.NewInternal("invalid outbox repository")
 	}
-	return &service{log: log, smtpClient: smtpClient}, nil
+	if templates == nil {
+		return nil, errors.NewInternal("invalid template registry")
+	}
+	if clk == nil {
+		return nil, errors.NewInternal("invalid clock")
+	}
+	return &service{log: log, outbox: outbox, templates: templates, clock: clk}, nil
 }
 
-func (svc *service) SendEmail(ctx context.Context, email *Email) error {
-	dir, err := os.Getwd()
+func (svc *service) QueueEmail(ctx context.Context, email *Email) error {
+	rendered, err := svc.templates.Render(email.Data, email.Languages...)
 	if err != nil {
+		svc.log.WithContext(ctx).Errorf("failed to render email '%s': %v", email.IdempotencyKey, err)
 		return err
 	}
 
-	// If you run it locally and through terminal please uncomment this and comment 52 line.
-	//root := filepath.Dir(dir)
-	//t, err := template.ParseFiles(path.Join(root, "templates/"+email.Template))
+	msg, err := svc.composeEmail(ctx, email.IdempotencyKey, email.Sender, email.Recipient, rendered)
+	if err != nil {
+		return err
+	}
+
+	return svc.enqueue(ctx, msg)
+}
 
-	t, err := template.ParseFiles(path.Join(dir, "templates/"+email.Template))
+func (svc *service) Notify(ctx context.Context, n *Notification) error {
+	if n.Recipient == nil || n.Data == nil {
+		return errors.WithMessage(ErrInvalidMessage, "recipient and data should be not empty")
+	}
+
+	// other channels are rendered without the phrase, even if the caller has set it
+	n.Data.SetAntiPhishingPhrase("")
+	rendered, err := svc.templates.Render(n.Data, n.Recipient.Languages...)
 	if err != nil {
-		return errors.NewInternal(err.Error())
+		svc.log.WithContext(ctx).Errorf("failed to render notification '%s': %v", n.IdempotencyKey, err)
+		return err
 	}
 
-	var body bytes.Buffer
+	for _, channel := range eventChannels(n.Data.Event(), n.Recipient.Channels) {
+		addresses := n.Recipient.address(channel)
+		if len(addresses) == 0 {
+			svc.log.WithContext(ctx).Infof("skip notification '%s' to %s: recipient has no address", n.IdempotencyKey, channel)
+			continue
+		}
 
-	_, err = body.Write([]byte(fmt.Sprintf("Subject: %s\n%s\n\n", email.Subject, mimeHeaders)))
+		key := n.IdempotencyKey + ":" + string(channel)
+
+		var msg *Message
+		if channel == ChannelEmail {
+			msg, err = svc.composeNotificationEmail(ctx, key, n, addresses[0])
+		} else {
+			msg, err = svc.composeMessage(key, channel, n, addresses, rendered)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = svc.enqueue(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// composeEmail composes multipart email of the rendered template
+func (svc *service) composeEmail(ctx context.Context, key, sender, recipient string, rendered *Rendered) (*Message, error) {
+	m := mailMessage{
+		From:      sender,
+		To:        []string{recipient},
+		Subject:   rendered.Subject,
+		HTML:      rendered.HTML,
+		Text:      rendered.Text,
+		Date:      svc.clock.Now(),
+		MessageID: newMessageID(sender),
+	}
+
+	body, err := m.Bytes()
 	if err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to write subject to email body: %v", err)
-		return errors.NewInternal(err.Error())
+		svc.log.WithContext(ctx).Errorf("failed to compose email: %v", err)
+		return nil, errors.NewInternal(err.Error())
 	}
 
-	if err := t.Execute(&body, &email.Data); err != nil {
-		svc.log.WithContext(ctx).Errorf("failed to write template data to email body: %v", err)
-		return errors.NewInternal(err.Error())
+	msg, err := NewMessage(key, sender, []string{recipient}, rendered.Subject, string(body))
+	if err != nil {
+		return nil, err
 	}
 
-	newCtx, cancel := context.WithTimeout(ctx, sendEmailTimeout)
-	defer cancel()
+	msg.Template = rendered.Template
+	return msg, nil
+}
 
-	done := make(chan struct{}, 1)
-	sendEmailErr := make(chan error, 1)
+// composeNotificationEmail renders the notification with recipient's anti-phishing phrase and composes email of it
+func (svc *service) composeNotificationEmail(ctx context.Context, key string, n *Notification, recipient string) (*Message, error) {
+	n.Data.SetAntiPhishingPhrase(n.Recipient.AntiPhishingPhrase)
+	defer n.Data.SetAntiPhishingPhrase("")
 
-	go func() {
-		select {
-		case <-newCtx.Done():
-			sendEmailErr <- errors.NewInternal("failed to send email due to timeout")
-			return
-		default:
-			if err := svc.smtpClient.SendMail(email.Sender, []string{email.Recipient}, body.Bytes()); err != nil {
-				svc.log.WithContext(ctx).Errorf("failed to send email: %v", err)
-				sendEmailErr <- err
-				return
-			}
-		}
+	rendered, err := svc.templates.Render(n.Data, n.Recipient.Languages...)
+	if err != nil {
+		svc.log.WithContext(ctx).Errorf("failed to render notification '%s': %v", n.IdempotencyKey, err)
+		return nil, err
+	}
+	return svc.composeEmail(ctx, key, n.Sender, recipient, rendered)
+}
+
+// composeMessage composes message of the channel other than email
+func (svc *service) composeMessage(key string, channel Channel, n *Notification, addresses []string, rendered *Rendered) (*Message, error) {
+	subject, body, err := channelBody(channel, n, rendered, svc.clock.Now())
+	if err != nil {
+		return nil, errors.NewInternal(err.Error())
+	}
+
+	msg, err := NewMessage(key, n.Sender, addresses, subject, body)
+	if err != nil {
+		return nil, err
+	}
 
-		done <- struct{}{}
-	}()
+	msg.Channel = channel
+	msg.Template = rendered.Template
+	return msg, nil
+}
 
-	select {
-	case <-done:
-		return nil
-	case err = <-sendEmailErr:
+// enqueue puts message to the outbox, message which is already queued is not an error
+func (svc *service) enqueue(ctx context.Context, msg *Message) error {
+	if err := svc.outbox.Enqueue(ctx, msg); err != nil {
+		if err == ErrAlreadyQueued {
+			svc.log.WithContext(ctx).Infof("message '%s' is already queued", msg.IdempotencyKey)
+			return nil
+		}
+
+		svc.log.WithContext(ctx).Errorf("failed to queue message: %v", err)
 		return err
 	}
+	return nil
+}
+
+func (svc *service) GetMessages(ctx context.Context, status MessageStatus) ([]*MessageDTO, error) {
+	switch status {
+	case MessagePending, MessageSent, MessageDead:
+	default:
+		return nil, errors.WithMessage(ErrInvalidStatus, "unknown status: %s", status)
+	}
+
+	messages, err := svc.outbox.GetMessages(ctx, status, defaultMessagesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapMessagesToDTO(messages), nil
+}
+
+func (svc *service) RequeueMessage(ctx context.Context, id string) (*MessageDTO, error) {
+	msg, err := svc.outbox.GetMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = msg.Requeue(svc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	if err = svc.outbox.SaveMessage(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	svc.log.WithContext(ctx).Infof("outbox message '%s' is requeued", id)
+	return MapMessageToDTO(msg), nil
 }
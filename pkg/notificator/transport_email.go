@@ -0,0 +1,46 @@
+package notificator
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/smtp"
+)
+
+// Sender delivers composed email, it is implemented by smtp.Client
+type Sender interface {
+	SendMail(from string, to []string, msg []byte) error
+}
+
+type emailTransport struct {
+	sender Sender
+}
+
+// NewEmailTransport returns transport which sends email messages through the sender
+func NewEmailTransport(sender Sender) (Transport, error) {
+	if sender == nil {
+		return nil, errors.NewInternal("invalid sender")
+	}
+	return &emailTransport{sender: sender}, nil
+}
+
+func (t *emailTransport) Send(ctx context.Context, msg *Message) error {
+	// smtp client doesn't accept context, so it is abandoned on timeout
+	sendErr := make(chan error, 1)
+	go func() {
+		sendErr <- t.sender.SendMail(msg.Sender, msg.Recipients, []byte(msg.Body))
+	}()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send email due to timeout: %w", ctx.Err())
+	case err := <-sendErr:
+		// email rejected by the server won't be accepted on retry
+		var smtpErr *smtp.Error
+		if stderrors.As(err, &smtpErr) && !smtpErr.Temporary() {
+			return Permanent(err)
+		}
+		return err
+	}
+}
@@ -0,0 +1,62 @@
+package notificator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"nnw_s/pkg/errors"
+)
+
+const defaultPushURL = "https://fcm.googleapis.com/fcm/send"
+
+type pushTransport struct {
+	url       string
+	serverKey string
+	client    *http.Client
+}
+
+// NewPushTransport returns transport which sends mobile push notifications through FCM compatible
+// HTTP API, recipients of the message are device tokens. Empty url means Firebase Cloud Messaging.
+func NewPushTransport(url, serverKey string, client *http.Client) (Transport, error) {
+	if serverKey == "" {
+		return nil, errors.NewInternal("invalid push server key")
+	}
+	if client == nil {
+		return nil, errors.NewInternal("invalid http client")
+	}
+	if url == "" {
+		url = defaultPushURL
+	}
+	return &pushTransport{url: url, serverKey: serverKey, client: client}, nil
+}
+
+type pushMessage struct {
+	RegistrationIDs []string          `json:"registration_ids"`
+	Notification    pushNotification  `json:"notification"`
+	Data            map[string]string `json:"data,omitempty"`
+}
+
+type pushNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func (t *pushTransport) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(&pushMessage{
+		RegistrationIDs: msg.Recipients,
+		Notification:    pushNotification{Title: msg.Subject, Body: msg.Body},
+		Data:            map[string]string{"template": msg.Template},
+	})
+	if err != nil {
+		return Permanent(err)
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "key="+t.serverKey)
+
+	if err = postJSON(ctx, t.client, t.url, header, body); err != nil {
+		return fmt.Errorf("failed to send push notification: %w", err)
+	}
+	return nil
+}
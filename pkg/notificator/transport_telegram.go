@@ -0,0 +1,55 @@
+package notificator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"nnw_s/pkg/errors"
+	"strings"
+)
+
+const defaultTelegramURL = "https://api.telegram.org"
+
+type telegramTransport struct {
+	url    string
+	client *http.Client
+}
+
+// NewTelegramTransport returns transport which sends messages to telegram chats by the bot,
+// recipient of the message is a chat id. Empty baseURL means Telegram Bot API.
+func NewTelegramTransport(baseURL, botToken string, client *http.Client) (Transport, error) {
+	if botToken == "" {
+		return nil, errors.NewInternal("invalid telegram bot token")
+	}
+	if client == nil {
+		return nil, errors.NewInternal("invalid http client")
+	}
+	if baseURL == "" {
+		baseURL = defaultTelegramURL
+	}
+	return &telegramTransport{
+		url:    strings.TrimSuffix(baseURL, "/") + "/bot" + botToken + "/sendMessage",
+		client: client,
+	}, nil
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+func (t *telegramTransport) Send(ctx context.Context, msg *Message) error {
+	for _, chatID := range msg.Recipients {
+		body, err := json.Marshal(&telegramMessage{ChatID: chatID, Text: msg.Body, DisableWebPagePreview: true})
+		if err != nil {
+			return Permanent(err)
+		}
+
+		if err = postJSON(ctx, t.client, t.url, nil, body); err != nil {
+			return fmt.Errorf("failed to send telegram message: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package notificator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"nnw_s/pkg/errors"
+)
+
+// headerIdempotencyKey lets receivers of webhooks drop retried deliveries
+const headerIdempotencyKey = "Idempotency-Key"
+
+type webhookTransport struct {
+	client *http.Client
+}
+
+// NewWebhookTransport returns transport which posts json body of the message to the recipient url
+func NewWebhookTransport(client *http.Client) (Transport, error) {
+	if client == nil {
+		return nil, errors.NewInternal("invalid http client")
+	}
+	return &webhookTransport{client: client}, nil
+}
+
+func (t *webhookTransport) Send(ctx context.Context, msg *Message) error {
+	header := http.Header{}
+	header.Set(headerIdempotencyKey, msg.IdempotencyKey)
+
+	for _, url := range msg.Recipients {
+		if err := postJSON(ctx, t.client, url, header, []byte(msg.Body)); err != nil {
+			return fmt.Errorf("failed to send webhook: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package notificator
+
+import (
+	"context"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/metrics"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultMaxAttempts  = 8
+	defaultBaseBackoff  = 30 * time.Second
+	defaultMaxBackoff   = time.Hour
+	defaultSendTimeout  = 30 * time.Second
+)
+
+type WorkerOptions struct {
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	SendTimeout  time.Duration
+}
+
+// Worker delivers messages from the outbox through transports of their channels, failed messages
+// are retried with exponential backoff and become dead after MaxAttempts or on permanent error
+type Worker struct {
+	outbox     OutboxRepository
+	transports map[Channel]Transport
+	clock      clock.Clock
+	opts       WorkerOptions
+
+	log *logrus.Logger
+}
+
+func NewWorker(log *logrus.Logger, outbox OutboxRepository, transports map[Channel]Transport, clk clock.Clock, opts WorkerOptions) (*Worker, error) {
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	if outbox == nil {
+		return nil, errors.NewInternal("invalid outbox repository")
+	}
+	if len(transports) == 0 {
+		return nil, errors.NewInternal("invalid transports")
+	}
+	if clk == nil {
+		return nil, errors.NewInternal("invalid clock")
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = defaultBaseBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultMaxBackoff
+	}
+	if opts.SendTimeout <= 0 {
+		opts.SendTimeout = defaultSendTimeout
+	}
+
+	return &Worker{outbox: outbox, transports: transports, clock: clk, opts: opts, log: log}, nil
+}
+
+// Run delivers due messages until ctx is done
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.ProcessDue(ctx); err != nil {
+			w.log.WithContext(ctx).Errorf("failed to process outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue delivers all messages which are due now and returns number of processed messages
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	var processed int
+	for ctx.Err() == nil {
+		// lease is longer than send timeout, so message is not claimed twice while it is being sent
+		msg, err := w.outbox.ClaimDue(ctx, w.clock.Now(), 2*w.opts.SendTimeout)
+		if err != nil {
+			return processed, err
+		}
+		if msg == nil {
+			return processed, nil
+		}
+
+		if err = w.deliver(ctx, msg); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (w *Worker) deliver(ctx context.Context, msg *Message) error {
+	sendErr := w.send(ctx, msg)
+	now := w.clock.Now()
+	metrics.Notifications.WithLabelValues(string(msg.GetChannel()), metrics.Result(sendErr)).Inc()
+
+	if sendErr == nil {
+		msg.MarkSent(now)
+		w.log.WithContext(ctx).Infof("outbox message '%s' is sent", msg.IdempotencyKey)
+		return w.outbox.SaveMessage(ctx, msg)
+	}
+
+	dead := msg.Attempts+1 >= w.opts.MaxAttempts || IsPermanent(sendErr)
+	msg.MarkFailed(now, sendErr, now.Add(w.backoff(msg.Attempts+1)), dead)
+
+	if dead {
+		w.log.WithContext(ctx).Errorf("outbox message '%s' is dead after %d attempts: %v", msg.IdempotencyKey, msg.Attempts, sendErr)
+	} else {
+		w.log.WithContext(ctx).Warnf("failed to send outbox message '%s', attempt %d: %v", msg.IdempotencyKey, msg.Attempts, sendErr)
+	}
+	return w.outbox.SaveMessage(ctx, msg)
+}
+
+func (w *Worker) send(ctx context.Context, msg *Message) error {
+	transport, ok := w.transports[msg.GetChannel()]
+	if !ok {
+		return errNoTransport(msg.GetChannel())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.opts.SendTimeout)
+	defer cancel()
+
+	return transport.Send(ctx, msg)
+}
+
+// backoff returns delay before the next attempt: base, 2*base, 4*base... but not more than max
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.opts.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= w.opts.MaxBackoff {
+			return w.opts.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,185 @@
+package notificator_test
+
+import (
+	"context"
+	"fmt"
+	"nnw_s/pkg/clock"
+	"nnw_s/pkg/notificator"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSender struct {
+	mu   sync.Mutex
+	err  error
+	sent [][]byte
+}
+
+func (s *testSender) SendMail(_ string, _ []string, msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestWorker(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, sender *testSender) (notificator.OutboxRepository, *notificator.Worker, *clock.Mock, *notificator.Message) {
+		outbox, err := notificator.NewOutboxMemoryRepository()
+		require.Nil(t, err)
+
+		msg, err := notificator.NewMessage("key", "from@mail.com", []string{"to@mail.com"}, "subject", "body")
+		require.Nil(t, err)
+		require.Nil(t, outbox.Enqueue(ctx, msg))
+
+		email, err := notificator.NewEmailTransport(sender)
+		require.Nil(t, err)
+
+		clk := clock.NewMock(msg.CreatedAt)
+		transports := map[notificator.Channel]notificator.Transport{notificator.ChannelEmail: email}
+		worker, err := notificator.NewWorker(logrus.New(), outbox, transports, clk, notificator.WorkerOptions{
+			MaxAttempts: 3,
+			BaseBackoff: time.Minute,
+			MaxBackoff:  time.Hour,
+		})
+		require.Nil(t, err)
+
+		return outbox, worker, clk, msg
+	}
+
+	t.Run("should send due message once", func(t *testing.T) {
+		sender := &testSender{}
+		outbox, worker, _, msg := setup(t, sender)
+
+		processed, err := worker.ProcessDue(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 1, processed)
+
+		processed, err = worker.ProcessDue(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 0, processed)
+
+		loaded, err := outbox.GetMessage(ctx, msg.ID.Hex())
+		require.Nil(t, err)
+		assert.Equal(t, notificator.MessageSent, loaded.Status)
+		assert.Equal(t, [][]byte{[]byte("body")}, sender.sent)
+	})
+
+	t.Run("should retry with exponential backoff and move message to dead letter", func(t *testing.T) {
+		sender := &testSender{err: fmt.Errorf("smtp is down")}
+		outbox, worker, clk, msg := setup(t, sender)
+
+		for attempt, backoff := range []time.Duration{time.Minute, 2 * time.Minute} {
+			_, err := worker.ProcessDue(ctx)
+			require.Nil(t, err)
+
+			loaded, err := outbox.GetMessage(ctx, msg.ID.Hex())
+			require.Nil(t, err)
+			assert.Equal(t, notificator.MessagePending, loaded.Status)
+			assert.Equal(t, attempt+1, loaded.Attempts)
+			assert.Equal(t, "smtp is down", loaded.LastError)
+
+			// message is not sent again before backoff is passed
+			clk.Add(backoff - time.Second)
+			processed, err := worker.ProcessDue(ctx)
+			require.Nil(t, err)
+			assert.Equal(t, 0, processed)
+			clk.Add(time.Second)
+		}
+
+		_, err := worker.ProcessDue(ctx)
+		require.Nil(t, err)
+
+		dead, err := outbox.GetMessages(ctx, notificator.MessageDead, 10)
+		require.Nil(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, 3, dead[0].Attempts)
+
+		// dead message is not sent anymore
+		clk.Add(24 * time.Hour)
+		processed, err := worker.ProcessDue(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 0, processed)
+	})
+
+	t.Run("should not retry message on permanent error", func(t *testing.T) {
+		sender := &testSender{err: notificator.Permanent(fmt.Errorf("mailbox does not exist"))}
+		outbox, worker, _, msg := setup(t, sender)
+
+		_, err := worker.ProcessDue(ctx)
+		require.Nil(t, err)
+
+		loaded, err := outbox.GetMessage(ctx, msg.ID.Hex())
+		require.Nil(t, err)
+		assert.Equal(t, notificator.MessageDead, loaded.Status)
+		assert.Equal(t, 1, loaded.Attempts)
+	})
+
+	t.Run("should move message of not configured channel to dead letter", func(t *testing.T) {
+		outbox, worker, clk, _ := setup(t, &testSender{})
+
+		msg, err := notificator.NewMessage("telegram_key", "from@mail.com", []string{"42"}, "subject", "body")
+		require.Nil(t, err)
+		msg.Channel = notificator.ChannelTelegram
+		require.Nil(t, outbox.Enqueue(ctx, msg))
+
+		// message is created after the clock was set, so it becomes due a bit later
+		clk.Set(msg.CreatedAt)
+
+		_, err = worker.ProcessDue(ctx)
+		require.Nil(t, err)
+
+		loaded, err := outbox.GetMessage(ctx, msg.ID.Hex())
+		require.Nil(t, err)
+		assert.Equal(t, notificator.MessageDead, loaded.Status)
+		assert.Equal(t, "channel 'telegram' is not configured", loaded.LastError)
+	})
+}
+
+func TestOutboxQueue(t *testing.T) {
+	ctx := context.Background()
+
+	outbox, err := notificator.NewOutboxMemoryRepository()
+	require.Nil(t, err)
+
+	svc, err := notificator.NewService(logrus.New(), outbox, newRegistry(t), clock.New())
+	require.Nil(t, err)
+
+	msg, err := notificator.NewMessage("key", "from@mail.com", []string{"to@mail.com"}, "subject", "body")
+	require.Nil(t, err)
+	require.Nil(t, outbox.Enqueue(ctx, msg))
+
+	t.Run("should not queue message with the same idempotency key twice", func(t *testing.T) {
+		duplicate, err := notificator.NewMessage("key", "from@mail.com", []string{"to@mail.com"}, "subject", "body")
+		require.Nil(t, err)
+		assert.Equal(t, notificator.ErrAlreadyQueued, outbox.Enqueue(ctx, duplicate))
+	})
+
+	t.Run("should requeue only dead message", func(t *testing.T) {
+		_, err := svc.RequeueMessage(ctx, msg.ID.Hex())
+		assert.Equal(t, notificator.ErrMessageNotFailed, err)
+
+		msg.MarkFailed(time.Now(), fmt.Errorf("smtp is down"), time.Now(), true)
+		require.Nil(t, outbox.SaveMessage(ctx, msg))
+
+		requeued, err := svc.RequeueMessage(ctx, msg.ID.Hex())
+		require.Nil(t, err)
+		assert.Equal(t, string(notificator.MessagePending), requeued.Status)
+		assert.Equal(t, 0, requeued.Attempts)
+	})
+
+	t.Run("should return 'message not found' error", func(t *testing.T) {
+		_, err := svc.RequeueMessage(ctx, "not_existent_id")
+		assert.Equal(t, notificator.ErrMessageNotFound, err)
+	})
+}
@@ -0,0 +1,115 @@
+// Package openapi validates requests against OpenAPI specification of the API.
+package openapi
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+)
+
+const StatusInvalidRequest errors.Status = "invalid_request"
+
+var ErrInvalidRequest = errors.New(codes.BadRequest, StatusInvalidRequest)
+
+// bodyField names invalid fields of the whole body, e.g. missing body
+const bodyField = "body"
+
+// Middleware validates requests of operations of spec before they are handled, requests of routes
+// which are not in spec are passed as is. Invalid request is rejected with ErrInvalidRequest which lists
+// every invalid field, reason of a field is a failed keyword of its schema, e.g. required or maxLength.
+func Middleware(spec *openapi3.T) (echo.MiddlewareFunc, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		MultiError: true,
+		// users are authorized by JWT of request by handlers
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route, pathParams, err := router.FindRoute(req)
+			if err != nil {
+				return next(c)
+			}
+
+			// body is read by validation and put back, so handler binds it again
+			if err := openapi3filter.ValidateRequest(req.Context(), &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}); err != nil {
+				return invalidRequest(err)
+			}
+			return next(c)
+		}
+	}, nil
+}
+
+// invalidRequest returns ErrInvalidRequest with invalid fields of err. Message of err is not returned,
+// it contains values of the request, e.g. passwords.
+func invalidRequest(err error) error {
+	fields := fieldErrors(err)
+	if len(fields) == 0 {
+		return errors.WithMessage(ErrInvalidRequest, "request does not match api specification")
+	}
+
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Field)
+	}
+	return errors.WithFields(errors.WithMessage(ErrInvalidRequest, "invalid fields: %s", strings.Join(names, ", ")), fields...)
+}
+
+func fieldErrors(err error) []errors.FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var fields []errors.FieldError
+		for _, err := range e {
+			fields = append(fields, fieldErrors(err)...)
+		}
+		return fields
+
+	case *openapi3filter.RequestError:
+		if e.Parameter == nil {
+			if e.Err == openapi3filter.ErrInvalidRequired {
+				return []errors.FieldError{{Field: bodyField, Reason: "required"}}
+			}
+			return fieldErrors(e.Err)
+		}
+
+		if e.Err == openapi3filter.ErrInvalidRequired {
+			return []errors.FieldError{{Field: e.Parameter.Name, Reason: "required"}}
+		}
+		fields := fieldErrors(e.Err)
+		if len(fields) == 0 {
+			return []errors.FieldError{{Field: e.Parameter.Name, Reason: "invalid"}}
+		}
+		for i := range fields {
+			if fields[i].Field == bodyField {
+				fields[i].Field = e.Parameter.Name
+			} else {
+				fields[i].Field = e.Parameter.Name + "." + fields[i].Field
+			}
+		}
+		return fields
+
+	case *openapi3.SchemaError:
+		field := strings.Join(e.JSONPointer(), ".")
+		if field == "" {
+			field = bodyField
+		}
+		return []errors.FieldError{{Field: field, Reason: e.SchemaField}}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/openapi"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /api/v1/rename-wallet:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [jwt, wallet_id]
+              properties:
+                jwt:
+                  type: string
+                wallet_id:
+                  type: string
+                label:
+                  type: string
+                  maxLength: 5
+      responses:
+        "200":
+          description: renamed wallet
+  /api/v1/profile:
+    get:
+      parameters:
+        - name: jwt
+          in: query
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: profile
+`
+
+func TestMiddleware(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	require.Nil(t, err)
+	require.Nil(t, doc.Validate(context.Background()))
+
+	middleware, err := openapi.Middleware(doc)
+	require.Nil(t, err)
+
+	var body string
+	router := echo.New()
+	router.HTTPErrorHandler = errors.HTTPErrorHandler(logrus.New())
+	router.Use(middleware)
+	router.POST("/api/v1/rename-wallet", func(c echo.Context) error {
+		buf, _ := ioutil.ReadAll(c.Request().Body)
+		body = string(buf)
+		return c.NoContent(http.StatusOK)
+	})
+	router.GET("/api/v1/profile", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	router.GET("/healthz", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	post := func(payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/rename-wallet", strings.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should pass valid request with its body", func(t *testing.T) {
+		payload := `{"jwt":"token","wallet_id":"1","label":"main"}`
+		rec := post(payload)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, payload, body)
+	})
+
+	t.Run("should reject request with every invalid field", func(t *testing.T) {
+		rec := post(`{"jwt":"token","label":"savings"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errors.MIMEProblemJSON, rec.Header().Get(echo.HeaderContentType))
+
+		var problem errors.Problem
+		require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, openapi.StatusInvalidRequest, problem.Code)
+		assert.ElementsMatch(t, []errors.FieldError{
+			{Field: "wallet_id", Reason: "required"},
+			{Field: "label", Reason: "maxLength"},
+		}, problem.InvalidParams)
+		assert.NotContains(t, rec.Body.String(), "savings", "values of request are not returned")
+	})
+
+	t.Run("should reject request without body", func(t *testing.T) {
+		rec := post("")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `{"field":"body","reason":"required"}`)
+	})
+
+	t.Run("should reject request without required query", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `{"field":"jwt","reason":"required"}`)
+	})
+
+	t.Run("should pass routes which are not in specification", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,59 @@
+package outbound_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/pkg/outbound"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowed(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, outbound.Allowed(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "203.0.113.7", "2606:4700:4700::1111"} {
+		assert.True(t, outbound.Allowed(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, u := range []string{"https://example.com/hook", "https://203.0.113.7:8443/hook"} {
+		assert.Nil(t, outbound.ValidateURL(u), u)
+	}
+	for _, u := range []string{
+		"http://example.com/hook",
+		"/hook",
+		"https://",
+		"https://localhost/hook",
+		"https://LOCALHOST./hook",
+		"https://127.0.0.1/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/hook",
+		"https://10.0.0.1/hook",
+	} {
+		assert.NotNil(t, outbound.ValidateURL(u), u)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("should not connect to private address", func(t *testing.T) {
+		var called bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		resp, err := outbound.NewClient(time.Second).Get(server.URL)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		require.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is not allowed")
+		assert.False(t, called)
+	})
+}
@@ -0,0 +1,383 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxMessages    = 100
+	defaultMaxMessageSize = 10 << 20
+	captureCommandTimeout = 5 * time.Minute
+)
+
+// CapturedMessage is an email received by the capture server
+type CapturedMessage struct {
+	ID         int       `json:"id"`
+	From       string    `json:"from"`
+	To         []string  `json:"to"`
+	Subject    string    `json:"subject"`
+	Size       int       `json:"size"`
+	ReceivedAt time.Time `json:"received_at"`
+
+	Data []byte `json:"-"`
+}
+
+// CaptureServer is SMTP server for development, it accepts every email, keeps last ones in memory
+// and shows them over HTTP instead of delivering
+type CaptureServer struct {
+	// MaxMessages is how many last emails are kept
+	MaxMessages int
+	// MaxMessageSize is a size of the biggest accepted email, bigger ones are rejected like real servers do
+	MaxMessageSize int
+	// TLSConfig enables STARTTLS if it is set
+	TLSConfig *tls.Config
+
+	mu       sync.RWMutex
+	messages []*CapturedMessage
+	nextID   int
+	listener net.Listener
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+func NewCaptureServer() *CaptureServer {
+	return &CaptureServer{
+		MaxMessages:    defaultMaxMessages,
+		MaxMessageSize: defaultMaxMessageSize,
+		nextID:         1,
+	}
+}
+
+// Serve accepts SMTP connections of the listener until the server is closed
+func (s *CaptureServer) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.RLock()
+			closed := s.closed
+			s.mu.RUnlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// Close stops accepting connections and waits for open sessions to finish
+func (s *CaptureServer) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	l := s.listener
+	s.mu.Unlock()
+
+	var err error
+	if l != nil {
+		err = l.Close()
+	}
+	s.wg.Wait()
+	return err
+}
+
+// Messages returns captured emails, the newest one is the first
+func (s *CaptureServer) Messages() []*CapturedMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]*CapturedMessage, len(s.messages))
+	copy(messages, s.messages)
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID > messages[j].ID })
+	return messages
+}
+
+func (s *CaptureServer) Message(id int) (*CapturedMessage, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, m := range s.messages {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// Reset removes all captured emails
+func (s *CaptureServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = nil
+}
+
+func (s *CaptureServer) store(from string, to []string, data []byte) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := &CapturedMessage{
+		ID:         s.nextID,
+		From:       from,
+		To:         to,
+		Subject:    subjectOf(data),
+		Size:       len(data),
+		ReceivedAt: time.Now(),
+		Data:       data,
+	}
+	s.nextID++
+
+	s.messages = append(s.messages, msg)
+	if len(s.messages) > s.MaxMessages {
+		s.messages = s.messages[len(s.messages)-s.MaxMessages:]
+	}
+	return msg.ID
+}
+
+// session is a state of SMTP transaction of one connection
+type session struct {
+	from string
+	to   []string
+	mail bool
+}
+
+func (s *CaptureServer) serveConn(conn net.Conn) {
+	tp := textproto.NewConn(conn)
+	defer tp.Close()
+
+	reply := func(format string, args ...interface{}) bool {
+		return tp.PrintfLine(format, args...) == nil
+	}
+
+	if !reply("220 nnw capture ESMTP") {
+		return
+	}
+
+	var tx session
+	tlsActive := false
+
+	for {
+		if err := conn.SetDeadline(time.Now().Add(captureCommandTimeout)); err != nil {
+			return
+		}
+
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			verb, arg = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			tx = session{}
+			ext := []string{"nnw capture", "8BITMIME", "AUTH PLAIN", "SIZE " + strconv.Itoa(s.MaxMessageSize)}
+			if s.TLSConfig != nil && !tlsActive {
+				ext = append(ext, "STARTTLS")
+			}
+			for i, e := range ext {
+				sep := "-"
+				if i == len(ext)-1 {
+					sep = " "
+				}
+				if !reply("250%s%s", sep, e) {
+					return
+				}
+			}
+		case "HELO":
+			tx = session{}
+			reply("250 nnw capture")
+		case "STARTTLS":
+			if s.TLSConfig == nil || tlsActive {
+				reply("502 5.5.1 STARTTLS is not supported")
+				continue
+			}
+			if !reply("220 2.0.0 Ready to start TLS") {
+				return
+			}
+			tlsConn := tls.Server(conn, s.TLSConfig)
+			if err = tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, tlsActive, tx = tlsConn, true, session{}
+			tp = textproto.NewConn(conn)
+		case "AUTH":
+			// every credential is accepted, initial response is read if client hasn't sent it
+			if fields := strings.Fields(arg); len(fields) == 1 {
+				if !reply("334 ") {
+					return
+				}
+				if _, err = tp.ReadLine(); err != nil {
+					return
+				}
+			}
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			tx = session{from: pathOf(arg, "FROM:"), mail: true}
+			reply("250 2.1.0 Ok")
+		case "RCPT":
+			if !tx.mail {
+				reply("503 5.5.1 Need MAIL command")
+				continue
+			}
+			tx.to = append(tx.to, pathOf(arg, "TO:"))
+			reply("250 2.1.5 Ok")
+		case "DATA":
+			if len(tx.to) == 0 {
+				reply("503 5.5.1 Need RCPT command")
+				continue
+			}
+			if !reply("354 End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+
+			r := tp.DotReader()
+			data, err := ioutil.ReadAll(io.LimitReader(r, int64(s.MaxMessageSize)+1))
+			if err != nil {
+				return
+			}
+			// the rest of the big email is read, so the session stays in sync
+			if _, err = io.Copy(ioutil.Discard, r); err != nil {
+				return
+			}
+
+			if len(data) > s.MaxMessageSize {
+				reply("552 5.3.4 Message size exceeds fixed limit")
+			} else {
+				reply("250 2.0.0 Ok: queued as %d", s.store(tx.from, tx.to, data))
+			}
+			tx = session{}
+		case "RSET":
+			tx = session{}
+			reply("250 2.0.0 Ok")
+		case "NOOP":
+			reply("250 2.0.0 Ok")
+		case "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			reply("502 5.5.2 Command not recognized")
+		}
+	}
+}
+
+// pathOf returns address of MAIL or RCPT argument, e.g. "FROM:<a@b.com> SIZE=100"
+func pathOf(arg, prefix string) string {
+	if len(arg) >= len(prefix) && strings.EqualFold(arg[:len(prefix)], prefix) {
+		arg = arg[len(prefix):]
+	}
+	if i := strings.IndexByte(arg, '>'); i >= 0 {
+		arg = arg[:i]
+	}
+	return strings.TrimPrefix(strings.TrimSpace(arg), "<")
+}
+
+func subjectOf(data []byte) string {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+
+	subject := msg.Header.Get("Subject")
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(subject); err == nil {
+		return decoded
+	}
+	return subject
+}
+
+var captureIndex = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Captured emails</title></head>
+<body>
+<h1>Captured emails</h1>
+<table>
+<tr><th>Received</th><th>From</th><th>To</th><th>Subject</th></tr>
+{{range .}}<tr>
+<td>{{.ReceivedAt.Format "2006-01-02 15:04:05"}}</td>
+<td>{{.From}}</td>
+<td>{{range $i, $to := .To}}{{if $i}}, {{end}}{{$to}}{{end}}</td>
+<td><a href="/messages/{{.ID}}">{{if .Subject}}{{.Subject}}{{else}}(no subject){{end}}</a></td>
+</tr>{{else}}<tr><td colspan="4">No emails yet</td></tr>{{end}}
+</table>
+</body>
+</html>
+`))
+
+// Handler shows captured emails:
+//
+//	GET    /                list of emails
+//	GET    /messages        list of emails in JSON
+//	GET    /messages/{id}   raw email, it can be opened by a mail client as .eml
+//	DELETE /messages        remove all emails
+func (s *CaptureServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = captureIndex.Execute(w, s.Messages())
+	})
+
+	mux.HandleFunc("/messages", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(s.Messages())
+		case http.MethodDelete:
+			s.Reset()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/messages/", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/messages/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		msg, ok := s.Message(id)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "message/rfc822")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%d.eml\"", msg.ID))
+		_, _ = w.Write(msg.Data)
+	})
+
+	return mux
+}
@@ -1,31 +1,286 @@
 package smtp
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	stderrors "errors"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"sync"
+	"time"
 )
 
-// Client is the wrapper for smtp
+// TLSMode is a way connection to SMTP server is secured
+type TLSMode string
+
+const (
+	// TLSImplicit is TLS from the first byte, it is usually used on port 465
+	TLSImplicit TLSMode = "implicit"
+	// TLSStartTLS upgrades plain connection with STARTTLS, delivery fails if server doesn't support it
+	TLSStartTLS TLSMode = "starttls"
+	// TLSNone is a plain connection, it is for local servers only
+	TLSNone TLSMode = "none"
+)
+
+const (
+	defaultTimeout     = 30 * time.Second
+	defaultIdleTimeout = time.Minute
+	defaultLocalName   = "localhost"
+	tcpKeepAlive       = 30 * time.Second
+)
+
+type Options struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+
+	// TLSMode is implicit for port 465 and starttls for other ports if it is empty
+	TLSMode TLSMode
+	// RootCAs verify server certificate, system roots are used if it is nil
+	RootCAs *x509.CertPool
+
+	// Timeout limits dialing and every SMTP command
+	Timeout time.Duration
+	// IdleTimeout is how long connection is kept open between emails
+	IdleTimeout time.Duration
+	// LocalName is a host name sent in EHLO
+	LocalName string
+}
+
+// Client sends emails through one connection which is reused while it is alive
 type Client struct {
-	host string
-	auth smtp.Auth
+	opts      Options
+	addr      string
+	tlsConfig *tls.Config
+
+	mu       sync.Mutex
+	conn     *smtp.Client
+	netConn  net.Conn
+	lastUsed time.Time
 }
 
-// NewClient returns a new smtp client wrapper
-func NewClient(smtpHost string, smtpPort int, userKey string, keyPass string) *Client {
-	auth := smtp.PlainAuth(
-		"",
-		userKey,
-		keyPass,
-		smtpHost,
-	)
+// NewClient returns a new smtp client, connection is dialed on the first email
+func NewClient(opts Options) (*Client, error) {
+	if opts.Host == "" || opts.Port <= 0 {
+		return nil, fmt.Errorf("invalid smtp address %s:%d", opts.Host, opts.Port)
+	}
+
+	switch opts.TLSMode {
+	case "":
+		opts.TLSMode = TLSStartTLS
+		if opts.Port == 465 {
+			opts.TLSMode = TLSImplicit
+		}
+	case TLSImplicit, TLSStartTLS, TLSNone:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %q", opts.TLSMode)
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = defaultIdleTimeout
+	}
+	if opts.LocalName == "" {
+		opts.LocalName = defaultLocalName
+	}
+
 	return &Client{
-		host: fmt.Sprintf("%s:%d", smtpHost, smtpPort),
-		auth: auth,
+		opts: opts,
+		addr: net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)),
+		tlsConfig: &tls.Config{
+			ServerName: opts.Host,
+			RootCAs:    opts.RootCAs,
+			MinVersion: tls.VersionTLS12,
+		},
+	}, nil
+}
+
+// LoadRootCAs reads PEM certificates of the file, e.g. CA of corporate SMTP relay
+func LoadRootCAs(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", file)
+	}
+	return pool, nil
+}
+
+// SendMail sends email through the pooled connection, returned error is *Error
+func (c *Client) SendMail(from string, to []string, msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conn, err := c.connection()
+	if err != nil {
+		return classify(stageConnect, err)
+	}
+
+	if stage, err := c.send(conn, from, to, msg); err != nil {
+		// server rejected the email, so the session is still usable after RSET
+		var reply *textproto.Error
+		if stderrors.As(err, &reply) && c.deadline() == nil && conn.Reset() == nil {
+			c.lastUsed = time.Now()
+		} else {
+			c.close()
+		}
+		return classify(stage, err)
+	}
+
+	c.lastUsed = time.Now()
+	return nil
+}
+
+// Check makes sure the server is reachable and accepts credentials, alive pooled connection is reused
+func (c *Client) Check() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil && c.deadline() == nil && c.conn.Noop() == nil {
+		return nil
+	}
+
+	c.close()
+	if err := c.dial(); err != nil {
+		return classify(stageConnect, err)
 	}
+	c.lastUsed = time.Now()
+	return nil
 }
 
-// SendMail wraps smtp.SendMail
-func (s *Client) SendMail(from string, to []string, msg []byte) error {
-	return smtp.SendMail(s.host, s.auth, from, to, msg)
+// Close closes pooled connection, next email dials a new one
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Quit()
+	c.close()
+	return err
+}
+
+// connection returns pooled connection if it is alive or dials a new one
+func (c *Client) connection() (*smtp.Client, error) {
+	if c.conn != nil {
+		if time.Since(c.lastUsed) < c.opts.IdleTimeout && c.deadline() == nil && c.conn.Noop() == nil {
+			return c.conn, nil
+		}
+		c.close()
+	}
+
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c.conn, nil
+}
+
+func (c *Client) dial() error {
+	dialer := &net.Dialer{Timeout: c.opts.Timeout, KeepAlive: tcpKeepAlive}
+
+	var (
+		netConn net.Conn
+		err     error
+	)
+	if c.opts.TLSMode == TLSImplicit {
+		netConn, err = tls.DialWithDialer(dialer, "tcp", c.addr, c.tlsConfig)
+	} else {
+		netConn, err = dialer.Dial("tcp", c.addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = netConn.SetDeadline(time.Now().Add(c.opts.Timeout)); err != nil {
+		netConn.Close()
+		return err
+	}
+
+	conn, err := smtp.NewClient(netConn, c.opts.Host)
+	if err != nil {
+		netConn.Close()
+		return err
+	}
+
+	if err = c.handshake(conn); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c.conn, c.netConn = conn, netConn
+	return nil
+}
+
+// handshake greets the server, upgrades connection to TLS and authenticates
+func (c *Client) handshake(conn *smtp.Client) error {
+	if err := conn.Hello(c.opts.LocalName); err != nil {
+		return err
+	}
+
+	if c.opts.TLSMode == TLSStartTLS {
+		if ok, _ := conn.Extension("STARTTLS"); !ok {
+			return errStartTLSNotSupported
+		}
+		if err := conn.StartTLS(c.tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if c.opts.Username == "" {
+		return nil
+	}
+	if ok, _ := conn.Extension("AUTH"); !ok {
+		return errAuthNotSupported
+	}
+	// PlainAuth refuses to send credentials over plain connection to any host but localhost
+	return conn.Auth(smtp.PlainAuth("", c.opts.Username, c.opts.Password, c.opts.Host))
+}
+
+// send runs SMTP transaction of the email and returns stage it failed on
+func (c *Client) send(conn *smtp.Client, from string, to []string, msg []byte) (stage, error) {
+	if err := c.deadline(); err != nil {
+		return stageConnect, err
+	}
+
+	if err := conn.Mail(from); err != nil {
+		return stageMail, err
+	}
+	for _, rcpt := range to {
+		if err := conn.Rcpt(rcpt); err != nil {
+			return stageRcpt, err
+		}
+	}
+
+	w, err := conn.Data()
+	if err != nil {
+		return stageData, err
+	}
+	if _, err = w.Write(msg); err != nil {
+		return stageData, err
+	}
+	return stageData, w.Close()
+}
+
+// deadline limits time of the next commands on the connection
+func (c *Client) deadline() error {
+	return c.netConn.SetDeadline(time.Now().Add(c.opts.Timeout))
+}
+
+func (c *Client) close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn, c.netConn = nil, nil
 }
@@ -0,0 +1,199 @@
+package smtp_test
+
+import (
+	stderrors "errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/pkg/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEmail = "From: from@nnw.com\r\nTo: to@mail.com\r\nSubject: =?utf-8?q?Hello_=E2=9C=93?=\r\n\r\nbody\r\n"
+
+// startCapture starts capture server on random local port
+func startCapture(t *testing.T, server *smtp.CaptureServer) (string, int) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+
+	addr := l.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestClient(t *testing.T) {
+	t.Run("should send emails through one connection", func(t *testing.T) {
+		server := smtp.NewCaptureServer()
+		host, port := startCapture(t, server)
+
+		client, err := smtp.NewClient(smtp.Options{Host: host, Port: port, TLSMode: smtp.TLSNone, Username: "user", Password: "pass"})
+		require.Nil(t, err)
+		defer client.Close()
+
+		require.Nil(t, client.SendMail("from@nnw.com", []string{"to@mail.com", "cc@mail.com"}, []byte(testEmail)))
+		require.Nil(t, client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail)))
+
+		messages := server.Messages()
+		require.Len(t, messages, 2)
+		assert.Equal(t, "from@nnw.com", messages[1].From)
+		assert.Equal(t, []string{"to@mail.com", "cc@mail.com"}, messages[1].To)
+		assert.Equal(t, "Hello ✓", messages[1].Subject)
+	})
+
+	t.Run("should redial if connection is closed by server", func(t *testing.T) {
+		server := smtp.NewCaptureServer()
+		host, port := startCapture(t, server)
+
+		client, err := smtp.NewClient(smtp.Options{Host: host, Port: port, TLSMode: smtp.TLSNone})
+		require.Nil(t, err)
+		defer client.Close()
+
+		require.Nil(t, client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail)))
+		require.Nil(t, client.Close())
+		require.Nil(t, client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail)))
+		assert.Len(t, server.Messages(), 2)
+	})
+
+	t.Run("should return permanent error if email is rejected", func(t *testing.T) {
+		server := smtp.NewCaptureServer()
+		server.MaxMessageSize = 16
+		host, port := startCapture(t, server)
+
+		client, err := smtp.NewClient(smtp.Options{Host: host, Port: port, TLSMode: smtp.TLSNone})
+		require.Nil(t, err)
+		defer client.Close()
+
+		err = client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail))
+
+		var smtpErr *smtp.Error
+		require.True(t, stderrors.As(err, &smtpErr))
+		assert.Equal(t, 552, smtpErr.Code)
+		assert.False(t, smtpErr.Temporary())
+
+		// session is still usable after rejected email
+		require.Nil(t, client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte("Subject: x\r\n\r\n")))
+		assert.Len(t, server.Messages(), 1)
+	})
+
+	t.Run("should return temporary error if server is down", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err)
+		port := l.Addr().(*net.TCPAddr).Port
+		require.Nil(t, l.Close())
+
+		client, err := smtp.NewClient(smtp.Options{Host: "127.0.0.1", Port: port, TLSMode: smtp.TLSNone})
+		require.Nil(t, err)
+
+		err = client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail))
+
+		var smtpErr *smtp.Error
+		require.True(t, stderrors.As(err, &smtpErr))
+		assert.True(t, smtpErr.Temporary())
+	})
+
+	t.Run("should upgrade connection with STARTTLS", func(t *testing.T) {
+		// test server of httptest has certificate of 127.0.0.1
+		tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+		defer tlsServer.Close()
+
+		server := smtp.NewCaptureServer()
+		server.TLSConfig = tlsServer.TLS
+		host, port := startCapture(t, server)
+
+		roots := tlsServer.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+		client, err := smtp.NewClient(smtp.Options{Host: host, Port: port, TLSMode: smtp.TLSStartTLS, RootCAs: roots})
+		require.Nil(t, err)
+		defer client.Close()
+
+		require.Nil(t, client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail)))
+		assert.Len(t, server.Messages(), 1)
+	})
+
+	t.Run("should not send email if STARTTLS is required but not supported", func(t *testing.T) {
+		server := smtp.NewCaptureServer()
+		host, port := startCapture(t, server)
+
+		client, err := smtp.NewClient(smtp.Options{Host: host, Port: port, TLSMode: smtp.TLSStartTLS})
+		require.Nil(t, err)
+
+		err = client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail))
+		require.NotNil(t, err)
+		assert.Contains(t, err.Error(), "STARTTLS")
+		assert.Empty(t, server.Messages())
+	})
+
+	t.Run("should check server and reuse the connection", func(t *testing.T) {
+		server := smtp.NewCaptureServer()
+		host, port := startCapture(t, server)
+
+		client, err := smtp.NewClient(smtp.Options{Host: host, Port: port, TLSMode: smtp.TLSNone})
+		require.Nil(t, err)
+		defer client.Close()
+
+		require.Nil(t, client.Check())
+		require.Nil(t, client.Check())
+		require.Nil(t, client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail)))
+		assert.Len(t, server.Messages(), 1)
+	})
+
+	t.Run("should fail check if server is down", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err)
+		port := l.Addr().(*net.TCPAddr).Port
+		require.Nil(t, l.Close())
+
+		client, err := smtp.NewClient(smtp.Options{Host: "127.0.0.1", Port: port, TLSMode: smtp.TLSNone})
+		require.Nil(t, err)
+		assert.NotNil(t, client.Check())
+	})
+
+	t.Run("should return error on unknown tls mode", func(t *testing.T) {
+		_, err := smtp.NewClient(smtp.Options{Host: "smtp.mail.com", Port: 25, TLSMode: "ssl"})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCaptureHandler(t *testing.T) {
+	server := smtp.NewCaptureServer()
+	host, port := startCapture(t, server)
+
+	client, err := smtp.NewClient(smtp.Options{Host: host, Port: port, TLSMode: smtp.TLSNone})
+	require.Nil(t, err)
+	defer client.Close()
+	require.Nil(t, client.SendMail("from@nnw.com", []string{"to@mail.com"}, []byte(testEmail)))
+
+	web := httptest.NewServer(server.Handler())
+	defer web.Close()
+
+	get := func(t *testing.T, path string) (int, string) {
+		resp, err := http.Get(web.URL + path)
+		require.Nil(t, err)
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.Nil(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get(t, "/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "Hello ✓")
+
+	code, body = get(t, "/messages")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"subject":"Hello ✓"`)
+
+	code, body = get(t, "/messages/1")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, strings.HasSuffix(body, "body\n"))
+
+	code, _ = get(t, "/messages/2")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,58 @@
+package smtp
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/textproto"
+)
+
+var (
+	errStartTLSNotSupported = stderrors.New("smtp server doesn't support STARTTLS")
+	errAuthNotSupported     = stderrors.New("smtp server doesn't support AUTH")
+)
+
+// stage is a part of SMTP session, it tells whether failure is caused by the email or by the server
+type stage string
+
+const (
+	stageConnect stage = "connect"
+	stageMail    stage = "mail"
+	stageRcpt    stage = "rcpt"
+	stageData    stage = "data"
+)
+
+// Error is a failure of email delivery
+type Error struct {
+	// Code is SMTP reply code, it is 0 if the server hasn't replied, e.g. on network error
+	Code int
+
+	stage     stage
+	temporary bool
+	err       error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("smtp %s: %v", e.stage, e.err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Temporary reports whether the email can be delivered on retry.
+// Only 5xx replies to the email itself are permanent, failures of connection, TLS or authentication
+// are problems of the server or its settings and go away once they are fixed.
+func (e *Error) Temporary() bool {
+	return e.temporary
+}
+
+func classify(s stage, err error) error {
+	e := &Error{stage: s, temporary: true, err: err}
+
+	var reply *textproto.Error
+	if stderrors.As(err, &reply) {
+		e.Code = reply.Code
+		e.temporary = s == stageConnect || reply.Code < 500
+	}
+	return e
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+// Exporters of spans
+const (
+	// ExporterNone does not record spans, trace context of requests is still propagated
+	ExporterNone = "none"
+	// ExporterStdout writes spans as JSON to stdout, it is for local development
+	ExporterStdout = "stdout"
+	// ExporterOTLP posts spans to OTLP/HTTP receiver, e.g. OpenTelemetry collector or Jaeger
+	ExporterOTLP = "otlp"
+)
+
+const defaultExportTimeout = 10 * time.Second
+
+type Config struct {
+	Exporter string
+	// Endpoint is base URL of OTLP/HTTP receiver, spans are posted to its /v1/traces
+	Endpoint    string
+	ServiceName string
+	// SampleRatio is a share of exported traces which are started by the server,
+	// traces started by callers are sampled as callers decide
+	SampleRatio float64
+	// Output of stdout exporter, it is os.Stdout if it is nil
+	Output io.Writer
+}
+
+// Setup installs global tracer provider of cfg and propagation of W3C trace context.
+// The returned shutdown exports remaining spans and stops the provider.
+func Setup(cfg Config) (shutdown func(ctx context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		output := cfg.Output
+		if output == nil {
+			output = os.Stdout
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(output))
+	case ExporterOTLP:
+		exporter, err = newOTLPExporter(cfg.Endpoint)
+	default:
+		return nil, fmt.Errorf("unknown exporter %q, should be %s, %s or %s", cfg.Exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// newOTLPExporter returns exporter which posts spans to /v1/traces of OTLP/HTTP receiver at base URL endpoint
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("%s exporter requires endpoint", ExporterOTLP)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%s endpoint should be absolute http(s) url", ExporterOTLP)
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(strings.TrimSuffix(u.Path, "/") + "/v1/traces"),
+		otlptracehttp.WithTimeout(defaultExportTimeout),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	// the exporter connects lazily, so it doesn't fail if receiver is not up yet
+	return otlptracehttp.New(context.Background(), opts...)
+}
@@ -0,0 +1,159 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"nnw_s/pkg/httpx"
+	"nnw_s/pkg/tracing"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs provider which records ended spans of the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	return recorder
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+
+	router := echo.New()
+	router.Use(tracing.Middleware())
+	router.Use(httpx.ErrorResponse())
+	router.GET("/wallet/:id", func(c echo.Context) error {
+		_, span := tracing.Start(c.Request().Context(), "wallet.GetWallet")
+		span.End()
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("should start span of route with child spans of handler", func(t *testing.T) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/wallet/1", nil))
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		child, server := spans[0], spans[1]
+
+		assert.Equal(t, "GET /wallet/:id", server.Name())
+		assert.Equal(t, "wallet.GetWallet", child.Name())
+		assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+		assert.Contains(t, server.Attributes(), attribute.Int("http.status_code", http.StatusOK))
+	})
+
+	t.Run("should continue trace of caller", func(t *testing.T) {
+		traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+		req := httptest.NewRequest(http.MethodGet, "/wallet/1", nil)
+		req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		spans := recorder.Ended()
+		server := spans[len(spans)-1]
+		assert.Equal(t, traceID, server.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	})
+
+	t.Run("should not name span with unknown path", func(t *testing.T) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/random/path", nil))
+
+		spans := recorder.Ended()
+		server := spans[len(spans)-1]
+		assert.Equal(t, "GET", server.Name())
+		assert.Contains(t, server.Attributes(), attribute.Int("http.status_code", http.StatusNotFound))
+	})
+}
+
+func TestEnd(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := tracing.Start(context.Background(), "rpc getbalance")
+	tracing.End(span, errors.New("wallet not found"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "wallet not found", spans[0].Status().Description)
+}
+
+func TestExporters(t *testing.T) {
+	// spans are exported by batcher of the provider, shutdown flushes them
+	emit := func(t *testing.T, cfg tracing.Config) {
+		shutdown, err := tracing.Setup(cfg)
+		require.Nil(t, err)
+
+		ctx, parent := tracing.Start(context.Background(), "wallet.GetWalletTx", attribute.String("chain", "BTC"))
+		_, child := tracing.StartClient(ctx, "rpc getrawtransaction", attribute.Int("attempt", 1))
+		tracing.End(child, errors.New("timeout"))
+		parent.End()
+
+		require.Nil(t, shutdown(context.Background()))
+	}
+
+	t.Run("should post spans to otlp receiver", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			path     string
+			received int
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			path = r.URL.Path
+			body, _ := ioutil.ReadAll(r.Body)
+			received += len(body)
+		}))
+		defer server.Close()
+
+		emit(t, tracing.Config{Exporter: tracing.ExporterOTLP, Endpoint: server.URL + "/", ServiceName: "nnw", SampleRatio: 1})
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "/v1/traces", path)
+		assert.NotZero(t, received)
+	})
+
+	t.Run("should write spans as json", func(t *testing.T) {
+		var out bytes.Buffer
+		emit(t, tracing.Config{Exporter: tracing.ExporterStdout, Output: &out, ServiceName: "nnw", SampleRatio: 1})
+
+		assert.Contains(t, out.String(), `"Name":"rpc getrawtransaction"`)
+		assert.Contains(t, out.String(), `"Name":"wallet.GetWalletTx"`)
+		assert.Contains(t, out.String(), `"Description":"timeout"`)
+	})
+}
+
+func TestSetup(t *testing.T) {
+	_, err := tracing.Setup(tracing.Config{Exporter: "zipkin"})
+	assert.NotNil(t, err)
+
+	_, err = tracing.Setup(tracing.Config{Exporter: tracing.ExporterOTLP})
+	assert.NotNil(t, err, "otlp exporter requires endpoint")
+
+	_, err = tracing.Setup(tracing.Config{Exporter: tracing.ExporterOTLP, Endpoint: "127.0.0.1:4318"})
+	assert.NotNil(t, err, "otlp endpoint should be url")
+
+	shutdown, err := tracing.Setup(tracing.Config{Exporter: tracing.ExporterNone})
+	require.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+}
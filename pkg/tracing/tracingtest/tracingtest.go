@@ -0,0 +1,34 @@
+// Package tracingtest helps to check in tests that services pass context of the caller down. Services start
+// spans, which replace context of the caller with a child one, so mocks can't expect the caller's context itself.
+package tracingtest
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+)
+
+type callerKey struct{}
+
+// Context returns context of a test caller, contexts derived from it are matched by FromContext
+func Context() context.Context {
+	return context.WithValue(context.Background(), callerKey{}, new(int))
+}
+
+type fromContext struct {
+	caller interface{}
+}
+
+// FromContext matches parent and contexts derived from it, parent should be created by Context
+func FromContext(parent context.Context) gomock.Matcher {
+	return fromContext{caller: parent.Value(callerKey{})}
+}
+
+func (m fromContext) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	return ok && ctx != nil && m.caller != nil && ctx.Value(callerKey{}) == m.caller
+}
+
+func (m fromContext) String() string {
+	return "is derived from context of the caller"
+}
@@ -0,0 +1,29 @@
+package uow
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// memoryUnit is a unit of work of storage without transactions, e.g. in-memory storage or standalone mongo server.
+// Changes of storage made before a failure are kept, only compensations are run.
+type memoryUnit struct {
+	log *logrus.Logger
+}
+
+func NewMemory(log *logrus.Logger) (UnitOfWork, error) {
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &memoryUnit{log: log}, nil
+}
+
+func (u *memoryUnit) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return compensate(ctx, u.log, fn)
+}
+
+func (u *memoryUnit) Compensate(ctx context.Context, fn func(ctx context.Context) error) error {
+	return compensate(ctx, u.log, fn)
+}
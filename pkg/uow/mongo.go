@@ -0,0 +1,80 @@
+package uow
+
+import (
+	"context"
+	"nnw_s/pkg/errors"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoUnit runs unit of work in transaction of mongo session. Session is passed to repositories by ctx of fn,
+// so they join the transaction without changes.
+type mongoUnit struct {
+	client *mongo.Client
+	log    *logrus.Logger
+}
+
+func NewMongo(client *mongo.Client, log *logrus.Logger) (UnitOfWork, error) {
+	if client == nil {
+		return nil, errors.NewInternal("invalid mongo client")
+	}
+	if log == nil {
+		return nil, errors.NewInternal("invalid logger")
+	}
+	return &mongoUnit{client: client, log: log}, nil
+}
+
+func (u *mongoUnit) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, c := withCompensations(ctx)
+
+	session, err := u.client.StartSession()
+	if err != nil {
+		u.log.WithContext(ctx).Errorf("failed to start mongo session: %v", err)
+		return errors.NewInternal(err.Error())
+	}
+	defer session.EndSession(detach(ctx))
+
+	err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		if err := session.StartTransaction(); err != nil {
+			u.log.WithContext(sc).Errorf("failed to start mongo transaction: %v", err)
+			return errors.NewInternal(err.Error())
+		}
+
+		if err := fn(sc); err != nil {
+			if abortErr := session.AbortTransaction(detach(sc)); abortErr != nil {
+				u.log.WithContext(sc).Errorf("failed to abort mongo transaction: %v", abortErr)
+			}
+			return err
+		}
+
+		if err := session.CommitTransaction(sc); err != nil {
+			u.log.WithContext(sc).Errorf("failed to commit mongo transaction: %v", err)
+			return errors.NewInternal(err.Error())
+		}
+		return nil
+	})
+
+	if err != nil {
+		c.rollback(ctx, u.log)
+	}
+	return err
+}
+
+func (u *mongoUnit) Compensate(ctx context.Context, fn func(ctx context.Context) error) error {
+	return compensate(ctx, u.log, fn)
+}
+
+// SupportsTransactions checks that mongo server is a member of replica set or a router of sharded cluster,
+// standalone servers do not support transactions.
+func SupportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
@@ -0,0 +1,91 @@
+// Package uow runs multi-step operations of services as a unit of work: changes of storage are committed
+// together or not at all, and side effects outside of storage are compensated if the operation fails.
+package uow
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type UnitOfWork interface {
+	// Do runs fn as a unit of work. Repositories which are called with ctx of fn join the unit,
+	// their changes are committed if fn succeeds. If fn or commit fails, changes are discarded and
+	// compensations registered by OnRollback are run in reverse order. Do does not retry fn,
+	// fn may call nodes of chains and these calls must not be repeated.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+	// Compensate runs fn without transaction. fn makes side effects outside of storage, e.g. calls nodes
+	// of chains, registers their compensations by OnRollback and then saves results by Do, so slow side
+	// effects don't hold the transaction open. Compensations are run in reverse order if fn fails.
+	Compensate(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Compensation reverts a side effect of a failed unit of work, e.g. a wallet created on a node
+type Compensation func(ctx context.Context) error
+
+type compensationKey struct{}
+
+// compensations of a unit of work, they are registered while the unit is running
+type compensations struct {
+	mu    sync.Mutex
+	names []string
+	funcs []Compensation
+}
+
+// OnRollback registers compensation of side effect of the unit of work of ctx, it is run if the unit fails.
+// It does nothing if ctx does not belong to a unit of work.
+func OnRollback(ctx context.Context, name string, compensate Compensation) {
+	c, ok := ctx.Value(compensationKey{}).(*compensations)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.names = append(c.names, name)
+	c.funcs = append(c.funcs, compensate)
+}
+
+func withCompensations(ctx context.Context) (context.Context, *compensations) {
+	c := &compensations{}
+	return context.WithValue(ctx, compensationKey{}, c), c
+}
+
+// compensate runs fn with compensations of its own, they are run if fn fails
+func compensate(ctx context.Context, log *logrus.Logger, fn func(ctx context.Context) error) error {
+	ctx, c := withCompensations(ctx)
+	if err := fn(ctx); err != nil {
+		c.rollback(ctx, log)
+		return err
+	}
+	return nil
+}
+
+// rollback runs compensations in reverse order of registration, failed compensations are logged
+// and do not stop the rest. ctx of the failed unit may be canceled, so compensations are run with detached context.
+func (c *compensations) rollback(ctx context.Context, log *logrus.Logger) {
+	c.mu.Lock()
+	names, funcs := c.names, c.funcs
+	c.mu.Unlock()
+
+	for i := len(funcs) - 1; i >= 0; i-- {
+		if err := funcs[i](detach(ctx)); err != nil {
+			log.WithContext(ctx).Errorf("failed to compensate '%s' of failed unit of work: %v", names[i], err)
+		}
+	}
+}
+
+// detachedContext keeps values of parent context, e.g. trace and request id, but not its cancellation
+type detachedContext struct {
+	context.Context
+	parent context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{Context: context.Background(), parent: ctx}
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
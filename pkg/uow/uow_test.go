@@ -0,0 +1,144 @@
+package uow_test
+
+import (
+	"context"
+	"errors"
+	"nnw_s/pkg/mongodb/mongotest"
+	"nnw_s/pkg/uow"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type key struct{}
+
+func TestMemory(t *testing.T) {
+	unit, err := uow.NewMemory(logrus.New())
+	require.Nil(t, err)
+
+	t.Run("should compensate side effects in reverse order on failure", func(t *testing.T) {
+		var calls []string
+		compensate := func(name string) uow.Compensation {
+			return func(context.Context) error {
+				calls = append(calls, name)
+				return errors.New("node is unavailable")
+			}
+		}
+
+		err := unit.Do(context.Background(), func(ctx context.Context) error {
+			uow.OnRollback(ctx, "first", compensate("first"))
+			uow.OnRollback(ctx, "second", compensate("second"))
+			return errors.New("failed")
+		})
+
+		assert.EqualError(t, err, "failed")
+		assert.Equal(t, []string{"second", "first"}, calls, "failed compensation does not stop the rest")
+	})
+
+	t.Run("should compensate side effects made before failed unit of work", func(t *testing.T) {
+		var calls []string
+		err := unit.Compensate(context.Background(), func(ctx context.Context) error {
+			uow.OnRollback(ctx, "wallet", func(context.Context) error {
+				calls = append(calls, "wallet")
+				return nil
+			})
+			return unit.Do(ctx, func(ctx context.Context) error {
+				return errors.New("failed to save")
+			})
+		})
+
+		assert.EqualError(t, err, "failed to save")
+		assert.Equal(t, []string{"wallet"}, calls)
+	})
+
+	t.Run("should not compensate on success", func(t *testing.T) {
+		compensated := false
+		err := unit.Do(context.Background(), func(ctx context.Context) error {
+			uow.OnRollback(ctx, "wallet", func(context.Context) error {
+				compensated = true
+				return nil
+			})
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.False(t, compensated)
+	})
+
+	t.Run("should compensate with values of canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "req-1"))
+
+		var compensateCtx context.Context
+		_ = unit.Do(ctx, func(ctx context.Context) error {
+			uow.OnRollback(ctx, "wallet", func(ctx context.Context) error {
+				compensateCtx = ctx
+				return nil
+			})
+			cancel()
+			return ctx.Err()
+		})
+
+		require.NotNil(t, compensateCtx)
+		assert.Nil(t, compensateCtx.Err())
+		assert.Equal(t, "req-1", compensateCtx.Value(key{}))
+	})
+}
+
+func TestOnRollback(t *testing.T) {
+	assert.NotPanics(t, func() {
+		uow.OnRollback(context.Background(), "wallet", func(context.Context) error { return nil })
+	}, "context without unit of work is ignored")
+}
+
+func TestMongo(t *testing.T) {
+	ctx := context.Background()
+	db := mongotest.DB(t)
+
+	supported, err := uow.SupportsTransactions(ctx, db.Client())
+	require.Nil(t, err)
+	if !supported {
+		t.Skip("mongo server does not support transactions")
+	}
+
+	// collections can't be created in a transaction of older servers
+	require.Nil(t, db.CreateCollection(ctx, "wallets"))
+
+	unit, err := uow.NewMongo(db.Client(), logrus.New())
+	require.Nil(t, err)
+
+	t.Run("should discard changes and compensate on failure", func(t *testing.T) {
+		compensated := false
+		err := unit.Do(ctx, func(ctx context.Context) error {
+			uow.OnRollback(ctx, "wallet", func(context.Context) error {
+				compensated = true
+				return nil
+			})
+			if _, err := db.Collection("wallets").InsertOne(ctx, bson.M{"_id": "discarded"}); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		})
+
+		assert.NotNil(t, err)
+		assert.True(t, compensated)
+
+		count, err := db.Collection("wallets").CountDocuments(ctx, bson.M{"_id": "discarded"})
+		require.Nil(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("should commit changes on success", func(t *testing.T) {
+		err := unit.Do(ctx, func(ctx context.Context) error {
+			_, err := db.Collection("wallets").InsertOne(ctx, bson.M{"_id": "committed"})
+			return err
+		})
+		require.Nil(t, err)
+
+		count, err := db.Collection("wallets").CountDocuments(ctx, bson.M{"_id": "committed"})
+		require.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
@@ -0,0 +1,55 @@
+// Package validation validates DTOs of requests and reports every invalid field.
+package validation
+
+import (
+	"nnw_s/pkg/errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// New returns validator which names invalid fields as clients name them, by json, query or path tags of DTO
+func New() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(fieldName)
+	return validate
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "param", "form"} {
+		// "-" is not returned, validator skips such fields
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// Error returns target error with all invalid fields of err of validator. Reason of a field is its failed rule
+// with parameter, e.g. required or min=8, so clients can map it to their messages.
+func Error(target error, err error) error {
+	validationErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.WithMessage(target, err.Error())
+	}
+
+	names := make([]string, 0, len(validationErrs))
+	fields := make([]errors.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		reason := fe.Tag()
+		if fe.Param() != "" {
+			reason += "=" + fe.Param()
+		}
+
+		// namespace starts with name of DTO type, it is not known to clients
+		name := fe.Namespace()
+		if i := strings.Index(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+
+		names = append(names, name)
+		fields = append(fields, errors.FieldError{Field: name, Reason: reason})
+	}
+	return errors.WithFields(errors.WithMessage(target, "invalid fields: %s", strings.Join(names, ", ")), fields...)
+}
@@ -0,0 +1,58 @@
+package validation_test
+
+import (
+	"nnw_s/pkg/codes"
+	"nnw_s/pkg/errors"
+	"nnw_s/pkg/validation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errInvalidRequest = errors.New(codes.BadRequest, "invalid_request")
+
+type walletDTO struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type createWalletDTO struct {
+	Jwt      string       `query:"jwt" validate:"required"`
+	Email    string       `json:"email,omitempty" validate:"required,email"`
+	Password string       `json:"password" validate:"min=8"`
+	Wallets  []*walletDTO `json:"wallets" validate:"dive"`
+	Internal string       `json:"-" validate:"required"`
+}
+
+func TestError(t *testing.T) {
+	t.Run("should report every invalid field", func(t *testing.T) {
+		dto := createWalletDTO{Email: "user", Password: "qwerty", Wallets: []*walletDTO{{}}}
+		err := validation.Error(errInvalidRequest, validation.New().Struct(dto))
+
+		domainErr, ok := err.(*errors.Error)
+		require.True(t, ok)
+		assert.Equal(t, errors.Status("invalid_request"), domainErr.Status)
+		assert.Equal(t, "invalid fields: jwt, email, password, wallets[0].name, Internal", domainErr.Message)
+		assert.Equal(t, []errors.FieldError{
+			{Field: "jwt", Reason: "required"},
+			{Field: "email", Reason: "email"},
+			{Field: "password", Reason: "min=8"},
+			{Field: "wallets[0].name", Reason: "required"},
+			{Field: "Internal", Reason: "required"},
+		}, domainErr.Fields)
+	})
+
+	t.Run("should keep target error of invalid value", func(t *testing.T) {
+		err := validation.Error(errInvalidRequest, validation.New().Struct(nil))
+
+		domainErr, ok := err.(*errors.Error)
+		require.True(t, ok)
+		assert.Equal(t, errors.Status("invalid_request"), domainErr.Status)
+		assert.Empty(t, domainErr.Fields)
+	})
+
+	t.Run("should pass valid dto", func(t *testing.T) {
+		dto := createWalletDTO{Jwt: "token", Email: "user@nnw.com", Password: "qwerty12", Internal: "id"}
+		assert.Nil(t, validation.New().Struct(&dto))
+	})
+}
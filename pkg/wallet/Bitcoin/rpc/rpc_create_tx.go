@@ -1,10 +1,9 @@
 package rpc
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"math/big"
-	"strings"
 )
 
 type UnspentList struct {
@@ -13,7 +12,7 @@ type UnspentList struct {
 	ScriptPubKey string `json:"scriptPubKey"`
 }
 
-func CreateTransaction(utxos []*UTXO, addressTo string, spendAmount *big.Int) (string, []*UnspentList, error) {
+func (c *Client) CreateTransaction(ctx context.Context, utxos []*UTXO, addressTo string, spendAmount *big.Int) (string, []*UnspentList, error) {
 	//var unspentParams []map[string]interface{}
 	var unspentParams []*UnspentList
 
@@ -81,7 +80,7 @@ func CreateTransaction(utxos []*UTXO, addressTo string, spendAmount *big.Int) (s
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, false, "")
+	err := c.call(ctx, req, &msg, false, "")
 	if err != nil {
 		return "", nil, errors.New("could not create transaction")
 	}
@@ -90,10 +89,5 @@ func CreateTransaction(utxos []*UTXO, addressTo string, spendAmount *big.Int) (s
 		return "", nil, errors.New(msg.Error.Message)
 	}
 
-	fmt.Printf("%-18s %s\n", "Balance:", utxosAmount)
-	fmt.Printf("%-18s %s\n", "Spend amount:", spendAmount)
-	fmt.Printf("%-18s %s\n", "Remainder: ", utxosAmount.Sub(utxosAmount, spendAmount))
-	fmt.Println(strings.Repeat("-", 106))
-
 	return msg.Result, unspentParams, nil
 }
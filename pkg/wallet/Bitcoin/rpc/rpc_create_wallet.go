@@ -1,10 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
-func CreateWallet(walletId string) (string, error) {
+func (c *Client) CreateWallet(ctx context.Context, walletId string) (string, error) {
 	msg := struct {
 		Result struct {
 			Name string `json:"name"`
@@ -25,7 +26,7 @@ func CreateWallet(walletId string) (string, error) {
 		Params:  []string{walletId},
 	}
 
-	err := Client(req, &msg, false, "")
+	err := c.call(ctx, req, &msg, false, "")
 	if err != nil {
 		return "", errors.New(msg.Error.Message)
 	}
@@ -1,8 +1,11 @@
 package rpc
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
-func EncryptWallet(password, walletId string) error {
+func (c *Client) EncryptWallet(ctx context.Context, password, walletId string) error {
 	msg := struct {
 		Result string `json:"result"`
 		Error  struct {
@@ -21,7 +24,7 @@ func EncryptWallet(password, walletId string) error {
 		Params:  []string{password},
 	}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return errors.New(msg.Error.Message)
 	}
@@ -1,13 +1,12 @@
 package rpc
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"github.com/btcsuite/btcutil"
-	"strings"
 )
 
-func FundForTransaction(createTxHash, changeAddress, walletId string) (string, error) {
+func (c *Client) FundForTransaction(ctx context.Context, createTxHash, changeAddress, walletId string) (string, error) {
 	subtractFeeFromOutputs := []int64{0}
 
 	params := map[string]interface{}{
@@ -35,7 +34,7 @@ func FundForTransaction(createTxHash, changeAddress, walletId string) (string, e
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return "", errors.New("could not fund for transaction")
 	}
@@ -44,14 +43,9 @@ func FundForTransaction(createTxHash, changeAddress, walletId string) (string, e
 		return "", errors.New(msg.Error.Message)
 	}
 
-	feeAmount, err := btcutil.NewAmount(msg.Result.Fee)
-	if err != nil {
+	if _, err := btcutil.NewAmount(msg.Result.Fee); err != nil {
 		return "", err
 	}
 
-	fmt.Printf("%-18s %v\n", "Fee for transaction in BTC:", feeAmount)
-	fmt.Printf("%-18s %v\n", "Fee for transaction in Satoshi:", feeAmount.Format(btcutil.AmountSatoshi))
-	fmt.Println(strings.Repeat("-", 106))
-
 	return msg.Result.Hex, nil
 }
@@ -1,10 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
-func AddressInfo(address, walletId string) (string, error) {
+func (c *Client) AddressInfo(ctx context.Context, address, walletId string) (string, error) {
 	req := struct {
 		JsonRPC string   `json:"json_rpc"`
 		Method  string   `json:"method"`
@@ -25,7 +26,7 @@ func AddressInfo(address, walletId string) (string, error) {
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return "", errors.New("could not get address info")
 	}
@@ -1,12 +1,13 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 	"github.com/btcsuite/btcutil"
 	"math/big"
 )
 
-func GetBalance(walletId string) (*big.Int, error) {
+func (c *Client) GetBalance(ctx context.Context, walletId string) (*big.Int, error) {
 	req := struct {
 		JsonRPC string        `json:"json_rpc"`
 		Method  string        `json:"method"`
@@ -24,7 +25,7 @@ func GetBalance(walletId string) (*big.Int, error) {
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return nil, errors.New("could not get address info")
 	}
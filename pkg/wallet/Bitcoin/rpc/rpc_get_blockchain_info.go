@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+)
+
+// GetChain returns name of the chain of node: main, test or regtest
+func (c *Client) GetChain(ctx context.Context) (string, error) {
+	msg := struct {
+		Result struct {
+			Chain  string `json:"chain"`
+			Blocks int64  `json:"blocks"`
+		} `json:"result"`
+		Error struct {
+			Code    int64  `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+
+	req := struct {
+		JsonRPC string   `json:"json_rpc"`
+		Method  string   `json:"method"`
+		Params  []string `json:"params"`
+	}{
+		JsonRPC: "2.0",
+		Method:  "getblockchaininfo",
+		Params:  []string{},
+	}
+
+	if err := c.call(ctx, req, &msg, false, ""); err != nil {
+		return "", err
+	}
+
+	if msg.Error.Message != "" {
+		return "", errors.New(msg.Error.Message)
+	}
+
+	return msg.Result.Chain, nil
+}
@@ -1,13 +1,14 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 	"log"
 	"math/big"
 )
 
 // GetCurrentFee gets the current fee in bitcoin
-func GetCurrentFee() (*float64, error) {
+func (c *Client) GetCurrentFee(ctx context.Context) (*float64, error) {
 	req := struct {
 		JsonRPC string `json:"json_rpc"`
 		Method  string `json:"method"`
@@ -28,7 +29,7 @@ func GetCurrentFee() (*float64, error) {
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, false, "first")
+	err := c.call(ctx, req, &msg, false, "first")
 	if err != nil {
 		return nil, err
 	}
@@ -60,8 +61,8 @@ func GetCurrentFee() (*float64, error) {
 }
 
 // GetCurrentFeeRate gets the current fee in satoshis per kb
-func GetCurrentFeeRate() (*big.Int, error) {
-	fee, err := GetCurrentFee()
+func (c *Client) GetCurrentFeeRate(ctx context.Context) (*big.Int, error) {
+	fee, err := c.GetCurrentFee(ctx)
 	if err != nil {
 		return nil, err
 	}
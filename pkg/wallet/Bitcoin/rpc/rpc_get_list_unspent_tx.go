@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 	"github.com/btcsuite/btcutil"
 	"math/big"
@@ -15,7 +16,7 @@ type UTXO struct {
 	PKScript string
 }
 
-func ListUnspentTXOs(address string, walletId string) ([]*UTXO, error) {
+func (c *Client) ListUnspentTXOs(ctx context.Context, address string, walletId string) ([]*UTXO, error) {
 	addressArray := []string{address}
 
 	req := struct {
@@ -50,7 +51,7 @@ func ListUnspentTXOs(address string, walletId string) ([]*UTXO, error) {
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return nil, errors.New("could not get utxos")
 	}
@@ -1,10 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
-func GetAddressPrivateKey(address, walletId string) (string, error) {
+func (c *Client) GetAddressPrivateKey(ctx context.Context, address, walletId string) (string, error) {
 	msg := struct {
 		Result string `json:"result"`
 		Error  struct {
@@ -22,7 +23,7 @@ func GetAddressPrivateKey(address, walletId string) (string, error) {
 		Params:  []string{address},
 	}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return "", errors.New("could not sent transaction")
 	}
@@ -1,8 +1,8 @@
 package rpc
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"time"
 )
 
@@ -23,7 +23,7 @@ type TxInfo struct {
 	Confirmations int64     `json:"confirmations"`
 }
 
-func GetRawTransaction(walletId, address, tx string) (*TxInfo, error) {
+func (c *Client) GetRawTransaction(ctx context.Context, walletId, address, tx string) (*TxInfo, error) {
 	req := struct {
 		JsonRPC string        `json:"json_rpc"`
 		Method  string        `json:"method"`
@@ -56,9 +56,8 @@ func GetRawTransaction(walletId, address, tx string) (*TxInfo, error) {
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
-		fmt.Println(err)
 		return nil, errors.New("could not get transaction list")
 	}
 
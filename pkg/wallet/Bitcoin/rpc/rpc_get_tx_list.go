@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
@@ -16,7 +17,7 @@ type Txs struct {
 	Txid     string      `json:"txid"`
 }
 
-func TransactionList(walletId string) ([]*UTXO, []*Txs, error) {
+func (c *Client) TransactionList(ctx context.Context, walletId string) ([]*UTXO, []*Txs, error) {
 	req := struct {
 		JsonRPC string        `json:"json_rpc"`
 		Method  string        `json:"method"`
@@ -42,7 +43,7 @@ func TransactionList(walletId string) ([]*UTXO, []*Txs, error) {
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return nil, nil, errors.New("could not get transaction list")
 	}
@@ -1,8 +1,11 @@
 package rpc
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
-func ImportPrivateKey(key, walletId string, scan bool) error {
+func (c *Client) ImportPrivateKey(ctx context.Context, key, walletId string, scan bool) error {
 	msg := struct {
 		Result string `json:"result"`
 		Error  struct {
@@ -21,7 +24,7 @@ func ImportPrivateKey(key, walletId string, scan bool) error {
 		Params:  []interface{}{key, "", scan},
 	}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return errors.New(msg.Error.Message)
 	}
@@ -1,10 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
-func LoadWallet(walletID string) (string, error) {
+func (c *Client) LoadWallet(ctx context.Context, walletID string) (string, error) {
 	msg := struct {
 		Result struct {
 			Name    string `json:"name"`
@@ -26,7 +27,7 @@ func LoadWallet(walletID string) (string, error) {
 		Params:  []string{walletID},
 	}
 
-	err := Client(req, &msg, true, walletID)
+	err := c.call(ctx, req, &msg, true, walletID)
 
 	if msg.Error.Code == -4 || msg.Error.Code == -35 {
 		return "", nil
@@ -1,8 +1,11 @@
 package rpc
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
-func LockWallet(walletId string) error {
+func (c *Client) LockWallet(ctx context.Context, walletId string) error {
 	msg := struct {
 		Result interface{} `json:"result"`
 		Error  struct {
@@ -21,7 +24,7 @@ func LockWallet(walletId string) error {
 		Params:  []string{},
 	}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return errors.New(msg.Error.Message)
 	}
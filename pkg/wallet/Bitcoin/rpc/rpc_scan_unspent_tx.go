@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/btcsuite/btcutil"
@@ -8,7 +9,7 @@ import (
 	"strconv"
 )
 
-func ScanUnspentTXOs(address string) ([]*UTXO, *btcutil.Amount, error) {
+func (c *Client) ScanUnspentTXOs(ctx context.Context, address string) ([]*UTXO, *btcutil.Amount, error) {
 	addressArray := []string{"addr(" + address + ")"}
 
 	req := struct {
@@ -42,7 +43,7 @@ func ScanUnspentTXOs(address string) ([]*UTXO, *btcutil.Amount, error) {
 		} `json:"error"`
 	}{}
 
-	err := Client(req, &msg, false, "")
+	err := c.call(ctx, req, &msg, false, "")
 	if err != nil {
 		return nil, nil, errors.New("could not scan unspent transactions")
 	}
@@ -70,7 +71,6 @@ func ScanUnspentTXOs(address string) ([]*UTXO, *btcutil.Amount, error) {
 		return nil, nil, err
 	}
 	totalAmount, _ := btcutil.NewAmount(float)
-	fmt.Println("TOTAL", int64(totalAmount))
 
 	return utxos, &totalAmount, nil
 }
@@ -1,10 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
-func SendTx(signedTX string) (string, error) {
+func (c *Client) SendTx(ctx context.Context, signedTX string) (string, error) {
 	msg := struct {
 		Result string `json:"result"`
 		Error  struct {
@@ -22,7 +23,7 @@ func SendTx(signedTX string) (string, error) {
 		Params:  []string{signedTX},
 	}
 
-	err := Client(req, &msg, false, "")
+	err := c.call(ctx, req, &msg, false, "")
 	if err != nil {
 		return "", errors.New("could not sent transaction")
 	}
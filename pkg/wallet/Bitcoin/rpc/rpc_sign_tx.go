@@ -1,10 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
-func SignTx(tx string, privateKey string, unspentUtxos []*UnspentList) (string, error) {
+func (c *Client) SignTx(ctx context.Context, tx string, privateKey string, unspentUtxos []*UnspentList) (string, error) {
 	privateKeyArray := []string{privateKey}
 
 	msg := struct {
@@ -28,7 +29,7 @@ func SignTx(tx string, privateKey string, unspentUtxos []*UnspentList) (string,
 		Params:  []interface{}{tx, privateKeyArray, unspentUtxos},
 	}
 
-	err := Client(req, &msg, false, "")
+	err := c.call(ctx, req, &msg, false, "")
 	if err != nil {
 		return "", errors.New("could not sign transaction")
 	}
@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+)
+
+// UnloadWallet unloads wallet from the node, the wallet file stays in wallets directory of the node
+func (c *Client) UnloadWallet(ctx context.Context, walletID string) error {
+	msg := struct {
+		Error struct {
+			Code    int64  `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+
+	req := struct {
+		JsonRPC string   `json:"json_rpc"`
+		Method  string   `json:"method"`
+		Params  []string `json:"params"`
+	}{
+		JsonRPC: "2.0",
+		Method:  "unloadwallet",
+		Params:  []string{walletID},
+	}
+
+	err := c.call(ctx, req, &msg, false, "")
+
+	// wallet is not loaded
+	if msg.Error.Code == -18 {
+		return nil
+	}
+
+	if err != nil {
+		return errors.New("could not unload wallet")
+	}
+
+	if msg.Error.Message != "" {
+		return errors.New(msg.Error.Message)
+	}
+
+	return nil
+}
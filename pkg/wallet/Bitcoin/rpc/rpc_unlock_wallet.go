@@ -1,10 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
-func UnLockWallet(password, walletId string) error {
+func (c *Client) UnLockWallet(ctx context.Context, password, walletId string) error {
 	msg := struct {
 		Result interface{} `json:"result"`
 		Error  struct {
@@ -23,7 +24,7 @@ func UnLockWallet(password, walletId string) error {
 		Params:  []interface{}{password, 60},
 	}
 
-	err := Client(req, &msg, true, walletId)
+	err := c.call(ctx, req, &msg, true, walletId)
 	if err != nil {
 		return errors.New(msg.Error.Message)
 	}
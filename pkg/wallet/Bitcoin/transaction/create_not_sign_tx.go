@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -13,18 +14,15 @@ import (
 	"nnw_s/pkg/wallet/Bitcoin/rpc"
 )
 
-func CreateNotSignTx(fromWalletPublicAddress, destinationAddress, userWalletId string, amountToSend *big.Int) (string, *big.Int, error) {
-	//chainParams := &chaincfg.MainNetParams
-	chainParams := &chaincfg.TestNet3Params
-
+func CreateNotSignTx(ctx context.Context, client *rpc.Client, chainParams *chaincfg.Params, fromWalletPublicAddress, destinationAddress, userWalletId string, amountToSend *big.Int) (string, *big.Int, error) {
 	// Get fee
-	feeRate, err := rpc.GetCurrentFeeRate()
+	feeRate, err := client.GetCurrentFeeRate(ctx)
 	if err != nil {
 		return "", nil, err
 	}
 
 	// List unspent
-	unspentTXOsList, err := rpc.ListUnspentTXOs(fromWalletPublicAddress, userWalletId)
+	unspentTXOsList, err := client.ListUnspentTXOs(ctx, fromWalletPublicAddress, userWalletId)
 	if err != nil {
 		return "", nil, err
 	}
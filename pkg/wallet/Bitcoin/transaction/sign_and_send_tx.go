@@ -1,14 +1,15 @@
 package transaction
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"nnw_s/pkg/wallet/Bitcoin/rpc"
 )
 
-func SignAndSendTx(userWalletPassword, userWalletId, fromWalletPublicAddress, txHash string, amountToSend *big.Int) (string, error) {
+func SignAndSendTx(ctx context.Context, client *rpc.Client, userWalletPassword, userWalletId, fromWalletPublicAddress, txHash string, amountToSend *big.Int) (string, error) {
 	// List unspent
-	unspentTXOsList, err := rpc.ListUnspentTXOs(fromWalletPublicAddress, userWalletId)
+	unspentTXOsList, err := client.ListUnspentTXOs(ctx, fromWalletPublicAddress, userWalletId)
 	if err != nil {
 		return "", err
 	}
@@ -41,30 +42,30 @@ func SignAndSendTx(userWalletPassword, userWalletId, fromWalletPublicAddress, tx
 		})
 	}
 
-	err = rpc.UnLockWallet(userWalletPassword, userWalletId)
+	err = client.UnLockWallet(ctx, userWalletPassword, userWalletId)
 	if err != nil {
 		return "", errors.New("Wrong password! ")
 	}
 
 	// Get Private key
-	privateKey, err := rpc.GetAddressPrivateKey(fromWalletPublicAddress, userWalletId)
+	privateKey, err := client.GetAddressPrivateKey(ctx, fromWalletPublicAddress, userWalletId)
 	if err != nil {
 		return "", err
 	}
 
 	// Sign Transaction
-	signTxHash, err := rpc.SignTx(txHash, privateKey, unspentTxs)
+	signTxHash, err := client.SignTx(ctx, txHash, privateKey, unspentTxs)
 	if err != nil {
 		return "", err
 	}
 
 	// Send Transaction
-	transactionHash, err := rpc.SendTx(signTxHash)
+	transactionHash, err := client.SendTx(ctx, signTxHash)
 	if err != nil {
 		return "", err
 	}
 
-	err = rpc.LockWallet(userWalletId)
+	err = client.LockWallet(ctx, userWalletId)
 	if err != nil {
 		return "", err
 	}
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -14,19 +15,16 @@ import (
 	"nnw_s/pkg/wallet/Bitcoin/rpc"
 )
 
-func BuildTransaction(fromWalletPublicAddress, destinationAddress, userWalletId, userWalletPassword string, amountToSend *big.Int) {
-	//chainParams := &chaincfg.MainNetParams
-	chainParams := &chaincfg.TestNet3Params
-
+func BuildTransaction(ctx context.Context, client *rpc.Client, chainParams *chaincfg.Params, fromWalletPublicAddress, destinationAddress, userWalletId, userWalletPassword string, amountToSend *big.Int) {
 	// Get fee
-	feeRate, err := rpc.GetCurrentFeeRate()
+	feeRate, err := client.GetCurrentFeeRate(ctx)
 	log.Printf("%-18s %s\n", "current fee rate:", feeRate)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// List unspent
-	unspentTXOsList, err := rpc.ListUnspentTXOs(fromWalletPublicAddress, userWalletId)
+	unspentTXOsList, err := client.ListUnspentTXOs(ctx, fromWalletPublicAddress, userWalletId)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -157,13 +155,13 @@ func BuildTransaction(fromWalletPublicAddress, destinationAddress, userWalletId,
 
 	// Prepare to sign tx
 	// Unlock wallet
-	err = rpc.UnLockWallet(userWalletPassword, userWalletId)
+	err = client.UnLockWallet(ctx, userWalletPassword, userWalletId)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// Get Private key
-	privWif, err := rpc.GetAddressPrivateKey(fromWalletPublicAddress, userWalletId)
+	privWif, err := client.GetAddressPrivateKey(ctx, fromWalletPublicAddress, userWalletId)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -206,7 +204,7 @@ func BuildTransaction(fromWalletPublicAddress, destinationAddress, userWalletId,
 	log.Printf("%-18s %s\n", "Redeem Tx:", hex.EncodeToString(buf.Bytes()))
 
 	// Send Transaction
-	sendHash, err := rpc.SendTx(hex.EncodeToString(buf.Bytes()))
+	sendHash, err := client.SendTx(ctx, hex.EncodeToString(buf.Bytes()))
 	if err != nil {
 		log.Fatal(err)
 	}
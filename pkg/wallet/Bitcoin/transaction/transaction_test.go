@@ -1,23 +1,37 @@
 package transaction
 
 import (
+	"context"
 	"fmt"
+	"github.com/btcsuite/btcd/chaincfg"
 	"math/big"
+	"nnw_s/pkg/wallet/Bitcoin/rpc"
+	"nnw_s/pkg/wallet/node/nodetest"
 	"strings"
 	"testing"
 )
 
 func TestBuildTransaction(t *testing.T) {
+	client, err := rpc.NewClient(nodetest.Config(t, "BTC"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	fromWalletPublicAddress := "mq6Qd7JJKsgBYkMFsGCk24MHMxUkuyTnkU"
 	destinationAddress := "mmfbzo2533SFa34ErmYNY4RdVtfw5XYK1u"
 	userWalletId := "first"
 	userWalletPassword := "password"
 	amountToSend := big.NewInt(5555) // amount to send in satoshis (0.01 btc)
 
-	BuildTransaction(fromWalletPublicAddress, destinationAddress, userWalletId, userWalletPassword, amountToSend)
+	BuildTransaction(context.Background(), client, &chaincfg.TestNet3Params, fromWalletPublicAddress, destinationAddress, userWalletId, userWalletPassword, amountToSend)
 }
 
 func TestBuildTransactionV2(t *testing.T) {
+	client, err := rpc.NewClient(nodetest.Config(t, "BTC"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	fromWalletPublicAddress := "mq6Qd7JJKsgBYkMFsGCk24MHMxUkuyTnkU"
 	destinationAddress := "mmfbzo2533SFa34ErmYNY4RdVtfw5XYK1u"
 	userWalletId := "first"
@@ -31,5 +45,5 @@ func TestBuildTransactionV2(t *testing.T) {
 	fmt.Printf("%-18s %s\n", "amount:", amountToSend)
 	fmt.Println(strings.Repeat("-", 106))
 
-	BuildTransactionV2(fromWalletPublicAddress, destinationAddress, userWalletId, userWalletPassword, amountToSend)
+	BuildTransactionV2(context.Background(), client, fromWalletPublicAddress, destinationAddress, userWalletId, userWalletPassword, amountToSend)
 }
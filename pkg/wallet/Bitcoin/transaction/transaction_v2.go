@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/big"
@@ -8,11 +9,11 @@ import (
 	"strings"
 )
 
-func BuildTransactionV2(fromWalletPublicAddress, destinationAddress, userWalletId, userWalletPassword string, amountToSend *big.Int) {
+func BuildTransactionV2(ctx context.Context, client *rpc.Client, fromWalletPublicAddress, destinationAddress, userWalletId, userWalletPassword string, amountToSend *big.Int) {
 	//chainParams := &chaincfg.TestNet3Params
 
 	// Get smart fee
-	feeRate, err := rpc.GetCurrentFeeRate()
+	feeRate, err := client.GetCurrentFeeRate(ctx)
 	fmt.Printf("%-18s %v\n", "current fee rate:", feeRate)
 	if err != nil {
 		log.Fatal(err)
@@ -20,13 +21,13 @@ func BuildTransactionV2(fromWalletPublicAddress, destinationAddress, userWalletI
 	fmt.Println(strings.Repeat("-", 106))
 
 	// Get list unspent tx
-	utxos, err := rpc.ListUnspentTXOs(fromWalletPublicAddress, userWalletId)
+	utxos, err := client.ListUnspentTXOs(ctx, fromWalletPublicAddress, userWalletId)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// Create Transaction
-	createTxHash, unspentUtxosList, err := rpc.CreateTransaction(utxos, destinationAddress, amountToSend)
+	createTxHash, unspentUtxosList, err := client.CreateTransaction(ctx, utxos, destinationAddress, amountToSend)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -36,7 +37,7 @@ func BuildTransactionV2(fromWalletPublicAddress, destinationAddress, userWalletI
 	fmt.Println(strings.Repeat("-", 106))
 
 	// Fund for transaction
-	fundTxHash, err := rpc.FundForTransaction(createTxHash, fromWalletPublicAddress, userWalletId)
+	fundTxHash, err := client.FundForTransaction(ctx, createTxHash, fromWalletPublicAddress, userWalletId)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -45,22 +46,19 @@ func BuildTransactionV2(fromWalletPublicAddress, destinationAddress, userWalletI
 	fmt.Println(strings.Repeat("-", 106))
 
 	// Unlock wallet
-	err = rpc.UnLockWallet(userWalletPassword, userWalletId)
+	err = client.UnLockWallet(ctx, userWalletPassword, userWalletId)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// Get Private key
-	privWif, err := rpc.GetAddressPrivateKey(fromWalletPublicAddress, userWalletId)
+	privWif, err := client.GetAddressPrivateKey(ctx, fromWalletPublicAddress, userWalletId)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	fmt.Printf("%-18s %s\n", "Private key:", privWif)
-	fmt.Println(strings.Repeat("-", 106))
-
 	// Sign Transaction
-	signTxHash, err := rpc.SignTx(fundTxHash, privWif, unspentUtxosList)
+	signTxHash, err := client.SignTx(ctx, fundTxHash, privWif, unspentUtxosList)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -69,7 +67,7 @@ func BuildTransactionV2(fromWalletPublicAddress, destinationAddress, userWalletI
 	fmt.Println(strings.Repeat("-", 106))
 
 	// Send Transaction
-	transactionHash, err := rpc.SendTx(signTxHash)
+	transactionHash, err := client.SendTx(ctx, signTxHash)
 	if err != nil {
 		log.Fatal(err)
 	}
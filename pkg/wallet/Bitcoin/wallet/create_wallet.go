@@ -1,7 +1,9 @@
 package wallet
 
 import (
+	"context"
 	"github.com/google/uuid"
+	"nnw_s/pkg/uow"
 	"nnw_s/pkg/wallet/Bitcoin/rpc"
 )
 
@@ -10,7 +12,12 @@ type Payload struct {
 	Address  string
 }
 
-func CreateBTCWallet(backup bool, password, wif, address, mnemonic string) (*Payload, error) {
+// RecordOrphan records wallet which is left on the node, so its files are removed by cleanup
+type RecordOrphan func(ctx context.Context, walletID string) error
+
+// CreateBTCWallet creates encrypted wallet of the key on the node. If unit of work of ctx fails,
+// the wallet is unloaded and passed to recordOrphan, bitcoind can't delete wallets by RPC.
+func CreateBTCWallet(ctx context.Context, client *rpc.Client, recordOrphan RecordOrphan, backup bool, password, wif, address, mnemonic string) (*Payload, error) {
 	//var km *KeyManager
 	//var bError error
 
@@ -56,27 +63,37 @@ func CreateBTCWallet(backup bool, password, wif, address, mnemonic string) (*Pay
 		return nil, err
 	}
 
-	wallet, err := rpc.CreateWallet(walletId.String())
+	wallet, err := client.CreateWallet(ctx, walletId.String())
 	if err != nil {
 		return nil, err
 	}
 
-	err = rpc.EncryptWallet(password, wallet)
+	// wallet is unloaded from the node and recorded as orphan if creation of user's wallets fails,
+	// it is recorded even if the node fails to unload it
+	uow.OnRollback(ctx, "create BTC wallet "+wallet, func(ctx context.Context) error {
+		unloadErr := client.UnloadWallet(ctx, wallet)
+		if err := recordOrphan(ctx, wallet); err != nil {
+			return err
+		}
+		return unloadErr
+	})
+
+	err = client.EncryptWallet(ctx, password, wallet)
 	if err != nil {
 		return nil, err
 	}
 
-	err = rpc.UnLockWallet(password, wallet)
+	err = client.UnLockWallet(ctx, password, wallet)
 	if err != nil {
 		return nil, err
 	}
 
-	err = rpc.ImportPrivateKey(wif, walletId.String(), false)
+	err = client.ImportPrivateKey(ctx, wif, walletId.String(), false)
 	if err != nil {
 		return nil, err
 	}
 
-	err = rpc.LockWallet(walletId.String())
+	err = client.LockWallet(ctx, walletId.String())
 	if err != nil {
 		return nil, err
 	}
@@ -54,9 +54,9 @@ type Key struct {
 	bip32Key *bip32.Key
 }
 
-func (k *Key) Encode(compress bool) (wif, address, segwitBech32, segwitNested string, err error) {
+func (k *Key) Encode(params *chaincfg.Params, compress bool) (wif, address, segwitBech32, segwitNested string, err error) {
 	prvKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), k.bip32Key.Key)
-	return GenerateFromBytes(prvKey, compress)
+	return GenerateFromBytes(params, prvKey, compress)
 }
 
 // https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki
@@ -263,17 +263,18 @@ func (km *KeyManager) GetKey(purpose, coinType, account, change, index uint32) (
 	return &Key{path: path, bip32Key: key}, nil
 }
 
-func Generate(compress bool) (wif, address, segwitBech32, segwitNested string, err error) {
+func Generate(params *chaincfg.Params, compress bool) (wif, address, segwitBech32, segwitNested string, err error) {
 	prvKey, err := btcec.NewPrivateKey(btcec.S256())
 	if err != nil {
 		return "", "", "", "", err
 	}
-	return GenerateFromBytes(prvKey, compress)
+	return GenerateFromBytes(params, prvKey, compress)
 }
 
-func GenerateFromBytes(prvKey *btcec.PrivateKey, compress bool) (wif, address, segwitBech32, segwitNested string, err error) {
+// GenerateFromBytes encodes WIF and addresses of the private key for the network of params
+func GenerateFromBytes(params *chaincfg.Params, prvKey *btcec.PrivateKey, compress bool) (wif, address, segwitBech32, segwitNested string, err error) {
 	// generate the wif(wallet import format) string
-	btcwif, err := btcutil.NewWIF(prvKey, &chaincfg.TestNet3Params, compress)
+	btcwif, err := btcutil.NewWIF(prvKey, params, compress)
 	if err != nil {
 		return "", "", "", "", err
 	}
@@ -281,7 +282,7 @@ func GenerateFromBytes(prvKey *btcec.PrivateKey, compress bool) (wif, address, s
 
 	// generate a normal p2pkh address
 	serializedPubKey := btcwif.SerializePubKey()
-	addressPubKey, err := btcutil.NewAddressPubKey(serializedPubKey, &chaincfg.TestNet3Params)
+	addressPubKey, err := btcutil.NewAddressPubKey(serializedPubKey, params)
 	if err != nil {
 		return "", "", "", "", err
 	}
@@ -289,7 +290,7 @@ func GenerateFromBytes(prvKey *btcec.PrivateKey, compress bool) (wif, address, s
 
 	// generate a normal p2wkh address from the pubkey hash
 	witnessProg := btcutil.Hash160(serializedPubKey)
-	addressWitnessPubKeyHash, err := btcutil.NewAddressWitnessPubKeyHash(witnessProg, &chaincfg.TestNet3Params)
+	addressWitnessPubKeyHash, err := btcutil.NewAddressWitnessPubKeyHash(witnessProg, params)
 	if err != nil {
 		return "", "", "", "", err
 	}
@@ -303,7 +304,7 @@ func GenerateFromBytes(prvKey *btcec.PrivateKey, compress bool) (wif, address, s
 	if err != nil {
 		return "", "", "", "", err
 	}
-	addressScriptHash, err := btcutil.NewAddressScriptHash(serializedScript, &chaincfg.TestNet3Params)
+	addressScriptHash, err := btcutil.NewAddressScriptHash(serializedScript, params)
 	if err != nil {
 		return "", "", "", "", err
 	}
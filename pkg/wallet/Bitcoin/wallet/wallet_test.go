@@ -1,19 +1,27 @@
 package wallet
 
 import (
+	"context"
 	"fmt"
+	"github.com/btcsuite/btcd/chaincfg"
 	"nnw_s/pkg/wallet/Bitcoin/rpc"
+	"nnw_s/pkg/wallet/node/nodetest"
 	"strings"
 	"testing"
 	"time"
 )
 
 func TestWalletAndTransaction(t *testing.T) {
+	client, err := rpc.NewClient(nodetest.Config(t, "BTC"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	/*
 		fmt.Printf("\n%-34s %-52s %-42s %s\n", "Bitcoin Address", "WIF(Wallet Import Format)", "SegWit(bech32)", "SegWit(nested)")
 		fmt.Println(strings.Repeat("-", 165))
 
-		wif, address, segwitBech32, segwitNested, err := Generate(true)
+		wif, address, segwitBech32, segwitNested, err := Generate(&chaincfg.TestNet3Params, true)
 		if err != nil {
 			t.Error(err)
 		}
@@ -84,7 +92,7 @@ func TestWalletAndTransaction(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	wif, address, _, _, err := key.Encode(true)
+	wif, address, _, _, err := key.Encode(&chaincfg.TestNet3Params, true)
 	if err != nil {
 		t.Error(err)
 	}
@@ -92,31 +100,31 @@ func TestWalletAndTransaction(t *testing.T) {
 	fmt.Printf("%-18s %-34s %s\n", key.GetPath(), address, wif)
 	fmt.Println(strings.Repeat("-", 106))
 
-	wallet, err := rpc.CreateWallet("ninth")
+	wallet, err := client.CreateWallet(context.Background(), "ninth")
 	if err != nil {
 		t.Error(err)
 	}
 
-	err = rpc.EncryptWallet("password", wallet)
+	err = client.EncryptWallet(context.Background(), "password", wallet)
 	if err != nil {
 		t.Error(err)
 	}
 
-	err = rpc.UnLockWallet("password", wallet)
+	err = client.UnLockWallet(context.Background(), "password", wallet)
 	if err != nil {
 		t.Error(err)
 	}
 
 	if backUp {
 		go func() {
-			err := rpc.ImportPrivateKey(wif, wallet, true)
+			err := client.ImportPrivateKey(context.Background(), wif, wallet, true)
 			if err != nil {
 				t.Error(err)
 			}
 		}()
 		time.Sleep(2 * time.Second)
 	} else {
-		err := rpc.ImportPrivateKey(wif, wallet, false)
+		err := client.ImportPrivateKey(context.Background(), wif, wallet, false)
 		if err != nil {
 			t.Error(err)
 		}
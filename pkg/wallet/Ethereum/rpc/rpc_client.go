@@ -1,54 +1,23 @@
 package rpc
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io/ioutil"
-	"net/http"
+	"context"
+	"nnw_s/pkg/wallet/node"
 )
 
-func Client(body, res interface{}) error {
-	localURL := "http://127.0.0.1:8545"
-
-	serverAddr := localURL
-
-	client := &http.Client{}
-
-	jsonBody, _ := json.Marshal(body)
-	reqBody := bytes.NewBuffer(jsonBody)
-	req, err := http.NewRequest("POST", serverAddr, reqBody)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
-	//req.SetBasicAuth("uuuset", "password123123")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	defer resp.Body.Close()
-
-	respBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	fmt.Println(string(respBody))
+// Client calls JSON RPC of Ethereum node
+type Client struct {
+	conn *node.Conn
+}
 
-	err = json.Unmarshal(respBody, res)
+func NewClient(cfg node.Config) (*Client, error) {
+	conn, err := node.NewConn(cfg)
 	if err != nil {
-		return err
-	}
-
-	if resp.StatusCode != 200 {
-		return errors.New(string(respBody))
+		return nil, err
 	}
+	return &Client{conn: conn}, nil
+}
 
-	return nil
+func (c *Client) call(ctx context.Context, body, res interface{}) error {
+	return c.conn.Post(ctx, "", body, res)
 }
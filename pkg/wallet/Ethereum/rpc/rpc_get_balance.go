@@ -1,12 +1,13 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"nnw_s/pkg/helpers"
 )
 
-func GetBalance(address string) (*big.Int, error) {
+func (c *Client) GetBalance(ctx context.Context, address string) (*big.Int, error) {
 	msg := struct {
 		JsonRPC string `json:"jsonrpc"`
 		Id      int64  `json:"id"`
@@ -30,7 +31,7 @@ func GetBalance(address string) (*big.Int, error) {
 		Id: 1,
 	}
 
-	err := Client(req, &msg)
+	err := c.call(ctx, req, &msg)
 	if err != nil {
 		return nil, errors.New("could not get address info")
 	}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 )
 
@@ -45,7 +46,7 @@ type Block struct {
 	//Uncles           []string      `json:"uncles"`
 }
 
-func GetBlock(block string, full bool) (*Block, error) {
+func (c *Client) GetBlock(ctx context.Context, block string, full bool) (*Block, error) {
 	msg := struct {
 		JsonRPC string `json:"jsonrpc"`
 		Id      int64  `json:"id"`
@@ -68,7 +69,7 @@ func GetBlock(block string, full bool) (*Block, error) {
 		Id:      1,
 	}
 
-	err := Client(req, &msg)
+	err := c.call(ctx, req, &msg)
 	if err != nil {
 		return nil, errors.New("could not get address info")
 	}
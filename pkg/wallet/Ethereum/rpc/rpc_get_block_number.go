@@ -1,12 +1,13 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"nnw_s/pkg/helpers"
 )
 
-func GetBlockNumber() (*big.Int, error) {
+func (c *Client) GetBlockNumber(ctx context.Context) (*big.Int, error) {
 	msg := struct {
 		JsonRPC string `json:"jsonrpc"`
 		Id      int64  `json:"id"`
@@ -29,9 +30,9 @@ func GetBlockNumber() (*big.Int, error) {
 		Id:      1,
 	}
 
-	err := Client(req, &msg)
+	err := c.call(ctx, req, &msg)
 	if err != nil {
-		return nil, errors.New(msg.Error.Message)
+		return nil, err
 	}
 
 	if msg.Error.Message != "" {
@@ -1,8 +1,11 @@
 package rpc
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
-func GetTransactionCount(address string) error {
+func (c *Client) GetTransactionCount(ctx context.Context, address string) error {
 	msg := struct {
 		JsonRPC string `json:"jsonrpc"`
 		Id      int64  `json:"id"`
@@ -25,7 +28,7 @@ func GetTransactionCount(address string) error {
 		Id:      1,
 	}
 
-	err := Client(req, &msg)
+	err := c.call(ctx, req, &msg)
 	if err != nil {
 		return errors.New("could not get address info")
 	}
@@ -1,8 +1,11 @@
 package rpc
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
-func ImportPrivateKey(privateKey, password string) (string, error) {
+func (c *Client) ImportPrivateKey(ctx context.Context, privateKey, password string) (string, error) {
 	msg := struct {
 		JsonRPC string `json:"jsonrpc"`
 		Id      int64  `json:"id"`
@@ -25,7 +28,7 @@ func ImportPrivateKey(privateKey, password string) (string, error) {
 		Id:      1,
 	}
 
-	err := Client(req, &msg)
+	err := c.call(ctx, req, &msg)
 	if err != nil {
 		return "", errors.New("could not sign transaction")
 	}
@@ -1,8 +1,11 @@
 package rpc
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
-func LockWallet(address string) (bool, error) {
+func (c *Client) LockWallet(ctx context.Context, address string) (bool, error) {
 	msg := struct {
 		JsonRPC string `json:"jsonrpc"`
 		Id      int64  `json:"id"`
@@ -25,7 +28,7 @@ func LockWallet(address string) (bool, error) {
 		Id:      1,
 	}
 
-	err := Client(req, &msg)
+	err := c.call(ctx, req, &msg)
 	if err != nil {
 		return false, errors.New(msg.Error.Message)
 	}
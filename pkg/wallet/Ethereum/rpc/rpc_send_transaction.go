@@ -1,6 +1,9 @@
 package rpc
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 type SendTx struct {
 	From  string `json:"from"`
@@ -8,7 +11,7 @@ type SendTx struct {
 	Value string `json:"value"`
 }
 
-func SendTransaction(from, to, value string) (string, error) {
+func (c *Client) SendTransaction(ctx context.Context, from, to, value string) (string, error) {
 	msg := struct {
 		JsonRPC string `json:"jsonrpc"`
 		Id      int64  `json:"id"`
@@ -37,7 +40,7 @@ func SendTransaction(from, to, value string) (string, error) {
 		Id: 1,
 	}
 
-	err := Client(req, &msg)
+	err := c.call(ctx, req, &msg)
 	if err != nil {
 		return "", errors.New(msg.Error.Message)
 	}
@@ -1,8 +1,10 @@
 package wallet
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
+	"nnw_s/pkg/uow"
 	"nnw_s/pkg/wallet/Ethereum/rpc"
 )
 
@@ -11,18 +13,28 @@ type Payload struct {
 	Address  string
 }
 
-func CreateETHWallet(password, privateKey string) (*Payload, error) {
+// RecordOrphan records account which is left on the node, so it is removed from keystore by cleanup
+type RecordOrphan func(ctx context.Context, address string) error
+
+// CreateETHWallet imports the key to keystore of the node. If unit of work of ctx fails, the account
+// is passed to recordOrphan, geth can't remove accounts by RPC.
+func CreateETHWallet(ctx context.Context, client *rpc.Client, recordOrphan RecordOrphan, password, privateKey string) (*Payload, error) {
 	walletId, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
 	}
 
-	address, err := rpc.ImportPrivateKey(privateKey, password)
+	address, err := client.ImportPrivateKey(ctx, privateKey, password)
 	if err != nil {
 		return nil, err
 	}
 
-	locked, err := rpc.LockWallet(address)
+	// account stays locked in keystore, it is recorded as orphan if creation of user's wallets fails
+	uow.OnRollback(ctx, "import ETH account "+address, func(ctx context.Context) error {
+		return recordOrphan(ctx, address)
+	})
+
+	locked, err := client.LockWallet(ctx, address)
 	if err != nil {
 		return nil, err
 	}
@@ -1,23 +1,27 @@
 package wallet
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/params"
 	"math/big"
 	"nnw_s/pkg/wallet/Ethereum/rpc"
+	"nnw_s/pkg/wallet/node/nodetest"
 	"strconv"
 	"testing"
 )
 
 func TestCreateETHWallet(t *testing.T) {
+	client := newClient(t)
+
 	//41de38e01e053b1d425b73d8fa202f3e9aa8a2be1c3ee3ca180a7ccb96cc7ab3
-	address, err := rpc.ImportPrivateKey("67d0fc18baac0fa03451ccd108c451119929ab6e7467665965b7117fa127896c", "asd")
+	address, err := client.ImportPrivateKey(context.Background(), "67d0fc18baac0fa03451ccd108c451119929ab6e7467665965b7117fa127896c", "asd")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	locked, err := rpc.LockWallet(address)
+	locked, err := client.LockWallet(context.Background(), address)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -32,7 +36,9 @@ func TestCreateETHWallet(t *testing.T) {
 }
 
 func TestGetBalance(t *testing.T) {
-	balance, err := rpc.GetBalance("0x090b60A52B3789924AfB9ABFBd88f31526DE868D")
+	client := newClient(t)
+
+	balance, err := client.GetBalance(context.Background(), "0x090b60A52B3789924AfB9ABFBd88f31526DE868D")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -44,7 +50,9 @@ func TestGetBalance(t *testing.T) {
 }
 
 func TestGetTransactionOfUserWallet(t *testing.T) {
-	blockNumberInt, err := rpc.GetBlockNumber()
+	client := newClient(t)
+
+	blockNumberInt, err := client.GetBlockNumber(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -57,7 +65,7 @@ func TestGetTransactionOfUserWallet(t *testing.T) {
 	for i := startBlock; i < endBlock; i++ {
 		hexNum := strconv.FormatInt(i, 16)
 
-		block, err := rpc.GetBlock("0x"+hexNum, true)
+		block, err := client.GetBlock(context.Background(), "0x"+hexNum, true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -73,7 +81,9 @@ func TestGetTransactionOfUserWallet(t *testing.T) {
 }
 
 func TestSendTransaction(t *testing.T) {
-	unlocked, err := rpc.UnlockWallet("0x090b60A52B3789924AfB9ABFBd88f31526DE868D", "asd123")
+	client := newClient(t)
+
+	unlocked, err := client.UnlockWallet(context.Background(), "0x090b60A52B3789924AfB9ABFBd88f31526DE868D", "asd123")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -85,14 +95,14 @@ func TestSendTransaction(t *testing.T) {
 	}
 
 	//0x214e8348c4f0000 = 0.15 eth
-	txHash, err := rpc.SendTransaction("0x090b60a52b3789924afb9abfbd88f31526de868d", "0x0c8bcef257c70727cd5016318b38ce2c63669437", "0x214e8348c4f0000")
+	txHash, err := client.SendTransaction(context.Background(), "0x090b60a52b3789924afb9abfbd88f31526de868d", "0x0c8bcef257c70727cd5016318b38ce2c63669437", "0x214e8348c4f0000")
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	fmt.Println("Tx HASH: ", txHash)
 
-	locked, err := rpc.LockWallet("0x090b60a52b3789924afb9abfbd88f31526de868d")
+	locked, err := client.LockWallet(context.Background(), "0x090b60a52b3789924afb9abfbd88f31526de868d")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -103,3 +113,11 @@ func TestSendTransaction(t *testing.T) {
 		t.Fatal(errors.New("Wallet doesn't lock. "))
 	}
 }
+
+func newClient(t *testing.T) *rpc.Client {
+	client, err := rpc.NewClient(nodetest.Config(t, "ETH"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
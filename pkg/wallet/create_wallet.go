@@ -13,7 +13,8 @@ import (
 func CreateWallet(coinType uint32, mnemonic string) (*hdkeychain.ExtendedKey, error) {
 	seed := bip39.NewSeed(mnemonic, "")
 
-	// Generate a new master node using the seed.
+	// Generate a new master node using the seed, params of the master key only define encoding
+	// of extended keys, network of derived keys is defined by the coin type.
 	masterKey, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
 	if err != nil {
 		return nil, err
@@ -52,8 +53,9 @@ func CreateWallet(coinType uint32, mnemonic string) (*hdkeychain.ExtendedKey, er
 	return indexPath, nil
 }
 
-func ToBTCWallet(key *hdkeychain.ExtendedKey) (*BTCWallet, error) {
-	address, err := key.Address(&chaincfg.TestNet3Params)
+// ToBTCWallet encodes address and WIF private key of the key for the network
+func ToBTCWallet(key *hdkeychain.ExtendedKey, network *BTCNetwork) (*BTCWallet, error) {
+	address, err := key.Address(network.Params)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +66,10 @@ func ToBTCWallet(key *hdkeychain.ExtendedKey) (*BTCWallet, error) {
 	}
 
 	privKey, _ := btcec.PrivKeyFromBytes(ECPKey.ToECDSA().PublicKey.Curve, crypto.FromECDSA(ECPKey.ToECDSA()))
-	btcwif, err := btcutil.NewWIF(privKey, &chaincfg.TestNet3Params, true)
+	btcwif, err := btcutil.NewWIF(privKey, network.Params, true)
+	if err != nil {
+		return nil, err
+	}
 
 	return &BTCWallet{
 		Address:    address.String(),
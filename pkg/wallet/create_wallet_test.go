@@ -8,12 +8,17 @@ import (
 )
 
 func TestCreateWallet(t *testing.T) {
-	btcKey, err := CreateWallet(BTCCoinType, "pepper fitness kangaroo awesome planet cave melt tide vote wing ramp trim connect estate ball add language absorb web cotton choice roast fluid guess")
+	network, err := NewBTCNetwork(BTCTestnet)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	btcWallet, err := ToBTCWallet(btcKey)
+	btcKey, err := network.CreateKey("pepper fitness kangaroo awesome planet cave melt tide vote wing ramp trim connect estate ball add language absorb web cotton choice roast fluid guess")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	btcWallet, err := ToBTCWallet(btcKey, network)
 	if err != nil {
 		t.Fatal(err)
 	}
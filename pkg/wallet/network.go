@@ -0,0 +1,70 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
+)
+
+const (
+	BTCMainnet = "mainnet"
+	BTCTestnet = "testnet"
+	BTCRegtest = "regtest"
+)
+
+var (
+	ErrUnknownNetwork = errors.New("unknown network")
+	ErrInvalidAddress = errors.New("invalid address")
+	ErrWrongNetwork   = errors.New("address of another network")
+)
+
+// BTCNetwork is a bitcoin network, it defines encoding of addresses and WIF keys
+// and BIP44 coin type of keys derived from mnemonic
+type BTCNetwork struct {
+	Name     string
+	Params   *chaincfg.Params
+	CoinType uint32
+	// NodeChain is name of the chain reported by getblockchaininfo of node
+	NodeChain string
+}
+
+var btcNetworks = []*BTCNetwork{
+	{Name: BTCMainnet, Params: &chaincfg.MainNetParams, CoinType: 0, NodeChain: "main"},
+	{Name: BTCTestnet, Params: &chaincfg.TestNet3Params, CoinType: 1, NodeChain: "test"},
+	{Name: BTCRegtest, Params: &chaincfg.RegressionNetParams, CoinType: 1, NodeChain: "regtest"},
+}
+
+func NewBTCNetwork(name string) (*BTCNetwork, error) {
+	for _, n := range btcNetworks {
+		if n.Name == name {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownNetwork, name)
+}
+
+// CreateKey derives key of the network from mnemonic with BIP44 path m/44H/<coin type>H/0H/0/0
+func (n *BTCNetwork) CreateKey(mnemonic string) (*hdkeychain.ExtendedKey, error) {
+	return CreateWallet(n.CoinType, mnemonic)
+}
+
+// DecodeAddress decodes address of the network, address of another network is rejected with ErrWrongNetwork
+func (n *BTCNetwork) DecodeAddress(address string) (btcutil.Address, error) {
+	addr, err := btcutil.DecodeAddress(address, n.Params)
+	if err == nil && addr.IsForNet(n.Params) {
+		return addr, nil
+	}
+
+	for _, other := range btcNetworks {
+		if other == n {
+			continue
+		}
+		if addr, err := btcutil.DecodeAddress(address, other.Params); err == nil && addr.IsForNet(other.Params) {
+			return nil, fmt.Errorf("%w: %s is %s address, expected %s", ErrWrongNetwork, address, other.Name, n.Name)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrInvalidAddress, address)
+}
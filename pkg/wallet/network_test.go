@@ -0,0 +1,103 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testMnemonic = "pepper fitness kangaroo awesome planet cave melt tide vote wing ramp trim connect estate ball add language absorb web cotton choice roast fluid guess"
+
+func TestBTCNetwork(t *testing.T) {
+	tests := []struct {
+		network       string
+		coinType      uint32
+		addressPrefix []string
+		wifPrefix     []string
+	}{
+		{network: BTCMainnet, coinType: 0, addressPrefix: []string{"1"}, wifPrefix: []string{"K", "L"}},
+		{network: BTCTestnet, coinType: 1, addressPrefix: []string{"m", "n"}, wifPrefix: []string{"c"}},
+		{network: BTCRegtest, coinType: 1, addressPrefix: []string{"m", "n"}, wifPrefix: []string{"c"}},
+	}
+
+	for _, tc := range tests {
+		t.Run("should encode keys of "+tc.network, func(t *testing.T) {
+			network, err := NewBTCNetwork(tc.network)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if network.CoinType != tc.coinType {
+				t.Fatalf("expected coin type %d, got %d", tc.coinType, network.CoinType)
+			}
+
+			key, err := network.CreateKey(testMnemonic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, err := ToBTCWallet(key, network)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !hasPrefix(w.Address, tc.addressPrefix) {
+				t.Errorf("unexpected address %s", w.Address)
+			}
+			if !hasPrefix(w.PrivateKey, tc.wifPrefix) {
+				t.Errorf("unexpected WIF %s", w.PrivateKey)
+			}
+			if _, err = network.DecodeAddress(w.Address); err != nil {
+				t.Errorf("address is rejected: %v", err)
+			}
+		})
+	}
+
+	t.Run("should reject unknown network", func(t *testing.T) {
+		if _, err := NewBTCNetwork("signet"); !errors.Is(err, ErrUnknownNetwork) {
+			t.Errorf("expected ErrUnknownNetwork, got %v", err)
+		}
+	})
+}
+
+func TestBTCNetworkDecodeAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		address string
+		err     error
+	}{
+		{name: "should accept mainnet address", network: BTCMainnet, address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+		{name: "should accept mainnet segwit address", network: BTCMainnet, address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{name: "should accept testnet address", network: BTCTestnet, address: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"},
+		{name: "should accept testnet segwit address", network: BTCTestnet, address: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+		{name: "should reject testnet address on mainnet", network: BTCMainnet, address: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", err: ErrWrongNetwork},
+		{name: "should reject mainnet address on testnet", network: BTCTestnet, address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", err: ErrWrongNetwork},
+		{name: "should reject testnet segwit address on regtest", network: BTCRegtest, address: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", err: ErrWrongNetwork},
+		{name: "should reject malformed address", network: BTCTestnet, address: "0x52908400098527886E0F7030069857D2E4169EE7", err: ErrInvalidAddress},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			network, err := NewBTCNetwork(tc.network)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = network.DecodeAddress(tc.address)
+			if tc.err == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func hasPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"nnw_s/pkg/tracing"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Kinds of authentication of node RPC
+const (
+	AuthNone = "none"
+	// AuthBasic uses user and password
+	AuthBasic = "basic"
+	// AuthCookie reads "user:password" from cookie file of the node, e.g. .cookie of bitcoind,
+	// the file is read on every request because node rewrites it on restart
+	AuthCookie = "cookie"
+	// AuthBearer uses token, e.g. API key of node provider
+	AuthBearer = "bearer"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// maxErrorBody is length of response body kept in error of non-200 status
+const maxErrorBody = 512
+
+// Config is a connection to RPC of a blockchain node
+type Config struct {
+	// Chain of the node, e.g. BTC, it is an attribute of spans of requests
+	Chain      string
+	URL        string
+	Auth       string
+	User       string
+	Password   string
+	CookieFile string
+	Token      string
+	Timeout    time.Duration
+	// Observer is called after every request, e.g. to record metrics of the node
+	Observer Observer
+}
+
+// Observer gets JSON RPC method of request, its duration and error, which is
+// either an error of transport or an error returned by the node
+type Observer func(method string, duration time.Duration, err error)
+
+// Validate checks that the URL is set and the auth has its credentials
+func (cfg *Config) Validate() error {
+	if cfg.URL == "" {
+		return errors.New("node url is empty")
+	}
+
+	switch cfg.Auth {
+	case "", AuthNone:
+	case AuthBasic:
+		if cfg.User == "" {
+			return errors.New("basic auth of node requires user")
+		}
+	case AuthCookie:
+		if cfg.CookieFile == "" {
+			return errors.New("cookie auth of node requires cookie file")
+		}
+	case AuthBearer:
+		if cfg.Token == "" {
+			return errors.New("bearer auth of node requires token")
+		}
+	default:
+		return fmt.Errorf("unknown auth %q of node, should be %s, %s, %s or %s", cfg.Auth, AuthNone, AuthBasic, AuthCookie, AuthBearer)
+	}
+	return nil
+}
+
+// Conn posts JSON RPC requests to the node
+type Conn struct {
+	cfg    Config
+	client *http.Client
+}
+
+func NewConn(cfg Config) (*Conn, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+
+	return &Conn{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+// Post sends body to path of the node and decodes response to res. If status is not 200, the error has
+// the status and the beginning of the body, JSON RPC error of the body is still decoded to res.
+func (c *Conn) Post(ctx context.Context, path string, body, res interface{}) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	method := rpcMethod(jsonBody)
+	ctx, span := tracing.StartClient(ctx, "rpc "+method,
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", method),
+		attribute.String("chain", c.cfg.Chain),
+	)
+
+	start := time.Now()
+	respBody, err := c.post(ctx, path, jsonBody, res)
+
+	observedErr := err
+	if observedErr == nil {
+		observedErr = rpcError(respBody)
+	}
+	if c.cfg.Observer != nil {
+		c.cfg.Observer(method, time.Since(start), observedErr)
+	}
+	tracing.End(span, observedErr)
+
+	return err
+}
+
+func (c *Conn) post(ctx context.Context, path string, jsonBody []byte, res interface{}) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL+path, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+
+	if err = c.authorize(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// body may be JSON RPC error, e.g. bitcoind responds 500 to failed calls, or a page of a proxy
+		_ = json.Unmarshal(respBody, res)
+		return respBody, fmt.Errorf("node responded with status %d: %s", resp.StatusCode, truncate(respBody))
+	}
+
+	if err = json.Unmarshal(respBody, res); err != nil {
+		return respBody, err
+	}
+	return respBody, nil
+}
+
+// truncate limits body of failed response in errors, so a page of a proxy does not flood logs
+func truncate(body []byte) string {
+	if len(body) > maxErrorBody {
+		return string(body[:maxErrorBody]) + "..."
+	}
+	return string(body)
+}
+
+// rpcMethod returns method of JSON RPC request, or "unknown" for a batch or another body
+func rpcMethod(jsonBody []byte) string {
+	var req struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(jsonBody, &req); err != nil || req.Method == "" {
+		return "unknown"
+	}
+	return req.Method
+}
+
+// rpcError returns error of JSON RPC response with status 200, callers of Post check it themselves
+func rpcError(respBody []byte) error {
+	var resp struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil
+	}
+	if len(resp.Error) == 0 || string(resp.Error) == "null" {
+		return nil
+	}
+	return errors.New(string(resp.Error))
+}
+
+func (c *Conn) authorize(req *http.Request) error {
+	switch c.cfg.Auth {
+	case AuthBasic:
+		req.SetBasicAuth(c.cfg.User, c.cfg.Password)
+	case AuthCookie:
+		cookie, err := ioutil.ReadFile(c.cfg.CookieFile)
+		if err != nil {
+			return fmt.Errorf("failed to read cookie file of node: %w", err)
+		}
+
+		credentials := strings.SplitN(strings.TrimSpace(string(cookie)), ":", 2)
+		if len(credentials) != 2 {
+			return errors.New("cookie file of node should contain user:password")
+		}
+		req.SetBasicAuth(credentials[0], credentials[1])
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// Package nodetest provides connections to blockchain nodes for tests of RPC clients.
+package nodetest
+
+import (
+	"nnw_s/pkg/wallet/node"
+	"os"
+	"testing"
+)
+
+// Config returns connection to the node of the chain set by <CHAIN>_TEST_RPC_URL, <CHAIN>_TEST_RPC_USER
+// and <CHAIN>_TEST_RPC_PASSWORD, e.g. BTC_TEST_RPC_URL. Test is skipped if url is not set.
+func Config(t *testing.T, chain string) node.Config {
+	t.Helper()
+
+	url := os.Getenv(chain + "_TEST_RPC_URL")
+	if url == "" {
+		t.Skipf("%s_TEST_RPC_URL is not set", chain)
+	}
+
+	cfg := node.Config{URL: url, Auth: node.AuthNone}
+	if user := os.Getenv(chain + "_TEST_RPC_USER"); user != "" {
+		cfg.Auth = node.AuthBasic
+		cfg.User = user
+		cfg.Password = os.Getenv(chain + "_TEST_RPC_PASSWORD")
+	}
+	return cfg
+}